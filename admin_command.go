@@ -15,6 +15,7 @@
 package aerospike
 
 import (
+	"context"
 	"encoding/binary"
 	"fmt"
 	"time"
@@ -62,6 +63,9 @@ const (
 type adminCommand struct {
 	dataBuffer []byte
 	dataOffset int
+
+	// ctx aborts the command once done; nil means never.
+	ctx context.Context
 }
 
 func newAdminCommand(buf []byte) *adminCommand {
@@ -74,6 +78,23 @@ func newAdminCommand(buf []byte) *adminCommand {
 	}
 }
 
+// context returns the context the command is bound to.
+func (acmd *adminCommand) context() context.Context {
+	if acmd.ctx == nil {
+		return context.Background()
+	}
+	return acmd.ctx
+}
+
+// contextErr replaces an I/O error with the context error
+// if the I/O was interrupted because the context is done.
+func (acmd *adminCommand) contextErr(err error) error {
+	if ctxErr := acmd.context().Err(); ctxErr != nil {
+		return ctxErr
+	}
+	return err
+}
+
 func (acmd *adminCommand) authenticate(conn *Connection, user string, password []byte) error {
 
 	acmd.setAuthenticate(user, password)
@@ -336,17 +357,25 @@ func (acmd *adminCommand) writeFieldHeader(id byte, size int) {
 	acmd.dataOffset++
 }
 
-func (acmd *adminCommand) executeCommand(cluster *Cluster, policy *AdminPolicy) error {
+func (acmd *adminCommand) executeCommand(cluster *Cluster, policy *AdminPolicy) (err error) {
 	// TODO: Remove the workaround in the future
 	defer time.Sleep(time.Millisecond * 10)
 
 	// defer bufPool.Put(acmd.dataBuffer)
 
+	if err := acmd.context().Err(); err != nil {
+		return err
+	}
+
 	acmd.writeSize()
 	node, err := cluster.GetRandomNode()
 	if err != nil {
 		return err
 	}
+
+	attempt := startAttempt(node, CMD_ADMIN, "", "", 1, acmd.context())
+	defer func() { attempt.finish(err) }()
+
 	timeout := 1 * time.Second
 	if policy != nil && policy.Timeout > 0 {
 		timeout = policy.Timeout
@@ -360,12 +389,15 @@ func (acmd *adminCommand) executeCommand(cluster *Cluster, policy *AdminPolicy)
 	}
 
 	conn := node.tendConn
+	attempt.attach(conn)
+	defer conn.watchContext(acmd.context())()
+
 	if _, err := conn.Write(acmd.dataBuffer[:acmd.dataOffset]); err != nil {
-		return err
+		return acmd.contextErr(err)
 	}
 
 	if _, err := conn.Read(acmd.dataBuffer, _HEADER_SIZE); err != nil {
-		return err
+		return acmd.contextErr(err)
 	}
 
 	result := acmd.dataBuffer[_RESULT_CODE]
@@ -376,12 +408,20 @@ func (acmd *adminCommand) executeCommand(cluster *Cluster, policy *AdminPolicy)
 	return nil
 }
 
-func (acmd *adminCommand) readUsers(cluster *Cluster, policy *AdminPolicy) ([]*UserRoles, error) {
+func (acmd *adminCommand) readUsers(cluster *Cluster, policy *AdminPolicy) (users []*UserRoles, err error) {
+	if err := acmd.context().Err(); err != nil {
+		return nil, err
+	}
+
 	acmd.writeSize()
 	node, err := cluster.GetRandomNode()
 	if err != nil {
 		return nil, err
 	}
+
+	attempt := startAttempt(node, CMD_ADMIN, "", "", 1, acmd.context())
+	defer func() { attempt.finish(err) }()
+
 	timeout := 1 * time.Second
 	if policy != nil && policy.Timeout > 0 {
 		timeout = policy.Timeout
@@ -395,13 +435,16 @@ func (acmd *adminCommand) readUsers(cluster *Cluster, policy *AdminPolicy) ([]*U
 	}
 
 	conn := node.tendConn
+	attempt.attach(conn)
+	defer conn.watchContext(acmd.context())()
+
 	if _, err := conn.Write(acmd.dataBuffer[:acmd.dataOffset]); err != nil {
-		return nil, err
+		return nil, acmd.contextErr(err)
 	}
 
 	status, list, err := acmd.readUserBlocks(conn)
 	if err != nil {
-		return nil, err
+		return nil, acmd.contextErr(err)
 	}
 
 	if status > 0 {
@@ -513,12 +556,20 @@ func hashPassword(password string) ([]byte, error) {
 	return []byte(hashedPassword), nil
 }
 
-func (acmd *adminCommand) readRoles(cluster *Cluster, policy *AdminPolicy) ([]*Role, error) {
+func (acmd *adminCommand) readRoles(cluster *Cluster, policy *AdminPolicy) (roles []*Role, err error) {
+	if err := acmd.context().Err(); err != nil {
+		return nil, err
+	}
+
 	acmd.writeSize()
 	node, err := cluster.GetRandomNode()
 	if err != nil {
 		return nil, err
 	}
+
+	attempt := startAttempt(node, CMD_ADMIN, "", "", 1, acmd.context())
+	defer func() { attempt.finish(err) }()
+
 	timeout := 1 * time.Second
 	if policy != nil && policy.Timeout > 0 {
 		timeout = policy.Timeout
@@ -532,13 +583,16 @@ func (acmd *adminCommand) readRoles(cluster *Cluster, policy *AdminPolicy) ([]*R
 	}
 
 	conn := node.tendConn
+	attempt.attach(conn)
+	defer conn.watchContext(acmd.context())()
+
 	if _, err := conn.Write(acmd.dataBuffer[:acmd.dataOffset]); err != nil {
-		return nil, err
+		return nil, acmd.contextErr(err)
 	}
 
 	status, list, err := acmd.readRoleBlocks(conn)
 	if err != nil {
-		return nil, err
+		return nil, acmd.contextErr(err)
 	}
 
 	if status > 0 {
@@ -0,0 +1,843 @@
+// Copyright 2013-2017 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aerospiketest
+
+import (
+	"math"
+	"reflect"
+	"sync"
+	"time"
+
+	as "github.com/aerospike/aerospike-client-go"
+	. "github.com/aerospike/aerospike-client-go/types"
+)
+
+// FakeClient is an in-memory implementation of as.ClientIfc for application unit tests.
+//
+// It implements single record commands, operate (except CDT operations), batch reads
+// and truncate with the semantics of the server: record exists actions, generation
+// checks, expiration, and the result codes of failed commands. Values are returned in
+// the types the client decodes them to, e.g. integers as int and lists as []interface{}.
+//
+// Scans, queries, UDFs, indexes, user management and object mapping are not
+// supported, and return an UNSUPPORTED_FEATURE error.
+type FakeClient struct {
+	mutex   sync.RWMutex
+	records map[fakeKey]*fakeRecord
+	closed  bool
+}
+
+// fakeKey identifies a record in the fake.
+type fakeKey struct {
+	namespace string
+	digest    string
+}
+
+type fakeRecord struct {
+	setName    string
+	bins       as.BinMap
+	generation uint32
+
+	// voidTime is the time the record expires, or zero if it never expires.
+	voidTime   time.Time
+	lastUpdate time.Time
+}
+
+func (rec *fakeRecord) expired(now time.Time) bool {
+	return !rec.voidTime.IsZero() && !now.Before(rec.voidTime)
+}
+
+// expiration returns the remaining TTL of the record in seconds.
+func (rec *fakeRecord) expiration(now time.Time) uint32 {
+	if rec.voidTime.IsZero() {
+		return as.TTLDontExpire
+	}
+	return uint32(rec.voidTime.Sub(now) / time.Second)
+}
+
+// NewFakeClient returns an empty, connected fake client.
+func NewFakeClient() *FakeClient {
+	return &FakeClient{records: map[fakeKey]*fakeRecord{}}
+}
+
+var errFakeUnsupported = NewAerospikeError(UNSUPPORTED_FEATURE, "Command is not supported by FakeClient")
+
+func keyOf(key *as.Key) fakeKey {
+	return fakeKey{namespace: key.Namespace(), digest: string(key.Digest())}
+}
+
+func writePolicy(policy *as.WritePolicy) *as.WritePolicy {
+	if policy == nil {
+		return as.NewWritePolicy(0, 0)
+	}
+	return policy
+}
+
+// lookup returns the record if it exists and has not expired. Expired records are removed.
+// The caller must hold the write lock.
+func (fc *FakeClient) lookup(key *as.Key, now time.Time) *fakeRecord {
+	k := keyOf(key)
+	rec := fc.records[k]
+	if rec != nil && rec.expired(now) {
+		delete(fc.records, k)
+		return nil
+	}
+	return rec
+}
+
+// record returns the client side view of the stored record, with the bins selected by binNames.
+// All bins are returned if binNames is nil.
+func (rec *fakeRecord) record(key *as.Key, now time.Time, binNames []string, header bool) *as.Record {
+	bins := as.BinMap{}
+	if !header {
+		if binNames == nil {
+			for name, value := range rec.bins {
+				bins[name] = normalize(value)
+			}
+		} else {
+			for _, name := range binNames {
+				if value, exists := rec.bins[name]; exists {
+					bins[name] = normalize(value)
+				}
+			}
+		}
+	}
+
+	return &as.Record{
+		Key:        key,
+		Bins:       bins,
+		Generation: rec.generation,
+		Expiration: rec.expiration(now),
+	}
+}
+
+// get reads a record. It returns nil if the record does not exist.
+func (fc *FakeClient) get(key *as.Key, binNames []string, header bool) *as.Record {
+	fc.mutex.Lock()
+	defer fc.mutex.Unlock()
+
+	now := time.Now()
+	rec := fc.lookup(key, now)
+	if rec == nil {
+		return nil
+	}
+	return rec.record(key, now, binNames, header)
+}
+
+// Close marks the client as disconnected.
+func (fc *FakeClient) Close() {
+	fc.mutex.Lock()
+	fc.closed = true
+	fc.mutex.Unlock()
+}
+
+// IsConnected returns true until the client is closed.
+func (fc *FakeClient) IsConnected() bool {
+	fc.mutex.RLock()
+	defer fc.mutex.RUnlock()
+	return !fc.closed
+}
+
+// GetNodes returns no nodes.
+func (fc *FakeClient) GetNodes() []*as.Node {
+	return nil
+}
+
+// GetNodeNames returns no node names.
+func (fc *FakeClient) GetNodeNames() []string {
+	return []string{}
+}
+
+// Stats returns empty statistics.
+func (fc *FakeClient) Stats() (map[string]interface{}, error) {
+	return map[string]interface{}{}, nil
+}
+
+// StatsSnapshot returns empty statistics.
+func (fc *FakeClient) StatsSnapshot() *as.ClientStats {
+	return &as.ClientStats{Nodes: map[string]*as.NodeStats{}, Cluster: &as.NodeStats{}}
+}
+
+// String implements the Stringer interface.
+func (fc *FakeClient) String() string {
+	return "FakeClient"
+}
+
+// Exists determines if a record key exists.
+func (fc *FakeClient) Exists(policy *as.BasePolicy, key *as.Key) (bool, error) {
+	return fc.get(key, nil, true) != nil, nil
+}
+
+// Get reads a record and the specified bins, or all bins if none are specified.
+// It returns nil if the record does not exist.
+func (fc *FakeClient) Get(policy *as.BasePolicy, key *as.Key, binNames ...string) (*as.Record, error) {
+	if len(binNames) == 0 {
+		binNames = nil
+	}
+	return fc.get(key, binNames, false), nil
+}
+
+// GetHeader reads the generation and expiration of a record.
+// It returns nil if the record does not exist.
+func (fc *FakeClient) GetHeader(policy *as.BasePolicy, key *as.Key) (*as.Record, error) {
+	return fc.get(key, nil, true), nil
+}
+
+// Put writes the bins of the map to a record.
+func (fc *FakeClient) Put(policy *as.WritePolicy, key *as.Key, binMap as.BinMap) error {
+	return fc.writeMap(policy, key, binMap, as.PutOp)
+}
+
+// PutBins writes the bins to a record.
+func (fc *FakeClient) PutBins(policy *as.WritePolicy, key *as.Key, bins ...*as.Bin) error {
+	return fc.writeBins(policy, key, bins, as.PutOp)
+}
+
+// Append appends the string or blob values of the map to the bins of a record.
+func (fc *FakeClient) Append(policy *as.WritePolicy, key *as.Key, binMap as.BinMap) error {
+	return fc.writeMap(policy, key, binMap, as.AppendOp)
+}
+
+// AppendBins appends the string or blob values to the bins of a record.
+func (fc *FakeClient) AppendBins(policy *as.WritePolicy, key *as.Key, bins ...*as.Bin) error {
+	return fc.writeBins(policy, key, bins, as.AppendOp)
+}
+
+// Prepend prepends the string or blob values of the map to the bins of a record.
+func (fc *FakeClient) Prepend(policy *as.WritePolicy, key *as.Key, binMap as.BinMap) error {
+	return fc.writeMap(policy, key, binMap, as.PrependOp)
+}
+
+// PrependBins prepends the string or blob values to the bins of a record.
+func (fc *FakeClient) PrependBins(policy *as.WritePolicy, key *as.Key, bins ...*as.Bin) error {
+	return fc.writeBins(policy, key, bins, as.PrependOp)
+}
+
+// Add adds the numeric values of the map to the bins of a record.
+func (fc *FakeClient) Add(policy *as.WritePolicy, key *as.Key, binMap as.BinMap) error {
+	return fc.writeMap(policy, key, binMap, as.AddOp)
+}
+
+// AddBins adds the numeric values to the bins of a record.
+func (fc *FakeClient) AddBins(policy *as.WritePolicy, key *as.Key, bins ...*as.Bin) error {
+	return fc.writeBins(policy, key, bins, as.AddOp)
+}
+
+func (fc *FakeClient) writeMap(policy *as.WritePolicy, key *as.Key, binMap as.BinMap, op func(*as.Bin) *as.Operation) error {
+	ops := make([]*as.Operation, 0, len(binMap))
+	for name, value := range binMap {
+		ops = append(ops, op(as.NewBin(name, value)))
+	}
+	_, err := fc.operate(policy, key, ops)
+	return err
+}
+
+func (fc *FakeClient) writeBins(policy *as.WritePolicy, key *as.Key, bins []*as.Bin, op func(*as.Bin) *as.Operation) error {
+	ops := make([]*as.Operation, len(bins))
+	for i, bin := range bins {
+		ops[i] = op(bin)
+	}
+	_, err := fc.operate(policy, key, ops)
+	return err
+}
+
+// Delete deletes a record. It returns true if the record existed.
+func (fc *FakeClient) Delete(policy *as.WritePolicy, key *as.Key) (bool, error) {
+	policy = writePolicy(policy)
+
+	fc.mutex.Lock()
+	defer fc.mutex.Unlock()
+
+	rec := fc.lookup(key, time.Now())
+	if rec == nil {
+		return false, nil
+	}
+	if err := checkFakeGeneration(policy, rec); err != nil {
+		return false, err
+	}
+	delete(fc.records, keyOf(key))
+	return true, nil
+}
+
+// Touch resets the expiration of a record and increments its generation.
+func (fc *FakeClient) Touch(policy *as.WritePolicy, key *as.Key) error {
+	_, err := fc.operate(policy, key, []*as.Operation{as.TouchOp()})
+	return err
+}
+
+// Operate applies the operations to a record, in order, and returns the bins read.
+// CDT operations are not supported.
+func (fc *FakeClient) Operate(policy *as.WritePolicy, key *as.Key, operations ...*as.Operation) (*as.Record, error) {
+	if len(operations) == 0 {
+		return nil, NewAerospikeError(PARAMETER_ERROR, "No operations were passed.")
+	}
+	return fc.operate(policy, key, operations)
+}
+
+func checkFakeGeneration(policy *as.WritePolicy, rec *fakeRecord) error {
+	switch policy.GenerationPolicy {
+	case as.EXPECT_GEN_EQUAL:
+		if policy.Generation != rec.generation {
+			return NewAerospikeError(GENERATION_ERROR)
+		}
+	case as.EXPECT_GEN_GT:
+		if policy.Generation <= rec.generation {
+			return NewAerospikeError(GENERATION_ERROR)
+		}
+	}
+	return nil
+}
+
+func (fc *FakeClient) operate(policy *as.WritePolicy, key *as.Key, operations []*as.Operation) (*as.Record, error) {
+	policy = writePolicy(policy)
+
+	fc.mutex.Lock()
+	defer fc.mutex.Unlock()
+
+	now := time.Now()
+	rec := fc.lookup(key, now)
+
+	hasWrite, readAll, readHeader := false, false, false
+	var binNames []string
+	for _, op := range operations {
+		switch op.Type() {
+		case as.READ:
+			if op.HeaderOnly() {
+				readHeader = true
+			} else if op.BinName() == "" {
+				readAll = true
+			}
+		case as.WRITE, as.ADD, as.APPEND, as.PREPEND, as.TOUCH:
+			hasWrite = true
+		default:
+			return nil, errFakeUnsupported
+		}
+	}
+
+	if !hasWrite {
+		if rec == nil {
+			return nil, nil
+		}
+		for _, op := range operations {
+			if op.BinName() != "" {
+				binNames = append(binNames, op.BinName())
+			}
+		}
+		if readAll {
+			binNames = nil
+		}
+		return rec.record(key, now, binNames, readHeader && !readAll && len(binNames) == 0), nil
+	}
+
+	exists := rec != nil
+	if exists {
+		if policy.RecordExistsAction == as.CREATE_ONLY {
+			return nil, NewAerospikeError(KEY_EXISTS_ERROR)
+		}
+		if err := checkFakeGeneration(policy, rec); err != nil {
+			return nil, err
+		}
+	} else if policy.RecordExistsAction == as.UPDATE_ONLY || policy.RecordExistsAction == as.REPLACE_ONLY {
+		return nil, NewAerospikeError(KEY_NOT_FOUND_ERROR)
+	}
+
+	// apply the operations to a copy, so that the record is not changed on failure
+	res := &fakeRecord{setName: key.SetName(), bins: as.BinMap{}}
+	if exists {
+		*res = *rec
+		res.bins = make(as.BinMap, len(rec.bins))
+		if policy.RecordExistsAction != as.REPLACE && policy.RecordExistsAction != as.REPLACE_ONLY {
+			for name, value := range rec.bins {
+				res.bins[name] = value
+			}
+		}
+	}
+
+	read := as.BinMap{}
+	for _, op := range operations {
+		name := op.BinName()
+		if len(name) > 14 {
+			return nil, NewAerospikeError(BIN_NAME_TOO_LONG)
+		}
+
+		var value interface{}
+		if op.BinValue() != nil {
+			value = normalize(op.BinValue())
+		}
+
+		switch op.Type() {
+		case as.READ:
+			if v, exists := res.bins[name]; exists && name != "" {
+				read[name] = normalize(v)
+			}
+		case as.WRITE:
+			if value == nil {
+				delete(res.bins, name)
+			} else {
+				res.bins[name] = value
+			}
+		case as.ADD:
+			sum, err := fakeAdd(res.bins[name], value)
+			if err != nil {
+				return nil, err
+			}
+			res.bins[name] = sum
+		case as.APPEND, as.PREPEND:
+			v, err := fakeConcat(res.bins[name], value, op.Type() == as.APPEND)
+			if err != nil {
+				return nil, err
+			}
+			res.bins[name] = v
+		case as.TOUCH:
+			if !exists {
+				return nil, NewAerospikeError(KEY_NOT_FOUND_ERROR)
+			}
+		}
+	}
+
+	if len(res.bins) == 0 {
+		delete(fc.records, keyOf(key))
+		return &as.Record{Key: key, Bins: read}, nil
+	}
+
+	res.generation++
+	res.lastUpdate = now
+	switch policy.Expiration {
+	case as.TTLServerDefault, as.TTLDontExpire:
+		res.voidTime = time.Time{}
+	case as.TTLDontUpdate:
+	default:
+		res.voidTime = now.Add(time.Duration(policy.Expiration) * time.Second)
+	}
+	fc.records[keyOf(key)] = res
+
+	if readAll {
+		for name, value := range res.bins {
+			read[name] = normalize(value)
+		}
+	}
+	return &as.Record{Key: key, Bins: read, Generation: res.generation, Expiration: res.expiration(now)}, nil
+}
+
+// fakeAdd adds a number to a bin value, which is nil if the bin does not exist.
+func fakeAdd(current, value interface{}) (interface{}, error) {
+	switch v := value.(type) {
+	case int:
+		if current == nil {
+			return v, nil
+		}
+		if c, ok := current.(int); ok {
+			return c + v, nil
+		}
+	case float64:
+		if current == nil {
+			return v, nil
+		}
+		if c, ok := current.(float64); ok {
+			return c + v, nil
+		}
+	}
+	return nil, NewAerospikeError(BIN_TYPE_ERROR)
+}
+
+// fakeConcat appends or prepends a string or blob to a bin value, which is nil if the bin does not exist.
+func fakeConcat(current, value interface{}, appnd bool) (interface{}, error) {
+	switch v := value.(type) {
+	case string:
+		if current == nil {
+			return v, nil
+		}
+		if c, ok := current.(string); ok {
+			if appnd {
+				return c + v, nil
+			}
+			return v + c, nil
+		}
+	case []byte:
+		if current == nil {
+			return v, nil
+		}
+		if c, ok := current.([]byte); ok {
+			if appnd {
+				return append(append([]byte{}, c...), v...), nil
+			}
+			return append(append([]byte{}, v...), c...), nil
+		}
+	}
+	return nil, NewAerospikeError(BIN_TYPE_ERROR)
+}
+
+// Truncate removes the records of the namespace, or of the set if set is not empty.
+// If beforeLastUpdate is not nil, only the records last updated before it are removed.
+func (fc *FakeClient) Truncate(policy *as.WritePolicy, namespace, set string, beforeLastUpdate *time.Time) error {
+	fc.mutex.Lock()
+	defer fc.mutex.Unlock()
+
+	for k, rec := range fc.records {
+		if k.namespace != namespace || (set != "" && rec.setName != set) {
+			continue
+		}
+		if beforeLastUpdate != nil && !rec.lastUpdate.Before(*beforeLastUpdate) {
+			continue
+		}
+		delete(fc.records, k)
+	}
+	return nil
+}
+
+// BatchExists determines if the records exist.
+func (fc *FakeClient) BatchExists(policy *as.BatchPolicy, keys []*as.Key) ([]bool, error) {
+	res := make([]bool, len(keys))
+	for i, key := range keys {
+		res[i] = fc.get(key, nil, true) != nil
+	}
+	return res, nil
+}
+
+// BatchGet reads the records and the specified bins, or all bins if none are specified.
+// The records that do not exist are nil.
+func (fc *FakeClient) BatchGet(policy *as.BatchPolicy, keys []*as.Key, binNames ...string) ([]*as.Record, error) {
+	if len(binNames) == 0 {
+		binNames = nil
+	}
+
+	res := make([]*as.Record, len(keys))
+	for i, key := range keys {
+		res[i] = fc.get(key, binNames, false)
+	}
+	return res, nil
+}
+
+// BatchGetComplex reads the records, setting the Record field of each BatchRead.
+func (fc *FakeClient) BatchGetComplex(policy *as.BatchPolicy, records []*as.BatchRead) error {
+	for _, br := range records {
+		switch {
+		case br.ReadAllBins:
+			br.Record = fc.get(br.Key, nil, false)
+		case len(br.BinNames) > 0:
+			br.Record = fc.get(br.Key, br.BinNames, false)
+		default:
+			br.Record = fc.get(br.Key, nil, true)
+		}
+	}
+	return nil
+}
+
+// BatchGetHeader reads the generation and expiration of the records.
+// The records that do not exist are nil.
+func (fc *FakeClient) BatchGetHeader(policy *as.BatchPolicy, keys []*as.Key) ([]*as.Record, error) {
+	res := make([]*as.Record, len(keys))
+	for i, key := range keys {
+		res[i] = fc.get(key, nil, true)
+	}
+	return res, nil
+}
+
+// BatchOperate applies the records in order, setting the result of each record.
+// BatchUDF records fail with UNSUPPORTED_FEATURE.
+func (fc *FakeClient) BatchOperate(policy *as.BatchPolicy, records []as.BatchRecordIfc) error {
+	for _, record := range records {
+		var err error
+		resultCode := OK
+
+		switch r := record.(type) {
+		case *as.BatchWrite:
+			r.Record, err = fc.Operate(r.Policy, r.Key, r.Operations...)
+		case *as.BatchDelete:
+			var existed bool
+			if existed, err = fc.Delete(r.Policy, r.Key); err == nil && !existed {
+				resultCode = KEY_NOT_FOUND_ERROR
+			}
+		default:
+			err = errFakeUnsupported
+		}
+
+		if aerr, ok := err.(AerospikeError); ok {
+			resultCode = aerr.ResultCode()
+		}
+
+		br := record.BatchRec()
+		br.ResultCode = resultCode
+		br.Err = err
+		br.InDoubt = false
+	}
+	return nil
+}
+
+// ScanAll is not supported.
+func (fc *FakeClient) ScanAll(policy *as.ScanPolicy, namespace string, setName string, binNames ...string) (*as.Recordset, error) {
+	return nil, errFakeUnsupported
+}
+
+// ScanNode is not supported.
+func (fc *FakeClient) ScanNode(policy *as.ScanPolicy, node *as.Node, namespace string, setName string, binNames ...string) (*as.Recordset, error) {
+	return nil, errFakeUnsupported
+}
+
+// ScanPartitions is not supported.
+func (fc *FakeClient) ScanPartitions(policy *as.ScanPolicy, filter *as.PartitionFilter, namespace string, setName string, binNames ...string) (*as.Recordset, error) {
+	return nil, errFakeUnsupported
+}
+
+// ScanPage is not supported.
+func (fc *FakeClient) ScanPage(policy *as.ScanPolicy, token string, pageSize int, namespace string, setName string, binNames ...string) (*as.RecordPage, error) {
+	return nil, errFakeUnsupported
+}
+
+// Query is not supported.
+func (fc *FakeClient) Query(policy *as.QueryPolicy, statement *as.Statement) (*as.Recordset, error) {
+	return nil, errFakeUnsupported
+}
+
+// QueryNode is not supported.
+func (fc *FakeClient) QueryNode(policy *as.QueryPolicy, node *as.Node, statement *as.Statement) (*as.Recordset, error) {
+	return nil, errFakeUnsupported
+}
+
+// QueryPartitions is not supported.
+func (fc *FakeClient) QueryPartitions(policy *as.QueryPolicy, statement *as.Statement, filter *as.PartitionFilter) (*as.Recordset, error) {
+	return nil, errFakeUnsupported
+}
+
+// QueryPage is not supported.
+func (fc *FakeClient) QueryPage(policy *as.QueryPolicy, statement *as.Statement, token string, pageSize int) (*as.RecordPage, error) {
+	return nil, errFakeUnsupported
+}
+
+// QueryAggregate is not supported.
+func (fc *FakeClient) QueryAggregate(policy *as.QueryPolicy, statement *as.Statement, packageName, functionName string, functionArgs ...interface{}) (*as.Recordset, error) {
+	return nil, errFakeUnsupported
+}
+
+// Aggregate is not supported. Use Aggregation.Apply to test aggregation pipelines without a server.
+func (fc *FakeClient) Aggregate(policy *as.QueryPolicy, statement *as.Statement, aggregation *as.Aggregation) (interface{}, error) {
+	return nil, errFakeUnsupported
+}
+
+// CreateIndex is not supported.
+func (fc *FakeClient) CreateIndex(policy *as.WritePolicy, namespace string, setName string, indexName string, binName string, indexType as.IndexType) (*as.IndexTask, error) {
+	return nil, errFakeUnsupported
+}
+
+// CreateComplexIndex is not supported.
+func (fc *FakeClient) CreateComplexIndex(policy *as.WritePolicy, namespace string, setName string, indexName string, binName string, indexType as.IndexType, indexCollectionType as.IndexCollectionType) (*as.IndexTask, error) {
+	return nil, errFakeUnsupported
+}
+
+// DropIndex is not supported.
+func (fc *FakeClient) DropIndex(policy *as.WritePolicy, namespace string, setName string, indexName string) error {
+	return errFakeUnsupported
+}
+
+// ListIndexes is not supported.
+func (fc *FakeClient) ListIndexes(policy *as.InfoPolicy, namespace string) ([]*as.IndexInfo, error) {
+	return nil, errFakeUnsupported
+}
+
+// GetIndexStats is not supported.
+func (fc *FakeClient) GetIndexStats(policy *as.InfoPolicy, namespace string, indexName string) (*as.IndexStats, error) {
+	return nil, errFakeUnsupported
+}
+
+// EnsureIndex is not supported.
+func (fc *FakeClient) EnsureIndex(policy *as.WritePolicy, index *as.IndexDefinition) (*as.IndexTask, error) {
+	return nil, errFakeUnsupported
+}
+
+// MigrateIndexes is not supported.
+func (fc *FakeClient) MigrateIndexes(policy *as.WritePolicy, migration *as.IndexMigration) ([]*as.IndexChange, error) {
+	return nil, errFakeUnsupported
+}
+
+// GetClusterInfo is not supported.
+func (fc *FakeClient) GetClusterInfo(policy *as.InfoPolicy) (*as.ClusterInfo, error) {
+	return nil, errFakeUnsupported
+}
+
+// ListNamespaces is not supported.
+func (fc *FakeClient) ListNamespaces(policy *as.InfoPolicy) ([]string, error) {
+	return nil, errFakeUnsupported
+}
+
+// GetNamespaceInfo is not supported.
+func (fc *FakeClient) GetNamespaceInfo(policy *as.InfoPolicy, namespace string) (*as.NamespaceInfo, error) {
+	return nil, errFakeUnsupported
+}
+
+// ListSets is not supported.
+func (fc *FakeClient) ListSets(policy *as.InfoPolicy, namespace string) ([]*as.SetInfo, error) {
+	return nil, errFakeUnsupported
+}
+
+// ListBins is not supported.
+func (fc *FakeClient) ListBins(policy *as.InfoPolicy, namespace string) ([]string, error) {
+	return nil, errFakeUnsupported
+}
+
+// RegisterUDFFromFile is not supported.
+func (fc *FakeClient) RegisterUDFFromFile(policy *as.WritePolicy, clientPath string, serverPath string, language as.Language) (*as.RegisterTask, error) {
+	return nil, errFakeUnsupported
+}
+
+// RegisterUDF is not supported.
+func (fc *FakeClient) RegisterUDF(policy *as.WritePolicy, udfBody []byte, serverPath string, language as.Language) (*as.RegisterTask, error) {
+	return nil, errFakeUnsupported
+}
+
+// RemoveUDF is not supported.
+func (fc *FakeClient) RemoveUDF(policy *as.WritePolicy, udfName string) (*as.RemoveTask, error) {
+	return nil, errFakeUnsupported
+}
+
+// ListUDF is not supported.
+func (fc *FakeClient) ListUDF(policy *as.BasePolicy) ([]*as.UDF, error) {
+	return nil, errFakeUnsupported
+}
+
+// Execute is not supported.
+func (fc *FakeClient) Execute(policy *as.WritePolicy, key *as.Key, packageName string, functionName string, args ...as.Value) (interface{}, error) {
+	return nil, errFakeUnsupported
+}
+
+// ExecuteUDF is not supported.
+func (fc *FakeClient) ExecuteUDF(policy *as.QueryPolicy, statement *as.Statement, packageName string, functionName string, functionArgs ...as.Value) (*as.ExecuteTask, error) {
+	return nil, errFakeUnsupported
+}
+
+// ExecuteUDFNode is not supported.
+func (fc *FakeClient) ExecuteUDFNode(policy *as.QueryPolicy, node *as.Node, statement *as.Statement, packageName string, functionName string, functionArgs ...as.Value) (*as.ExecuteTask, error) {
+	return nil, errFakeUnsupported
+}
+
+// CreateUser is not supported.
+func (fc *FakeClient) CreateUser(policy *as.AdminPolicy, user string, password string, roles []string) error {
+	return errFakeUnsupported
+}
+
+// DropUser is not supported.
+func (fc *FakeClient) DropUser(policy *as.AdminPolicy, user string) error {
+	return errFakeUnsupported
+}
+
+// ChangePassword is not supported.
+func (fc *FakeClient) ChangePassword(policy *as.AdminPolicy, user string, password string) error {
+	return errFakeUnsupported
+}
+
+// GrantRoles is not supported.
+func (fc *FakeClient) GrantRoles(policy *as.AdminPolicy, user string, roles []string) error {
+	return errFakeUnsupported
+}
+
+// RevokeRoles is not supported.
+func (fc *FakeClient) RevokeRoles(policy *as.AdminPolicy, user string, roles []string) error {
+	return errFakeUnsupported
+}
+
+// QueryUser is not supported.
+func (fc *FakeClient) QueryUser(policy *as.AdminPolicy, user string) (*as.UserRoles, error) {
+	return nil, errFakeUnsupported
+}
+
+// QueryUsers is not supported.
+func (fc *FakeClient) QueryUsers(policy *as.AdminPolicy) ([]*as.UserRoles, error) {
+	return nil, errFakeUnsupported
+}
+
+// QueryRole is not supported.
+func (fc *FakeClient) QueryRole(policy *as.AdminPolicy, role string) (*as.Role, error) {
+	return nil, errFakeUnsupported
+}
+
+// QueryRoles is not supported.
+func (fc *FakeClient) QueryRoles(policy *as.AdminPolicy) ([]*as.Role, error) {
+	return nil, errFakeUnsupported
+}
+
+// CreateRole is not supported.
+func (fc *FakeClient) CreateRole(policy *as.AdminPolicy, roleName string, privileges []as.Privilege) error {
+	return errFakeUnsupported
+}
+
+// DropRole is not supported.
+func (fc *FakeClient) DropRole(policy *as.AdminPolicy, roleName string) error {
+	return errFakeUnsupported
+}
+
+// GrantPrivileges is not supported.
+func (fc *FakeClient) GrantPrivileges(policy *as.AdminPolicy, roleName string, privileges []as.Privilege) error {
+	return errFakeUnsupported
+}
+
+// RevokePrivileges is not supported.
+func (fc *FakeClient) RevokePrivileges(policy *as.AdminPolicy, roleName string, privileges []as.Privilege) error {
+	return errFakeUnsupported
+}
+
+// normalize converts a value to the type the client decodes it to when it is read from
+// the server. Lists and maps are copied.
+func normalize(value interface{}) interface{} {
+	if v, ok := value.(as.Value); ok {
+		value = v.GetObject()
+	}
+
+	switch v := value.(type) {
+	case nil:
+		return nil
+	case string, bool:
+		return v
+	case []byte:
+		return append([]byte{}, v...)
+	case float32:
+		return float64(v)
+	case float64:
+		return v
+	}
+
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return int(rv.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		u := rv.Uint()
+		if u > math.MaxInt64 {
+			return u
+		}
+		return int(u)
+	case reflect.Float32, reflect.Float64:
+		return rv.Float()
+	case reflect.String:
+		return rv.String()
+	case reflect.Slice, reflect.Array:
+		if rv.Type().Elem().Kind() == reflect.Uint8 && rv.Kind() == reflect.Slice {
+			return append([]byte{}, rv.Bytes()...)
+		}
+		res := make([]interface{}, rv.Len())
+		for i := range res {
+			res[i] = normalize(rv.Index(i).Interface())
+		}
+		return res
+	case reflect.Map:
+		res := make(map[interface{}]interface{}, rv.Len())
+		for _, k := range rv.MapKeys() {
+			res[normalize(k.Interface())] = normalize(rv.MapIndex(k).Interface())
+		}
+		return res
+	}
+	return value
+}
+
+// FakeClient implements all the interfaces of the client.
+var _ as.ClientIfc = &FakeClient{}
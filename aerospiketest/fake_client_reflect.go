@@ -0,0 +1,56 @@
+// +build !as_performance
+
+// Copyright 2013-2017 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aerospiketest
+
+import (
+	as "github.com/aerospike/aerospike-client-go"
+)
+
+// PutObject is not supported.
+func (fc *FakeClient) PutObject(policy *as.WritePolicy, key *as.Key, obj interface{}) error {
+	return errFakeUnsupported
+}
+
+// GetObject is not supported.
+func (fc *FakeClient) GetObject(policy *as.BasePolicy, key *as.Key, obj interface{}) error {
+	return errFakeUnsupported
+}
+
+// BatchGetObjects is not supported.
+func (fc *FakeClient) BatchGetObjects(policy *as.BatchPolicy, keys []*as.Key, objects []interface{}) ([]bool, error) {
+	return nil, errFakeUnsupported
+}
+
+// ScanAllObjects is not supported.
+func (fc *FakeClient) ScanAllObjects(policy *as.ScanPolicy, objChan interface{}, namespace string, setName string, binNames ...string) (*as.Recordset, error) {
+	return nil, errFakeUnsupported
+}
+
+// ScanNodeObjects is not supported.
+func (fc *FakeClient) ScanNodeObjects(policy *as.ScanPolicy, node *as.Node, objChan interface{}, namespace string, setName string, binNames ...string) (*as.Recordset, error) {
+	return nil, errFakeUnsupported
+}
+
+// QueryObjects is not supported.
+func (fc *FakeClient) QueryObjects(policy *as.QueryPolicy, statement *as.Statement, objChan interface{}) (*as.Recordset, error) {
+	return nil, errFakeUnsupported
+}
+
+// QueryNodeObjects is not supported.
+func (fc *FakeClient) QueryNodeObjects(policy *as.QueryPolicy, node *as.Node, statement *as.Statement, objChan interface{}) (*as.Recordset, error) {
+	return nil, errFakeUnsupported
+}
@@ -0,0 +1,263 @@
+// Copyright 2013-2017 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aerospiketest_test
+
+import (
+	"time"
+
+	as "github.com/aerospike/aerospike-client-go"
+	"github.com/aerospike/aerospike-client-go/aerospiketest"
+	. "github.com/aerospike/aerospike-client-go/types"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Fake Client", func() {
+
+	var client as.ClientIfc
+	var key *as.Key
+
+	BeforeEach(func() {
+		client = aerospiketest.NewFakeClient()
+
+		var err error
+		key, err = as.NewKey("test", "fake", 1)
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	resultCode := func(err error) ResultCode {
+		Expect(err).To(HaveOccurred())
+		return err.(AerospikeError).ResultCode()
+	}
+
+	It("must put, get and delete records", func() {
+		Expect(client.Put(nil, key, as.BinMap{
+			"i":    int64(1),
+			"f":    float32(1.5),
+			"s":    "a",
+			"list": []int{1, 2},
+			"map":  map[string]interface{}{"a": 1},
+		})).ToNot(HaveOccurred())
+
+		rec, err := client.Get(nil, key)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(rec.Key).To(Equal(key))
+		Expect(rec.Generation).To(Equal(uint32(1)))
+		Expect(rec.Expiration).To(Equal(uint32(as.TTLDontExpire)))
+		Expect(rec.Bins).To(Equal(as.BinMap{
+			"i":    1,
+			"f":    1.5,
+			"s":    "a",
+			"list": []interface{}{1, 2},
+			"map":  map[interface{}]interface{}{"a": 1},
+		}))
+
+		rec, err = client.Get(nil, key, "s", "missing")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(rec.Bins).To(Equal(as.BinMap{"s": "a"}))
+
+		existed, err := client.Delete(nil, key)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(existed).To(BeTrue())
+
+		rec, err = client.Get(nil, key)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(rec).To(BeNil())
+
+		exists, err := client.Exists(nil, key)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(exists).To(BeFalse())
+	})
+
+	It("must not share values with the caller", func() {
+		list := []interface{}{1}
+		Expect(client.PutBins(nil, key, as.NewBin("list", list))).ToNot(HaveOccurred())
+		list[0] = 2
+
+		rec, err := client.Get(nil, key)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(rec.Bins["list"]).To(Equal([]interface{}{1}))
+	})
+
+	It("must add, append and prepend", func() {
+		Expect(client.Put(nil, key, as.BinMap{"i": 1, "s": "b", "b": []byte{2}})).ToNot(HaveOccurred())
+		Expect(client.Add(nil, key, as.BinMap{"i": 2, "new": 5})).ToNot(HaveOccurred())
+		Expect(client.AppendBins(nil, key, as.NewBin("s", "c"), as.NewBin("b", []byte{3}))).ToNot(HaveOccurred())
+		Expect(client.PrependBins(nil, key, as.NewBin("s", "a"))).ToNot(HaveOccurred())
+
+		rec, err := client.Get(nil, key)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(rec.Generation).To(Equal(uint32(4)))
+		Expect(rec.Bins).To(Equal(as.BinMap{"i": 3, "new": 5, "s": "abc", "b": []byte{2, 3}}))
+
+		Expect(resultCode(client.AddBins(nil, key, as.NewBin("s", 1)))).To(Equal(BIN_TYPE_ERROR))
+		Expect(resultCode(client.AppendBins(nil, key, as.NewBin("i", "x")))).To(Equal(BIN_TYPE_ERROR))
+	})
+
+	It("must operate on records", func() {
+		Expect(client.PutBins(nil, key, as.NewBin("i", 1), as.NewBin("s", "a"))).ToNot(HaveOccurred())
+
+		rec, err := client.Operate(nil, key,
+			as.AddOp(as.NewBin("i", 1)),
+			as.GetOpForBin("i"),
+			as.PutOp(as.NewBin("s", nil)),
+		)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(rec.Generation).To(Equal(uint32(2)))
+		Expect(rec.Bins).To(Equal(as.BinMap{"i": 2}))
+
+		rec, err = client.Operate(nil, key, as.PutOp(as.NewBin("j", 1)), as.GetOp())
+		Expect(err).ToNot(HaveOccurred())
+		Expect(rec.Bins).To(Equal(as.BinMap{"i": 2, "j": 1}))
+
+		rec, err = client.Operate(nil, key, as.GetHeaderOp())
+		Expect(err).ToNot(HaveOccurred())
+		Expect(rec.Generation).To(Equal(uint32(3)))
+		Expect(rec.Bins).To(BeEmpty())
+
+		_, err = client.Operate(nil, key, as.ListAppendOp("l", 1))
+		Expect(resultCode(err)).To(Equal(UNSUPPORTED_FEATURE))
+
+		// failed operations do not change the record
+		_, err = client.Operate(nil, key, as.PutOp(as.NewBin("k", 1)), as.AppendOp(as.NewBin("i", "x")))
+		Expect(resultCode(err)).To(Equal(BIN_TYPE_ERROR))
+		rec, err = client.Get(nil, key)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(rec.Bins).ToNot(HaveKey("k"))
+	})
+
+	It("must enforce the write policy", func() {
+		policy := as.NewWritePolicy(0, 0)
+		policy.RecordExistsAction = as.UPDATE_ONLY
+		Expect(resultCode(client.PutBins(policy, key, as.NewBin("a", 1)))).To(Equal(KEY_NOT_FOUND_ERROR))
+		Expect(resultCode(client.Touch(nil, key))).To(Equal(KEY_NOT_FOUND_ERROR))
+
+		policy.RecordExistsAction = as.CREATE_ONLY
+		Expect(client.PutBins(policy, key, as.NewBin("a", 1))).ToNot(HaveOccurred())
+		Expect(resultCode(client.PutBins(policy, key, as.NewBin("a", 1)))).To(Equal(KEY_EXISTS_ERROR))
+
+		policy.RecordExistsAction = as.REPLACE_ONLY
+		Expect(client.PutBins(policy, key, as.NewBin("b", 1))).ToNot(HaveOccurred())
+		rec, err := client.Get(nil, key)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(rec.Bins).To(Equal(as.BinMap{"b": 1}))
+
+		policy = as.NewWritePolicy(1, 0)
+		policy.GenerationPolicy = as.EXPECT_GEN_EQUAL
+		Expect(resultCode(client.PutBins(policy, key, as.NewBin("b", 2)))).To(Equal(GENERATION_ERROR))
+		_, err = client.Delete(policy, key)
+		Expect(resultCode(err)).To(Equal(GENERATION_ERROR))
+
+		policy.Generation = 2
+		Expect(client.PutBins(policy, key, as.NewBin("b", 2))).ToNot(HaveOccurred())
+
+		Expect(resultCode(client.PutBins(nil, key, as.NewBin("a_very_long_bin_name", 1)))).To(Equal(BIN_NAME_TOO_LONG))
+	})
+
+	It("must expire records", func() {
+		Expect(client.PutBins(as.NewWritePolicy(0, 100), key, as.NewBin("a", 1))).ToNot(HaveOccurred())
+
+		rec, err := client.GetHeader(nil, key)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(rec.Expiration).To(BeNumerically("~", 100, 1))
+
+		Expect(client.Touch(as.NewWritePolicy(0, as.TTLDontUpdate), key)).ToNot(HaveOccurred())
+		rec, err = client.GetHeader(nil, key)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(rec.Generation).To(Equal(uint32(2)))
+		Expect(rec.Expiration).To(BeNumerically("~", 100, 1))
+
+		Expect(client.Touch(as.NewWritePolicy(0, 1), key)).ToNot(HaveOccurred())
+		Eventually(func() bool {
+			exists, _ := client.Exists(nil, key)
+			return exists
+		}, 2*time.Second, 100*time.Millisecond).Should(BeFalse())
+	})
+
+	It("must batch read records", func() {
+		keys := make([]*as.Key, 3)
+		for i := range keys {
+			keys[i], _ = as.NewKey("test", "fake", i)
+		}
+		Expect(client.PutBins(nil, keys[0], as.NewBin("a", 0), as.NewBin("b", 0))).ToNot(HaveOccurred())
+		Expect(client.PutBins(nil, keys[2], as.NewBin("a", 2))).ToNot(HaveOccurred())
+
+		records, err := client.BatchGet(nil, keys, "a")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(records[0].Bins).To(Equal(as.BinMap{"a": 0}))
+		Expect(records[1]).To(BeNil())
+		Expect(records[2].Bins).To(Equal(as.BinMap{"a": 2}))
+
+		exists, err := client.BatchExists(nil, keys)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(exists).To(Equal([]bool{true, false, true}))
+
+		headers, err := client.BatchGetHeader(nil, keys)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(headers[0].Bins).To(BeEmpty())
+		Expect(headers[0].Generation).To(Equal(uint32(1)))
+
+		reads := []*as.BatchRead{as.NewBatchRead(keys[0], nil), as.NewBatchRead(keys[0], []string{"b"}), as.NewBatchReadHeader(keys[1])}
+		Expect(client.BatchGetComplex(nil, reads)).ToNot(HaveOccurred())
+		Expect(reads[0].Record.Bins).To(Equal(as.BinMap{"a": 0, "b": 0}))
+		Expect(reads[1].Record.Bins).To(Equal(as.BinMap{"b": 0}))
+		Expect(reads[2].Record).To(BeNil())
+	})
+
+	It("must batch operate on records", func() {
+		other, _ := as.NewKey("test", "fake", 2)
+		Expect(client.PutBins(nil, key, as.NewBin("a", 1))).ToNot(HaveOccurred())
+
+		records := []as.BatchRecordIfc{
+			as.NewBatchWrite(nil, key, as.AddOp(as.NewBin("a", 1)), as.GetOp()),
+			as.NewBatchPut(nil, other, as.NewBin("b", 2)),
+			as.NewBatchDelete(nil, key),
+			as.NewBatchDelete(nil, key),
+			as.NewBatchUDF(nil, other, "pkg", "fn"),
+		}
+		Expect(client.BatchOperate(nil, records)).ToNot(HaveOccurred())
+
+		Expect(records[0].BatchRec().Record.Bins).To(Equal(as.BinMap{"a": 2}))
+		Expect(records[1].BatchRec().ResultCode).To(Equal(OK))
+		Expect(records[2].BatchRec().ResultCode).To(Equal(OK))
+		Expect(records[3].BatchRec().ResultCode).To(Equal(KEY_NOT_FOUND_ERROR))
+		Expect(records[4].BatchRec().ResultCode).To(Equal(UNSUPPORTED_FEATURE))
+
+		exists, err := client.BatchExists(nil, []*as.Key{key, other})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(exists).To(Equal([]bool{false, true}))
+	})
+
+	It("must truncate sets", func() {
+		other, _ := as.NewKey("test", "other", 1)
+		Expect(client.PutBins(nil, key, as.NewBin("a", 1))).ToNot(HaveOccurred())
+		Expect(client.PutBins(nil, other, as.NewBin("a", 1))).ToNot(HaveOccurred())
+
+		Expect(client.Truncate(nil, "test", "fake", nil)).ToNot(HaveOccurred())
+
+		exists, err := client.BatchExists(nil, []*as.Key{key, other})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(exists).To(Equal([]bool{false, true}))
+	})
+
+	It("must report unsupported commands", func() {
+		_, err := client.ScanAll(nil, "test", "fake")
+		Expect(resultCode(err)).To(Equal(UNSUPPORTED_FEATURE))
+
+		client.Close()
+		Expect(client.IsConnected()).To(BeFalse())
+	})
+})
@@ -0,0 +1,303 @@
+// Copyright 2013-2017 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aerospiketest
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	. "github.com/aerospike/aerospike-client-go/types"
+)
+
+const (
+	_PARTITIONS = 4096
+
+	// the server version reported by the build info command
+	_BUILD = "3.15.0.0"
+
+	// features supported by the server
+	_FEATURES = "float;batch-index;replicas-all;geo;peers;pscans;pquery"
+
+	// the memory size of each namespace, in bytes
+	_MEMORY_SIZE = 1 << 30
+
+	// the maximum number of bin names of each namespace
+	_BIN_NAMES_QUOTA = 32768
+)
+
+// ownedPartitions is the base64 bitmap of all partitions,
+// all of which are owned by the server.
+var ownedPartitions = func() string {
+	bitmap := bytes.Repeat([]byte{0xFF}, _PARTITIONS/8)
+	return base64.StdEncoding.EncodeToString(bitmap)
+}()
+
+// info answers the info commands in the request, one per line.
+// Each command is answered as "name\tvalue\n", in the order of the request.
+func (srv *Server) info(request []byte) []byte {
+	var buf bytes.Buffer
+	for _, name := range strings.Split(string(request), "\n") {
+		if name == "" {
+			continue
+		}
+		buf.WriteString(name)
+		buf.WriteByte('\t')
+		buf.WriteString(srv.infoValue(name))
+		buf.WriteByte('\n')
+	}
+	return buf.Bytes()
+}
+
+func (srv *Server) infoValue(name string) string {
+	command, params := name, ""
+	if i := strings.IndexByte(name, ':'); i >= 0 {
+		command, params = name[:i], name[i+1:]
+	}
+
+	switch command {
+	case "build":
+		return _BUILD
+	case "node":
+		return srv.nodeName
+	case "features":
+		return _FEATURES
+	case "cluster-name":
+		return srv.ClusterName
+	case "namespaces":
+		return strings.Join(srv.store.namespaces, ";")
+	case "partitions":
+		return strconv.Itoa(_PARTITIONS)
+	case "partition-generation":
+		return "1"
+	case "peers-generation":
+		return "1"
+	case "peers-clear-std", "peers-clear-alt", "peers-tls-std", "peers-tls-alt":
+		// generation, default port and an empty list of peers
+		return "1," + strconv.Itoa(srv.Port()) + ",[]"
+	case "services", "services-alternate":
+		return ""
+	case "replicas-master":
+		return srv.replicas(func(ns string) string { return ns + ":" + ownedPartitions })
+	case "replicas-all":
+		return srv.replicas(func(ns string) string { return ns + ":1," + ownedPartitions })
+	case "truncate":
+		return srv.truncate(params)
+	case "sindex-create":
+		return srv.createIndex(params)
+	case "sindex-delete":
+		return srv.dropIndex(params)
+	}
+
+	if strings.HasPrefix(command, "sindex/") {
+		return srv.indexInfo(strings.Split(command, "/")[1:])
+	}
+	if strings.HasPrefix(command, "namespace/") {
+		return srv.namespaceInfo(command[len("namespace/"):])
+	}
+	if strings.HasPrefix(command, "sets/") {
+		return srv.setsInfo(command[len("sets/"):])
+	}
+	if strings.HasPrefix(command, "bins/") {
+		return srv.binsInfo(command[len("bins/"):])
+	}
+
+	return "ERROR::unrecognized command"
+}
+
+// replicas formats the partition ownership of each namespace.
+func (srv *Server) replicas(format func(namespace string) string) string {
+	res := make([]string, len(srv.store.namespaces))
+	for i, ns := range srv.store.namespaces {
+		res[i] = format(ns)
+	}
+	return strings.Join(res, ";")
+}
+
+// truncate removes the records of a namespace or set.
+// Format: truncate:namespace=<ns>[;set=<set>][;lut=<nanos>]
+func (srv *Server) truncate(params string) string {
+	var namespace, setName string
+	lut := time.Now().UnixNano()
+	for _, param := range strings.Split(params, ";") {
+		kv := strings.SplitN(param, "=", 2)
+		if len(kv) != 2 {
+			continue
+		}
+		switch kv[0] {
+		case "namespace":
+			namespace = kv[1]
+		case "set":
+			setName = kv[1]
+		case "lut":
+			if nanos, err := strconv.ParseInt(kv[1], 10, 64); err == nil {
+				lut = nanos
+			}
+		}
+	}
+
+	// the server reports the cutoff in milliseconds since the Citrusleaf epoch
+	lut = lut/int64(time.Millisecond) - CITRUSLEAF_EPOCH*1000
+	if !srv.store.truncate(namespace, setName, lut) {
+		return "ERROR::namespace not found"
+	}
+	return "ok"
+}
+
+// infoParams parses the "name=value" parameters of an info command, separated by ';'.
+func infoParams(params string) map[string]string {
+	res := map[string]string{}
+	for _, param := range strings.Split(params, ";") {
+		kv := strings.SplitN(param, "=", 2)
+		if len(kv) == 2 {
+			res[kv[0]] = kv[1]
+		}
+	}
+	return res
+}
+
+// createIndex creates a secondary index.
+// Format: sindex-create:ns=<ns>[;set=<set>];indexname=<name>;numbins=1[;indextype=<type>];indexdata=<bin>,<type>
+func (srv *Server) createIndex(params string) string {
+	p := infoParams(params)
+	indexData := strings.Split(p["indexdata"], ",")
+	if p["indexname"] == "" || len(indexData) != 2 {
+		return "FAIL:4:Invalid parameters"
+	}
+
+	idx := &sindex{
+		setName:        p["set"],
+		binName:        indexData[0],
+		indexType:      strings.ToUpper(indexData[1]),
+		collectionType: p["indextype"],
+	}
+	if idx.collectionType == "" {
+		idx.collectionType = "NONE"
+	}
+
+	if !srv.store.hasNamespace(p["ns"]) {
+		return "FAIL:20:Namespace not found"
+	}
+	if !srv.store.createIndex(p["ns"], p["indexname"], idx) {
+		return "FAIL:200:Index with the same name already exists"
+	}
+	return "OK"
+}
+
+// dropIndex drops a secondary index.
+// Format: sindex-delete:ns=<ns>[;set=<set>];indexname=<name>
+func (srv *Server) dropIndex(params string) string {
+	p := infoParams(params)
+	if !srv.store.dropIndex(p["ns"], p["indexname"]) {
+		return "FAIL:201:Index does not exist"
+	}
+	return "OK"
+}
+
+// indexInfo lists the secondary indexes of a namespace, or returns the statistics of one.
+// Format: sindex/<ns>[/<index>]
+func (srv *Server) indexInfo(path []string) string {
+	namespace := path[0]
+	if !srv.store.hasNamespace(namespace) {
+		return "FAIL:20:Namespace not found"
+	}
+
+	if len(path) == 1 {
+		var buf bytes.Buffer
+		for _, name := range srv.store.indexNames(namespace) {
+			idx := srv.store.index(namespace, name)
+			if idx == nil {
+				continue
+			}
+
+			setName := idx.setName
+			if setName == "" {
+				setName = "NULL"
+			}
+			fmt.Fprintf(&buf, "ns=%s:set=%s:indexname=%s:num_bins=1:bins=%s:type=%s:indextype=%s:path=%s:sync_state=synced:state=RW;",
+				namespace, setName, name, idx.binName, idx.indexType, idx.collectionType, idx.binName)
+		}
+		return buf.String()
+	}
+
+	idx := srv.store.index(namespace, path[1])
+	if idx == nil {
+		return "FAIL:201:NO INDEX"
+	}
+
+	// every record with the bin has an entry
+	entries := 0
+	_, records := srv.store.scan(namespace, idx.setName)
+	for _, rec := range records {
+		if _, exists := rec.get(idx.binName); exists {
+			entries++
+		}
+	}
+
+	return fmt.Sprintf("keys=%d;entries=%d;ibtr_memory_used=%d;nbtr_memory_used=%d;load_pct=100;loadtime=0",
+		entries, entries, 18432, entries*64)
+}
+
+// namespaceInfo returns the statistics of a namespace. The namespace is
+// in memory, without replicas.
+// Format: namespace/<ns>
+func (srv *Server) namespaceInfo(namespace string) string {
+	if !srv.store.hasNamespace(namespace) {
+		return "type=unknown"
+	}
+
+	memory := 0
+	_, records := srv.store.scan(namespace, "")
+	for _, rec := range records {
+		memory += rec.size()
+	}
+
+	return fmt.Sprintf("objects=%d;master_objects=%d;tombstones=0;replication-factor=1;effective_replication_factor=1;"+
+		"memory_used_bytes=%d;memory-size=%d;stop_writes=false;hwm_breached=false;storage-engine=memory",
+		len(records), len(records), memory, _MEMORY_SIZE)
+}
+
+// setsInfo returns the statistics of the sets of a namespace.
+// Format: sets/<ns>
+func (srv *Server) setsInfo(namespace string) string {
+	if !srv.store.hasNamespace(namespace) {
+		return "ERROR::namespace not found"
+	}
+
+	var buf bytes.Buffer
+	for _, set := range srv.store.sets(namespace) {
+		fmt.Fprintf(&buf, "ns=%s:set=%s:objects=%d:tombstones=0:memory_data_bytes=%d:truncate_lut=%d:stop-writes-count=0:disable-eviction=false;",
+			namespace, set.name, set.objects, set.memoryBytes, set.truncateLUT)
+	}
+	return buf.String()
+}
+
+// binsInfo returns the bin names of a namespace.
+// Format: bins/<ns>
+func (srv *Server) binsInfo(namespace string) string {
+	if !srv.store.hasNamespace(namespace) {
+		return "ERROR::namespace not found"
+	}
+
+	names := srv.store.binNames(namespace)
+	res := fmt.Sprintf("bin_names=%d,bin_names_quota=%d", len(names), _BIN_NAMES_QUOTA)
+	for _, name := range names {
+		res += "," + name
+	}
+	return res
+}
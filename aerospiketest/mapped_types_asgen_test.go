@@ -0,0 +1,298 @@
+// Code generated by asgen; DO NOT EDIT.
+
+package aerospiketest_test
+
+import (
+	"time"
+
+	as "github.com/aerospike/aerospike-client-go"
+	"github.com/aerospike/aerospike-client-go/utils/binconv"
+)
+
+// MarshalBins implements the as.BinMarshaler interface.
+func (v *mappedUser) MarshalBins() (as.BinMap, error) {
+	return as.BinMap(asgenEncodemappedUser(v)), nil
+}
+
+// UnmarshalBins implements the as.BinUnmarshaler interface.
+func (v *mappedUser) UnmarshalBins(bins as.BinMap, generation, expiration uint32) error {
+	if err := asgenDecodemappedUser(v, map[string]interface{}(bins)); err != nil {
+		return err
+	}
+	v.Gen = uint32(generation)
+	v.TTL = uint32(expiration)
+	return nil
+}
+
+func asgenEncodemappedUser(v *mappedUser) map[string]interface{} {
+	m := make(map[string]interface{}, 18)
+	m["name"] = v.Name
+	if v.Email != "" {
+		m["email"] = v.Email
+	}
+	m["age"] = int64(v.Age)
+	m["score"] = v.Score
+	m["active"] = binconv.FromBool(v.Active)
+	m["status"] = int64(v.Status)
+	if v.Avatar == nil {
+		m["avatar"] = nil
+	} else {
+		m["avatar"] = v.Avatar
+	}
+	m["created"] = binconv.FromTime(v.Created)
+	m["timeout"] = int64(v.Timeout)
+	m["addr"] = asgenEncodemappedAddress(&v.Address)
+	if v.Previous != nil {
+		m["prev"] = asgenEncodemappedAddress(v.Previous)
+	}
+	if v.Tags == nil {
+		m["tags"] = nil
+	} else {
+		l1 := make([]interface{}, len(v.Tags))
+		for i2 := range v.Tags {
+			l1[i2] = v.Tags[i2]
+		}
+		m["tags"] = l1
+	}
+	if v.Counts == nil {
+		m["counts"] = nil
+	} else {
+		m3 := make(map[interface{}]interface{}, len(v.Counts))
+		for k4, e5 := range v.Counts {
+			var k6, e7 interface{}
+			k6 = k4
+			e7 = int64(e5)
+			m3[k6] = e7
+		}
+		m["counts"] = m3
+	}
+	if v.History == nil {
+		m["history"] = nil
+	} else {
+		l8 := make([]interface{}, len(v.History))
+		for i9 := range v.History {
+			l8[i9] = asgenEncodemappedAddress(&v.History[i9])
+		}
+		m["history"] = l8
+	}
+	{
+		l10 := make([]interface{}, len(v.Coords))
+		for i11 := range v.Coords {
+			l10[i11] = int64(v.Coords[i11])
+		}
+		m["coords"] = l10
+	}
+	m["extra"] = v.Extra
+	return m
+}
+
+func asgenDecodemappedUser(v *mappedUser, m map[string]interface{}) error {
+	if value := m["name"]; value != nil {
+		x12, err := binconv.String(value)
+		if err != nil {
+			return err
+		}
+		v.Name = x12
+	}
+	if value := m["email"]; value != nil {
+		x13, err := binconv.String(value)
+		if err != nil {
+			return err
+		}
+		v.Email = x13
+	}
+	if value := m["age"]; value != nil {
+		x14, err := binconv.Int64(value)
+		if err != nil {
+			return err
+		}
+		v.Age = int(x14)
+	}
+	if value := m["score"]; value != nil {
+		x15, err := binconv.Float64(value)
+		if err != nil {
+			return err
+		}
+		v.Score = x15
+	}
+	if value := m["active"]; value != nil {
+		x16, err := binconv.Bool(value)
+		if err != nil {
+			return err
+		}
+		v.Active = x16
+	}
+	if value := m["status"]; value != nil {
+		x17, err := binconv.Int64(value)
+		if err != nil {
+			return err
+		}
+		v.Status = mappedStatus(x17)
+	}
+	if value := m["avatar"]; value != nil {
+		x18, err := binconv.Bytes(value)
+		if err != nil {
+			return err
+		}
+		v.Avatar = x18
+	}
+	if value := m["created"]; value != nil {
+		x19, err := binconv.Time(value)
+		if err != nil {
+			return err
+		}
+		v.Created = x19
+	}
+	if value := m["timeout"]; value != nil {
+		x20, err := binconv.Int64(value)
+		if err != nil {
+			return err
+		}
+		v.Timeout = time.Duration(x20)
+	}
+	if value := m["addr"]; value != nil {
+		x21, err := binconv.StringMap(value)
+		if err != nil {
+			return err
+		}
+		if err := asgenDecodemappedAddress(&v.Address, x21); err != nil {
+			return err
+		}
+	}
+	if value := m["prev"]; value != nil {
+		var p22 mappedAddress
+		x23, err := binconv.StringMap(value)
+		if err != nil {
+			return err
+		}
+		if err := asgenDecodemappedAddress(&p22, x23); err != nil {
+			return err
+		}
+		v.Previous = &p22
+	}
+	if value := m["tags"]; value != nil {
+		l24, err := binconv.List(value)
+		if err != nil {
+			return err
+		}
+		s25 := make([]string, len(l24))
+		for i26, e27 := range l24 {
+			if e27 != nil {
+				x28, err := binconv.String(e27)
+				if err != nil {
+					return err
+				}
+				s25[i26] = x28
+			}
+		}
+		v.Tags = s25
+	}
+	if value := m["counts"]; value != nil {
+		m29, err := binconv.Map(value)
+		if err != nil {
+			return err
+		}
+		r30 := make(map[string]int, len(m29))
+		for k31, e32 := range m29 {
+			var k33 string
+			if k31 != nil {
+				x35, err := binconv.String(k31)
+				if err != nil {
+					return err
+				}
+				k33 = x35
+			}
+			var e34 int
+			if e32 != nil {
+				x36, err := binconv.Int64(e32)
+				if err != nil {
+					return err
+				}
+				e34 = int(x36)
+			}
+			r30[k33] = e34
+		}
+		v.Counts = r30
+	}
+	if value := m["history"]; value != nil {
+		l37, err := binconv.List(value)
+		if err != nil {
+			return err
+		}
+		s38 := make([]mappedAddress, len(l37))
+		for i39, e40 := range l37 {
+			if e40 != nil {
+				x41, err := binconv.StringMap(e40)
+				if err != nil {
+					return err
+				}
+				if err := asgenDecodemappedAddress(&s38[i39], x41); err != nil {
+					return err
+				}
+			}
+		}
+		v.History = s38
+	}
+	if value := m["coords"]; value != nil {
+		l42, err := binconv.List(value)
+		if err != nil {
+			return err
+		}
+		var s43 [2]int
+		for i44, e45 := range l42 {
+			if i44 >= len(s43) {
+				break
+			}
+			if e45 != nil {
+				x46, err := binconv.Int64(e45)
+				if err != nil {
+					return err
+				}
+				s43[i44] = int(x46)
+			}
+		}
+		v.Coords = s43
+	}
+	if value := m["extra"]; value != nil {
+		v.Extra = value
+	}
+	return nil
+}
+
+// MarshalBins implements the as.BinMarshaler interface.
+func (v *mappedAddress) MarshalBins() (as.BinMap, error) {
+	return as.BinMap(asgenEncodemappedAddress(v)), nil
+}
+
+// UnmarshalBins implements the as.BinUnmarshaler interface.
+func (v *mappedAddress) UnmarshalBins(bins as.BinMap, generation, expiration uint32) error {
+	if err := asgenDecodemappedAddress(v, map[string]interface{}(bins)); err != nil {
+		return err
+	}
+	return nil
+}
+
+func asgenEncodemappedAddress(v *mappedAddress) map[string]interface{} {
+	m := make(map[string]interface{}, 2)
+	m["street"] = v.Street
+	m["zip"] = int64(v.Zip)
+	return m
+}
+
+func asgenDecodemappedAddress(v *mappedAddress, m map[string]interface{}) error {
+	if value := m["street"]; value != nil {
+		x47, err := binconv.String(value)
+		if err != nil {
+			return err
+		}
+		v.Street = x47
+	}
+	if value := m["zip"]; value != nil {
+		x48, err := binconv.Int64(value)
+		if err != nil {
+			return err
+		}
+		v.Zip = int(x48)
+	}
+	return nil
+}
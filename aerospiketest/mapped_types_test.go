@@ -0,0 +1,74 @@
+// Copyright 2013-2017 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aerospiketest_test
+
+import (
+	"time"
+)
+
+//go:generate go run ../tools/asgen -type=mappedUser,mappedAddress -output=mapped_types_asgen_test.go
+
+type mappedStatus int8
+
+type mappedAddress struct {
+	Street string `as:"street"`
+	Zip    int    `as:"zip"`
+}
+
+// mappedUser is mapped by generated code.
+type mappedUser struct {
+	Name     string          `as:"name"`
+	Email    string          `as:"email,omitempty"`
+	Age      int             `as:"age"`
+	Score    float64         `as:"score"`
+	Active   bool            `as:"active"`
+	Status   mappedStatus    `as:"status"`
+	Avatar   []byte          `as:"avatar"`
+	Created  time.Time       `as:"created"`
+	Timeout  time.Duration   `as:"timeout"`
+	Address  mappedAddress   `as:"addr"`
+	Previous *mappedAddress  `as:"prev,omitempty"`
+	Tags     []string        `as:"tags"`
+	Counts   map[string]int  `as:"counts"`
+	History  []mappedAddress `as:"history"`
+	Coords   [2]int          `as:"coords"`
+	Extra    interface{}     `as:"extra"`
+	Secret   string          `as:"-"`
+	Gen      uint32          `asm:"gen"`
+	TTL      uint32          `asm:"ttl"`
+}
+
+// reflectedUser has the same bins as mappedUser, and is mapped by reflection.
+type reflectedUser struct {
+	Name     string          `as:"name"`
+	Email    string          `as:"email,omitempty"`
+	Age      int             `as:"age"`
+	Score    float64         `as:"score"`
+	Active   bool            `as:"active"`
+	Status   mappedStatus    `as:"status"`
+	Avatar   []byte          `as:"avatar"`
+	Created  time.Time       `as:"created"`
+	Timeout  time.Duration   `as:"timeout"`
+	Address  mappedAddress   `as:"addr"`
+	Previous *mappedAddress  `as:"prev,omitempty"`
+	Tags     []string        `as:"tags"`
+	Counts   map[string]int  `as:"counts"`
+	History  []mappedAddress `as:"history"`
+	Coords   [2]int          `as:"coords"`
+	Extra    interface{}     `as:"extra"`
+	Secret   string          `as:"-"`
+	Gen      uint32          `asm:"gen"`
+	TTL      uint32          `asm:"ttl"`
+}
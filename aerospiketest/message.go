@@ -0,0 +1,720 @@
+// Copyright 2013-2017 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aerospiketest
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"math"
+
+	as "github.com/aerospike/aerospike-client-go"
+	. "github.com/aerospike/aerospike-client-go/types"
+	ParticleType "github.com/aerospike/aerospike-client-go/types/particle_type"
+)
+
+// Size of the message header, after the protocol header.
+const _MSG_HEADER_SIZE = 22
+
+// Flags of the message header.
+const (
+	_INFO1_READ      = (1 << 0)
+	_INFO1_GET_ALL   = (1 << 1)
+	_INFO1_NOBINDATA = (1 << 5)
+
+	_INFO2_WRITE           = (1 << 0)
+	_INFO2_DELETE          = (1 << 1)
+	_INFO2_GENERATION      = (1 << 2)
+	_INFO2_GENERATION_GT   = (1 << 3)
+	_INFO2_CREATE_ONLY     = (1 << 5)
+	_INFO2_RESPOND_ALL_OPS = (1 << 7)
+
+	_INFO3_LAST              = (1 << 0)
+	_INFO3_PARTITION_DONE    = (1 << 2)
+	_INFO3_UPDATE_ONLY       = (1 << 3)
+	_INFO3_CREATE_OR_REPLACE = (1 << 4)
+	_INFO3_REPLACE_ONLY      = (1 << 5)
+)
+
+// Operation codes.
+const (
+	_OP_READ       = 1
+	_OP_WRITE      = 2
+	_OP_CDT_READ   = 3
+	_OP_CDT_MODIFY = 4
+	_OP_ADD        = 5
+	_OP_APPEND     = 9
+	_OP_PREPEND    = 10
+	_OP_TOUCH      = 11
+)
+
+const (
+	_DIGEST_SIZE   = 20
+	_MAX_BIN_NAME  = 14
+	_MAX_FRAME_LEN = 128 * 1024
+)
+
+var errMalformed = errors.New("malformed message")
+
+type field struct {
+	fieldType as.FieldType
+	data      []byte
+}
+
+type operation struct {
+	op       byte
+	name     string
+	particle particle
+}
+
+// request is a parsed AS_MSG request.
+type request struct {
+	info1, info2, info3 byte
+	generation          uint32
+	ttl                 uint32
+	fields              []field
+	ops                 []operation
+}
+
+func (req *request) field(fieldType as.FieldType) []byte {
+	for i := range req.fields {
+		if req.fields[i].fieldType == fieldType {
+			return req.fields[i].data
+		}
+	}
+	return nil
+}
+
+func (req *request) hasField(fieldType as.FieldType) bool {
+	for i := range req.fields {
+		if req.fields[i].fieldType == fieldType {
+			return true
+		}
+	}
+	return false
+}
+
+// reader reads the wire format of a message.
+type reader struct {
+	buf    []byte
+	offset int
+	err    error
+}
+
+func (r *reader) next(n int) []byte {
+	if r.err != nil || n < 0 || r.offset+n > len(r.buf) {
+		r.err = errMalformed
+		return nil
+	}
+	res := r.buf[r.offset : r.offset+n]
+	r.offset += n
+	return res
+}
+
+func (r *reader) byte() byte {
+	if b := r.next(1); b != nil {
+		return b[0]
+	}
+	return 0
+}
+
+func (r *reader) uint16() uint16 {
+	if b := r.next(2); b != nil {
+		return binary.BigEndian.Uint16(b)
+	}
+	return 0
+}
+
+func (r *reader) uint32() uint32 {
+	if b := r.next(4); b != nil {
+		return binary.BigEndian.Uint32(b)
+	}
+	return 0
+}
+
+func (r *reader) fields(count int) []field {
+	res := make([]field, 0, count)
+	for i := 0; i < count && r.err == nil; i++ {
+		size := int(r.uint32())
+		if size < 1 {
+			r.err = errMalformed
+			break
+		}
+		fieldType := as.FieldType(r.byte())
+		res = append(res, field{fieldType: fieldType, data: r.next(size - 1)})
+	}
+	return res
+}
+
+func (r *reader) ops(count int) []operation {
+	res := make([]operation, 0, count)
+	for i := 0; i < count && r.err == nil; i++ {
+		size := int(r.uint32())
+		op := r.byte()
+		particleType := r.byte()
+		r.byte() // version
+		nameLen := int(r.byte())
+		name := r.next(nameLen)
+		value := r.next(size - 4 - nameLen)
+		res = append(res, operation{
+			op:       op,
+			name:     string(name),
+			particle: particle{particleType: particleType, value: value},
+		})
+	}
+	return res
+}
+
+func parseRequest(body []byte) (*request, error) {
+	r := &reader{buf: body}
+	header := r.next(_MSG_HEADER_SIZE)
+	if r.err != nil || header[0] != _MSG_HEADER_SIZE {
+		return nil, errMalformed
+	}
+
+	req := &request{
+		info1:      header[1],
+		info2:      header[2],
+		info3:      header[3],
+		generation: binary.BigEndian.Uint32(header[6:]),
+		ttl:        binary.BigEndian.Uint32(header[10:]),
+	}
+	req.fields = r.fields(int(binary.BigEndian.Uint16(header[18:])))
+	req.ops = r.ops(int(binary.BigEndian.Uint16(header[20:])))
+	if r.err != nil {
+		return nil, r.err
+	}
+	return req, nil
+}
+
+// msgWriter builds the messages of a response, splitting them in protocol frames.
+type msgWriter struct {
+	buf []byte
+	out []byte
+}
+
+func (w *msgWriter) writeMessage(resultCode ResultCode, info3 byte, generation, voidTime, batchIndex uint32, fields []field, ops []operation) {
+	var header [_MSG_HEADER_SIZE]byte
+	header[0] = _MSG_HEADER_SIZE
+	header[3] = info3
+	header[5] = byte(resultCode)
+	binary.BigEndian.PutUint32(header[6:], generation)
+	binary.BigEndian.PutUint32(header[10:], voidTime)
+	binary.BigEndian.PutUint32(header[14:], batchIndex)
+	binary.BigEndian.PutUint16(header[18:], uint16(len(fields)))
+	binary.BigEndian.PutUint16(header[20:], uint16(len(ops)))
+	w.buf = append(w.buf, header[:]...)
+
+	for _, f := range fields {
+		w.buf = appendUint32(w.buf, uint32(len(f.data)+1))
+		w.buf = append(w.buf, byte(f.fieldType))
+		w.buf = append(w.buf, f.data...)
+	}
+
+	for _, op := range ops {
+		w.buf = appendUint32(w.buf, uint32(4+len(op.name)+len(op.particle.value)))
+		w.buf = append(w.buf, op.op, op.particle.particleType, 0, byte(len(op.name)))
+		w.buf = append(w.buf, op.name...)
+		w.buf = append(w.buf, op.particle.value...)
+	}
+
+	if len(w.buf) >= _MAX_FRAME_LEN {
+		w.flush()
+	}
+}
+
+// flush frames the buffered messages.
+func (w *msgWriter) flush() {
+	if len(w.buf) > 0 {
+		w.out = append(w.out, frame(_MSG_TYPE_AS, w.buf)...)
+		w.buf = w.buf[:0]
+	}
+}
+
+// bytes returns the framed response.
+func (w *msgWriter) bytes() []byte {
+	w.flush()
+	return w.out
+}
+
+func appendUint32(buf []byte, v uint32) []byte {
+	return append(buf, byte(v>>24), byte(v>>16), byte(v>>8), byte(v))
+}
+
+// message executes an AS_MSG request and returns the framed response.
+func (srv *Server) message(body []byte) []byte {
+	req, err := parseRequest(body)
+	if err != nil {
+		return errorResponse(PARAMETER_ERROR, true)
+	}
+
+	switch {
+	case req.hasField(as.SCAN_OPTIONS):
+		return srv.scan(req)
+	case req.hasField(as.BATCH_INDEX), req.hasField(as.BATCH_INDEX_WITH_SET):
+		return srv.batchIndex(req)
+	case req.hasField(as.DIGEST_RIPE_ARRAY):
+		return srv.batchDigests(req)
+	case req.hasField(as.INDEX_NAME), req.hasField(as.INDEX_RANGE),
+		req.hasField(as.UDF_PACKAGE_NAME), req.hasField(as.UDF_FUNCTION):
+		return errorResponse(UNSUPPORTED_FEATURE, true)
+	}
+
+	return srv.single(req)
+}
+
+// single executes a command on a single record.
+func (srv *Server) single(req *request) []byte {
+	namespace := string(req.field(as.NAMESPACE))
+	d := req.field(as.DIGEST_RIPE)
+	if !srv.store.hasNamespace(namespace) {
+		return errorResponse(INVALID_NAMESPACE, false)
+	}
+	if len(d) != _DIGEST_SIZE {
+		return errorResponse(PARAMETER_ERROR, false)
+	}
+
+	var key digest
+	copy(key[:], d)
+
+	w := &msgWriter{}
+	switch {
+	case req.info2&_INFO2_DELETE != 0:
+		resultCode := srv.store.update(namespace, key, func(rec *record) (*record, ResultCode) {
+			if rec == nil {
+				return nil, KEY_NOT_FOUND_ERROR
+			}
+			if resultCode := checkGeneration(req, rec); resultCode != OK {
+				return nil, resultCode
+			}
+			return nil, OK
+		})
+		w.writeMessage(resultCode, 0, 0, 0, 0, nil, nil)
+
+	case req.info2&_INFO2_WRITE != 0:
+		var generation, voidTime uint32
+		var results []operation
+		resultCode := srv.store.update(namespace, key, func(rec *record) (*record, ResultCode) {
+			rec, res, resultCode := write(req, rec)
+			if resultCode != OK || rec == nil {
+				return nil, resultCode
+			}
+			generation, voidTime, results = rec.generation, rec.voidTime, res
+			return rec, OK
+		})
+		w.writeMessage(resultCode, 0, generation, voidTime, 0, nil, results)
+
+	default:
+		rec := srv.store.get(namespace, key)
+		if rec == nil {
+			w.writeMessage(KEY_NOT_FOUND_ERROR, 0, 0, 0, 0, nil, nil)
+			break
+		}
+		results, resultCode := read(req.info1, req.info2, req.ops, rec)
+		if resultCode != OK {
+			w.writeMessage(resultCode, 0, 0, 0, 0, nil, nil)
+			break
+		}
+		w.writeMessage(OK, 0, rec.generation, rec.voidTime, 0, nil, results)
+	}
+	return w.bytes()
+}
+
+func checkGeneration(req *request, rec *record) ResultCode {
+	if req.info2&_INFO2_GENERATION != 0 && req.generation != rec.generation {
+		return GENERATION_ERROR
+	}
+	if req.info2&_INFO2_GENERATION_GT != 0 && req.generation <= rec.generation {
+		return GENERATION_ERROR
+	}
+	return OK
+}
+
+// read returns the bins of the record requested by the read attributes and operations.
+func read(info1, info2 byte, ops []operation, rec *record) ([]operation, ResultCode) {
+	if info1&_INFO1_NOBINDATA != 0 {
+		return nil, OK
+	}
+	if info1&_INFO1_GET_ALL != 0 {
+		return allBins(rec), OK
+	}
+
+	respondAll := info2&_INFO2_RESPOND_ALL_OPS != 0
+	var res []operation
+	for _, op := range ops {
+		switch op.op {
+		case _OP_READ:
+			if p, exists := rec.get(op.name); exists {
+				res = append(res, operation{op: _OP_READ, name: op.name, particle: p})
+			} else if respondAll {
+				res = append(res, nullOp(op.name))
+			}
+		default:
+			return nil, UNSUPPORTED_FEATURE
+		}
+	}
+	return res, OK
+}
+
+func allBins(rec *record) []operation {
+	res := make([]operation, 0, len(rec.binNames))
+	for _, name := range rec.binNames {
+		res = append(res, operation{op: _OP_READ, name: name, particle: rec.bins[name]})
+	}
+	return res
+}
+
+func nullOp(name string) operation {
+	return operation{op: _OP_READ, name: name, particle: particle{particleType: ParticleType.NULL}}
+}
+
+// write applies the write request to the record, which is nil if it does not exist.
+// It returns the new record, which is nil if the record was deleted, and the results
+// of the operations.
+func write(req *request, rec *record) (*record, []operation, ResultCode) {
+	exists := rec != nil
+
+	if exists {
+		if req.info2&_INFO2_CREATE_ONLY != 0 {
+			return nil, nil, KEY_EXISTS_ERROR
+		}
+		if resultCode := checkGeneration(req, rec); resultCode != OK {
+			return nil, nil, resultCode
+		}
+		if req.info3&(_INFO3_CREATE_OR_REPLACE|_INFO3_REPLACE_ONLY) != 0 {
+			rec.clearBins()
+		}
+	} else {
+		if req.info3&(_INFO3_UPDATE_ONLY|_INFO3_REPLACE_ONLY) != 0 {
+			return nil, nil, KEY_NOT_FOUND_ERROR
+		}
+		rec = newRecord(string(req.field(as.TABLE)))
+	}
+
+	respondAll := req.info2&_INFO2_RESPOND_ALL_OPS != 0
+	var results []operation
+	for _, op := range req.ops {
+		if len(op.name) > _MAX_BIN_NAME {
+			return nil, nil, BIN_NAME_TOO_LONG
+		}
+
+		switch op.op {
+		case _OP_READ:
+			if op.name == "" {
+				// read all bins after the writes
+				continue
+			}
+			if p, exists := rec.get(op.name); exists {
+				results = append(results, operation{op: _OP_READ, name: op.name, particle: p})
+			} else if respondAll {
+				results = append(results, nullOp(op.name))
+			}
+			continue
+
+		case _OP_WRITE:
+			if op.particle.particleType == ParticleType.NULL {
+				rec.remove(op.name)
+			} else {
+				rec.put(op.name, op.particle)
+			}
+
+		case _OP_ADD:
+			p, resultCode := add(rec, op)
+			if resultCode != OK {
+				return nil, nil, resultCode
+			}
+			rec.put(op.name, p)
+
+		case _OP_APPEND, _OP_PREPEND:
+			p, resultCode := concat(rec, op)
+			if resultCode != OK {
+				return nil, nil, resultCode
+			}
+			rec.put(op.name, p)
+
+		case _OP_TOUCH:
+			if !exists {
+				return nil, nil, KEY_NOT_FOUND_ERROR
+			}
+
+		default:
+			return nil, nil, UNSUPPORTED_FEATURE
+		}
+
+		if respondAll {
+			results = append(results, nullOp(op.name))
+		}
+	}
+
+	if req.info1&_INFO1_GET_ALL != 0 {
+		results = append(results, allBins(rec)...)
+	}
+
+	if len(rec.bins) == 0 {
+		return nil, nil, OK
+	}
+
+	rec.generation++
+	rec.setTTL(req.ttl)
+	if key := req.field(as.KEY); key != nil {
+		rec.key = key
+	}
+	return rec, results, OK
+}
+
+// add increments a numeric bin. Missing bins are created with the value.
+func add(rec *record, op operation) (particle, ResultCode) {
+	p, exists := rec.get(op.name)
+	if !exists {
+		return op.particle, OK
+	}
+	if p.particleType != op.particle.particleType || len(p.value) != 8 || len(op.particle.value) != 8 {
+		return particle{}, BIN_TYPE_ERROR
+	}
+
+	res := particle{particleType: p.particleType, value: make([]byte, 8)}
+	switch p.particleType {
+	case ParticleType.INTEGER:
+		sum := int64(binary.BigEndian.Uint64(p.value)) + int64(binary.BigEndian.Uint64(op.particle.value))
+		binary.BigEndian.PutUint64(res.value, uint64(sum))
+	case ParticleType.FLOAT:
+		sum := math.Float64frombits(binary.BigEndian.Uint64(p.value)) + math.Float64frombits(binary.BigEndian.Uint64(op.particle.value))
+		binary.BigEndian.PutUint64(res.value, math.Float64bits(sum))
+	default:
+		return particle{}, BIN_TYPE_ERROR
+	}
+	return res, OK
+}
+
+// concat appends or prepends to a string or blob bin. Missing bins are created with the value.
+func concat(rec *record, op operation) (particle, ResultCode) {
+	p, exists := rec.get(op.name)
+	if !exists {
+		return op.particle, OK
+	}
+	if p.particleType != op.particle.particleType ||
+		(p.particleType != ParticleType.STRING && p.particleType != ParticleType.BLOB) {
+		return particle{}, BIN_TYPE_ERROR
+	}
+
+	value := make([]byte, 0, len(p.value)+len(op.particle.value))
+	if op.op == _OP_APPEND {
+		value = append(append(value, p.value...), op.particle.value...)
+	} else {
+		value = append(append(value, op.particle.value...), p.value...)
+	}
+	return particle{particleType: p.particleType, value: value}, OK
+}
+
+// batchIndex executes a batch read in the batch index format:
+// count(4) inline(1), then for each key: index(4) digest(20) repeat(1), followed
+// by attr(1) fieldCount(2) opCount(2) fields ops if the previous key is not repeated.
+func (srv *Server) batchIndex(req *request) []byte {
+	data := req.field(as.BATCH_INDEX)
+	if data == nil {
+		data = req.field(as.BATCH_INDEX_WITH_SET)
+	}
+
+	r := &reader{buf: data}
+	count := int(r.uint32())
+	r.byte() // allow inline
+
+	w := &msgWriter{}
+	var namespace string
+	var attr byte
+	var ops []operation
+	for i := 0; i < count && r.err == nil; i++ {
+		index := r.uint32()
+		var key digest
+		copy(key[:], r.next(_DIGEST_SIZE))
+
+		if r.byte() == 0 {
+			attr = r.byte()
+			fieldCount := int(r.uint16())
+			opCount := int(r.uint16())
+			fields := r.fields(fieldCount)
+			ops = r.ops(opCount)
+
+			namespace = ""
+			for _, f := range fields {
+				if f.fieldType == as.NAMESPACE {
+					namespace = string(f.data)
+				}
+			}
+		}
+
+		if r.err != nil {
+			break
+		}
+		if !srv.store.hasNamespace(namespace) {
+			return errorResponse(INVALID_NAMESPACE, true)
+		}
+		srv.batchRecord(w, namespace, key, attr, ops, index)
+	}
+
+	if r.err != nil {
+		return errorResponse(PARAMETER_ERROR, true)
+	}
+
+	w.writeMessage(OK, _INFO3_LAST, 0, 0, 0, nil, nil)
+	return w.bytes()
+}
+
+// batchDigests executes a batch read in the legacy format, with a namespace field
+// and the digests of the keys.
+func (srv *Server) batchDigests(req *request) []byte {
+	namespace := string(req.field(as.NAMESPACE))
+	digests := req.field(as.DIGEST_RIPE_ARRAY)
+	if !srv.store.hasNamespace(namespace) {
+		return errorResponse(INVALID_NAMESPACE, true)
+	}
+	if len(digests)%_DIGEST_SIZE != 0 {
+		return errorResponse(PARAMETER_ERROR, true)
+	}
+
+	// all bins are read if no bin name is sent
+	info1 := req.info1
+	if len(req.ops) == 0 {
+		info1 |= _INFO1_GET_ALL
+	}
+
+	w := &msgWriter{}
+	for i := 0; i < len(digests); i += _DIGEST_SIZE {
+		var key digest
+		copy(key[:], digests[i:])
+		srv.batchRecord(w, namespace, key, info1, req.ops, 0)
+	}
+
+	w.writeMessage(OK, _INFO3_LAST, 0, 0, 0, nil, nil)
+	return w.bytes()
+}
+
+// batchRecord writes the response of a key in a batch.
+func (srv *Server) batchRecord(w *msgWriter, namespace string, key digest, attr byte, ops []operation, index uint32) {
+	fields := []field{{fieldType: as.DIGEST_RIPE, data: key[:]}}
+
+	rec := srv.store.get(namespace, key)
+	if rec == nil {
+		w.writeMessage(KEY_NOT_FOUND_ERROR, 0, 0, 0, index, fields, nil)
+		return
+	}
+
+	results, resultCode := read(attr, 0, ops, rec)
+	if resultCode != OK {
+		w.writeMessage(resultCode, 0, 0, 0, index, fields, nil)
+		return
+	}
+	w.writeMessage(OK, 0, rec.generation, rec.voidTime, index, fields, results)
+}
+
+// scan streams the records of a namespace or set, sorted by digest.
+func (srv *Server) scan(req *request) []byte {
+	namespace := string(req.field(as.NAMESPACE))
+	setName := string(req.field(as.TABLE))
+	if !srv.store.hasNamespace(namespace) {
+		return errorResponse(INVALID_NAMESPACE, true)
+	}
+
+	// all bins are read if no bin name is sent
+	info1 := req.info1
+	if len(req.ops) == 0 {
+		info1 |= _INFO1_GET_ALL
+	}
+
+	// at most max records are returned if the scan is limited
+	max := -1
+	if req.hasField(as.MAX_RECORDS) {
+		max = int(binary.BigEndian.Uint64(req.field(as.MAX_RECORDS)))
+	}
+
+	w := &msgWriter{}
+	digests, records := srv.store.scan(namespace, setName)
+	if req.hasField(as.PID_ARRAY) || req.hasField(as.DIGEST_ARRAY) {
+		srv.scanPartitions(w, req, namespace, info1, digests, records, max)
+	} else {
+		for i, rec := range records {
+			if i == max {
+				break
+			}
+			scanRecord(w, req, namespace, info1, digests[i], rec)
+		}
+	}
+
+	w.writeMessage(OK, _INFO3_LAST, 0, 0, 0, nil, nil)
+	return w.bytes()
+}
+
+// scanRecord writes a record of a scan.
+func scanRecord(w *msgWriter, req *request, namespace string, info1 byte, d digest, rec *record) {
+	fields := []field{
+		{fieldType: as.NAMESPACE, data: []byte(namespace)},
+		{fieldType: as.DIGEST_RIPE, data: d[:]},
+	}
+	if rec.setName != "" {
+		fields = append(fields, field{fieldType: as.TABLE, data: []byte(rec.setName)})
+	}
+	if rec.key != nil {
+		fields = append(fields, field{fieldType: as.KEY, data: rec.key})
+	}
+
+	results, _ := read(info1, 0, req.ops, rec)
+	w.writeMessage(OK, 0, rec.generation, rec.voidTime, 0, fields, results)
+}
+
+// scanPartitions streams the records of the requested partitions one partition
+// after the other, each followed by a partition done message. Partitions requested
+// by digest are resumed after the digest. The scan stops without marking the
+// current partition done when max records are sent, unless max is negative.
+func (srv *Server) scanPartitions(w *msgWriter, req *request, namespace string, info1 byte, digests []digest, records []*record, max int) {
+	type partitionCursor struct {
+		id    int
+		after []byte
+	}
+
+	var cursors []partitionCursor
+	pids := req.field(as.PID_ARRAY)
+	for i := 0; i+2 <= len(pids); i += 2 {
+		cursors = append(cursors, partitionCursor{id: int(binary.LittleEndian.Uint16(pids[i:]))})
+	}
+	ds := req.field(as.DIGEST_ARRAY)
+	for i := 0; i+len(digest{}) <= len(ds); i += len(digest{}) {
+		after := ds[i : i+len(digest{})]
+		cursors = append(cursors, partitionCursor{id: partitionId(after), after: after})
+	}
+
+	for _, cursor := range cursors {
+		if !srv.partitionAvailable(cursor.id) {
+			w.writeMessage(PARTITION_UNAVAILABLE, _INFO3_PARTITION_DONE, uint32(cursor.id), 0, 0, nil, nil)
+			continue
+		}
+
+		for i, rec := range records {
+			if partitionId(digests[i][:]) != cursor.id || (cursor.after != nil && bytes.Compare(digests[i][:], cursor.after) <= 0) {
+				continue
+			}
+			if max == 0 {
+				return
+			}
+			scanRecord(w, req, namespace, info1, digests[i], rec)
+			max--
+		}
+		w.writeMessage(OK, _INFO3_PARTITION_DONE, uint32(cursor.id), 0, 0, nil, nil)
+	}
+}
+
+// partitionId returns the partition of the digest.
+func partitionId(d []byte) int {
+	return int(binary.LittleEndian.Uint16(d)) & (_PARTITIONS - 1)
+}
@@ -0,0 +1,290 @@
+// Copyright 2013-2017 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package aerospiketest provides an in-process Aerospike server and an in-memory
+// fake client for tests.
+//
+// The server speaks the Aerospike wire protocol over a loopback socket and keeps
+// the records in memory. It answers the info commands the client uses to discover
+// the cluster, and supports:
+//
+//  - single record reads, writes, operations (except CDT operations) and deletes,
+//    including pipelined and async commands
+//  - batch reads, and the writes and deletes of BatchOperate
+//  - scans, partition scans, and queries without an index filter, which are enough
+//    for Client.Aggregate
+//  - truncating sets
+//  - creating, listing and dropping secondary indexes, which queries do not use
+//  - namespace, set and bin statistics
+//
+// Queries with an index filter, CDT operations and UDFs fail with UNSUPPORTED_FEATURE,
+// and user management is not supported. Record UDFs can be unit tested without a
+// server with UDFModule.
+//
+// SetPartitionUnavailable and SetResponseDelay inject failures to test retries and timeouts.
+//
+// Example:
+//
+//  server, err := aerospiketest.NewServer("test")
+//  if err != nil {
+//  	t.Fatal(err)
+//  }
+//  defer server.Close()
+//
+//  client, err := as.NewClientWithPolicyAndHost(nil, server.Host())
+//
+// FakeClient implements as.ClientIfc without any network, for application code that
+// depends on the interface instead of *as.Client.
+package aerospiketest
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+	"time"
+
+	as "github.com/aerospike/aerospike-client-go"
+	. "github.com/aerospike/aerospike-client-go/types"
+)
+
+const (
+	_PROTO_VERSION = 2
+	_PROTO_HEADER  = 8
+
+	_MSG_TYPE_INFO = 1
+	_MSG_TYPE_AS   = 3
+
+	// maximum accepted size of a request
+	_MAX_REQUEST_SIZE = 10 * 1024 * 1024
+)
+
+// Server is an in-process, single node Aerospike server backed by an in-memory store.
+// All partitions of its namespaces are owned by the node.
+type Server struct {
+	listener net.Listener
+	nodeName string
+	store    *store
+
+	// ClusterName is returned by the cluster-name info command.
+	ClusterName string
+
+	mutex  sync.Mutex
+	conns  map[net.Conn]struct{}
+	closed bool
+	wg     sync.WaitGroup
+
+	// unavailable counts the partition scans each partition is reported unavailable to
+	unavailable map[int]int
+
+	// delay postpones the responses to record commands, after they were applied
+	delay time.Duration
+}
+
+// NewServer starts a server on a random loopback port, serving the given namespaces.
+// If no namespace is given, the server serves the "test" namespace.
+func NewServer(namespaces ...string) (*Server, error) {
+	if len(namespaces) == 0 {
+		namespaces = []string{"test"}
+	}
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, err
+	}
+
+	srv := &Server{
+		listener:    listener,
+		store:       newStore(namespaces),
+		conns:       map[net.Conn]struct{}{},
+		unavailable: map[int]int{},
+	}
+	srv.nodeName = fmt.Sprintf("BB9%013X", srv.Port())
+
+	srv.wg.Add(1)
+	go srv.serve()
+
+	return srv, nil
+}
+
+// Host returns the address the server listens on.
+func (srv *Server) Host() *as.Host {
+	return as.NewHost("127.0.0.1", srv.Port())
+}
+
+// Port returns the port the server listens on.
+func (srv *Server) Port() int {
+	return srv.listener.Addr().(*net.TCPAddr).Port
+}
+
+// NodeName returns the name of the server node.
+func (srv *Server) NodeName() string {
+	return srv.nodeName
+}
+
+// Namespaces returns the namespaces the server serves.
+func (srv *Server) Namespaces() []string {
+	return append([]string(nil), srv.store.namespaces...)
+}
+
+// RecordCount returns the number of records in the namespace.
+func (srv *Server) RecordCount(namespace string) int {
+	return srv.store.count(namespace)
+}
+
+// SetPartitionUnavailable makes the next partition scans of the partition report it
+// as unavailable, as if it were migrating, for the given number of times.
+func (srv *Server) SetPartitionUnavailable(partitionId, times int) {
+	srv.mutex.Lock()
+	srv.unavailable[partitionId] = times
+	srv.mutex.Unlock()
+}
+
+// partitionAvailable returns false if the partition is reported unavailable to this scan.
+func (srv *Server) partitionAvailable(partitionId int) bool {
+	srv.mutex.Lock()
+	defer srv.mutex.Unlock()
+
+	if srv.unavailable[partitionId] > 0 {
+		srv.unavailable[partitionId]--
+		return false
+	}
+	return true
+}
+
+// SetResponseDelay postpones the responses to record commands by d, after the
+// commands were applied, as if the node were slow. Zero disables the delay.
+func (srv *Server) SetResponseDelay(d time.Duration) {
+	srv.mutex.Lock()
+	srv.delay = d
+	srv.mutex.Unlock()
+}
+
+// responseDelay returns the delay set by SetResponseDelay.
+func (srv *Server) responseDelay() time.Duration {
+	srv.mutex.Lock()
+	defer srv.mutex.Unlock()
+	return srv.delay
+}
+
+// Clear removes all records from the server.
+func (srv *Server) Clear() {
+	srv.store.clear()
+}
+
+// Close stops the server and closes all client connections.
+func (srv *Server) Close() error {
+	srv.mutex.Lock()
+	if srv.closed {
+		srv.mutex.Unlock()
+		return nil
+	}
+	srv.closed = true
+	err := srv.listener.Close()
+	for conn := range srv.conns {
+		conn.Close()
+	}
+	srv.mutex.Unlock()
+
+	srv.wg.Wait()
+	return err
+}
+
+func (srv *Server) serve() {
+	defer srv.wg.Done()
+
+	for {
+		conn, err := srv.listener.Accept()
+		if err != nil {
+			return
+		}
+
+		srv.mutex.Lock()
+		if srv.closed {
+			srv.mutex.Unlock()
+			conn.Close()
+			return
+		}
+		srv.conns[conn] = struct{}{}
+		srv.wg.Add(1)
+		srv.mutex.Unlock()
+
+		go srv.handle(conn)
+	}
+}
+
+// handle serves the requests of a connection until it is closed.
+func (srv *Server) handle(conn net.Conn) {
+	defer func() {
+		conn.Close()
+		srv.mutex.Lock()
+		delete(srv.conns, conn)
+		srv.mutex.Unlock()
+		srv.wg.Done()
+	}()
+
+	header := make([]byte, _PROTO_HEADER)
+	for {
+		if _, err := io.ReadFull(conn, header); err != nil {
+			return
+		}
+
+		proto := binary.BigEndian.Uint64(header)
+		version, msgType, size := byte(proto>>56), byte(proto>>48), int64(proto&0xFFFFFFFFFFFF)
+		if version != _PROTO_VERSION || size > _MAX_REQUEST_SIZE {
+			return
+		}
+
+		body := make([]byte, size)
+		if _, err := io.ReadFull(conn, body); err != nil {
+			return
+		}
+
+		var response []byte
+		switch msgType {
+		case _MSG_TYPE_INFO:
+			response = frame(_MSG_TYPE_INFO, srv.info(body))
+		case _MSG_TYPE_AS:
+			response = srv.message(body)
+			if delay := srv.responseDelay(); delay > 0 {
+				time.Sleep(delay)
+			}
+		default:
+			return
+		}
+
+		if _, err := conn.Write(response); err != nil {
+			return
+		}
+	}
+}
+
+// frame prepends the protocol header to the message.
+func frame(msgType byte, data []byte) []byte {
+	res := make([]byte, _PROTO_HEADER, _PROTO_HEADER+len(data))
+	binary.BigEndian.PutUint64(res, uint64(_PROTO_VERSION)<<56|uint64(msgType)<<48|uint64(len(data)))
+	return append(res, data...)
+}
+
+// errorResponse returns a single message response with the result code.
+func errorResponse(resultCode ResultCode, last bool) []byte {
+	w := &msgWriter{}
+	info3 := byte(0)
+	if last {
+		info3 = _INFO3_LAST
+	}
+	w.writeMessage(resultCode, info3, 0, 0, 0, nil, nil)
+	return w.bytes()
+}
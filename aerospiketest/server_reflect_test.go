@@ -0,0 +1,167 @@
+// +build !as_performance
+
+// Copyright 2013-2017 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aerospiketest_test
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	as "github.com/aerospike/aerospike-client-go"
+	"github.com/aerospike/aerospike-client-go/aerospiketest"
+	. "github.com/aerospike/aerospike-client-go/types"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Test Server", func() {
+
+	var server *aerospiketest.Server
+	var client *as.Client
+	var ns = "test"
+	var set = "mock"
+
+	BeforeEach(func() {
+		var err error
+		server, err = aerospiketest.NewServer(ns)
+		Expect(err).ToNot(HaveOccurred())
+
+		client, err = as.NewClientWithPolicyAndHost(nil, server.Host())
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		client.Close()
+		Expect(server.Close()).ToNot(HaveOccurred())
+	})
+
+	Context("object mapping", func() {
+
+		newUser := func(i int) *mappedUser {
+			return &mappedUser{
+				Name:     fmt.Sprintf("user%d", i),
+				Age:      20 + i,
+				Score:    1.5,
+				Active:   true,
+				Status:   mappedStatus(2),
+				Avatar:   []byte{1, 2, 3},
+				Created:  time.Unix(0, 1500000000123456789),
+				Timeout:  3 * time.Second,
+				Address:  mappedAddress{Street: "main", Zip: 1000 + i},
+				Previous: &mappedAddress{Street: "old", Zip: 99},
+				Tags:     []string{"a", "b"},
+				Counts:   map[string]int{"x": 1, "y": 2},
+				History:  []mappedAddress{{Street: "first", Zip: 1}},
+				Coords:   [2]int{3, 4},
+				Extra:    "extra",
+				Secret:   "not stored",
+			}
+		}
+
+		It("must put and get objects with generated code", func() {
+			key, _ := as.NewKey(ns, set, "user")
+			user := newUser(1)
+			Expect(client.PutObject(nil, key, user)).ToNot(HaveOccurred())
+
+			rec, err := client.Get(nil, key)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(rec.Bins).ToNot(HaveKey("email"))
+			Expect(rec.Bins).ToNot(HaveKey("Secret"))
+			Expect(rec.Bins["active"]).To(Equal(1))
+			Expect(rec.Bins["addr"]).To(Equal(map[interface{}]interface{}{"street": "main", "zip": 1001}))
+
+			var res mappedUser
+			Expect(client.GetObject(nil, key, &res)).ToNot(HaveOccurred())
+			Expect(res.Gen).To(Equal(uint32(1)))
+			user.Secret, user.Gen, user.TTL = "", res.Gen, res.TTL
+			Expect(&res).To(Equal(user))
+
+			missing, _ := as.NewKey(ns, set, "missing")
+			err = client.GetObject(nil, missing, &res)
+			Expect(err).To(HaveOccurred())
+			Expect(err.(AerospikeError).ResultCode()).To(Equal(KEY_NOT_FOUND_ERROR))
+		})
+
+		It("must read and write the same records as reflection", func() {
+			key, _ := as.NewKey(ns, set, "user")
+			user := newUser(1)
+			user.Email = "user@example.com"
+			user.Secret = ""
+			Expect(client.PutObject(nil, key, user)).ToNot(HaveOccurred())
+
+			var reflected reflectedUser
+			Expect(client.GetObject(nil, key, &reflected)).ToNot(HaveOccurred())
+			Expect(reflected.Name).To(Equal(user.Name))
+			Expect(reflected.Email).To(Equal(user.Email))
+			Expect(reflected.Active).To(BeTrue())
+			Expect(reflected.Created.Equal(user.Created)).To(BeTrue())
+			Expect(reflected.Address).To(Equal(user.Address))
+			Expect(reflected.Counts).To(Equal(user.Counts))
+			Expect(reflected.Coords).To(Equal(user.Coords))
+
+			key2, _ := as.NewKey(ns, set, "user2")
+			Expect(client.PutObject(nil, key2, &reflected)).ToNot(HaveOccurred())
+
+			var res mappedUser
+			Expect(client.GetObject(nil, key2, &res)).ToNot(HaveOccurred())
+			Expect(res.Created.Equal(user.Created)).To(BeTrue())
+			res.Created, user.Created = time.Time{}, time.Time{}
+			user.Gen, user.TTL = res.Gen, res.TTL
+			Expect(&res).To(Equal(user))
+		})
+
+		It("must batch get and scan objects with generated code", func() {
+			keys := make([]*as.Key, 10)
+			for i := range keys {
+				keys[i], _ = as.NewKey(ns, set, i)
+				if i < len(keys)-1 {
+					Expect(client.PutObject(nil, keys[i], newUser(i))).ToNot(HaveOccurred())
+				}
+			}
+
+			objects := make([]interface{}, len(keys))
+			for i := range objects {
+				objects[i] = &mappedUser{}
+			}
+			found, err := client.BatchGetObjects(nil, keys, objects)
+			Expect(err).ToNot(HaveOccurred())
+			for i := range keys[:len(keys)-1] {
+				Expect(found[i]).To(BeTrue())
+				Expect(objects[i].(*mappedUser).Address.Zip).To(Equal(1000 + i))
+			}
+			Expect(found[len(keys)-1]).To(BeFalse())
+
+			objChan := make(chan *mappedUser, 10)
+			recordset, err := client.ScanAllObjects(nil, objChan, ns, set)
+			Expect(err).ToNot(HaveOccurred())
+
+			var zips []int
+			for user := range objChan {
+				Expect(user.Gen).To(Equal(uint32(1)))
+				zips = append(zips, user.Address.Zip)
+			}
+			for err := range recordset.Errors {
+				Expect(err).ToNot(HaveOccurred())
+			}
+
+			sort.Ints(zips)
+			Expect(len(zips)).To(Equal(len(keys) - 1))
+			Expect(zips[0]).To(Equal(1000))
+		})
+	})
+})
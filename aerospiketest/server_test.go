@@ -0,0 +1,814 @@
+// Copyright 2013-2017 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aerospiketest_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	as "github.com/aerospike/aerospike-client-go"
+	"github.com/aerospike/aerospike-client-go/aerospiketest"
+	. "github.com/aerospike/aerospike-client-go/types"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Test Server", func() {
+
+	var server *aerospiketest.Server
+	var client *as.Client
+	var ns = "test"
+	var set = "mock"
+
+	BeforeEach(func() {
+		var err error
+		server, err = aerospiketest.NewServer(ns)
+		Expect(err).ToNot(HaveOccurred())
+
+		client, err = as.NewClientWithPolicyAndHost(nil, server.Host())
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		client.Close()
+		Expect(server.Close()).ToNot(HaveOccurred())
+	})
+
+	It("must be discovered as a single node cluster", func() {
+		Expect(client.IsConnected()).To(BeTrue())
+
+		nodes := client.GetNodes()
+		Expect(len(nodes)).To(Equal(1))
+		Expect(nodes[0].GetName()).To(Equal(server.NodeName()))
+		Expect(server.Namespaces()).To(Equal([]string{ns}))
+	})
+
+	It("must answer info commands", func() {
+		info, err := as.RequestNodeInfo(client.GetNodes()[0], "node", "partitions", "namespaces")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(info).To(Equal(map[string]string{
+			"node":       server.NodeName(),
+			"partitions": "4096",
+			"namespaces": ns,
+		}))
+	})
+
+	Context("single record commands", func() {
+
+		var key *as.Key
+
+		BeforeEach(func() {
+			var err error
+			key, err = as.NewKey(ns, set, "key")
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("must put, get and delete records", func() {
+			bins := as.BinMap{"int": 1, "float": 1.5, "str": "a", "blob": []byte{1, 2}, "list": []interface{}{1, "b"}}
+			Expect(client.Put(nil, key, bins)).ToNot(HaveOccurred())
+			Expect(server.RecordCount(ns)).To(Equal(1))
+
+			rec, err := client.Get(nil, key)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(rec.Generation).To(Equal(uint32(1)))
+			Expect(rec.Bins).To(Equal(bins))
+
+			rec, err = client.Get(nil, key, "int", "missing")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(rec.Bins).To(Equal(as.BinMap{"int": 1}))
+
+			rec, err = client.GetHeader(nil, key)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(rec.Generation).To(Equal(uint32(1)))
+			Expect(rec.Bins).To(BeEmpty())
+
+			exists, err := client.Exists(nil, key)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(exists).To(BeTrue())
+
+			existed, err := client.Delete(nil, key)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(existed).To(BeTrue())
+
+			existed, err = client.Delete(nil, key)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(existed).To(BeFalse())
+
+			rec, err = client.Get(nil, key)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(rec).To(BeNil())
+		})
+
+		It("must delete bins set to nil and the record without bins", func() {
+			Expect(client.Put(nil, key, as.BinMap{"a": 1, "b": 2})).ToNot(HaveOccurred())
+			Expect(client.PutBins(nil, key, as.NewBin("a", nil))).ToNot(HaveOccurred())
+
+			rec, err := client.Get(nil, key)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(rec.Bins).To(Equal(as.BinMap{"b": 2}))
+
+			Expect(client.PutBins(nil, key, as.NewBin("b", nil))).ToNot(HaveOccurred())
+			exists, err := client.Exists(nil, key)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(exists).To(BeFalse())
+		})
+
+		It("must add, append, prepend and touch", func() {
+			Expect(client.Put(nil, key, as.BinMap{"i": 1, "f": 1.5, "s": "b"})).ToNot(HaveOccurred())
+
+			Expect(client.AddBins(nil, key, as.NewBin("i", 2), as.NewBin("f", 1.0))).ToNot(HaveOccurred())
+			Expect(client.AppendBins(nil, key, as.NewBin("s", "c"))).ToNot(HaveOccurred())
+			Expect(client.PrependBins(nil, key, as.NewBin("s", "a"))).ToNot(HaveOccurred())
+			Expect(client.Touch(nil, key)).ToNot(HaveOccurred())
+
+			rec, err := client.Get(nil, key)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(rec.Generation).To(Equal(uint32(5)))
+			Expect(rec.Bins).To(Equal(as.BinMap{"i": 3, "f": 2.5, "s": "abc"}))
+
+			err = client.AddBins(nil, key, as.NewBin("s", 1))
+			Expect(err).To(HaveOccurred())
+			Expect(err.(AerospikeError).ResultCode()).To(Equal(BIN_TYPE_ERROR))
+
+			missing, _ := as.NewKey(ns, set, "missing")
+			err = client.Touch(nil, missing)
+			Expect(err).To(HaveOccurred())
+			Expect(err.(AerospikeError).ResultCode()).To(Equal(KEY_NOT_FOUND_ERROR))
+		})
+
+		It("must operate on a record", func() {
+			Expect(client.Put(nil, key, as.BinMap{"i": 1, "s": "a"})).ToNot(HaveOccurred())
+
+			rec, err := client.Operate(nil, key,
+				as.AddOp(as.NewBin("i", 4)),
+				as.AppendOp(as.NewBin("s", "b")),
+				as.GetOp(),
+			)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(rec.Generation).To(Equal(uint32(2)))
+			Expect(rec.Bins).To(Equal(as.BinMap{"i": 5, "s": "ab"}))
+
+			rec, err = client.Operate(nil, key, as.GetOpForBin("i"))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(rec.Bins).To(Equal(as.BinMap{"i": 5}))
+		})
+
+		It("must enforce record exists actions and generations", func() {
+			policy := as.NewWritePolicy(0, 0)
+			policy.RecordExistsAction = as.UPDATE_ONLY
+			err := client.PutBins(policy, key, as.NewBin("a", 1))
+			Expect(err.(AerospikeError).ResultCode()).To(Equal(KEY_NOT_FOUND_ERROR))
+
+			policy.RecordExistsAction = as.CREATE_ONLY
+			Expect(client.PutBins(policy, key, as.NewBin("a", 1))).ToNot(HaveOccurred())
+			err = client.PutBins(policy, key, as.NewBin("a", 1))
+			Expect(err.(AerospikeError).ResultCode()).To(Equal(KEY_EXISTS_ERROR))
+
+			policy.RecordExistsAction = as.REPLACE
+			Expect(client.PutBins(policy, key, as.NewBin("b", 2))).ToNot(HaveOccurred())
+			rec, err := client.Get(nil, key)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(rec.Bins).To(Equal(as.BinMap{"b": 2}))
+
+			policy = as.NewWritePolicy(1, 0)
+			policy.GenerationPolicy = as.EXPECT_GEN_EQUAL
+			err = client.PutBins(policy, key, as.NewBin("b", 3))
+			Expect(err.(AerospikeError).ResultCode()).To(Equal(GENERATION_ERROR))
+
+			policy.Generation = 2
+			Expect(client.PutBins(policy, key, as.NewBin("b", 3))).ToNot(HaveOccurred())
+
+			err = client.PutBins(nil, key, as.NewBin("a_very_long_bin_name", 1))
+			Expect(err.(AerospikeError).ResultCode()).To(Equal(BIN_NAME_TOO_LONG))
+		})
+
+		It("must describe the command in errors", func() {
+			policy := as.NewWritePolicy(0, 0)
+			policy.RecordExistsAction = as.CREATE_ONLY
+			Expect(client.PutBins(policy, key, as.NewBin("a", 1))).ToNot(HaveOccurred())
+
+			err := client.PutBins(policy, key, as.NewBin("a", 1)).(AerospikeError)
+			Expect(err.Is(ErrKeyExists)).To(BeTrue())
+			Expect(err.Node()).To(Equal(client.GetNodes()[0].String()))
+			Expect(err.Namespace()).To(Equal(ns))
+			Expect(err.Digest()).To(Equal(key.Digest()))
+			Expect(err.InDoubt()).To(BeFalse())
+			Expect(len(err.Attempts())).To(Equal(1))
+
+			// reads are retried, while writes could be in doubt
+			Expect(server.Close()).ToNot(HaveOccurred())
+			rpolicy := as.NewPolicy()
+			rpolicy.MaxRetries = 1
+			_, gerr := client.Get(rpolicy, key)
+			err = gerr.(AerospikeError)
+			Expect(err.Is(ErrTimeout)).To(BeTrue())
+			Expect(err.Unwrap()).ToNot(BeNil())
+			Expect(len(err.Attempts())).To(Equal(2))
+		})
+
+		It("must not resend writes that timed out after they were sent", func() {
+			server.SetResponseDelay(100 * time.Millisecond)
+			policy := as.NewWritePolicy(0, 0)
+			policy.SocketTimeout = 20 * time.Millisecond
+			policy.Timeout = time.Second
+
+			err := client.AddBins(policy, key, as.NewBin("a", 1)).(AerospikeError)
+			Expect(err.InDoubt()).To(BeTrue())
+			Expect(len(err.Attempts())).To(Equal(1))
+
+			// a retry policy can opt in to resend in doubt writes
+			policy.RetryPolicy = &as.ExponentialBackoff{MaxRetries: 1, BaseDelay: time.Millisecond, RetryInDoubt: true}
+			err = client.AddBins(policy, key, as.NewBin("a", 1)).(AerospikeError)
+			Expect(err.InDoubt()).To(BeTrue())
+			Expect(len(err.Attempts())).To(Equal(2))
+
+			server.SetResponseDelay(0)
+			time.Sleep(200 * time.Millisecond)
+			rec, err2 := client.Get(nil, key)
+			Expect(err2).ToNot(HaveOccurred())
+			Expect(rec.Bins["a"]).To(Equal(3))
+		})
+
+		It("must report writes cancelled after they were sent as in doubt", func() {
+			server.SetResponseDelay(200 * time.Millisecond)
+			ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+			defer cancel()
+
+			err := client.WithContext(ctx).AddBins(nil, key, as.NewBin("a", 1))
+			Expect(errors.Is(err, context.DeadlineExceeded)).To(BeTrue())
+			aerr := err.(AerospikeError)
+			Expect(aerr.ResultCode()).To(Equal(TIMEOUT))
+			Expect(aerr.InDoubt()).To(BeTrue())
+			Expect(aerr.Node()).ToNot(BeEmpty())
+			Expect(len(aerr.Attempts())).To(Equal(1))
+
+			// reads are never in doubt
+			_, err = client.WithContext(ctx).Get(nil, key)
+			Expect(errors.Is(err, context.DeadlineExceeded)).To(BeTrue())
+			Expect(err.(AerospikeError).InDoubt()).To(BeFalse())
+		})
+
+		It("must expire records", func() {
+			policy := as.NewWritePolicy(0, 100)
+			Expect(client.PutBins(policy, key, as.NewBin("a", 1))).ToNot(HaveOccurred())
+
+			rec, err := client.GetHeader(nil, key)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(rec.Expiration).To(BeNumerically("~", 100, 2))
+
+			Expect(client.PutBins(nil, key, as.NewBin("a", 1))).ToNot(HaveOccurred())
+			rec, err = client.GetHeader(nil, key)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(rec.Expiration).To(Equal(uint32(as.TTLDontExpire)))
+		})
+	})
+
+	Context("multi record commands", func() {
+
+		const count = 50
+		var keys []*as.Key
+
+		BeforeEach(func() {
+			keys = make([]*as.Key, count+1)
+			policy := as.NewWritePolicy(0, 0)
+			policy.SendKey = true
+			for i := 0; i < count; i++ {
+				key, err := as.NewKey(ns, set, i)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(client.PutBins(policy, key, as.NewBin("i", i), as.NewBin("s", "v"))).ToNot(HaveOccurred())
+				keys[i] = key
+			}
+
+			// a key which does not exist
+			keys[count], _ = as.NewKey(ns, set, count)
+		})
+
+		It("must batch get and exists", func() {
+			records, err := client.BatchGet(nil, keys)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(len(records)).To(Equal(count + 1))
+			for i := 0; i < count; i++ {
+				Expect(records[i].Bins).To(Equal(as.BinMap{"i": i, "s": "v"}))
+			}
+			Expect(records[count]).To(BeNil())
+
+			records, err = client.BatchGet(nil, keys, "i")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(records[1].Bins).To(Equal(as.BinMap{"i": 1}))
+
+			exists, err := client.BatchExists(nil, keys)
+			Expect(err).ToNot(HaveOccurred())
+			for i := 0; i < count; i++ {
+				Expect(exists[i]).To(BeTrue())
+			}
+			Expect(exists[count]).To(BeFalse())
+		})
+
+		It("must batch operate on records", func() {
+			records := []as.BatchRecordIfc{
+				as.NewBatchPut(nil, keys[0], as.NewBin("s", "w")),
+				as.NewBatchWrite(nil, keys[1], as.AddOp(as.NewBin("i", 10)), as.GetOp()),
+				as.NewBatchDelete(nil, keys[2]),
+				as.NewBatchDelete(nil, keys[count]),
+				as.NewBatchUDF(nil, keys[3], "pkg", "fn"),
+			}
+			Expect(client.BatchOperate(nil, records)).ToNot(HaveOccurred())
+
+			Expect(records[0].BatchRec().ResultCode).To(Equal(OK))
+			Expect(records[1].BatchRec().Record.Bins).To(Equal(as.BinMap{"i": 11, "s": "v"}))
+			Expect(records[2].BatchRec().ResultCode).To(Equal(OK))
+			Expect(records[3].BatchRec().ResultCode).To(Equal(KEY_NOT_FOUND_ERROR))
+			Expect(records[4].BatchRec().ResultCode).To(Equal(UNSUPPORTED_FEATURE))
+			Expect(records[4].BatchRec().Err).To(HaveOccurred())
+
+			rec, err := client.Get(nil, keys[0])
+			Expect(err).ToNot(HaveOccurred())
+			Expect(rec.Bins).To(Equal(as.BinMap{"i": 0, "s": "w"}))
+			Expect(server.RecordCount(ns)).To(Equal(count - 1))
+		})
+
+		It("must batch get with the legacy protocol", func() {
+			policy := as.NewBatchPolicy()
+			policy.UseBatchDirect = true
+
+			records, err := client.BatchGet(policy, keys)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(records[2].Bins).To(Equal(as.BinMap{"i": 2, "s": "v"}))
+			Expect(records[count]).To(BeNil())
+		})
+
+		It("must scan records with their keys", func() {
+			recordset, err := client.ScanAll(nil, ns, set, "i")
+			Expect(err).ToNot(HaveOccurred())
+
+			var values []int
+			for res := range recordset.Results() {
+				Expect(res.Err).ToNot(HaveOccurred())
+				Expect(res.Record.Key.Value().GetObject()).To(BeEquivalentTo(res.Record.Bins["i"]))
+				Expect(res.Record.Bins).ToNot(HaveKey("s"))
+				values = append(values, res.Record.Bins["i"].(int))
+			}
+
+			sort.Ints(values)
+			Expect(len(values)).To(Equal(count))
+			for i := range values {
+				Expect(values[i]).To(Equal(i))
+			}
+		})
+
+		It("must aggregate query records with Go pipelines", func() {
+			bin := func(v interface{}) interface{} { return v.(*as.Record).Bins["i"] }
+
+			res, err := client.Aggregate(nil, as.NewStatement(ns, set, "i"), as.NewAggregation().Map(bin).Sum())
+			Expect(err).ToNot(HaveOccurred())
+			Expect(res).To(Equal(int64(count * (count - 1) / 2)))
+
+			res, err = client.Aggregate(nil, as.NewStatement(ns, set), as.NewAggregation().
+				Map(bin).
+				GroupBy(
+					func(v interface{}) interface{} { return v.(int) % 2 },
+					as.NewAggregation().Filter(func(v interface{}) bool { return v.(int) < 10 }).Count(),
+				))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(res).To(Equal(map[interface{}]interface{}{0: int64(5), 1: int64(5)}))
+
+			res, err = client.Aggregate(nil, as.NewStatement(ns, set), as.NewAggregation().
+				Map(bin).
+				TopK(3, func(a, b interface{}) bool { return a.(int) < b.(int) }))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(res).To(Equal([]interface{}{count - 1, count - 2, count - 3}))
+
+			_, err = client.Aggregate(nil, as.NewStatement(ns, set), as.NewAggregation().Sum())
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("must scan partitions, retry unavailable partitions and resume from a cursor", func() {
+			scan := func(policy *as.ScanPolicy, filter *as.PartitionFilter) ([]int, error) {
+				recordset, err := client.ScanPartitions(policy, filter, ns, set)
+				Expect(err).ToNot(HaveOccurred())
+
+				var values []int
+				for res := range recordset.Results() {
+					if res.Err != nil {
+						return values, res.Err
+					}
+					values = append(values, res.Record.Bins["i"].(int))
+				}
+				return values, nil
+			}
+
+			_, err := client.ScanPartitions(nil, nil, ns, set)
+			Expect(err.(AerospikeError).ResultCode()).To(Equal(PARAMETER_ERROR))
+			_, err = client.QueryPartitions(nil, as.NewStatement(ns, set), nil)
+			Expect(err.(AerospikeError).ResultCode()).To(Equal(PARAMETER_ERROR))
+
+			filter := as.NewPartitionFilterAll()
+			values, err := scan(nil, filter)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(len(values)).To(Equal(count))
+			Expect(filter.IsDone()).To(BeTrue())
+
+			// split the scan between workers, which receive their part as a cursor
+			values = nil
+			for _, part := range as.NewPartitionFilterAll().Split(3) {
+				cursor, err := part.MarshalBinary()
+				Expect(err).ToNot(HaveOccurred())
+
+				part, err = as.NewPartitionFilterFromCursor(cursor)
+				Expect(err).ToNot(HaveOccurred())
+
+				res, err := scan(nil, part)
+				Expect(err).ToNot(HaveOccurred())
+				values = append(values, res...)
+			}
+			sort.Ints(values)
+			Expect(len(values)).To(Equal(count))
+			for i := range values {
+				Expect(values[i]).To(Equal(i))
+			}
+
+			// partitions that are unavailable are retried
+			partitionId := as.NewPartitionByKey(keys[0]).PartitionId
+			server.SetPartitionUnavailable(partitionId, 1)
+			values, err = scan(nil, as.NewPartitionFilterAll())
+			Expect(err).ToNot(HaveOccurred())
+			Expect(len(values)).To(Equal(count))
+
+			// the scan fails when the retries are exhausted, and resumes from its cursor
+			policy := as.NewScanPolicy()
+			policy.MaxRetries = 1
+			server.SetPartitionUnavailable(partitionId, 2)
+			filter = as.NewPartitionFilterAll()
+			values, err = scan(policy, filter)
+			Expect(err).To(HaveOccurred())
+			Expect(err.(AerospikeError).ResultCode()).To(Equal(PARTITION_UNAVAILABLE))
+			Expect(values).ToNot(ContainElement(0))
+			Expect(filter.IsDone()).To(BeFalse())
+
+			cursor, err := filter.MarshalBinary()
+			Expect(err).ToNot(HaveOccurred())
+			filter, err = as.NewPartitionFilterFromCursor(cursor)
+			Expect(err).ToNot(HaveOccurred())
+
+			resumed, err := scan(policy, filter)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(resumed).To(ContainElement(0))
+			Expect(len(values) + len(resumed)).To(Equal(count))
+			Expect(filter.IsDone()).To(BeTrue())
+		})
+
+		It("must limit scans and read them in pages", func() {
+			policy := as.NewScanPolicy()
+			policy.MaxRecords = 10
+			policy.RecordsPerSecond = 1000
+			recordset, err := client.ScanAll(policy, ns, set)
+			Expect(err).ToNot(HaveOccurred())
+			n := 0
+			for res := range recordset.Results() {
+				Expect(res.Err).ToNot(HaveOccurred())
+				n++
+			}
+			Expect(n).To(Equal(10))
+
+			var values []int
+			token := ""
+			for pages := 0; ; pages++ {
+				Expect(pages).To(BeNumerically("<=", count/7+1))
+
+				page, err := client.ScanPage(nil, token, 7, ns, set)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(len(page.Records)).To(BeNumerically("<=", 7))
+				for _, rec := range page.Records {
+					values = append(values, rec.Bins["i"].(int))
+				}
+
+				if token = page.Token; token == "" {
+					break
+				}
+			}
+			sort.Ints(values)
+			Expect(len(values)).To(Equal(count))
+			for i := range values {
+				Expect(values[i]).To(Equal(i))
+			}
+
+			stmt := as.NewStatement(ns, set)
+			page, err := client.QueryPage(nil, stmt, "", 20)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(len(page.Records)).To(Equal(20))
+			Expect(page.Token).ToNot(BeEmpty())
+
+			page, err = client.QueryPage(nil, stmt, page.Token, count)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(len(page.Records)).To(Equal(count - 20))
+
+			_, err = client.ScanPage(nil, "not a token", 10, ns, set)
+			Expect(err).To(HaveOccurred())
+			Expect(err.(AerospikeError).ResultCode()).To(Equal(PARAMETER_ERROR))
+		})
+
+		It("must serve async commands", func() {
+			policy := as.NewClientPolicy()
+			policy.AsyncMaxCommandsPerNode = 2
+			aclient, err := as.NewClientWithPolicyAndHost(policy, server.Host())
+			Expect(err).ToNot(HaveOccurred())
+			defer aclient.Close()
+
+			futures := make([]*as.RecordFuture, count)
+			for i := range futures {
+				futures[i] = aclient.GetAsync(nil, keys[i])
+			}
+			for i := range futures {
+				rec, err := futures[i].Result()
+				Expect(err).ToNot(HaveOccurred())
+				Expect(rec.Bins["i"]).To(Equal(i))
+			}
+
+			records, err := aclient.BatchGetAsync(nil, keys).Result()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(records[count]).To(BeNil())
+		})
+
+		It("must bound batch operate by the timeout of the batch", func() {
+			server.SetResponseDelay(100 * time.Millisecond)
+			defer server.SetResponseDelay(0)
+
+			records := make([]as.BatchRecordIfc, 5)
+			for i := range records {
+				records[i] = as.NewBatchPut(nil, keys[i], as.NewBin("s", "w"))
+			}
+
+			policy := as.NewBatchPolicy()
+			policy.Timeout = 250 * time.Millisecond
+			start := time.Now()
+			Expect(client.BatchOperate(policy, records)).ToNot(HaveOccurred())
+			Expect(time.Since(start)).To(BeNumerically("<", 450*time.Millisecond))
+
+			Expect(records[0].BatchRec().ResultCode).To(Equal(OK))
+			last := records[len(records)-1].BatchRec()
+			Expect(last.ResultCode).To(Equal(TIMEOUT))
+			Expect(last.InDoubt).To(BeFalse())
+		})
+
+		It("must serve pipelined commands", func() {
+			policy := as.NewClientPolicy()
+			policy.PipelineDepth = 4
+			pclient, err := as.NewClientWithPolicyAndHost(policy, server.Host())
+			Expect(err).ToNot(HaveOccurred())
+			defer pclient.Close()
+
+			errs := make(chan error, count)
+			for i := range keys[:count] {
+				go func(i int) {
+					if err := pclient.PutBins(nil, keys[i], as.NewBin("i", i), as.NewBin("j", -i)); err != nil {
+						errs <- err
+						return
+					}
+					rec, err := pclient.Get(nil, keys[i])
+					if err == nil && (rec.Bins["i"] != i || rec.Bins["j"] != -i) {
+						err = fmt.Errorf("record %d mismatched: %v", i, rec.Bins)
+					}
+					errs <- err
+				}(i)
+			}
+			for range keys[:count] {
+				Expect(<-errs).ToNot(HaveOccurred())
+			}
+
+			rec, err := pclient.Get(nil, keys[count])
+			Expect(err).ToNot(HaveOccurred())
+			Expect(rec).To(BeNil())
+		})
+
+		It("must not replay pipelined writes that failed after they were sent", func() {
+			policy := as.NewClientPolicy()
+			policy.PipelineDepth = 4
+			pclient, err := as.NewClientWithPolicyAndHost(policy, server.Host())
+			Expect(err).ToNot(HaveOccurred())
+			defer pclient.Close()
+
+			server.SetResponseDelay(100 * time.Millisecond)
+			wpolicy := as.NewWritePolicy(0, 0)
+			wpolicy.SocketTimeout = 20 * time.Millisecond
+			wpolicy.Timeout = time.Second
+			wpolicy.RetryPolicy = &as.ExponentialBackoff{MaxRetries: 2, BaseDelay: time.Millisecond, RetryInDoubt: true}
+
+			aerr := pclient.AddBins(wpolicy, keys[0], as.NewBin("i", 1)).(AerospikeError)
+			Expect(aerr.ResultCode()).To(Equal(TIMEOUT))
+			Expect(aerr.InDoubt()).To(BeTrue())
+			Expect(len(aerr.Attempts())).To(Equal(1))
+		})
+
+		It("must truncate sets", func() {
+			Expect(client.Truncate(nil, ns, set, nil)).ToNot(HaveOccurred())
+			Expect(server.RecordCount(ns)).To(Equal(0))
+		})
+	})
+
+	Context("secondary indexes", func() {
+
+		def := func(name, bin string, indexType as.IndexType) *as.IndexDefinition {
+			return &as.IndexDefinition{Namespace: ns, SetName: set, Name: name, BinName: bin, Type: indexType}
+		}
+
+		It("must ensure, list and drop indexes", func() {
+			for i := 0; i < 3; i++ {
+				key, err := as.NewKey(ns, set, i)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(client.Put(nil, key, as.BinMap{"a": i})).ToNot(HaveOccurred())
+			}
+
+			task, err := client.EnsureIndex(nil, def("idx_a", "a", as.NUMERIC))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(<-task.OnComplete()).ToNot(HaveOccurred())
+
+			// idempotent
+			_, err = client.EnsureIndex(nil, def("idx_a", "a", as.NUMERIC))
+			Expect(err).ToNot(HaveOccurred())
+
+			indexes, err := client.ListIndexes(nil, ns)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(indexes).To(Equal([]*as.IndexInfo{{
+				Namespace:      ns,
+				SetName:        set,
+				Name:           "idx_a",
+				BinName:        "a",
+				Type:           as.NUMERIC,
+				CollectionType: as.ICT_DEFAULT,
+				State:          "RW",
+				LoadPercent:    100,
+				Entries:        3,
+			}}))
+			Expect(indexes[0].Ready()).To(BeTrue())
+
+			stats, err := client.GetIndexStats(nil, ns, "idx_a")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(stats.Entries).To(Equal(int64(3)))
+			Expect(stats.LoadPercent).To(Equal(100))
+			Expect(stats.MemoryUsed).To(BeNumerically(">", 0))
+			Expect(stats.Nodes).To(HaveKey(server.NodeName()))
+
+			Expect(client.DropIndex(nil, ns, set, "idx_a")).ToNot(HaveOccurred())
+			indexes, err = client.ListIndexes(nil, ns)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(indexes).To(BeEmpty())
+
+			_, err = client.GetIndexStats(nil, ns, "idx_a")
+			Expect(err).To(HaveOccurred())
+			Expect(err.(AerospikeError).ResultCode()).To(Equal(INDEX_NOTFOUND))
+
+			_, err = client.ListIndexes(nil, "unknown")
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("must migrate indexes", func() {
+			_, err := client.CreateComplexIndex(nil, ns, set, "idx_old", "old", as.STRING, as.ICT_DEFAULT)
+			Expect(err).ToNot(HaveOccurred())
+			_, err = client.CreateIndex(nil, ns, set, "idx_b", "b", as.STRING)
+			Expect(err).ToNot(HaveOccurred())
+
+			listB := def("idx_b", "b", as.NUMERIC)
+			listB.CollectionType = as.ICT_LIST
+
+			migration := &as.IndexMigration{
+				Indexes: []*as.IndexDefinition{def("idx_a", "a", as.NUMERIC), listB},
+				Drop:    []*as.IndexDefinition{{Namespace: ns, SetName: set, Name: "idx_old"}, {Namespace: ns, Name: "idx_missing"}},
+			}
+
+			changes, err := client.MigrateIndexes(nil, migration)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(changes).To(Equal([]*as.IndexChange{
+				{Action: as.IndexDropped, Index: migration.Drop[0]},
+				{Action: as.IndexCreated, Index: migration.Indexes[0]},
+				{Action: as.IndexRecreated, Index: listB},
+			}))
+
+			indexes, err := client.ListIndexes(nil, ns)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(len(indexes)).To(Equal(2))
+			Expect(indexes[0].Name).To(Equal("idx_a"))
+			Expect(indexes[1].Name).To(Equal("idx_b"))
+			Expect(indexes[1].Type).To(Equal(as.NUMERIC))
+			Expect(indexes[1].CollectionType).To(Equal(as.ICT_LIST))
+
+			// applying the migration again changes nothing
+			changes, err = client.MigrateIndexes(nil, migration)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(changes).To(BeEmpty())
+
+			_, err = client.MigrateIndexes(nil, &as.IndexMigration{Indexes: []*as.IndexDefinition{{Namespace: ns, Name: "idx_c"}}})
+			Expect(err).To(HaveOccurred())
+			Expect(err.(AerospikeError).ResultCode()).To(Equal(PARAMETER_ERROR))
+		})
+
+		It("must bound the wait for index builds", func() {
+			migration := &as.IndexMigration{
+				Indexes: []*as.IndexDefinition{{Namespace: ns, SetName: set, Name: "idx_a", BinName: "a", Type: as.STRING}},
+			}
+
+			policy := as.NewWritePolicy(0, 0)
+			policy.Timeout = 100 * time.Millisecond
+			start := time.Now()
+			_, err := client.MigrateIndexes(policy, migration)
+			Expect(err).To(HaveOccurred())
+			Expect(err.(AerospikeError).ResultCode()).To(Equal(TIMEOUT))
+			Expect(time.Since(start)).To(BeNumerically("<", 500*time.Millisecond))
+
+			ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+			defer cancel()
+			migration.Indexes[0].Name = "idx_b"
+			_, err = client.WithContext(ctx).MigrateIndexes(nil, migration)
+			Expect(errors.Is(err, context.DeadlineExceeded)).To(BeTrue())
+
+			// the indexes were created, and are left to the server to build
+			indexes, err := client.ListIndexes(nil, ns)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(len(indexes)).To(Equal(2))
+		})
+
+	})
+
+	Context("cluster introspection", func() {
+
+		It("must describe the cluster", func() {
+			info, err := client.GetClusterInfo(nil)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(info.Namespaces).To(Equal([]string{ns}))
+			Expect(len(info.Nodes)).To(Equal(1))
+			Expect(info.Nodes[0].Name).To(Equal(server.NodeName()))
+			Expect(info.Builds()).To(Equal([]string{"3.15.0.0"}))
+			Expect(info.HasFeature("batch-index")).To(BeTrue())
+			Expect(info.HasFeature("unknown")).To(BeFalse())
+
+			namespaces, err := client.ListNamespaces(nil)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(namespaces).To(Equal([]string{ns}))
+		})
+
+		It("must return the statistics of namespaces, sets and bins", func() {
+			for i := 0; i < 3; i++ {
+				key, err := as.NewKey(ns, set, i)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(client.Put(nil, key, as.BinMap{"a": i, "b": "x"})).ToNot(HaveOccurred())
+			}
+			key, err := as.NewKey(ns, "other", 0)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(client.Put(nil, key, as.BinMap{"c": 1})).ToNot(HaveOccurred())
+
+			nsInfo, err := client.GetNamespaceInfo(nil, ns)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(nsInfo.Objects).To(Equal(int64(4)))
+			Expect(nsInfo.MasterObjects).To(Equal(int64(4)))
+			Expect(nsInfo.ReplicationFactor).To(Equal(1))
+			Expect(nsInfo.MemoryUsed).To(BeNumerically(">", 0))
+			Expect(nsInfo.StopWrites).To(BeFalse())
+			Expect(nsInfo.Nodes).To(HaveKey(server.NodeName()))
+
+			_, err = client.GetNamespaceInfo(nil, "unknown")
+			Expect(err).To(HaveOccurred())
+			Expect(err.(AerospikeError).ResultCode()).To(Equal(INVALID_NAMESPACE))
+
+			sets, err := client.ListSets(nil, ns)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(len(sets)).To(Equal(2))
+			Expect(sets[0].Name).To(Equal(set))
+			Expect(sets[0].Namespace).To(Equal(ns))
+			Expect(sets[0].Objects).To(Equal(int64(3)))
+			Expect(sets[0].TruncateLUT.IsZero()).To(BeTrue())
+			Expect(sets[1].Name).To(Equal("other"))
+			Expect(sets[1].Objects).To(Equal(int64(1)))
+
+			bins, err := client.ListBins(nil, ns)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(bins).To(Equal([]string{"a", "b", "c"}))
+		})
+
+		It("must report the truncate cutoff of sets", func() {
+			cutoff := time.Now().Add(-time.Hour).Truncate(time.Millisecond)
+			Expect(client.Truncate(nil, ns, set, &cutoff)).ToNot(HaveOccurred())
+
+			sets, err := client.ListSets(nil, ns)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(len(sets)).To(Equal(1))
+			Expect(sets[0].Objects).To(Equal(int64(0)))
+			Expect(sets[0].TruncateLUT.Equal(cutoff)).To(BeTrue())
+		})
+
+	})
+})
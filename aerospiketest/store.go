@@ -0,0 +1,391 @@
+// Copyright 2013-2017 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aerospiketest
+
+import (
+	"bytes"
+	"sort"
+	"sync"
+	"time"
+
+	. "github.com/aerospike/aerospike-client-go/types"
+)
+
+// Special TTL values sent by the client.
+const (
+	_TTL_NAMESPACE_DEFAULT = 0
+	_TTL_DONT_UPDATE       = 0xFFFFFFFE
+	_TTL_DONT_EXPIRE       = 0xFFFFFFFF
+)
+
+type digest [20]byte
+
+// byDigest sorts digests in ascending order.
+type byDigest []digest
+
+func (d byDigest) Len() int           { return len(d) }
+func (d byDigest) Swap(i, j int)      { d[i], d[j] = d[j], d[i] }
+func (d byDigest) Less(i, j int) bool { return bytes.Compare(d[i][:], d[j][:]) < 0 }
+
+// particle is a bin value in its wire format.
+type particle struct {
+	particleType byte
+	value        []byte
+}
+
+// record is a stored record. Bins keep their insertion order.
+type record struct {
+	setName    string
+	key        []byte // particle type followed by the value, if the key was sent
+	binNames   []string
+	bins       map[string]particle
+	generation uint32
+	voidTime   uint32
+}
+
+func newRecord(setName string) *record {
+	return &record{setName: setName, bins: map[string]particle{}}
+}
+
+func (rec *record) clone() *record {
+	res := *rec
+	res.binNames = append([]string(nil), rec.binNames...)
+	res.bins = make(map[string]particle, len(rec.bins))
+	for name, p := range rec.bins {
+		res.bins[name] = p
+	}
+	return &res
+}
+
+func (rec *record) get(name string) (particle, bool) {
+	p, exists := rec.bins[name]
+	return p, exists
+}
+
+func (rec *record) put(name string, p particle) {
+	if _, exists := rec.bins[name]; !exists {
+		rec.binNames = append(rec.binNames, name)
+	}
+	rec.bins[name] = p
+}
+
+func (rec *record) remove(name string) {
+	if _, exists := rec.bins[name]; !exists {
+		return
+	}
+	delete(rec.bins, name)
+	for i := range rec.binNames {
+		if rec.binNames[i] == name {
+			rec.binNames = append(rec.binNames[:i], rec.binNames[i+1:]...)
+			break
+		}
+	}
+}
+
+func (rec *record) clearBins() {
+	rec.binNames = nil
+	rec.bins = map[string]particle{}
+}
+
+// size estimates the memory used by the record, in bytes.
+func (rec *record) size() int {
+	n := len(rec.key)
+	for name, p := range rec.bins {
+		n += len(name) + 1 + len(p.value)
+	}
+	return n
+}
+
+// expired returns true if the void time of the record has passed.
+func (rec *record) expired(now uint32) bool {
+	return rec.voidTime != 0 && rec.voidTime <= now
+}
+
+// setTTL sets the void time of the record from the TTL sent by the client.
+func (rec *record) setTTL(ttl uint32) {
+	switch ttl {
+	case _TTL_NAMESPACE_DEFAULT, _TTL_DONT_EXPIRE:
+		rec.voidTime = 0
+	case _TTL_DONT_UPDATE:
+	default:
+		rec.voidTime = citrusleafNow() + ttl
+	}
+}
+
+// citrusleafNow returns the current time in seconds since the Citrusleaf epoch.
+func citrusleafNow() uint32 {
+	return uint32(time.Now().Unix() - CITRUSLEAF_EPOCH)
+}
+
+// store keeps the records of all namespaces in memory.
+type store struct {
+	namespaces []string
+
+	mutex   sync.RWMutex
+	records map[string]map[digest]*record
+
+	// indexes holds the secondary indexes of each namespace, by name
+	indexes map[string]map[string]*sindex
+
+	// truncated holds the truncate cutoff of each set of each namespace,
+	// in milliseconds since the Citrusleaf epoch
+	truncated map[string]map[string]int64
+}
+
+// setStats are the statistics of a set, as reported by the sets info command.
+type setStats struct {
+	name        string
+	objects     int
+	memoryBytes int
+	truncateLUT int64
+}
+
+// sindex is a secondary index definition. Indexes are only listed; queries do not use them.
+type sindex struct {
+	setName        string
+	binName        string
+	indexType      string
+	collectionType string
+}
+
+func newStore(namespaces []string) *store {
+	st := &store{
+		namespaces: namespaces,
+		records:    make(map[string]map[digest]*record, len(namespaces)),
+		indexes:    make(map[string]map[string]*sindex, len(namespaces)),
+		truncated:  make(map[string]map[string]int64, len(namespaces)),
+	}
+	for _, ns := range namespaces {
+		st.records[ns] = map[digest]*record{}
+		st.indexes[ns] = map[string]*sindex{}
+		st.truncated[ns] = map[string]int64{}
+	}
+	return st
+}
+
+func (st *store) hasNamespace(namespace string) bool {
+	_, exists := st.records[namespace]
+	return exists
+}
+
+// get returns a copy of the record, or nil if it does not exist or has expired.
+func (st *store) get(namespace string, d digest) *record {
+	st.mutex.RLock()
+	defer st.mutex.RUnlock()
+
+	rec := st.records[namespace][d]
+	if rec == nil || rec.expired(citrusleafNow()) {
+		return nil
+	}
+	return rec.clone()
+}
+
+// update applies fn to a copy of the record under the store's lock. The record is nil
+// if it does not exist. If fn succeeds, its result replaces the record; a nil result
+// deletes the record.
+func (st *store) update(namespace string, d digest, fn func(rec *record) (*record, ResultCode)) ResultCode {
+	st.mutex.Lock()
+	defer st.mutex.Unlock()
+
+	records := st.records[namespace]
+	rec := records[d]
+	if rec != nil {
+		if rec.expired(citrusleafNow()) {
+			delete(records, d)
+			rec = nil
+		} else {
+			rec = rec.clone()
+		}
+	}
+
+	res, resultCode := fn(rec)
+	if resultCode != OK {
+		return resultCode
+	}
+
+	if res == nil {
+		delete(records, d)
+	} else {
+		records[d] = res
+	}
+	return OK
+}
+
+// scan returns copies of the live records of the namespace, and of the set if
+// setName is not empty, sorted by digest.
+func (st *store) scan(namespace, setName string) ([]digest, []*record) {
+	st.mutex.RLock()
+	defer st.mutex.RUnlock()
+
+	now := citrusleafNow()
+	var digests []digest
+	for d, rec := range st.records[namespace] {
+		if rec.expired(now) || (setName != "" && rec.setName != setName) {
+			continue
+		}
+		digests = append(digests, d)
+	}
+
+	sort.Sort(byDigest(digests))
+
+	records := make([]*record, len(digests))
+	for i, d := range digests {
+		records[i] = st.records[namespace][d].clone()
+	}
+	return digests, records
+}
+
+// truncate removes the records of the namespace, and of the set if setName is not empty.
+// The cutoff is recorded for the set, in milliseconds since the Citrusleaf epoch.
+// It returns false if the namespace does not exist.
+func (st *store) truncate(namespace, setName string, lut int64) bool {
+	st.mutex.Lock()
+	defer st.mutex.Unlock()
+
+	records, exists := st.records[namespace]
+	if !exists {
+		return false
+	}
+
+	if setName != "" {
+		st.truncated[namespace][setName] = lut
+	}
+
+	for d, rec := range records {
+		if setName == "" || rec.setName == setName {
+			delete(records, d)
+		}
+	}
+	return true
+}
+
+// createIndex adds a secondary index. It returns false if the namespace does not
+// exist or the index already exists.
+func (st *store) createIndex(namespace, name string, idx *sindex) bool {
+	st.mutex.Lock()
+	defer st.mutex.Unlock()
+
+	indexes, exists := st.indexes[namespace]
+	if !exists || indexes[name] != nil {
+		return false
+	}
+	indexes[name] = idx
+	return true
+}
+
+// dropIndex removes a secondary index. It returns false if it does not exist.
+func (st *store) dropIndex(namespace, name string) bool {
+	st.mutex.Lock()
+	defer st.mutex.Unlock()
+
+	if st.indexes[namespace][name] == nil {
+		return false
+	}
+	delete(st.indexes[namespace], name)
+	return true
+}
+
+// index returns the secondary index, or nil if it does not exist.
+func (st *store) index(namespace, name string) *sindex {
+	st.mutex.RLock()
+	defer st.mutex.RUnlock()
+
+	return st.indexes[namespace][name]
+}
+
+// indexNames returns the names of the secondary indexes of the namespace, sorted.
+func (st *store) indexNames(namespace string) []string {
+	st.mutex.RLock()
+	defer st.mutex.RUnlock()
+
+	names := make([]string, 0, len(st.indexes[namespace]))
+	for name := range st.indexes[namespace] {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// sets returns the statistics of the sets of the namespace, sorted by name.
+// Truncated sets are kept even if they are empty, like on the server.
+func (st *store) sets(namespace string) []*setStats {
+	_, records := st.scan(namespace, "")
+
+	st.mutex.RLock()
+	defer st.mutex.RUnlock()
+
+	byName := map[string]*setStats{}
+	for name, lut := range st.truncated[namespace] {
+		byName[name] = &setStats{name: name, truncateLUT: lut}
+	}
+	for _, rec := range records {
+		if rec.setName == "" {
+			continue
+		}
+		set := byName[rec.setName]
+		if set == nil {
+			set = &setStats{name: rec.setName}
+			byName[rec.setName] = set
+		}
+		set.objects++
+		set.memoryBytes += rec.size()
+	}
+
+	names := make([]string, 0, len(byName))
+	for name := range byName {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	res := make([]*setStats, len(names))
+	for i, name := range names {
+		res[i] = byName[name]
+	}
+	return res
+}
+
+// binNames returns the names of the bins of the live records of the namespace, sorted.
+func (st *store) binNames(namespace string) []string {
+	_, records := st.scan(namespace, "")
+
+	names := map[string]bool{}
+	for _, rec := range records {
+		for _, name := range rec.binNames {
+			names[name] = true
+		}
+	}
+
+	res := make([]string, 0, len(names))
+	for name := range names {
+		res = append(res, name)
+	}
+	sort.Strings(res)
+	return res
+}
+
+func (st *store) count(namespace string) int {
+	_, records := st.scan(namespace, "")
+	return len(records)
+}
+
+func (st *store) clear() {
+	st.mutex.Lock()
+	defer st.mutex.Unlock()
+
+	for ns := range st.records {
+		st.records[ns] = map[digest]*record{}
+		st.truncated[ns] = map[string]int64{}
+	}
+}
@@ -0,0 +1,252 @@
+// Copyright 2013-2017 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aerospiketest
+
+import (
+	"bytes"
+	"io/ioutil"
+	"math"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+
+	as "github.com/aerospike/aerospike-client-go"
+	lualib "github.com/aerospike/aerospike-client-go/internal/lua"
+	. "github.com/aerospike/aerospike-client-go/types"
+	"github.com/yuin/gopher-lua"
+)
+
+// UDFModule is a Lua UDF module loaded in a local interpreter, to unit-test
+// record UDFs without a server.
+//
+// Record UDFs are run against an in-memory record with the same API as on the
+// server: bin access, record.ttl, record.gen, record.set_ttl, and
+// aerospike:exists, aerospike:create, aerospike:update and aerospike:remove.
+// Bin changes are only stored when the UDF calls aerospike:create or aerospike:update.
+//
+// A UDFModule is safe for concurrent use; UDF executions are serialized.
+type UDFModule struct {
+	mutex sync.Mutex
+	L     *lua.LState
+	name  string
+}
+
+// UDFRecord is the record a UDF is executed against.
+type UDFRecord struct {
+	// Key is the key of the record. It is optional.
+	Key *as.Key
+
+	// Bins are the bins of the record. A nil map means the record does not exist.
+	Bins as.BinMap
+
+	// Generation is the generation of the record.
+	Generation uint32
+
+	// TTL is the time to live of the record in seconds.
+	TTL uint32
+}
+
+// UDFResult is the outcome of a UDF execution.
+type UDFResult struct {
+	// Result is the value returned by the UDF, in the types the client decodes
+	// it to, e.g. integers as int and lists as []interface{}.
+	Result interface{}
+
+	// Record is the record after the UDF execution, or nil if it does not exist.
+	Record *UDFRecord
+
+	// Changes are the bins the UDF stored. Removed bins are set to nil.
+	Changes as.BinMap
+
+	// Created, Updated and Removed report what the UDF did to the record.
+	Created, Updated, Removed bool
+}
+
+// NewUDFModule loads a UDF module from its Lua source code.
+func NewUDFModule(name string, body []byte) (*UDFModule, error) {
+	L, _ := lualib.LuaPool.Get().(*lua.LState)
+	if L == nil {
+		return nil, NewAerospikeError(UDF_BAD_RESPONSE, "Error fetching a lua instance from pool")
+	}
+
+	// load the module under its file name, to be shown in errors and stack traces
+	fn, err := L.Load(bytes.NewReader(body), name+".lua")
+	if err == nil {
+		L.Push(fn)
+		err = L.PCall(0, lua.MultRet, nil)
+	}
+	if err != nil {
+		L.Close()
+		return nil, lualib.NewError(err)
+	}
+
+	return &UDFModule{L: L, name: name}, nil
+}
+
+// LoadUDF loads a UDF module from a file. The module is named after the file,
+// without the .lua extension.
+func LoadUDF(path string) (*UDFModule, error) {
+	body, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewUDFModule(strings.TrimSuffix(filepath.Base(path), ".lua"), body)
+}
+
+// Name returns the name of the module.
+func (m *UDFModule) Name() string {
+	return m.name
+}
+
+// Close releases the interpreter of the module.
+func (m *UDFModule) Close() {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if m.L != nil {
+		m.L.Close()
+		m.L = nil
+	}
+}
+
+// Execute runs the record UDF functionName against the record with the given arguments.
+// The record is not changed; the record after the execution is returned in the result.
+// Errors raised by the UDF are returned as UDF_BAD_RESPONSE errors caused by a *LuaError
+// with the Lua stack trace.
+func (m *UDFModule) Execute(record *UDFRecord, functionName string, args ...interface{}) (*UDFResult, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if m.L == nil {
+		return nil, NewAerospikeError(UDF_BAD_RESPONSE, "UDF module `"+m.name+"` is closed")
+	}
+
+	fn := m.L.GetGlobal(functionName)
+	if fn.Type() != lua.LTFunction {
+		return nil, NewAerospikeError(UDF_BAD_RESPONSE, "function `"+functionName+"` not found in UDF module `"+m.name+"`")
+	}
+
+	if record == nil {
+		record = &UDFRecord{}
+	}
+	rec := newLuaRecord(record)
+
+	luaArgs := []lua.LValue{fn, rec.NewUserData(m.L)}
+	for _, a := range args {
+		luaArgs = append(luaArgs, lualib.NewValue(m.L, normalize(a)))
+	}
+
+	if err := m.L.CallByParam(lua.P{
+		Fn:      m.L.GetGlobal("apply_record"),
+		NRet:    1,
+		Protect: true,
+	},
+		luaArgs...,
+	); err != nil {
+		return nil, lualib.NewError(err)
+	}
+
+	ret := m.L.Get(-1)
+	m.L.Pop(1)
+
+	res := &UDFResult{
+		Result:  fromLua(lualib.LValueToInterface(ret)),
+		Changes: as.BinMap{},
+		Created: rec.Created(),
+		Updated: rec.Updated(),
+		Removed: rec.Removed(),
+	}
+
+	bins := as.BinMap{}
+	for name, value := range rec.Bins() {
+		bins[name] = fromLua(value)
+	}
+
+	for name, value := range bins {
+		if old, exists := record.Bins[name]; !exists || !reflect.DeepEqual(normalize(old), value) {
+			res.Changes[name] = value
+		}
+	}
+	for name := range record.Bins {
+		if _, exists := bins[name]; !exists {
+			res.Changes[name] = nil
+		}
+	}
+
+	if rec.Exists() {
+		res.Record = &UDFRecord{
+			Key:        record.Key,
+			Bins:       bins,
+			Generation: rec.Generation(),
+			TTL:        rec.TTL(),
+		}
+	}
+
+	return res, nil
+}
+
+func newLuaRecord(record *UDFRecord) *lualib.LuaRecord {
+	var bins map[string]interface{}
+	if record.Bins != nil {
+		bins = make(map[string]interface{}, len(record.Bins))
+		for name, value := range record.Bins {
+			bins[name] = normalize(value)
+		}
+	}
+
+	var key interface{}
+	var setName string
+	var digest []byte
+	if record.Key != nil {
+		if record.Key.Value() != nil {
+			key = normalize(record.Key.Value())
+		}
+		setName = record.Key.SetName()
+		digest = record.Key.Digest()
+	}
+
+	return lualib.NewLuaRecord(key, setName, digest, bins, record.Generation, record.TTL)
+}
+
+// fromLua converts a value from the interpreter to the type the client decodes it to.
+// Lua only has floating point numbers, so integral numbers are converted to int.
+func fromLua(value interface{}) interface{} {
+	switch v := value.(type) {
+	case lua.LValue:
+		return fromLua(lualib.LValueToInterface(v))
+	case lualib.GeoJSON:
+		return as.NewGeoJSONValue(string(v))
+	case float64:
+		if v == math.Trunc(v) && math.Abs(v) <= math.MaxInt64 {
+			return int(v)
+		}
+		return v
+	case []interface{}:
+		res := make([]interface{}, len(v))
+		for i := range v {
+			res[i] = fromLua(v[i])
+		}
+		return res
+	case map[interface{}]interface{}:
+		res := make(map[interface{}]interface{}, len(v))
+		for k, e := range v {
+			res[fromLua(k)] = fromLua(e)
+		}
+		return res
+	}
+	return value
+}
@@ -0,0 +1,210 @@
+// Copyright 2013-2017 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aerospiketest_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	as "github.com/aerospike/aerospike-client-go"
+	"github.com/aerospike/aerospike-client-go/aerospiketest"
+	. "github.com/aerospike/aerospike-client-go/types"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+const udfBody = `
+function incr(rec, bin, value)
+	if not aerospike:exists(rec) then
+		rec[bin] = value
+		aerospike:create(rec)
+	else
+		rec[bin] = rec[bin] + value
+		aerospike:update(rec)
+	end
+	return rec[bin]
+end
+
+function touch(rec, ttl)
+	record.set_ttl(rec, ttl)
+	rec["touched"] = true
+	aerospike:update(rec)
+	return record.gen(rec)
+end
+
+function meta(rec)
+	local m = map()
+	m["ttl"] = record.ttl(rec)
+	m["gen"] = record.gen(rec)
+	m["set"] = record.setname(rec)
+	m["numbins"] = record.numbins(rec)
+	return m
+end
+
+function drop(rec, bin)
+	rec[bin] = nil
+	aerospike:update(rec)
+end
+
+function delete(rec)
+	return aerospike:remove(rec)
+end
+
+function unsaved(rec)
+	rec["x"] = 1
+	return list{1, 2.5, "a"}
+end
+
+function fail(rec)
+	error("failed on purpose")
+end
+`
+
+var _ = Describe("UDF Module", func() {
+
+	var module *aerospiketest.UDFModule
+	var key *as.Key
+
+	BeforeEach(func() {
+		var err error
+		module, err = aerospiketest.NewUDFModule("udf", []byte(udfBody))
+		Expect(err).ToNot(HaveOccurred())
+
+		key, err = as.NewKey("test", "udf", 1)
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		module.Close()
+	})
+
+	resultCode := func(err error) ResultCode {
+		Expect(err).To(HaveOccurred())
+		return err.(AerospikeError).ResultCode()
+	}
+
+	It("must create a record that does not exist", func() {
+		res, err := module.Execute(&aerospiketest.UDFRecord{Key: key}, "incr", "c", 5)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(res.Result).To(Equal(5))
+		Expect(res.Created).To(BeTrue())
+		Expect(res.Updated).To(BeFalse())
+		Expect(res.Changes).To(Equal(as.BinMap{"c": 5}))
+		Expect(res.Record).To(Equal(&aerospiketest.UDFRecord{
+			Key:        key,
+			Bins:       as.BinMap{"c": 5},
+			Generation: 1,
+		}))
+	})
+
+	It("must update an existing record", func() {
+		record := &aerospiketest.UDFRecord{Key: key, Bins: as.BinMap{"c": 1, "s": "a"}, Generation: 3}
+		res, err := module.Execute(record, "incr", "c", int64(2))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(res.Result).To(Equal(3))
+		Expect(res.Updated).To(BeTrue())
+		Expect(res.Created).To(BeFalse())
+		Expect(res.Changes).To(Equal(as.BinMap{"c": 3}))
+		Expect(res.Record.Bins).To(Equal(as.BinMap{"c": 3, "s": "a"}))
+		Expect(res.Record.Generation).To(Equal(uint32(4)))
+
+		// the input record is not changed
+		Expect(record.Bins).To(Equal(as.BinMap{"c": 1, "s": "a"}))
+	})
+
+	It("must set the ttl and expose record metadata", func() {
+		record := &aerospiketest.UDFRecord{Key: key, Bins: as.BinMap{"a": 1}, Generation: 2, TTL: 100}
+		res, err := module.Execute(record, "meta")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(res.Result).To(Equal(map[interface{}]interface{}{"ttl": 100, "gen": 2, "set": "udf", "numbins": 1}))
+		Expect(res.Changes).To(BeEmpty())
+
+		res, err = module.Execute(record, "touch", 500)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(res.Result).To(Equal(3))
+		Expect(res.Record.TTL).To(Equal(uint32(500)))
+		Expect(res.Changes).To(Equal(as.BinMap{"touched": true}))
+	})
+
+	It("must report removed bins", func() {
+		record := &aerospiketest.UDFRecord{Key: key, Bins: as.BinMap{"a": 1, "b": []interface{}{1, 2}}}
+		res, err := module.Execute(record, "drop", "b")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(res.Result).To(BeNil())
+		Expect(res.Changes).To(Equal(as.BinMap{"b": nil}))
+		Expect(res.Record.Bins).To(Equal(as.BinMap{"a": 1}))
+	})
+
+	It("must remove a record", func() {
+		record := &aerospiketest.UDFRecord{Key: key, Bins: as.BinMap{"a": 1}, Generation: 2}
+		res, err := module.Execute(record, "delete")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(res.Result).To(Equal(0))
+		Expect(res.Removed).To(BeTrue())
+		Expect(res.Record).To(BeNil())
+		Expect(res.Changes).To(Equal(as.BinMap{"a": nil}))
+
+		res, err = module.Execute(&aerospiketest.UDFRecord{Key: key}, "delete")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(res.Result).To(Equal(2))
+		Expect(res.Removed).To(BeFalse())
+	})
+
+	It("must not store bin changes without create or update", func() {
+		record := &aerospiketest.UDFRecord{Key: key, Bins: as.BinMap{"a": 1}}
+		res, err := module.Execute(record, "unsaved")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(res.Result).To(Equal([]interface{}{1, 2.5, "a"}))
+		Expect(res.Changes).To(BeEmpty())
+		Expect(res.Record.Bins).To(Equal(as.BinMap{"a": 1}))
+	})
+
+	It("must return UDF errors", func() {
+		_, err := module.Execute(&aerospiketest.UDFRecord{Key: key}, "fail")
+		Expect(resultCode(err)).To(Equal(UDF_BAD_RESPONSE))
+		Expect(err.Error()).To(ContainSubstring("failed on purpose"))
+
+		lerr, ok := err.(AerospikeError).Unwrap().(*LuaError)
+		Expect(ok).To(BeTrue())
+		Expect(lerr.StackTrace).To(ContainSubstring("udf.lua:"))
+
+		_, err = module.Execute(&aerospiketest.UDFRecord{Key: key}, "missing")
+		Expect(resultCode(err)).To(Equal(UDF_BAD_RESPONSE))
+
+		_, err = aerospiketest.NewUDFModule("broken", []byte("function ("))
+		Expect(resultCode(err)).To(Equal(UDF_BAD_RESPONSE))
+	})
+
+	It("must load a module from a file", func() {
+		dir, err := ioutil.TempDir("", "udf")
+		Expect(err).ToNot(HaveOccurred())
+		defer os.RemoveAll(dir)
+
+		path := filepath.Join(dir, "counter.lua")
+		Expect(ioutil.WriteFile(path, []byte(udfBody), 0644)).ToNot(HaveOccurred())
+
+		m, err := aerospiketest.LoadUDF(path)
+		Expect(err).ToNot(HaveOccurred())
+		defer m.Close()
+		Expect(m.Name()).To(Equal("counter"))
+
+		res, err := m.Execute(nil, "incr", "c", 1)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(res.Result).To(Equal(1))
+	})
+
+})
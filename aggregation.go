@@ -0,0 +1,470 @@
+// Copyright 2013-2017 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aerospike
+
+import (
+	"fmt"
+	"sort"
+
+	. "github.com/aerospike/aerospike-client-go/types"
+)
+
+// Aggregation is a Go-native aggregation pipeline, an alternative to Lua stream UDFs
+// for the client side of query aggregations.
+//
+// A pipeline consists of any number of Filter and Map stages, followed by one
+// terminal stage: Reduce, Sum, Count, TopK or GroupBy. The first stage receives
+// the *Record values of the query. Client.Aggregate runs the pipeline on the
+// records of each node concurrently, and merges the partial results of the nodes.
+// Since the partial results can be merged in any order, Reduce functions must be
+// associative and commutative.
+//
+// Pipelines can be run on a Recordset with Run, or on in-memory values with Apply,
+// which makes it possible to unit test them without a server.
+//
+// Example: the sum of the "amount" bin of the records, grouped by their "region" bin
+//
+//  agg := NewAggregation().
+//    GroupBy(
+//      func(v interface{}) interface{} { return v.(*Record).Bins["region"] },
+//      NewAggregation().
+//        Map(func(v interface{}) interface{} { return v.(*Record).Bins["amount"] }).
+//        Sum(),
+//    )
+//  res, err := client.Aggregate(nil, NewStatement("test", "sales"), agg)
+//  // res is a map[interface{}]interface{} of regions to their int64 sums
+type Aggregation struct {
+	stages  []func(interface{}) (interface{}, bool)
+	reducer aggregationReducer
+
+	// the first error found while building the pipeline
+	err error
+}
+
+// aggregationReducer folds the values of a pipeline into a partial result.
+// Partial results are merged into a single result, which is then finalized.
+type aggregationReducer interface {
+	zero() interface{}
+	add(acc, value interface{}) (interface{}, error)
+	merge(acc1, acc2 interface{}) (interface{}, error)
+	result(acc interface{}) interface{}
+}
+
+// NewAggregation creates an empty aggregation pipeline.
+func NewAggregation() *Aggregation {
+	return &Aggregation{}
+}
+
+func (agg *Aggregation) setError(format string, args ...interface{}) *Aggregation {
+	if agg.err == nil {
+		agg.err = NewAerospikeError(PARAMETER_ERROR, fmt.Sprintf(format, args...))
+	}
+	return agg
+}
+
+func (agg *Aggregation) addStage(name string, stage func(interface{}) (interface{}, bool)) *Aggregation {
+	if agg.reducer != nil {
+		return agg.setError("%s stage cannot follow the terminal stage of the aggregation.", name)
+	}
+	agg.stages = append(agg.stages, stage)
+	return agg
+}
+
+func (agg *Aggregation) setReducer(name string, reducer aggregationReducer) *Aggregation {
+	if agg.reducer != nil {
+		return agg.setError("%s stage cannot follow the terminal stage of the aggregation.", name)
+	}
+	agg.reducer = reducer
+	return agg
+}
+
+// Filter adds a stage which only passes on the values for which the function returns true.
+func (agg *Aggregation) Filter(f func(value interface{}) bool) *Aggregation {
+	if f == nil {
+		return agg.setError("Filter requires a function.")
+	}
+	return agg.addStage("Filter", func(v interface{}) (interface{}, bool) {
+		return v, f(v)
+	})
+}
+
+// Map adds a stage which passes on the values returned by the function.
+func (agg *Aggregation) Map(f func(value interface{}) interface{}) *Aggregation {
+	if f == nil {
+		return agg.setError("Map requires a function.")
+	}
+	return agg.addStage("Map", func(v interface{}) (interface{}, bool) {
+		return f(v), true
+	})
+}
+
+// Reduce sets the terminal stage to fold the values with the function.
+// The function must be associative and commutative. The result is nil if
+// there are no values.
+func (agg *Aggregation) Reduce(f func(value1, value2 interface{}) interface{}) *Aggregation {
+	if f == nil {
+		return agg.setError("Reduce requires a function.")
+	}
+	return agg.setReducer("Reduce", reduceReducer(f))
+}
+
+// Sum sets the terminal stage to add up the values, which must be Go integers or floats.
+// The result is an int64, or a float64 if any of the values is a float.
+func (agg *Aggregation) Sum() *Aggregation {
+	return agg.setReducer("Sum", sumReducer{})
+}
+
+// Count sets the terminal stage to count the values. The result is an int64.
+func (agg *Aggregation) Count() *Aggregation {
+	return agg.setReducer("Count", countReducer{})
+}
+
+// TopK sets the terminal stage to keep the k greatest values according to the less function.
+// The result is an []interface{} of at most k values, from the greatest to the least.
+func (agg *Aggregation) TopK(k int, less func(value1, value2 interface{}) bool) *Aggregation {
+	if k <= 0 {
+		return agg.setError("TopK requires a positive k, not %d.", k)
+	}
+	if less == nil {
+		return agg.setError("TopK requires a less function.")
+	}
+	return agg.setReducer("TopK", &topKReducer{k: k, less: less})
+}
+
+// GroupBy sets the terminal stage to group the values by the key the function returns,
+// and to run the group aggregation on the values of each group.
+// Keys must be comparable; lists, maps, and arrays or structs holding them are rejected
+// with a PARAMETER_ERROR.
+// The result is a map[interface{}]interface{} of the keys to the results of their
+// group aggregation.
+func (agg *Aggregation) GroupBy(key func(value interface{}) interface{}, group *Aggregation) *Aggregation {
+	if key == nil || group == nil {
+		return agg.setError("GroupBy requires a key function and a group aggregation.")
+	}
+	if err := group.validate(); err != nil {
+		return agg.setError("Invalid group aggregation: %s", err.Error())
+	}
+	return agg.setReducer("GroupBy", &groupByReducer{key: key, group: group})
+}
+
+// validate returns the first error found while building the pipeline.
+func (agg *Aggregation) validate() error {
+	if agg.err != nil {
+		return agg.err
+	}
+
+	if agg.reducer == nil {
+		return NewAerospikeError(PARAMETER_ERROR, "Aggregation requires a terminal stage: Reduce, Sum, Count, TopK or GroupBy.")
+	}
+	return nil
+}
+
+// zero, add, merge and result implement aggregationReducer,
+// which allows the aggregation to be used for the groups of GroupBy.
+func (agg *Aggregation) zero() interface{} {
+	return agg.reducer.zero()
+}
+
+func (agg *Aggregation) add(acc, value interface{}) (interface{}, error) {
+	for _, stage := range agg.stages {
+		var ok bool
+		if value, ok = stage(value); !ok {
+			return acc, nil
+		}
+	}
+	return agg.reducer.add(acc, value)
+}
+
+func (agg *Aggregation) merge(acc1, acc2 interface{}) (interface{}, error) {
+	return agg.reducer.merge(acc1, acc2)
+}
+
+func (agg *Aggregation) result(acc interface{}) interface{} {
+	return agg.reducer.result(acc)
+}
+
+// partial runs the pipeline on the results and returns the partial result.
+func (agg *Aggregation) partial(results <-chan *Result) (interface{}, error) {
+	acc := agg.zero()
+	for res := range results {
+		if res.Err != nil {
+			return nil, res.Err
+		}
+
+		var err error
+		if acc, err = agg.add(acc, res.Record); err != nil {
+			return nil, err
+		}
+	}
+	return acc, nil
+}
+
+// Run runs the pipeline on the records of the recordset and returns the result.
+// The recordset is closed when the function returns.
+func (agg *Aggregation) Run(recordset *Recordset) (interface{}, error) {
+	if err := agg.validate(); err != nil {
+		return nil, err
+	}
+
+	defer recordset.Close()
+	acc, err := agg.partial(recordset.Results())
+	if err != nil {
+		return nil, err
+	}
+	return agg.result(acc), nil
+}
+
+// Apply runs the pipeline on the values and returns the result.
+func (agg *Aggregation) Apply(values ...interface{}) (interface{}, error) {
+	if err := agg.validate(); err != nil {
+		return nil, err
+	}
+
+	acc := agg.zero()
+	for _, v := range values {
+		var err error
+		if acc, err = agg.add(acc, v); err != nil {
+			return nil, err
+		}
+	}
+	return agg.result(acc), nil
+}
+
+// ---------------- Reduce
+
+type reduceReducer func(value1, value2 interface{}) interface{}
+
+type reduceState struct {
+	value interface{}
+	set   bool
+}
+
+func (r reduceReducer) zero() interface{} {
+	return &reduceState{}
+}
+
+func (r reduceReducer) add(acc, value interface{}) (interface{}, error) {
+	state := acc.(*reduceState)
+	if state.set {
+		state.value = r(state.value, value)
+	} else {
+		state.value, state.set = value, true
+	}
+	return state, nil
+}
+
+func (r reduceReducer) merge(acc1, acc2 interface{}) (interface{}, error) {
+	if state := acc2.(*reduceState); state.set {
+		return r.add(acc1, state.value)
+	}
+	return acc1, nil
+}
+
+func (r reduceReducer) result(acc interface{}) interface{} {
+	return acc.(*reduceState).value
+}
+
+// ---------------- Sum
+
+type sumReducer struct{}
+
+type sumState struct {
+	i       int64
+	f       float64
+	isFloat bool
+}
+
+func (sumReducer) zero() interface{} {
+	return &sumState{}
+}
+
+func (sumReducer) add(acc, value interface{}) (interface{}, error) {
+	state := acc.(*sumState)
+	switch v := value.(type) {
+	case int:
+		state.i += int64(v)
+	case int8:
+		state.i += int64(v)
+	case int16:
+		state.i += int64(v)
+	case int32:
+		state.i += int64(v)
+	case int64:
+		state.i += v
+	case uint:
+		state.i += int64(v)
+	case uint8:
+		state.i += int64(v)
+	case uint16:
+		state.i += int64(v)
+	case uint32:
+		state.i += int64(v)
+	case uint64:
+		state.i += int64(v)
+	case float32:
+		state.f += float64(v)
+		state.isFloat = true
+	case float64:
+		state.f += v
+		state.isFloat = true
+	default:
+		return nil, NewAerospikeError(PARAMETER_ERROR, fmt.Sprintf("Sum requires integer or float values, not %T.", value))
+	}
+	return state, nil
+}
+
+func (sumReducer) merge(acc1, acc2 interface{}) (interface{}, error) {
+	state1, state2 := acc1.(*sumState), acc2.(*sumState)
+	state1.i += state2.i
+	state1.f += state2.f
+	state1.isFloat = state1.isFloat || state2.isFloat
+	return state1, nil
+}
+
+func (sumReducer) result(acc interface{}) interface{} {
+	state := acc.(*sumState)
+	if state.isFloat {
+		return float64(state.i) + state.f
+	}
+	return state.i
+}
+
+// ---------------- Count
+
+type countReducer struct{}
+
+func (countReducer) zero() interface{} {
+	return int64(0)
+}
+
+func (countReducer) add(acc, value interface{}) (interface{}, error) {
+	return acc.(int64) + 1, nil
+}
+
+func (countReducer) merge(acc1, acc2 interface{}) (interface{}, error) {
+	return acc1.(int64) + acc2.(int64), nil
+}
+
+func (countReducer) result(acc interface{}) interface{} {
+	return acc
+}
+
+// ---------------- TopK
+
+type topKReducer struct {
+	k    int
+	less func(value1, value2 interface{}) bool
+}
+
+func (r *topKReducer) zero() interface{} {
+	return make([]interface{}, 0, r.k)
+}
+
+// add inserts the value into the values sorted from the greatest to the least,
+// and drops the least value if there are more than k values.
+func (r *topKReducer) add(acc, value interface{}) (interface{}, error) {
+	values := acc.([]interface{})
+	i := sort.Search(len(values), func(i int) bool { return r.less(values[i], value) })
+	if i >= r.k {
+		return values, nil
+	}
+
+	if len(values) < r.k {
+		values = append(values, nil)
+	}
+	copy(values[i+1:], values[i:])
+	values[i] = value
+	return values, nil
+}
+
+func (r *topKReducer) merge(acc1, acc2 interface{}) (interface{}, error) {
+	for _, v := range acc2.([]interface{}) {
+		acc1, _ = r.add(acc1, v)
+	}
+	return acc1, nil
+}
+
+func (r *topKReducer) result(acc interface{}) interface{} {
+	return acc
+}
+
+// ---------------- GroupBy
+
+type groupByReducer struct {
+	key   func(value interface{}) interface{}
+	group *Aggregation
+}
+
+func (r *groupByReducer) zero() interface{} {
+	return make(map[interface{}]interface{})
+}
+
+func (r *groupByReducer) add(acc, value interface{}) (interface{}, error) {
+	groups := acc.(map[interface{}]interface{})
+	key := r.key(value)
+	groupAcc, exists, err := lookupGroup(groups, key)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		groupAcc = r.group.zero()
+	}
+
+	groupAcc, err = r.group.add(groupAcc, value)
+	if err != nil {
+		return nil, err
+	}
+	groups[key] = groupAcc
+	return groups, nil
+}
+
+// lookupGroup returns the accumulator of the group of the key. Keys which are not
+// comparable, including arrays and structs which hold slices, maps or functions,
+// make the lookup panic, which is returned as an error.
+func lookupGroup(groups map[interface{}]interface{}, key interface{}) (groupAcc interface{}, exists bool, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = NewAerospikeError(PARAMETER_ERROR, fmt.Sprintf("GroupBy key of type %T is not comparable", key))
+		}
+	}()
+
+	groupAcc, exists = groups[key]
+	return groupAcc, exists, nil
+}
+
+func (r *groupByReducer) merge(acc1, acc2 interface{}) (interface{}, error) {
+	groups := acc1.(map[interface{}]interface{})
+	for key, groupAcc2 := range acc2.(map[interface{}]interface{}) {
+		if groupAcc1, exists := groups[key]; exists {
+			groupAcc, err := r.group.merge(groupAcc1, groupAcc2)
+			if err != nil {
+				return nil, err
+			}
+			groups[key] = groupAcc
+		} else {
+			groups[key] = groupAcc2
+		}
+	}
+	return groups, nil
+}
+
+func (r *groupByReducer) result(acc interface{}) interface{} {
+	groups := acc.(map[interface{}]interface{})
+	res := make(map[interface{}]interface{}, len(groups))
+	for key, groupAcc := range groups {
+		res[key] = r.group.result(groupAcc)
+	}
+	return res
+}
@@ -0,0 +1,166 @@
+// Copyright 2013-2017 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aerospike
+
+import (
+	. "github.com/aerospike/aerospike-client-go/types"
+
+	. "github.com/onsi/ginkgo"
+	gm "github.com/onsi/gomega"
+)
+
+var _ = Describe("Aggregation test", func() {
+
+	ints := func(n int) []interface{} {
+		res := make([]interface{}, n)
+		for i := range res {
+			res[i] = i
+		}
+		return res
+	}
+
+	less := func(a, b interface{}) bool { return a.(int) < b.(int) }
+
+	// partials runs the aggregation on the values split among the nodes,
+	// and merges the partial results like Client.Aggregate does.
+	partials := func(agg *Aggregation, nodes int, values []interface{}) interface{} {
+		accs := make([]interface{}, nodes)
+		for i := range accs {
+			accs[i] = agg.zero()
+		}
+
+		for i, v := range values {
+			var err error
+			accs[i%nodes], err = agg.add(accs[i%nodes], v)
+			gm.Expect(err).ToNot(gm.HaveOccurred())
+		}
+
+		acc := accs[0]
+		for _, a := range accs[1:] {
+			var err error
+			acc, err = agg.merge(acc, a)
+			gm.Expect(err).ToNot(gm.HaveOccurred())
+		}
+		return agg.result(acc)
+	}
+
+	It("must filter, map and reduce values", func() {
+		agg := NewAggregation().
+			Filter(func(v interface{}) bool { return v.(int)%2 == 0 }).
+			Map(func(v interface{}) interface{} { return v.(int) * 10 }).
+			Reduce(func(a, b interface{}) interface{} { return a.(int) + b.(int) })
+
+		res, err := agg.Apply(ints(10)...)
+		gm.Expect(err).ToNot(gm.HaveOccurred())
+		gm.Expect(res).To(gm.Equal(200))
+
+		gm.Expect(partials(agg, 3, ints(10))).To(gm.Equal(200))
+
+		res, err = agg.Apply()
+		gm.Expect(err).ToNot(gm.HaveOccurred())
+		gm.Expect(res).To(gm.BeNil())
+	})
+
+	It("must sum and count values", func() {
+		res, err := NewAggregation().Sum().Apply(1, int8(2), uint32(3), int64(4))
+		gm.Expect(err).ToNot(gm.HaveOccurred())
+		gm.Expect(res).To(gm.Equal(int64(10)))
+
+		res, err = NewAggregation().Sum().Apply(1, 0.5)
+		gm.Expect(err).ToNot(gm.HaveOccurred())
+		gm.Expect(res).To(gm.Equal(1.5))
+
+		_, err = NewAggregation().Sum().Apply(1, "a")
+		gm.Expect(err).To(gm.HaveOccurred())
+		gm.Expect(err.(AerospikeError).ResultCode()).To(gm.Equal(PARAMETER_ERROR))
+
+		gm.Expect(partials(NewAggregation().Sum(), 4, ints(100))).To(gm.Equal(int64(4950)))
+		gm.Expect(partials(NewAggregation().Count(), 4, ints(100))).To(gm.Equal(int64(100)))
+	})
+
+	It("must keep the top k values", func() {
+		res, err := NewAggregation().TopK(3, less).Apply(5, 1, 9, 3, 7, 9)
+		gm.Expect(err).ToNot(gm.HaveOccurred())
+		gm.Expect(res).To(gm.Equal([]interface{}{9, 9, 7}))
+
+		res, err = NewAggregation().TopK(3, less).Apply(2, 1)
+		gm.Expect(err).ToNot(gm.HaveOccurred())
+		gm.Expect(res).To(gm.Equal([]interface{}{2, 1}))
+
+		gm.Expect(partials(NewAggregation().TopK(4, less), 3, ints(100))).To(gm.Equal([]interface{}{99, 98, 97, 96}))
+	})
+
+	It("must group values and aggregate the groups", func() {
+		agg := NewAggregation().GroupBy(
+			func(v interface{}) interface{} { return v.(int) % 3 },
+			NewAggregation().Map(func(v interface{}) interface{} { return v.(int) * 2 }).Sum(),
+		)
+
+		expected := map[interface{}]interface{}{0: int64(36), 1: int64(24), 2: int64(30)}
+
+		res, err := agg.Apply(ints(10)...)
+		gm.Expect(err).ToNot(gm.HaveOccurred())
+		gm.Expect(res).To(gm.Equal(expected))
+
+		gm.Expect(partials(agg, 4, ints(10))).To(gm.Equal(expected))
+	})
+
+	It("must reject keys that are not comparable", func() {
+		agg := NewAggregation().GroupBy(
+			func(v interface{}) interface{} { return []interface{}{v} },
+			NewAggregation().Count(),
+		)
+
+		_, err := agg.Apply(ints(3)...)
+		gm.Expect(err).To(gm.HaveOccurred())
+		gm.Expect(err.(AerospikeError).ResultCode()).To(gm.Equal(PARAMETER_ERROR))
+
+		// arrays and structs are comparable only if their elements are
+		type pair struct{ a, b interface{} }
+		for _, key := range []func(v interface{}) interface{}{
+			func(v interface{}) interface{} { return [1]interface{}{[]int{1}} },
+			func(v interface{}) interface{} { return pair{v, map[int]int{}} },
+		} {
+			_, err = NewAggregation().GroupBy(key, NewAggregation().Count()).Apply(ints(3)...)
+			gm.Expect(err).To(gm.HaveOccurred())
+			gm.Expect(err.(AerospikeError).ResultCode()).To(gm.Equal(PARAMETER_ERROR))
+		}
+
+		// comparable arrays and structs are valid keys
+		res, err := NewAggregation().GroupBy(
+			func(v interface{}) interface{} { return pair{v.(int) % 2, "x"} },
+			NewAggregation().Count(),
+		).Apply(ints(3)...)
+		gm.Expect(err).ToNot(gm.HaveOccurred())
+		gm.Expect(res).To(gm.HaveLen(2))
+	})
+
+	It("must reject invalid pipelines", func() {
+		validate := func(agg *Aggregation) string {
+			_, err := agg.Apply(1)
+			gm.Expect(err).To(gm.HaveOccurred())
+			gm.Expect(err.(AerospikeError).ResultCode()).To(gm.Equal(PARAMETER_ERROR))
+			return err.Error()
+		}
+
+		gm.Expect(validate(NewAggregation().Map(func(v interface{}) interface{} { return v }))).To(gm.ContainSubstring("requires a terminal stage"))
+		gm.Expect(validate(NewAggregation().Count().Filter(func(v interface{}) bool { return true }))).To(gm.ContainSubstring("cannot follow the terminal stage"))
+		gm.Expect(validate(NewAggregation().Count().Sum())).To(gm.ContainSubstring("cannot follow the terminal stage"))
+		gm.Expect(validate(NewAggregation().TopK(0, less))).To(gm.ContainSubstring("positive k"))
+		gm.Expect(validate(NewAggregation().Reduce(nil))).To(gm.ContainSubstring("requires a function"))
+		gm.Expect(validate(NewAggregation().GroupBy(func(v interface{}) interface{} { return v }, NewAggregation()))).To(gm.ContainSubstring("Invalid group aggregation"))
+	})
+
+})
@@ -44,9 +44,22 @@ type baseMultiCommand struct {
 
 	errChan chan error
 
+	// partitions are the partitions read by a partition scan or query, if any
+	partitions *nodePartitions
+
+	// maxRecords is the number of records requested from the node; 0 means all
+	maxRecords int64
+
+	// limitReached is set when the records of the node are discarded
+	// because the maximum number of records of the command is reached
+	limitReached bool
+
 	resObjType     reflect.Type
 	resObjMappings map[string]string
 	selectCases    []reflect.SelectCase
+
+	// binUnmarshaler is set if the objects implement BinUnmarshaler
+	binUnmarshaler bool
 }
 
 var multiObjectParser func(
@@ -69,6 +82,10 @@ func newMultiCommand(node *Node, recordset *Recordset) *baseMultiCommand {
 		recordset: recordset,
 	}
 
+	if recordset != nil && recordset.limit != nil {
+		cmd.maxRecords = recordset.limit.nodeMax
+	}
+
 	if prepareReflectionData != nil {
 		prepareReflectionData(cmd)
 	}
@@ -79,8 +96,12 @@ func (cmd *baseMultiCommand) getNode(ifc command) (*Node, error) {
 	return cmd.node, nil
 }
 
+func (cmd *baseMultiCommand) commandType() CommandType {
+	return CMD_BATCH
+}
+
 func (cmd *baseMultiCommand) getConnection(timeout time.Duration) (*Connection, error) {
-	return cmd.node.getConnectionWithHint(timeout, byte(xrand.Int64()%256))
+	return cmd.node.getConnectionWithHint(cmd.context(), timeout, byte(xrand.Int64()%256))
 }
 
 func (cmd *baseMultiCommand) putConnection(conn *Connection) {
@@ -197,6 +218,18 @@ func (cmd *baseMultiCommand) parseRecordResults(ifc command, receiveSize int) (b
 			return false, err
 		}
 		resultCode := ResultCode(cmd.dataBuffer[5] & 0xFF)
+		info3 := int(cmd.dataBuffer[3])
+		generation := Buffer.BytesToUint32(cmd.dataBuffer, 6)
+
+		// Partition scans and queries signal the end of each partition.
+		// The generation holds the partition id, and a result code other than OK
+		// means the partition was not available on the node and will be retried.
+		if cmd.partitions != nil && (info3&_INFO3_PARTITION_DONE) == _INFO3_PARTITION_DONE {
+			if resultCode == OK && !cmd.limitReached {
+				cmd.partitions.partitionDone(int(generation))
+			}
+			continue
+		}
 
 		if resultCode != 0 {
 			if resultCode == KEY_NOT_FOUND_ERROR {
@@ -207,14 +240,11 @@ func (cmd *baseMultiCommand) parseRecordResults(ifc command, receiveSize int) (b
 			return false, err
 		}
 
-		info3 := int(cmd.dataBuffer[3])
-
 		// If cmd is the end marker of the response, do not proceed further
 		if (info3 & _INFO3_LAST) == _INFO3_LAST {
 			return false, nil
 		}
 
-		generation := Buffer.BytesToUint32(cmd.dataBuffer, 6)
 		expiration := TTL(Buffer.BytesToUint32(cmd.dataBuffer, 10))
 		fieldCount := int(Buffer.BytesToUint16(cmd.dataBuffer, 18))
 		opCount := int(Buffer.BytesToUint16(cmd.dataBuffer, 20))
@@ -225,43 +255,24 @@ func (cmd *baseMultiCommand) parseRecordResults(ifc command, receiveSize int) (b
 			return false, err
 		}
 
+		// Records over the maximum of the command are discarded, and the partitions
+		// which are not done yet are resumed from the last returned record.
+		if cmd.recordset != nil && !cmd.recordset.limit.take() {
+			cmd.limitReached = true
+			if err := cmd.skipBins(opCount); err != nil {
+				err = newNodeError(cmd.node, err)
+				return false, err
+			}
+			continue
+		}
+
 		// if there is a recordset, process the record traditionally
 		// otherwise, it is supposed to be a record channel
 		if cmd.selectCases == nil {
-			// Parse bins.
-			var bins BinMap
-
-			for i := 0; i < opCount; i++ {
-				if err := cmd.readBytes(8); err != nil {
-					err = newNodeError(cmd.node, err)
-					return false, err
-				}
-
-				opSize := int(Buffer.BytesToUint32(cmd.dataBuffer, 0))
-				particleType := int(cmd.dataBuffer[5])
-				nameSize := int(cmd.dataBuffer[7])
-
-				if err := cmd.readBytes(nameSize); err != nil {
-					err = newNodeError(cmd.node, err)
-					return false, err
-				}
-				name := string(cmd.dataBuffer[:nameSize])
-
-				particleBytesSize := int((opSize - (4 + nameSize)))
-				if err = cmd.readBytes(particleBytesSize); err != nil {
-					err = newNodeError(cmd.node, err)
-					return false, err
-				}
-				value, err := bytesToParticle(particleType, cmd.dataBuffer, 0, particleBytesSize)
-				if err != nil {
-					err = newNodeError(cmd.node, err)
-					return false, err
-				}
-
-				if bins == nil {
-					bins = make(BinMap, opCount)
-				}
-				bins[name] = value
+			bins, err := cmd.parseBins(opCount)
+			if err != nil {
+				err = newNodeError(cmd.node, err)
+				return false, err
 			}
 
 			// If the channel is full and it blocks, we don't want this command to
@@ -271,10 +282,21 @@ func (cmd *baseMultiCommand) parseRecordResults(ifc command, receiveSize int) (b
 			case cmd.recordset.Records <- newRecord(cmd.node, key, bins, generation, expiration):
 			case <-cmd.recordset.cancelled:
 				return false, NewAerospikeError(cmd.terminationErrorType)
+			case <-cmd.context().Done():
+				return false, cmd.context().Err()
 			}
 		} else if multiObjectParser != nil {
 			obj := reflect.New(cmd.resObjType)
-			if err := multiObjectParser(cmd, obj, opCount, fieldCount, generation, expiration); err != nil {
+			if cmd.binUnmarshaler {
+				bins, err := cmd.parseBins(opCount)
+				if err != nil {
+					err = newNodeError(cmd.node, err)
+					return false, err
+				}
+				if err := obj.Interface().(BinUnmarshaler).UnmarshalBins(bins, generation, expiration); err != nil {
+					return false, err
+				}
+			} else if err := multiObjectParser(cmd, obj, opCount, fieldCount, generation, expiration); err != nil {
 				err = newNodeError(cmd.node, err)
 				return false, err
 			}
@@ -282,14 +304,84 @@ func (cmd *baseMultiCommand) parseRecordResults(ifc command, receiveSize int) (b
 			// set the object to send
 			cmd.selectCases[0].Send = obj
 
+			// also stop sending if the context is done
+			if done := cmd.context().Done(); done != nil && len(cmd.selectCases) == 2 {
+				cmd.selectCases = append(cmd.selectCases, reflect.SelectCase{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(done)})
+			}
+
 			chosen, _, _ := reflect.Select(cmd.selectCases)
 			switch chosen {
 			case 0: // object sent
 			case 1: // cancel channel is closed
 				return false, NewAerospikeError(cmd.terminationErrorType)
+			case 2: // context is done
+				return false, cmd.context().Err()
 			}
 		}
+
+		if cmd.partitions != nil {
+			cmd.partitions.recordReceived(key)
+		}
 	}
 
 	return true, nil
 }
+
+// parseBins reads the bins of a record.
+func (cmd *baseMultiCommand) parseBins(opCount int) (BinMap, error) {
+	var bins BinMap
+
+	for i := 0; i < opCount; i++ {
+		if err := cmd.readBytes(8); err != nil {
+			return nil, err
+		}
+
+		opSize := int(Buffer.BytesToUint32(cmd.dataBuffer, 0))
+		particleType := int(cmd.dataBuffer[5])
+		nameSize := int(cmd.dataBuffer[7])
+
+		if err := cmd.readBytes(nameSize); err != nil {
+			return nil, err
+		}
+		name := string(cmd.dataBuffer[:nameSize])
+
+		particleBytesSize := int((opSize - (4 + nameSize)))
+		if err := cmd.readBytes(particleBytesSize); err != nil {
+			return nil, err
+		}
+		value, err := bytesToParticle(particleType, cmd.dataBuffer, 0, particleBytesSize)
+		if err != nil {
+			return nil, err
+		}
+
+		if bins == nil {
+			bins = make(BinMap, opCount)
+		}
+		bins[name] = value
+	}
+	return bins, nil
+}
+
+// skipBins reads the bins of a record without parsing them.
+func (cmd *baseMultiCommand) skipBins(opCount int) error {
+	for i := 0; i < opCount; i++ {
+		if err := cmd.readBytes(4); err != nil {
+			return err
+		}
+
+		opSize := int(Buffer.BytesToUint32(cmd.dataBuffer, 0))
+		if err := cmd.readBytes(opSize); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// batchTarget returns the namespace and set name of the first key in the batch.
+func batchTarget(keys []*Key, offsets []int) (namespace, setName string) {
+	if len(offsets) == 0 || offsets[0] >= len(keys) || keys[offsets[0]] == nil {
+		return "", ""
+	}
+	key := keys[offsets[0]]
+	return key.namespace, key.setName
+}
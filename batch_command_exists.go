@@ -67,6 +67,13 @@ func (cmd *batchCommandExists) getPolicy(ifc command) Policy {
 	return cmd.policy
 }
 
+func (cmd *batchCommandExists) target() (namespace, setName string) {
+	if cmd.isBatchIndex {
+		return batchTarget(cmd.keys, cmd.batch.offsets)
+	}
+	return batchTarget(cmd.keys, cmd.batchNamespace.offsets)
+}
+
 func (cmd *batchCommandExists) writeBuffer(ifc command) error {
 	if cmd.isBatchIndex {
 		return cmd.setBatchIndexReadCompat(cmd.policy, cmd.keys, cmd.batch, nil, _INFO1_READ|_INFO1_NOBINDATA)
@@ -87,9 +94,9 @@ func (cmd *batchCommandExists) parseRecordResults(ifc command, receiveSize int)
 
 		resultCode := ResultCode(cmd.dataBuffer[5] & 0xFF)
 
-		// The only valid server return codes are "ok" and "not found".
+		// The only valid server return codes are "ok", "not found" and "filtered out".
 		// If other return codes are received, then abort the batch.
-		if resultCode != 0 && resultCode != KEY_NOT_FOUND_ERROR {
+		if resultCode != 0 && resultCode != KEY_NOT_FOUND_ERROR && resultCode != FILTERED_OUT {
 			return false, NewAerospikeError(resultCode)
 		}
 
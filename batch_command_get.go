@@ -96,6 +96,13 @@ func (cmd *batchCommandGet) getPolicy(ifc command) Policy {
 	return cmd.policy
 }
 
+func (cmd *batchCommandGet) target() (namespace, setName string) {
+	if cmd.isBatchIndex {
+		return batchTarget(cmd.keys, cmd.batch.offsets)
+	}
+	return batchTarget(cmd.keys, cmd.batchNamespace.offsets)
+}
+
 func (cmd *batchCommandGet) writeBuffer(ifc command) error {
 	if cmd.isBatchIndex {
 		return cmd.setBatchIndexReadCompat(cmd.policy, cmd.keys, cmd.batch, cmd.binNames, cmd.readAttr)
@@ -153,9 +160,9 @@ func (cmd *batchCommandGet) parseRecordResults(ifc command, receiveSize int) (bo
 		}
 		resultCode := ResultCode(cmd.dataBuffer[5] & 0xFF)
 
-		// The only valid server return codes are "ok" and "not found".
+		// The only valid server return codes are "ok", "not found" and "filtered out".
 		// If other return codes are received, then abort the batch.
-		if resultCode != 0 && resultCode != KEY_NOT_FOUND_ERROR {
+		if resultCode != 0 && resultCode != KEY_NOT_FOUND_ERROR && resultCode != FILTERED_OUT {
 			return false, NewAerospikeError(resultCode)
 		}
 
@@ -28,12 +28,15 @@ func init() {
 	prepareReflectionData = concretePrepareReflectionData
 }
 
+var binUnmarshalerType = reflect.TypeOf((*BinUnmarshaler)(nil)).Elem()
+
 func concretePrepareReflectionData(cmd *baseMultiCommand) {
 	// if a channel is assigned, assign its value type
 	if cmd.recordset != nil && !cmd.recordset.objChan.IsNil() {
 		// this channel must be of type chan *T
 		cmd.resObjType = cmd.recordset.objChan.Type().Elem().Elem()
 		cmd.resObjMappings = objectMappings.getMapping(cmd.recordset.objChan.Type().Elem().Elem())
+		cmd.binUnmarshaler = cmd.recordset.objChan.Type().Elem().Implements(binUnmarshalerType)
 
 		cmd.selectCases = []reflect.SelectCase{
 			{Dir: reflect.SelectSend, Chan: cmd.recordset.objChan},
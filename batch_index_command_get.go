@@ -61,6 +61,14 @@ func (cmd *batchIndexCommandGet) getPolicy(ifc command) Policy {
 	return cmd.policy
 }
 
+func (cmd *batchIndexCommandGet) target() (namespace, setName string) {
+	if len(cmd.batch.offsets) == 0 {
+		return "", ""
+	}
+	key := cmd.indexRecords[cmd.batch.offsets[0]].Key
+	return key.namespace, key.setName
+}
+
 func (cmd *batchIndexCommandGet) writeBuffer(ifc command) error {
 	return cmd.setBatchIndexRead(cmd.policy, cmd.indexRecords, cmd.batch)
 }
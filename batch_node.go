@@ -101,6 +101,45 @@ func newBatchIndexNodeList(cluster *Cluster, policy *BatchPolicy, records []*Bat
 	return batchNodes, nil
 }
 
+// newBatchRecordNodeList groups the records by the node of their master partition.
+// Records without a node are left out, with the error set as their result.
+func newBatchRecordNodeList(cluster *Cluster, records []BatchRecordIfc) ([]*batchNode, error) {
+	nodes := cluster.GetNodes()
+
+	if len(nodes) == 0 {
+		return nil, NewAerospikeError(SERVER_NOT_AVAILABLE, "command failed because cluster is empty.")
+	}
+
+	// Create initial key capacity for each node as average + 25%.
+	keysPerNode := len(records) / len(nodes)
+	keysPerNode += keysPerNode / 2
+
+	// The minimum key capacity is 10.
+	if keysPerNode < 10 {
+		keysPerNode = 10
+	}
+
+	// Split keys by server node.
+	batchNodes := make([]*batchNode, 0, len(nodes))
+
+	for i := range records {
+		partition := NewPartitionByKey(records[i].BatchRec().Key)
+
+		node, err := cluster.getMasterNode(partition)
+		if err != nil {
+			records[i].BatchRec().setResult(err)
+			continue
+		}
+
+		if batchNode := findBatchNode(batchNodes, node); batchNode == nil {
+			batchNodes = append(batchNodes, newBatchNode(node, keysPerNode, i))
+		} else {
+			batchNode.AddKey(i)
+		}
+	}
+	return batchNodes, nil
+}
+
 func newBatchNode(node *Node, capacity int, offset int) *batchNode {
 	res := &batchNode{
 		Node:    node,
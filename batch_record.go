@@ -0,0 +1,178 @@
+// Copyright 2013-2017 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aerospike
+
+import (
+	"fmt"
+
+	. "github.com/aerospike/aerospike-client-go/types"
+)
+
+// BatchRecordIfc is implemented by the records of BatchOperate:
+// BatchWrite, BatchDelete and BatchUDF.
+type BatchRecordIfc interface {
+	// BatchRec returns the key and the result of the record.
+	BatchRec() *BatchRecord
+
+	// execute runs the command of the record, and sets its result.
+	execute(clnt *Client, policy *WritePolicy)
+}
+
+// BatchRecord holds the key and the result of a record in BatchOperate.
+type BatchRecord struct {
+	// Key specifies the record.
+	Key *Key
+
+	// Record holds the bins read by the operations of a BatchWrite, if any.
+	Record *Record
+
+	// ResultCode is the result of the command on the record.
+	// It is OK if the command succeeded.
+	ResultCode ResultCode
+
+	// Err is the error the command failed with, if any.
+	Err error
+
+	// InDoubt is true if the command failed, but the write may have been applied.
+	InDoubt bool
+}
+
+// BatchRec returns the key and the result of the record.
+func (br *BatchRecord) BatchRec() *BatchRecord {
+	return br
+}
+
+// setResult sets the result of the record from the error of its command.
+func (br *BatchRecord) setResult(err error) {
+	br.Err = err
+	br.ResultCode = OK
+	br.InDoubt = false
+	if err != nil {
+		br.ResultCode = resultCodeOf(err)
+		if aerr, ok := err.(AerospikeError); ok {
+			br.InDoubt = aerr.InDoubt()
+		}
+	}
+}
+
+// String implements the Stringer interface.
+func (br *BatchRecord) String() string {
+	return fmt.Sprintf("%s: %s", br.Key, ResultCodeToString(br.ResultCode))
+}
+
+// BatchWrite applies operations to a record in BatchOperate, like Operate.
+// Operations include puts, CDT list and map operations, and reads.
+type BatchWrite struct {
+	BatchRecord
+
+	// Policy is the write policy of the record. If it is nil, the default
+	// write policy with the timeouts of the batch policy is used.
+	Policy *WritePolicy
+
+	// Operations are applied to the record.
+	Operations []*Operation
+}
+
+// NewBatchWrite defines operations to apply to a record in a batch operation.
+func NewBatchWrite(policy *WritePolicy, key *Key, operations ...*Operation) *BatchWrite {
+	return &BatchWrite{
+		BatchRecord: BatchRecord{Key: key},
+		Policy:      policy,
+		Operations:  operations,
+	}
+}
+
+// NewBatchPut defines bins to write to a record in a batch operation.
+func NewBatchPut(policy *WritePolicy, key *Key, bins ...*Bin) *BatchWrite {
+	ops := make([]*Operation, len(bins))
+	for i := range bins {
+		ops[i] = PutOp(bins[i])
+	}
+	return NewBatchWrite(policy, key, ops...)
+}
+
+func (bw *BatchWrite) execute(clnt *Client, policy *WritePolicy) {
+	if bw.Policy != nil {
+		policy = bw.Policy
+	}
+	record, err := clnt.Operate(policy, bw.Key, bw.Operations...)
+	bw.Record = record
+	bw.setResult(err)
+}
+
+// BatchDelete deletes a record in BatchOperate.
+// If the record does not exist, ResultCode is KEY_NOT_FOUND_ERROR and Err is nil.
+type BatchDelete struct {
+	BatchRecord
+
+	// Policy is the write policy of the record. If it is nil, the default
+	// write policy with the timeouts of the batch policy is used.
+	Policy *WritePolicy
+}
+
+// NewBatchDelete defines a record to delete in a batch operation.
+func NewBatchDelete(policy *WritePolicy, key *Key) *BatchDelete {
+	return &BatchDelete{
+		BatchRecord: BatchRecord{Key: key},
+		Policy:      policy,
+	}
+}
+
+func (bd *BatchDelete) execute(clnt *Client, policy *WritePolicy) {
+	if bd.Policy != nil {
+		policy = bd.Policy
+	}
+	existed, err := clnt.Delete(policy, bd.Key)
+	bd.setResult(err)
+	if err == nil && !existed {
+		bd.ResultCode = KEY_NOT_FOUND_ERROR
+	}
+}
+
+// BatchUDF executes a user defined function on a record in BatchOperate, like Execute.
+type BatchUDF struct {
+	BatchRecord
+
+	// Policy is the write policy of the record. If it is nil, the default
+	// write policy with the timeouts of the batch policy is used.
+	Policy *WritePolicy
+
+	PackageName  string
+	FunctionName string
+	FunctionArgs []Value
+
+	// Result is the value returned by the function.
+	Result interface{}
+}
+
+// NewBatchUDF defines a user defined function to execute on a record in a batch operation.
+func NewBatchUDF(policy *WritePolicy, key *Key, packageName, functionName string, functionArgs ...Value) *BatchUDF {
+	return &BatchUDF{
+		BatchRecord:  BatchRecord{Key: key},
+		Policy:       policy,
+		PackageName:  packageName,
+		FunctionName: functionName,
+		FunctionArgs: functionArgs,
+	}
+}
+
+func (bu *BatchUDF) execute(clnt *Client, policy *WritePolicy) {
+	if bu.Policy != nil {
+		policy = bu.Policy
+	}
+	result, err := clnt.Execute(policy, bu.Key, bu.PackageName, bu.FunctionName, bu.FunctionArgs...)
+	bu.Result = result
+	bu.setResult(err)
+}
@@ -0,0 +1,33 @@
+// Copyright 2013-2017 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aerospike
+
+// BinMarshaler is implemented by objects which convert themselves into the bins
+// of a record. PutObject uses it in place of reflection.
+//
+// The asgen tool generates implementations from the same `as` struct tags
+// that reflection uses. See tools/asgen.
+type BinMarshaler interface {
+	// MarshalBins returns the bins of the object.
+	MarshalBins() (BinMap, error)
+}
+
+// BinUnmarshaler is implemented by objects which set themselves from the bins
+// and the metadata of a record. GetObject, BatchGetObjects and the object scans
+// and queries use it in place of reflection.
+type BinUnmarshaler interface {
+	// UnmarshalBins sets the object from the bins, generation and expiration of the record.
+	UnmarshalBins(bins BinMap, generation, expiration uint32) error
+}
@@ -0,0 +1,433 @@
+// Copyright 2013-2017 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aerospike
+
+// Bit operations. Create bit operations used by the client operate command.
+// The bit operations work on blob bins.
+//
+// Offset orientation is left-to-right.  Negative offsets are supported.
+// If the offset is negative, the offset starts backwards from end of the bitmap.
+// If an offset is out of bounds, a parameter error will be returned.
+//
+// Bit operations on bitmap items nested in lists/maps are not currently
+// supported by the server.
+
+const (
+	_CDT_BITWISE_RESIZE   = 0
+	_CDT_BITWISE_INSERT   = 1
+	_CDT_BITWISE_REMOVE   = 2
+	_CDT_BITWISE_SET      = 3
+	_CDT_BITWISE_OR       = 4
+	_CDT_BITWISE_XOR      = 5
+	_CDT_BITWISE_AND      = 6
+	_CDT_BITWISE_NOT      = 7
+	_CDT_BITWISE_LSHIFT   = 8
+	_CDT_BITWISE_RSHIFT   = 9
+	_CDT_BITWISE_ADD      = 10
+	_CDT_BITWISE_SUBTRACT = 11
+	_CDT_BITWISE_SET_INT  = 12
+	_CDT_BITWISE_GET      = 50
+	_CDT_BITWISE_COUNT    = 51
+	_CDT_BITWISE_LSCAN    = 52
+	_CDT_BITWISE_RSCAN    = 53
+	_CDT_BITWISE_GET_INT  = 54
+
+	_BIT_INT_FLAGS_SIGNED = 1
+)
+
+// Bit write flags. The flags can be combined with a bitwise OR.
+var BitWriteFlags = struct {
+	// Default allows create or update.
+	DEFAULT int
+
+	// If the bin already exists, the operation will be denied.
+	// If the bin does not exist, a new bin will be created.
+	CREATE_ONLY int
+
+	// If the bin already exists, the bin will be overwritten.
+	// If the bin does not exist, the operation will be denied.
+	UPDATE_ONLY int
+
+	// Do not raise error if operation is denied.
+	NO_FAIL int
+
+	// Allow other valid operations to be committed if this operations is
+	// denied due to flag constraints.
+	PARTIAL int
+}{0, 1, 2, 4, 8}
+
+// Bit resize flags. The flags can be combined with a bitwise OR.
+var BitResizeFlags = struct {
+	// Default resizes the bitmap at its end.
+	DEFAULT int
+
+	// Add/remove bytes from the beginning instead of the end.
+	FROM_FRONT int
+
+	// Only allow the bitmap size to increase.
+	GROW_ONLY int
+
+	// Only allow the bitmap size to decrease.
+	SHRINK_ONLY int
+}{0, 1, 2, 4}
+
+type bitOverflowAction int
+
+// Action to take when bitwise add/subtract results in overflow/underflow.
+var BitOverflowAction = struct {
+	// Fail operation with error.
+	FAIL bitOverflowAction
+
+	// If add/subtract overflows/underflows, set to max/min value.
+	// Example: MAXINT + 1 = MAXINT
+	SATURATE bitOverflowAction
+
+	// If add/subtract overflows/underflows, wrap the value.
+	// Example: MAXINT + 1 = -1
+	WRAP bitOverflowAction
+}{0, 2, 4}
+
+// BitPolicy determines the bit operation behavior.
+type BitPolicy struct {
+	flags int
+}
+
+// NewBitPolicy creates a policy with the specified write flags.
+func NewBitPolicy(flags int) *BitPolicy {
+	return &BitPolicy{flags: flags}
+}
+
+// DefaultBitPolicy returns the default bit policy
+func DefaultBitPolicy() *BitPolicy {
+	return NewBitPolicy(BitWriteFlags.DEFAULT)
+}
+
+func newBitCreateOperationEncoder(op *Operation, packer BufferEx) (int, error) {
+	return packOpParamsAsArray(packer, *op.opSubType, op.binValue.(ListValue))
+}
+
+// packOpParamsAsArray packs the operation type and its params as a single array.
+// Unlike list and map operations, bit and HLL operations carry the operation
+// type inside the msgpack array.
+func packOpParamsAsArray(packer BufferEx, opType int, params ListValue) (int, error) {
+	size, err := __PackArrayBegin(packer, len(params)+1)
+	if err != nil {
+		return size, err
+	}
+
+	n, err := __PackAInt(packer, opType)
+	size += n
+	if err != nil {
+		return size, err
+	}
+
+	for i := range params {
+		n, err = __PackObject(packer, params[i], false)
+		size += n
+		if err != nil {
+			return size, err
+		}
+	}
+	return size, nil
+}
+
+func newBitCreateOperation(command int, typ OperationType, binName string, params ...interface{}) *Operation {
+	return &Operation{
+		opType:    typ,
+		opSubType: &command,
+		binName:   binName,
+		binValue:  ListValue(params),
+		encoder:   newBitCreateOperationEncoder,
+	}
+}
+
+// BitResizeOp creates a byte "resize" operation.
+// Server resizes byte[] to byteSize according to resizeFlags.
+// Server does not return a result.
+//
+// Example:
+//
+//  bin =        [0b00000001, 0b01000010]
+//  byteSize =   4
+//  resizeFlags = 0
+//  bin result = [0b00000001, 0b01000010, 0b00000000, 0b00000000]
+func BitResizeOp(policy *BitPolicy, binName string, byteSize int, resizeFlags int) *Operation {
+	return newBitCreateOperation(_CDT_BITWISE_RESIZE, BIT_MODIFY, binName, IntegerValue(byteSize), IntegerValue(policy.flags), IntegerValue(resizeFlags))
+}
+
+// BitInsertOp creates byte "insert" operation.
+// Server inserts value bytes into byte[] bin at byteOffset.
+// Server does not return a result.
+//
+// Example:
+//
+//  bin =        [0b00000001, 0b01000010, 0b00000011, 0b00000100, 0b00000101]
+//  byteOffset = 1
+//  value =      [0b11111111, 0b11000111]
+//  bin result = [0b00000001, 0b11111111, 0b11000111, 0b01000010, 0b00000011, 0b00000100, 0b00000101]
+func BitInsertOp(policy *BitPolicy, binName string, byteOffset int, value []byte) *Operation {
+	return newBitCreateOperation(_CDT_BITWISE_INSERT, BIT_MODIFY, binName, IntegerValue(byteOffset), BytesValue(value), IntegerValue(policy.flags))
+}
+
+// BitRemoveOp creates byte "remove" operation.
+// Server removes bytes from byte[] bin at byteOffset for byteSize.
+// Server does not return a result.
+//
+// Example:
+//
+//  bin =        [0b00000001, 0b01000010, 0b00000011, 0b00000100, 0b00000101]
+//  byteOffset = 2
+//  byteSize =   3
+//  bin result = [0b00000001, 0b01000010]
+func BitRemoveOp(policy *BitPolicy, binName string, byteOffset int, byteSize int) *Operation {
+	return newBitCreateOperation(_CDT_BITWISE_REMOVE, BIT_MODIFY, binName, IntegerValue(byteOffset), IntegerValue(byteSize), IntegerValue(policy.flags))
+}
+
+// BitSetOp creates bit "set" operation.
+// Server sets value on byte[] bin at bitOffset for bitSize.
+// Server does not return a result.
+//
+// Example:
+//
+//  bin =        [0b00000001, 0b01000010, 0b00000011, 0b00000100, 0b00000101]
+//  bitOffset =  13
+//  bitSize =    3
+//  value =      [0b11100000]
+//  bin result = [0b00000001, 0b01000111, 0b00000011, 0b00000100, 0b00000101]
+func BitSetOp(policy *BitPolicy, binName string, bitOffset int, bitSize int, value []byte) *Operation {
+	return newBitCreateOperation(_CDT_BITWISE_SET, BIT_MODIFY, binName, IntegerValue(bitOffset), IntegerValue(bitSize), BytesValue(value), IntegerValue(policy.flags))
+}
+
+// BitOrOp creates bit "or" operation.
+// Server performs bitwise "or" on value and byte[] bin at bitOffset for bitSize.
+// Server does not return a result.
+//
+// Example:
+//
+//  bin =        [0b00000001, 0b01000010, 0b00000011, 0b00000100, 0b00000101]
+//  bitOffset =  17
+//  bitSize =    6
+//  value =      [0b10101000]
+//  bin result = [0b00000001, 0b01000010, 0b01010111, 0b00000100, 0b00000101]
+func BitOrOp(policy *BitPolicy, binName string, bitOffset int, bitSize int, value []byte) *Operation {
+	return newBitCreateOperation(_CDT_BITWISE_OR, BIT_MODIFY, binName, IntegerValue(bitOffset), IntegerValue(bitSize), BytesValue(value), IntegerValue(policy.flags))
+}
+
+// BitXorOp creates bit "exclusive or" operation.
+// Server performs bitwise "xor" on value and byte[] bin at bitOffset for bitSize.
+// Server does not return a result.
+//
+// Example:
+//
+//  bin =        [0b00000001, 0b01000010, 0b00000011, 0b00000100, 0b00000101]
+//  bitOffset =  17
+//  bitSize =    6
+//  value =      [0b10101100]
+//  bin result = [0b00000001, 0b01000010, 0b01010101, 0b00000100, 0b00000101]
+func BitXorOp(policy *BitPolicy, binName string, bitOffset int, bitSize int, value []byte) *Operation {
+	return newBitCreateOperation(_CDT_BITWISE_XOR, BIT_MODIFY, binName, IntegerValue(bitOffset), IntegerValue(bitSize), BytesValue(value), IntegerValue(policy.flags))
+}
+
+// BitAndOp creates bit "and" operation.
+// Server performs bitwise "and" on value and byte[] bin at bitOffset for bitSize.
+// Server does not return a result.
+//
+// Example:
+//
+//  bin =        [0b00000001, 0b01000010, 0b00000011, 0b00000100, 0b00000101]
+//  bitOffset =  23
+//  bitSize =    9
+//  value =      [0b00111100, 0b10000000]
+//  bin result = [0b00000001, 0b01000010, 0b00000010, 0b00000000, 0b00000101]
+func BitAndOp(policy *BitPolicy, binName string, bitOffset int, bitSize int, value []byte) *Operation {
+	return newBitCreateOperation(_CDT_BITWISE_AND, BIT_MODIFY, binName, IntegerValue(bitOffset), IntegerValue(bitSize), BytesValue(value), IntegerValue(policy.flags))
+}
+
+// BitNotOp creates bit "not" operation.
+// Server negates byte[] bin starting at bitOffset for bitSize.
+// Server does not return a result.
+//
+// Example:
+//
+//  bin =        [0b00000001, 0b01000010, 0b00000011, 0b00000100, 0b00000101]
+//  bitOffset =  25
+//  bitSize =    6
+//  bin result = [0b00000001, 0b01000010, 0b00000011, 0b01111010, 0b00000101]
+func BitNotOp(policy *BitPolicy, binName string, bitOffset int, bitSize int) *Operation {
+	return newBitCreateOperation(_CDT_BITWISE_NOT, BIT_MODIFY, binName, IntegerValue(bitOffset), IntegerValue(bitSize), IntegerValue(policy.flags))
+}
+
+// BitLShiftOp creates bit "left shift" operation.
+// Server shifts left byte[] bin starting at bitOffset for bitSize.
+// Server does not return a result.
+//
+// Example:
+//
+//  bin =        [0b00000001, 0b01000010, 0b00000011, 0b00000100, 0b00000101]
+//  bitOffset =  32
+//  bitSize =    8
+//  shift =      3
+//  bin result = [0b00000001, 0b01000010, 0b00000011, 0b00000100, 0b00101000]
+func BitLShiftOp(policy *BitPolicy, binName string, bitOffset int, bitSize int, shift int) *Operation {
+	return newBitCreateOperation(_CDT_BITWISE_LSHIFT, BIT_MODIFY, binName, IntegerValue(bitOffset), IntegerValue(bitSize), IntegerValue(shift), IntegerValue(policy.flags))
+}
+
+// BitRShiftOp creates bit "right shift" operation.
+// Server shifts right byte[] bin starting at bitOffset for bitSize.
+// Server does not return a result.
+//
+// Example:
+//
+//  bin =        [0b00000001, 0b01000010, 0b00000011, 0b00000100, 0b00000101]
+//  bitOffset =  0
+//  bitSize =    9
+//  shift =      1
+//  bin result = [0b00000000, 0b11000010, 0b00000011, 0b00000100, 0b00000101]
+func BitRShiftOp(policy *BitPolicy, binName string, bitOffset int, bitSize int, shift int) *Operation {
+	return newBitCreateOperation(_CDT_BITWISE_RSHIFT, BIT_MODIFY, binName, IntegerValue(bitOffset), IntegerValue(bitSize), IntegerValue(shift), IntegerValue(policy.flags))
+}
+
+// BitAddOp creates bit "add" operation.
+// Server adds value to byte[] bin starting at bitOffset for bitSize. BitSize must be <= 64.
+// Signed indicates if bits should be treated as a signed number.
+// If add overflows/underflows, BitOverflowAction is used.
+// Server does not return a result.
+//
+// Example:
+//
+//  bin =        [0b00000001, 0b01000010, 0b00000011, 0b00000100, 0b00000101]
+//  bitOffset =  24
+//  bitSize =    16
+//  value =      128
+//  signed =     false
+//  bin result = [0b00000001, 0b01000010, 0b00000011, 0b00000100, 0b10000101]
+func BitAddOp(policy *BitPolicy, binName string, bitOffset int, bitSize int, value int64, signed bool, action bitOverflowAction) *Operation {
+	return newBitMathOperation(_CDT_BITWISE_ADD, policy, binName, bitOffset, bitSize, value, signed, action)
+}
+
+// BitSubtractOp creates bit "subtract" operation.
+// Server subtracts value from byte[] bin starting at bitOffset for bitSize. BitSize must be <= 64.
+// Signed indicates if bits should be treated as a signed number.
+// If subtract overflows/underflows, BitOverflowAction is used.
+// Server does not return a result.
+//
+// Example:
+//
+//  bin =        [0b00000001, 0b01000010, 0b00000011, 0b00000100, 0b00000101]
+//  bitOffset =  24
+//  bitSize =    16
+//  value =      128
+//  signed =     false
+//  bin result = [0b00000001, 0b01000010, 0b00000011, 0b0000011, 0b10000101]
+func BitSubtractOp(policy *BitPolicy, binName string, bitOffset int, bitSize int, value int64, signed bool, action bitOverflowAction) *Operation {
+	return newBitMathOperation(_CDT_BITWISE_SUBTRACT, policy, binName, bitOffset, bitSize, value, signed, action)
+}
+
+func newBitMathOperation(command int, policy *BitPolicy, binName string, bitOffset int, bitSize int, value int64, signed bool, action bitOverflowAction) *Operation {
+	flags := int(action)
+	if signed {
+		flags |= _BIT_INT_FLAGS_SIGNED
+	}
+	return newBitCreateOperation(command, BIT_MODIFY, binName, IntegerValue(bitOffset), IntegerValue(bitSize), LongValue(value), IntegerValue(policy.flags), IntegerValue(flags))
+}
+
+// BitSetIntOp creates bit "setInt" operation.
+// Server sets value to byte[] bin starting at bitOffset for bitSize. Size must be <= 64.
+// Server does not return a result.
+//
+// Example:
+//
+//  bin =        [0b00000001, 0b01000010, 0b00000011, 0b00000100, 0b00000101]
+//  bitOffset =  1
+//  bitSize =    8
+//  value =      127
+//  bin result = [0b00111111, 0b11000010, 0b00000011, 0b0000100, 0b00000101]
+func BitSetIntOp(policy *BitPolicy, binName string, bitOffset int, bitSize int, value int64) *Operation {
+	return newBitCreateOperation(_CDT_BITWISE_SET_INT, BIT_MODIFY, binName, IntegerValue(bitOffset), IntegerValue(bitSize), LongValue(value), IntegerValue(policy.flags))
+}
+
+// BitGetOp creates bit "get" operation.
+// Server returns bits from byte[] bin starting at bitOffset for bitSize.
+//
+// Example:
+//
+//  bin =        [0b00000001, 0b01000010, 0b00000011, 0b00000100, 0b00000101]
+//  bitOffset =  9
+//  bitSize =    5
+//  returns [0b1000000]
+func BitGetOp(binName string, bitOffset int, bitSize int) *Operation {
+	return newBitCreateOperation(_CDT_BITWISE_GET, BIT_READ, binName, IntegerValue(bitOffset), IntegerValue(bitSize))
+}
+
+// BitCountOp creates bit "count" operation.
+// Server returns integer count of set bits from byte[] bin starting at bitOffset for bitSize.
+//
+// Example:
+//
+//  bin =        [0b00000001, 0b01000010, 0b00000011, 0b00000100, 0b00000101]
+//  bitOffset =  20
+//  bitSize =    4
+//  returns 2
+func BitCountOp(binName string, bitOffset int, bitSize int) *Operation {
+	return newBitCreateOperation(_CDT_BITWISE_COUNT, BIT_READ, binName, IntegerValue(bitOffset), IntegerValue(bitSize))
+}
+
+// BitLScanOp creates bit "left scan" operation.
+// Server returns integer bit offset of the first specified value bit in byte[] bin
+// starting at bitOffset for bitSize, or -1 if the value is not found.
+//
+// Example:
+//
+//  bin =        [0b00000001, 0b01000010, 0b00000011, 0b00000100, 0b00000101]
+//  bitOffset =  24
+//  bitSize =    8
+//  value =      true
+//  returns 5
+func BitLScanOp(binName string, bitOffset int, bitSize int, value bool) *Operation {
+	return newBitCreateOperation(_CDT_BITWISE_LSCAN, BIT_READ, binName, IntegerValue(bitOffset), IntegerValue(bitSize), value)
+}
+
+// BitRScanOp creates bit "right scan" operation.
+// Server returns integer bit offset of the last specified value bit in byte[] bin
+// starting at bitOffset for bitSize, or -1 if the value is not found.
+//
+// Example:
+//
+//  bin =        [0b00000001, 0b01000010, 0b00000011, 0b00000100, 0b00000101]
+//  bitOffset =  32
+//  bitSize =    8
+//  value =      true
+//  returns 7
+func BitRScanOp(binName string, bitOffset int, bitSize int, value bool) *Operation {
+	return newBitCreateOperation(_CDT_BITWISE_RSCAN, BIT_READ, binName, IntegerValue(bitOffset), IntegerValue(bitSize), value)
+}
+
+// BitGetIntOp creates bit "get integer" operation.
+// Server returns integer from byte[] bin starting at bitOffset for bitSize.
+// Signed indicates if bits should be treated as a signed number.
+//
+// Example:
+//
+//  bin =        [0b00000001, 0b01000010, 0b00000011, 0b00000100, 0b00000101]
+//  bitOffset =  8
+//  bitSize =    16
+//  signed =     false
+//  returns 16899
+func BitGetIntOp(binName string, bitOffset int, bitSize int, signed bool) *Operation {
+	if signed {
+		return newBitCreateOperation(_CDT_BITWISE_GET_INT, BIT_READ, binName, IntegerValue(bitOffset), IntegerValue(bitSize), IntegerValue(_BIT_INT_FLAGS_SIGNED))
+	}
+	return newBitCreateOperation(_CDT_BITWISE_GET_INT, BIT_READ, binName, IntegerValue(bitOffset), IntegerValue(bitSize))
+}
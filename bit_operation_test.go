@@ -0,0 +1,52 @@
+// Copyright 2013-2017 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aerospike
+
+import (
+	. "github.com/onsi/ginkgo"
+	gm "github.com/onsi/gomega"
+)
+
+var _ = Describe("Bit operations test", func() {
+
+	encode := func(op *Operation) []byte {
+		packer := newPacker()
+		_, err := op.encoder(op, packer)
+		gm.Expect(err).ToNot(gm.HaveOccurred())
+		return packer.Bytes()
+	}
+
+	It("must pack the operation type inside the params array", func() {
+		op := BitSetOp(DefaultBitPolicy(), "b", 13, 3, []byte{0xe0})
+		gm.Expect(op.opType).To(gm.Equal(BIT_MODIFY))
+		gm.Expect(encode(op)).To(gm.Equal([]byte{0x95, 0x03, 0x0d, 0x03, 0xa2, 0x04, 0xe0, 0x00}))
+
+		op = BitCountOp("b", 20, 4)
+		gm.Expect(op.opType).To(gm.Equal(BIT_READ))
+		gm.Expect(encode(op)).To(gm.Equal([]byte{0x93, 0x33, 0x14, 0x04}))
+
+		gm.Expect(encode(BitLScanOp("b", 24, 8, true))).To(gm.Equal([]byte{0x94, 0x34, 0x18, 0x08, 0xc3}))
+		gm.Expect(encode(BitResizeOp(NewBitPolicy(BitWriteFlags.UPDATE_ONLY), "b", 4, BitResizeFlags.FROM_FRONT))).To(gm.Equal([]byte{0x94, 0x00, 0x04, 0x02, 0x01}))
+	})
+
+	It("must pack the overflow action and sign flags", func() {
+		gm.Expect(encode(BitAddOp(DefaultBitPolicy(), "b", 24, 16, 128, false, BitOverflowAction.FAIL))).To(gm.Equal([]byte{0x96, 0x0a, 0x18, 0x10, 0xcc, 0x80, 0x00, 0x00}))
+		gm.Expect(encode(BitSubtractOp(DefaultBitPolicy(), "b", 24, 16, 1, true, BitOverflowAction.WRAP))).To(gm.Equal([]byte{0x96, 0x0b, 0x18, 0x10, 0x01, 0x00, 0x05}))
+
+		gm.Expect(encode(BitGetIntOp("b", 8, 16, false))).To(gm.Equal([]byte{0x93, 0x36, 0x08, 0x10}))
+		gm.Expect(encode(BitGetIntOp("b", 8, 16, true))).To(gm.Equal([]byte{0x94, 0x36, 0x08, 0x10, 0x01}))
+	})
+
+})
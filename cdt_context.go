@@ -0,0 +1,192 @@
+// Copyright 2013-2017 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aerospike
+
+import (
+	"fmt"
+
+	. "github.com/aerospike/aerospike-client-go/types"
+)
+
+const (
+	_CDT_CONTEXT_EVAL = 0xff
+
+	_CTX_LIST_INDEX = 0x10
+	_CTX_LIST_RANK  = 0x11
+	_CTX_LIST_VALUE = 0x13
+	_CTX_MAP_INDEX  = 0x20
+	_CTX_MAP_RANK   = 0x21
+	_CTX_MAP_KEY    = 0x22
+	_CTX_MAP_VALUE  = 0x23
+)
+
+// CDTContext selects a nested list or map element within a bin.
+// A list or map operation applies to the element selected by its context,
+// set with Operation.WithContext.
+//
+// The context is a path from the top level of the bin. To increment the zip code of the
+// third address of the profile map {"addresses": [...]} in bin "profile":
+//
+//  op := MapIncrementOp(DefaultMapPolicy(), "profile", "zip", 1).WithContext(
+//    CtxMapKey("addresses"),
+//    CtxListIndex(2),
+//  )
+type CDTContext struct {
+	id    int
+	value Value
+}
+
+// String implements the Stringer interface.
+func (ctx *CDTContext) String() string {
+	return fmt.Sprintf("ctx(%#x, %v)", ctx.id, ctx.value)
+}
+
+// CtxListIndex selects the list element at index.
+// If the index is negative, the resolved index starts backwards from end of list.
+//
+// Examples:
+//
+//  0: First item.
+//  4: Fifth item.
+//  -1: Last item.
+//  -3: Third to last item.
+func CtxListIndex(index int) *CDTContext {
+	return &CDTContext{id: _CTX_LIST_INDEX, value: IntegerValue(index)}
+}
+
+// CtxListIndexCreate selects the list element at index, and creates it as a list
+// of the given order if it does not exist. If pad is true, an unordered list is
+// padded with nil elements up to the index.
+func CtxListIndexCreate(index int, order listOrderType, pad bool) *CDTContext {
+	return &CDTContext{id: _CTX_LIST_INDEX | listOrderFlag(order, pad), value: IntegerValue(index)}
+}
+
+// CtxListRank selects the list element by rank.
+//
+// Examples:
+//
+//  0: Smallest value.
+//  4: Fifth smallest value.
+//  -1: Largest value.
+//  -3: Third largest value.
+func CtxListRank(rank int) *CDTContext {
+	return &CDTContext{id: _CTX_LIST_RANK, value: IntegerValue(rank)}
+}
+
+// CtxListValue selects the list element by value.
+func CtxListValue(value interface{}) *CDTContext {
+	return &CDTContext{id: _CTX_LIST_VALUE, value: NewValue(value)}
+}
+
+// CtxMapIndex selects the map element at index.
+// If the index is negative, the resolved index starts backwards from end of map.
+func CtxMapIndex(index int) *CDTContext {
+	return &CDTContext{id: _CTX_MAP_INDEX, value: IntegerValue(index)}
+}
+
+// CtxMapRank selects the map element by value rank.
+func CtxMapRank(rank int) *CDTContext {
+	return &CDTContext{id: _CTX_MAP_RANK, value: IntegerValue(rank)}
+}
+
+// CtxMapKey selects the map element by key.
+func CtxMapKey(key interface{}) *CDTContext {
+	return &CDTContext{id: _CTX_MAP_KEY, value: NewValue(key)}
+}
+
+// CtxMapKeyCreate selects the map element by key, and creates it as a map
+// of the given order if it does not exist.
+func CtxMapKeyCreate(key interface{}, order mapOrderType) *CDTContext {
+	return &CDTContext{id: _CTX_MAP_KEY | mapOrderFlag(order), value: NewValue(key)}
+}
+
+// CtxMapValue selects the map element by value.
+func CtxMapValue(value interface{}) *CDTContext {
+	return &CDTContext{id: _CTX_MAP_VALUE, value: NewValue(value)}
+}
+
+// listOrderFlag returns the context flag which creates a missing list.
+func listOrderFlag(order listOrderType, pad bool) int {
+	if order == ListOrder.ORDERED {
+		return 0xc0
+	}
+	if pad {
+		return 0x80
+	}
+	return 0x40
+}
+
+// mapOrderFlag returns the context flag which creates a missing map.
+func mapOrderFlag(order mapOrderType) int {
+	switch order {
+	case MapOrder.KEY_ORDERED:
+		return 0x80
+	case MapOrder.KEY_VALUE_ORDERED:
+		return 0xc0
+	}
+	return 0x40
+}
+
+// packCDTContext packs the context, and the array header which holds it with the operation.
+func packCDTContext(packer BufferEx, ctx []*CDTContext) (int, error) {
+	size := 0
+	n, err := __PackArrayBegin(packer, 3)
+	if err != nil {
+		return size + n, err
+	}
+	size += n
+
+	if n, err = __PackAInt(packer, _CDT_CONTEXT_EVAL); err != nil {
+		return size + n, err
+	}
+	size += n
+
+	if n, err = __PackArrayBegin(packer, len(ctx)*2); err != nil {
+		return size + n, err
+	}
+	size += n
+
+	for _, c := range ctx {
+		if n, err = __PackAInt(packer, c.id); err != nil {
+			return size + n, err
+		}
+		size += n
+
+		if n, err = c.value.pack(packer); err != nil {
+			return size + n, err
+		}
+		size += n
+	}
+	return size, nil
+}
+
+// WithContext returns a copy of the list or map operation, which applies to the
+// nested element selected by the context instead of the top level of the bin.
+// It panics for other operations.
+func (op *Operation) WithContext(ctx ...*CDTContext) *Operation {
+	switch op.opType {
+	case CDT_READ, CDT_MODIFY, MAP_READ, MAP_MODIFY:
+	default:
+		panic(NewAerospikeError(PARAMETER_ERROR, "Context is only supported by list and map operations."))
+	}
+
+	if op.encoder == nil {
+		panic(NewAerospikeError(PARAMETER_ERROR, "Context cannot be set on a cached operation."))
+	}
+
+	res := *op
+	res.ctx = ctx
+	return &res
+}
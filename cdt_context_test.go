@@ -0,0 +1,72 @@
+// Copyright 2013-2017 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aerospike
+
+import (
+	. "github.com/onsi/ginkgo"
+	gm "github.com/onsi/gomega"
+)
+
+var _ = Describe("CDT context test", func() {
+
+	encode := func(op *Operation) []byte {
+		packer := newPacker()
+		_, err := op.encoder(op, packer)
+		gm.Expect(err).ToNot(gm.HaveOccurred())
+		return packer.Bytes()
+	}
+
+	It("must pack operations without context as before", func() {
+		gm.Expect(encode(ListAppendOp("b", 1))).To(gm.Equal([]byte{0x00, 0x01, 0x91, 0x01}))
+		gm.Expect(encode(ListSizeOp("b"))).To(gm.Equal([]byte{0x00, 0x10}))
+	})
+
+	It("must pack the context with the operation", func() {
+		op := ListAppendOp("b", 1)
+		gm.Expect(encode(op.WithContext(CtxMapKey("a"), CtxListIndex(2)))).To(gm.Equal([]byte{
+			0x93, 0xcc, 0xff,
+			0x94, 0x22, 0xa2, 0x03, 'a', 0x10, 0x02,
+			0x92, 0x01, 0x01,
+		}))
+
+		// the original operation is not changed
+		gm.Expect(op.ctx).To(gm.BeNil())
+
+		gm.Expect(encode(ListSizeOp("b").WithContext(CtxListRank(-1)))).To(gm.Equal([]byte{
+			0x93, 0xcc, 0xff,
+			0x92, 0x11, 0xff,
+			0x91, 0x10,
+		}))
+
+		gm.Expect(encode(MapSizeOp("b").WithContext(CtxMapIndex(0), CtxMapRank(1), CtxMapValue(2), CtxListValue(3)))).To(gm.Equal([]byte{
+			0x93, 0xcc, 0xff,
+			0x98, 0x20, 0x00, 0x21, 0x01, 0x23, 0x02, 0x13, 0x03,
+			0x91, 0x60,
+		}))
+	})
+
+	It("must set the create flags", func() {
+		gm.Expect(CtxListIndexCreate(0, ListOrder.UNORDERED, false).id).To(gm.Equal(0x50))
+		gm.Expect(CtxListIndexCreate(0, ListOrder.UNORDERED, true).id).To(gm.Equal(0x90))
+		gm.Expect(CtxListIndexCreate(0, ListOrder.ORDERED, false).id).To(gm.Equal(0xd0))
+		gm.Expect(CtxMapKeyCreate("a", MapOrder.UNORDERED).id).To(gm.Equal(0x62))
+		gm.Expect(CtxMapKeyCreate("a", MapOrder.KEY_ORDERED).id).To(gm.Equal(0xa2))
+		gm.Expect(CtxMapKeyCreate("a", MapOrder.KEY_VALUE_ORDERED).id).To(gm.Equal(0xe2))
+	})
+
+	It("must only accept list and map operations", func() {
+		gm.Expect(func() { GetOp().WithContext(CtxListIndex(0)) }).To(gm.Panic())
+	})
+})
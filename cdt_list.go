@@ -36,81 +36,224 @@ import (
 // out of bounds, the valid part of the range will be returned.
 
 const (
-	_CDT_LIST_APPEND       = 1
-	_CDT_LIST_APPEND_ITEMS = 2
-	_CDT_LIST_INSERT       = 3
-	_CDT_LIST_INSERT_ITEMS = 4
-	_CDT_LIST_POP          = 5
-	_CDT_LIST_POP_RANGE    = 6
-	_CDT_LIST_REMOVE       = 7
-	_CDT_LIST_REMOVE_RANGE = 8
-	_CDT_LIST_SET          = 9
-	_CDT_LIST_TRIM         = 10
-	_CDT_LIST_CLEAR        = 11
-	_CDT_LIST_INCREMENT    = 12
-	_CDT_LIST_SIZE         = 16
-	_CDT_LIST_GET          = 17
-	_CDT_LIST_GET_RANGE    = 18
+	_CDT_LIST_SET_TYPE                       = 0
+	_CDT_LIST_APPEND                         = 1
+	_CDT_LIST_APPEND_ITEMS                   = 2
+	_CDT_LIST_INSERT                         = 3
+	_CDT_LIST_INSERT_ITEMS                   = 4
+	_CDT_LIST_POP                            = 5
+	_CDT_LIST_POP_RANGE                      = 6
+	_CDT_LIST_REMOVE                         = 7
+	_CDT_LIST_REMOVE_RANGE                   = 8
+	_CDT_LIST_SET                            = 9
+	_CDT_LIST_TRIM                           = 10
+	_CDT_LIST_CLEAR                          = 11
+	_CDT_LIST_INCREMENT                      = 12
+	_CDT_LIST_SORT                           = 13
+	_CDT_LIST_SIZE                           = 16
+	_CDT_LIST_GET                            = 17
+	_CDT_LIST_GET_RANGE                      = 18
+	_CDT_LIST_GET_BY_INDEX                   = 19
+	_CDT_LIST_GET_BY_RANK                    = 21
+	_CDT_LIST_GET_BY_VALUE                   = 22
+	_CDT_LIST_GET_BY_VALUE_LIST              = 23
+	_CDT_LIST_GET_BY_INDEX_RANGE             = 24
+	_CDT_LIST_GET_BY_VALUE_INTERVAL          = 25
+	_CDT_LIST_GET_BY_RANK_RANGE              = 26
+	_CDT_LIST_GET_BY_VALUE_REL_RANK_RANGE    = 27
+	_CDT_LIST_REMOVE_BY_INDEX                = 32
+	_CDT_LIST_REMOVE_BY_RANK                 = 34
+	_CDT_LIST_REMOVE_BY_VALUE                = 35
+	_CDT_LIST_REMOVE_BY_VALUE_LIST           = 36
+	_CDT_LIST_REMOVE_BY_INDEX_RANGE          = 37
+	_CDT_LIST_REMOVE_BY_VALUE_INTERVAL       = 38
+	_CDT_LIST_REMOVE_BY_RANK_RANGE           = 39
+	_CDT_LIST_REMOVE_BY_VALUE_REL_RANK_RANGE = 40
 )
 
-func packCDTParamsAsArray(packer BufferEx, opType int16, params ...Value) (int, error) {
-	size := 0
-	n, err := __PackShortRaw(packer, opType)
+type listOrderType int
+
+// List storage order.
+var ListOrder = struct {
+	// List is not ordered. This is the default.
+	UNORDERED listOrderType // 0
+
+	// List is ordered.
+	ORDERED listOrderType // 1
+}{0, 1}
+
+// List write flags. The flags can be combined with a bitwise OR.
+var ListWriteFlags = struct {
+	// Default. Allow duplicate values and insertions at any index.
+	DEFAULT int
+
+	// Only add unique values.
+	ADD_UNIQUE int
+
+	// Enforce list boundaries when inserting. Do not allow values to be inserted
+	// at index outside current list boundaries.
+	INSERT_BOUNDED int
+
+	// Do not raise error if a list item fails due to write flag constraints.
+	NO_FAIL int
+
+	// Allow other valid list items to be committed if a list item fails due to
+	// write flag constraints.
+	PARTIAL int
+}{0, 1, 2, 4, 8}
+
+// List sort flags.
+var ListSortFlags = struct {
+	// Default. Preserve duplicate values when sorting list.
+	DEFAULT int
+
+	// Drop duplicate values when sorting list.
+	DROP_DUPLICATES int
+}{0, 2}
+
+type listReturnType int
+
+// List return type. Type of data to return when selecting or removing items from the list.
+var ListReturnType = struct {
+	// Do not return a result.
+	NONE listReturnType
+
+	// Return index offset order.
+	//
+	// 0 = first item
+	// N = Nth item
+	// -1 = last item
+	INDEX listReturnType
+
+	// Return reverse index offset order.
+	//
+	// 0 = last item
+	// -1 = first item
+	REVERSE_INDEX listReturnType
+
+	// Return value order.
+	//
+	// 0 = smallest value
+	// N = Nth smallest value
+	// -1 = largest value
+	RANK listReturnType
+
+	// Return reverse value order.
+	//
+	// 0 = largest value
+	// N = Nth largest value
+	// -1 = smallest value
+	REVERSE_RANK listReturnType
+
+	// Return count of items selected.
+	COUNT listReturnType
+
+	// Return value for single item read and value list for range read.
+	VALUE listReturnType
+
+	// Invert meaning of list command and return values. Combine it with
+	// another return type with a bitwise OR:
+	//
+	//  ListReturnType.VALUE | ListReturnType.INVERTED
+	//
+	// With INVERTED, the items outside of the specified index, rank or value
+	// range are selected or removed.
+	INVERTED listReturnType
+}{
+	0, 1, 2, 3, 4, 5, 7, 0x10000,
+}
+
+// ListPolicy directives when creating a list and writing list items.
+type ListPolicy struct {
+	attributes listOrderType
+	flags      int
+}
+
+// NewListPolicy creates a policy with the specified list order, used when the list
+// does not exist, and the write flags, used when writing list items.
+func NewListPolicy(order listOrderType, flags int) *ListPolicy {
+	return &ListPolicy{
+		attributes: order,
+		flags:      flags,
+	}
+}
+
+// DefaultListPolicy returns the default list policy
+func DefaultListPolicy() *ListPolicy {
+	return NewListPolicy(ListOrder.UNORDERED, ListWriteFlags.DEFAULT)
+}
+
+func packCDTParamsAsArray(packer BufferEx, opType int16, ctx []*CDTContext, params ...Value) (int, error) {
+	size, err := packCDTHeader(packer, opType, ctx, len(params))
 	if err != nil {
-		return n, err
+		return size, err
 	}
-	size += n
 
-	if len(params) > 0 {
-		if n, err = __PackArrayBegin(packer, len(params)); err != nil {
+	for i := range params {
+		n, err := params[i].pack(packer)
+		if err != nil {
 			return size + n, err
 		}
 		size += n
-
-		for i := range params {
-			if n, err = params[i].pack(packer); err != nil {
-				return size + n, err
-			}
-			size += n
-		}
 	}
 	return size, nil
 }
 
-func packCDTIfcParamsAsArray(packer BufferEx, opType int16, params ListValue) (int, error) {
-	return packCDTIfcVarParamsAsArray(packer, opType, []interface{}(params)...)
+func packCDTIfcParamsAsArray(packer BufferEx, opType int16, ctx []*CDTContext, params ListValue) (int, error) {
+	return packCDTIfcVarParamsAsArray(packer, opType, ctx, []interface{}(params)...)
 }
 
-func packCDTIfcVarParamsAsArray(packer BufferEx, opType int16, params ...interface{}) (int, error) {
-	size := 0
-	n, err := __PackShortRaw(packer, opType)
+func packCDTIfcVarParamsAsArray(packer BufferEx, opType int16, ctx []*CDTContext, params ...interface{}) (int, error) {
+	size, err := packCDTHeader(packer, opType, ctx, len(params))
 	if err != nil {
-		return n, err
+		return size, err
 	}
-	size += n
 
-	if len(params) > 0 {
-		if n, err = __PackArrayBegin(packer, len(params)); err != nil {
+	for i := range params {
+		n, err := __PackObject(packer, params[i], false)
+		if err != nil {
 			return size + n, err
 		}
 		size += n
+	}
+	return size, nil
+}
 
-		for i := range params {
-			if n, err = __PackObject(packer, params[i], false); err != nil {
-				return size + n, err
-			}
-			size += n
+// packCDTHeader packs the operation type and the array header of the params.
+// Operations on nested elements are packed as an array of the context and the
+// operation, with the operation type inside the array of the params.
+func packCDTHeader(packer BufferEx, opType int16, ctx []*CDTContext, paramCount int) (int, error) {
+	if len(ctx) > 0 {
+		size, err := packCDTContext(packer, ctx)
+		if err != nil {
+			return size, err
+		}
+
+		n, err := __PackArrayBegin(packer, paramCount+1)
+		if err != nil {
+			return size + n, err
 		}
+		size += n
+
+		n, err = __PackAInt(packer, int(opType))
+		return size + n, err
 	}
-	return size, nil
+
+	size, err := __PackShortRaw(packer, opType)
+	if err != nil || paramCount == 0 {
+		return size, err
+	}
+
+	n, err := __PackArrayBegin(packer, paramCount)
+	return size + n, err
 }
 
 func listAppendOpEncoder(op *Operation, packer BufferEx) (int, error) {
 	params := op.binValue.(ListValue)
 	if len(params) == 1 {
-		return packCDTIfcVarParamsAsArray(packer, _CDT_LIST_APPEND, params[0])
+		return packCDTIfcVarParamsAsArray(packer, _CDT_LIST_APPEND, op.ctx, params[0])
 	} else if len(params) > 1 {
-		return packCDTParamsAsArray(packer, _CDT_LIST_APPEND_ITEMS, params)
+		return packCDTParamsAsArray(packer, _CDT_LIST_APPEND_ITEMS, op.ctx, params)
 	}
 
 	return -1, NewAerospikeError(PARAMETER_ERROR, "At least one value must be provided for ListAppendOp")
@@ -128,9 +271,9 @@ func listInsertOpEncoder(op *Operation, packer BufferEx) (int, error) {
 	args := op.binValue.(ValueArray)
 	params := args[1].(ListValue)
 	if len(params) == 1 {
-		return packCDTIfcVarParamsAsArray(packer, _CDT_LIST_INSERT, args[0], params[0])
+		return packCDTIfcVarParamsAsArray(packer, _CDT_LIST_INSERT, op.ctx, args[0], params[0])
 	} else if len(params) > 1 {
-		return packCDTParamsAsArray(packer, _CDT_LIST_INSERT_ITEMS, args[0], params)
+		return packCDTParamsAsArray(packer, _CDT_LIST_INSERT_ITEMS, op.ctx, args[0], params)
 	}
 
 	return -1, NewAerospikeError(PARAMETER_ERROR, "At least one value must be provided for ListInsertOp")
@@ -145,7 +288,7 @@ func ListInsertOp(binName string, index int, values ...interface{}) *Operation {
 }
 
 func listPopOpEncoder(op *Operation, packer BufferEx) (int, error) {
-	return packCDTParamsAsArray(packer, _CDT_LIST_POP, op.binValue)
+	return packCDTParamsAsArray(packer, _CDT_LIST_POP, op.ctx, op.binValue)
 }
 
 // ListPopOp creates list pop operation.
@@ -155,7 +298,7 @@ func ListPopOp(binName string, index int) *Operation {
 }
 
 func listPopRangeOpEncoder(op *Operation, packer BufferEx) (int, error) {
-	return packCDTParamsAsArray(packer, _CDT_LIST_POP_RANGE, op.binValue.(ValueArray)...)
+	return packCDTParamsAsArray(packer, _CDT_LIST_POP_RANGE, op.ctx, op.binValue.(ValueArray)...)
 }
 
 // ListPopRangeOp creates a list pop range operation.
@@ -169,7 +312,7 @@ func ListPopRangeOp(binName string, index int, count int) *Operation {
 }
 
 func listPopRangeFromOpEncoder(op *Operation, packer BufferEx) (int, error) {
-	return packCDTParamsAsArray(packer, _CDT_LIST_POP_RANGE, op.binValue)
+	return packCDTParamsAsArray(packer, _CDT_LIST_POP_RANGE, op.ctx, op.binValue)
 }
 
 // ListPopRangeFromOp creates a list pop range operation.
@@ -179,7 +322,7 @@ func ListPopRangeFromOp(binName string, index int) *Operation {
 }
 
 func listRemoveOpEncoder(op *Operation, packer BufferEx) (int, error) {
-	return packCDTParamsAsArray(packer, _CDT_LIST_REMOVE, op.binValue)
+	return packCDTParamsAsArray(packer, _CDT_LIST_REMOVE, op.ctx, op.binValue)
 }
 
 // ListRemoveOp creates a list remove operation.
@@ -190,7 +333,7 @@ func ListRemoveOp(binName string, index int) *Operation {
 }
 
 func listRemoveRangeOpEncoder(op *Operation, packer BufferEx) (int, error) {
-	return packCDTParamsAsArray(packer, _CDT_LIST_REMOVE_RANGE, op.binValue.(ValueArray)...)
+	return packCDTParamsAsArray(packer, _CDT_LIST_REMOVE_RANGE, op.ctx, op.binValue.(ValueArray)...)
 }
 
 // ListRemoveRangeOp creates a list remove range operation.
@@ -205,7 +348,7 @@ func ListRemoveRangeOp(binName string, index int, count int) *Operation {
 }
 
 func listRemoveRangeFromOpEncoder(op *Operation, packer BufferEx) (int, error) {
-	return packCDTParamsAsArray(packer, _CDT_LIST_REMOVE_RANGE, op.binValue)
+	return packCDTParamsAsArray(packer, _CDT_LIST_REMOVE_RANGE, op.ctx, op.binValue)
 }
 
 // ListRemoveRangeFromOp creates a list remove range operation.
@@ -216,7 +359,7 @@ func ListRemoveRangeFromOp(binName string, index int) *Operation {
 }
 
 func listSetOpEncoder(op *Operation, packer BufferEx) (int, error) {
-	return packCDTIfcParamsAsArray(packer, _CDT_LIST_SET, op.binValue.(ListValue))
+	return packCDTIfcParamsAsArray(packer, _CDT_LIST_SET, op.ctx, op.binValue.(ListValue))
 }
 
 // ListSetOp creates a list set operation.
@@ -227,7 +370,7 @@ func ListSetOp(binName string, index int, value interface{}) *Operation {
 }
 
 func listTrimOpEncoder(op *Operation, packer BufferEx) (int, error) {
-	return packCDTParamsAsArray(packer, _CDT_LIST_TRIM, op.binValue.(ValueArray)...)
+	return packCDTParamsAsArray(packer, _CDT_LIST_TRIM, op.ctx, op.binValue.(ValueArray)...)
 }
 
 // ListTrimOp creates a list trim operation.
@@ -239,7 +382,7 @@ func ListTrimOp(binName string, index int, count int) *Operation {
 }
 
 func listClearOpEncoder(op *Operation, packer BufferEx) (int, error) {
-	return packCDTParamsAsArray(packer, _CDT_LIST_CLEAR)
+	return packCDTParamsAsArray(packer, _CDT_LIST_CLEAR, op.ctx)
 }
 
 // ListClearOp creates a list clear operation.
@@ -250,7 +393,7 @@ func ListClearOp(binName string) *Operation {
 }
 
 func listIncrementOpEncoder(op *Operation, packer BufferEx) (int, error) {
-	return packCDTParamsAsArray(packer, _CDT_LIST_INCREMENT, op.binValue.(ValueArray)...)
+	return packCDTParamsAsArray(packer, _CDT_LIST_INCREMENT, op.ctx, op.binValue.(ValueArray)...)
 }
 
 // ListIncrementOp creates a list increment operation.
@@ -268,7 +411,7 @@ func ListIncrementOp(binName string, index int, value interface{}) *Operation {
 }
 
 func listSizeOpEncoder(op *Operation, packer BufferEx) (int, error) {
-	return packCDTParamsAsArray(packer, _CDT_LIST_SIZE)
+	return packCDTParamsAsArray(packer, _CDT_LIST_SIZE, op.ctx)
 }
 
 // ListSizeOp creates a list size operation.
@@ -278,7 +421,7 @@ func ListSizeOp(binName string) *Operation {
 }
 
 func listGetOpEncoder(op *Operation, packer BufferEx) (int, error) {
-	return packCDTParamsAsArray(packer, _CDT_LIST_GET, op.binValue)
+	return packCDTParamsAsArray(packer, _CDT_LIST_GET, op.ctx, op.binValue)
 }
 
 // ListGetOp creates a list get operation.
@@ -288,7 +431,7 @@ func ListGetOp(binName string, index int) *Operation {
 }
 
 func listGetRangeOpEncoder(op *Operation, packer BufferEx) (int, error) {
-	return packCDTParamsAsArray(packer, _CDT_LIST_GET_RANGE, op.binValue.(ValueArray)...)
+	return packCDTParamsAsArray(packer, _CDT_LIST_GET_RANGE, op.ctx, op.binValue.(ValueArray)...)
 }
 
 // ListGetRangeOp creates a list get range operation.
@@ -298,7 +441,7 @@ func ListGetRangeOp(binName string, index int, count int) *Operation {
 }
 
 func listGetRangeFromOpEncoder(op *Operation, packer BufferEx) (int, error) {
-	return packCDTParamsAsArray(packer, _CDT_LIST_GET_RANGE, op.binValue)
+	return packCDTParamsAsArray(packer, _CDT_LIST_GET_RANGE, op.ctx, op.binValue)
 }
 
 // ListGetRangeFromOp creates a list get range operation.
@@ -306,3 +449,272 @@ func listGetRangeFromOpEncoder(op *Operation, packer BufferEx) (int, error) {
 func ListGetRangeFromOp(binName string, index int) *Operation {
 	return &Operation{opType: CDT_READ, binName: binName, binValue: IntegerValue(index), encoder: listGetRangeFromOpEncoder}
 }
+
+func newListCreateOperationEncoder(op *Operation, packer BufferEx) (int, error) {
+	return packCDTIfcParamsAsArray(packer, int16(*op.opSubType), op.ctx, op.binValue.(ListValue))
+}
+
+func newListCreateOperation(command int, typ OperationType, binName string, params ...interface{}) *Operation {
+	return &Operation{
+		opType:    typ,
+		opSubType: &command,
+		binName:   binName,
+		binValue:  ListValue(params),
+		encoder:   newListCreateOperationEncoder,
+	}
+}
+
+func newListCreateRangeOperation(command int, typ OperationType, binName string, begin interface{}, end interface{}, returnType listReturnType) *Operation {
+	if end == nil {
+		return newListCreateOperation(command, typ, binName, IntegerValue(returnType), begin)
+	}
+	return newListCreateOperation(command, typ, binName, IntegerValue(returnType), begin, end)
+}
+
+// ListSetOrderOp creates a set list order operation.
+// Server sets list order.  Server returns null.
+func ListSetOrderOp(binName string, listOrder listOrderType) *Operation {
+	return newListCreateOperation(_CDT_LIST_SET_TYPE, CDT_MODIFY, binName, IntegerValue(listOrder))
+}
+
+// ListAppendWithPolicyOp creates a list append operation.
+// Server appends values to end of list bin, or adds them to an ordered list
+// in their sort order. Server returns list size on bin name.
+// The list policy dictates the order of the list when it does not exist,
+// and the write flags.
+func ListAppendWithPolicyOp(policy *ListPolicy, binName string, values ...interface{}) *Operation {
+	if len(values) == 1 {
+		return newListCreateOperation(_CDT_LIST_APPEND, CDT_MODIFY, binName, values[0], IntegerValue(policy.attributes), IntegerValue(policy.flags))
+	}
+	return newListCreateOperation(_CDT_LIST_APPEND_ITEMS, CDT_MODIFY, binName, ListValue(values), IntegerValue(policy.attributes), IntegerValue(policy.flags))
+}
+
+// ListInsertWithPolicyOp creates a list insert operation.
+// Server inserts values at specified index of list bin.
+// Server returns list size on bin name.
+// The list policy specifies the write flags.
+func ListInsertWithPolicyOp(policy *ListPolicy, binName string, index int, values ...interface{}) *Operation {
+	if len(values) == 1 {
+		return newListCreateOperation(_CDT_LIST_INSERT, CDT_MODIFY, binName, IntegerValue(index), values[0], IntegerValue(policy.flags))
+	}
+	return newListCreateOperation(_CDT_LIST_INSERT_ITEMS, CDT_MODIFY, binName, IntegerValue(index), ListValue(values), IntegerValue(policy.flags))
+}
+
+// ListIncrementWithPolicyOp creates a list increment operation.
+// Server increments list[index] by value.
+// Value should be integer(IntegerValue, LongValue) or float(FloatValue).
+// Server returns list[index] after incrementing.
+// The list policy dictates the order of the list when it does not exist,
+// and the write flags.
+func ListIncrementWithPolicyOp(policy *ListPolicy, binName string, index int, value interface{}) *Operation {
+	val := NewValue(value)
+	switch val.(type) {
+	case LongValue, IntegerValue, FloatValue:
+	default:
+		panic("Increment operation only accepts Integer or Float values")
+	}
+	return newListCreateOperation(_CDT_LIST_INCREMENT, CDT_MODIFY, binName, IntegerValue(index), val, IntegerValue(policy.attributes), IntegerValue(policy.flags))
+}
+
+// ListSetWithPolicyOp creates a list set operation.
+// Server sets item value at specified index in list bin.
+// Server does not return a result by default.
+// The list policy specifies the write flags.
+func ListSetWithPolicyOp(policy *ListPolicy, binName string, index int, value interface{}) *Operation {
+	return newListCreateOperation(_CDT_LIST_SET, CDT_MODIFY, binName, IntegerValue(index), value, IntegerValue(policy.flags))
+}
+
+// ListSortOp creates a list sort operation.
+// Server sorts list according to sortFlags.
+// Server does not return a result by default.
+func ListSortOp(binName string, sortFlags int) *Operation {
+	return newListCreateOperation(_CDT_LIST_SORT, CDT_MODIFY, binName, IntegerValue(sortFlags))
+}
+
+// ListRemoveByValueOp creates list remove by value operation.
+// Server removes list items identified by value and returns removed data specified by returnType.
+func ListRemoveByValueOp(binName string, value interface{}, returnType listReturnType) *Operation {
+	return newListCreateOperation(_CDT_LIST_REMOVE_BY_VALUE, CDT_MODIFY, binName, IntegerValue(returnType), value)
+}
+
+// ListRemoveByValueListOp creates list remove by value operation.
+// Server removes list items identified by values and returns removed data specified by returnType.
+func ListRemoveByValueListOp(binName string, values []interface{}, returnType listReturnType) *Operation {
+	return newListCreateOperation(_CDT_LIST_REMOVE_BY_VALUE_LIST, CDT_MODIFY, binName, IntegerValue(returnType), ListValue(values))
+}
+
+// ListRemoveByValueRangeOp creates list remove by value range operation.
+// Server removes list items identified by value range (valueBegin inclusive, valueEnd exclusive).
+// If valueBegin is nil, the range is less than valueEnd.
+// If valueEnd is nil, the range is greater than equal to valueBegin.
+//
+// Server returns removed data specified by returnType.
+func ListRemoveByValueRangeOp(binName string, valueBegin interface{}, valueEnd interface{}, returnType listReturnType) *Operation {
+	return newListCreateRangeOperation(_CDT_LIST_REMOVE_BY_VALUE_INTERVAL, CDT_MODIFY, binName, valueBegin, valueEnd, returnType)
+}
+
+// ListRemoveByValueRelativeRankRangeOp creates a list remove by value relative to rank range operation.
+// Server removes list items nearest to value and greater by relative rank.
+// Server returns removed data specified by returnType.
+//
+// Examples for ordered list [0,4,5,9,11,15]:
+//
+//  (value,rank) = [removed items]
+//  (5,0) = [5,9,11,15]
+//  (5,1) = [9,11,15]
+//  (5,-1) = [4,5,9,11,15]
+//  (3,0) = [4,5,9,11,15]
+//  (3,3) = [11,15]
+//  (3,-3) = [0,4,5,9,11,15]
+func ListRemoveByValueRelativeRankRangeOp(binName string, value interface{}, rank int, returnType listReturnType) *Operation {
+	return newListCreateOperation(_CDT_LIST_REMOVE_BY_VALUE_REL_RANK_RANGE, CDT_MODIFY, binName, IntegerValue(returnType), value, IntegerValue(rank))
+}
+
+// ListRemoveByValueRelativeRankRangeCountOp creates a list remove by value relative to rank range operation.
+// Server removes "count" list items nearest to value and greater by relative rank.
+// Server returns removed data specified by returnType.
+//
+// Examples for ordered list [0,4,5,9,11,15]:
+//
+//  (value,rank,count) = [removed items]
+//  (5,0,2) = [5,9]
+//  (5,1,1) = [9]
+//  (5,-1,2) = [4,5]
+//  (3,0,1) = [4]
+//  (3,3,7) = [11,15]
+//  (3,-3,2) = []
+func ListRemoveByValueRelativeRankRangeCountOp(binName string, value interface{}, rank, count int, returnType listReturnType) *Operation {
+	return newListCreateOperation(_CDT_LIST_REMOVE_BY_VALUE_REL_RANK_RANGE, CDT_MODIFY, binName, IntegerValue(returnType), value, IntegerValue(rank), IntegerValue(count))
+}
+
+// ListRemoveByIndexOp creates a list remove by index operation.
+// Server removes list item identified by index and returns removed data specified by returnType.
+func ListRemoveByIndexOp(binName string, index int, returnType listReturnType) *Operation {
+	return newListCreateOperation(_CDT_LIST_REMOVE_BY_INDEX, CDT_MODIFY, binName, IntegerValue(returnType), IntegerValue(index))
+}
+
+// ListRemoveByIndexRangeOp creates a list remove by index range operation.
+// Server removes list items starting at specified index to the end of list and returns removed
+// data specified by returnType.
+func ListRemoveByIndexRangeOp(binName string, index int, returnType listReturnType) *Operation {
+	return newListCreateOperation(_CDT_LIST_REMOVE_BY_INDEX_RANGE, CDT_MODIFY, binName, IntegerValue(returnType), IntegerValue(index))
+}
+
+// ListRemoveByIndexRangeCountOp creates a list remove by index range operation.
+// Server removes "count" list items starting at specified index and returns removed data specified by returnType.
+func ListRemoveByIndexRangeCountOp(binName string, index, count int, returnType listReturnType) *Operation {
+	return newListCreateOperation(_CDT_LIST_REMOVE_BY_INDEX_RANGE, CDT_MODIFY, binName, IntegerValue(returnType), IntegerValue(index), IntegerValue(count))
+}
+
+// ListRemoveByRankOp creates a list remove by rank operation.
+// Server removes list item identified by rank and returns removed data specified by returnType.
+func ListRemoveByRankOp(binName string, rank int, returnType listReturnType) *Operation {
+	return newListCreateOperation(_CDT_LIST_REMOVE_BY_RANK, CDT_MODIFY, binName, IntegerValue(returnType), IntegerValue(rank))
+}
+
+// ListRemoveByRankRangeOp creates a list remove by rank range operation.
+// Server removes list items starting at specified rank to the last ranked item and returns removed
+// data specified by returnType.
+func ListRemoveByRankRangeOp(binName string, rank int, returnType listReturnType) *Operation {
+	return newListCreateOperation(_CDT_LIST_REMOVE_BY_RANK_RANGE, CDT_MODIFY, binName, IntegerValue(returnType), IntegerValue(rank))
+}
+
+// ListRemoveByRankRangeCountOp creates a list remove by rank range operation.
+// Server removes "count" list items starting at specified rank and returns removed data specified by returnType.
+func ListRemoveByRankRangeCountOp(binName string, rank, count int, returnType listReturnType) *Operation {
+	return newListCreateOperation(_CDT_LIST_REMOVE_BY_RANK_RANGE, CDT_MODIFY, binName, IntegerValue(returnType), IntegerValue(rank), IntegerValue(count))
+}
+
+// ListGetByValueOp creates a list get by value operation.
+// Server selects list items identified by value and returns selected data specified by returnType.
+func ListGetByValueOp(binName string, value interface{}, returnType listReturnType) *Operation {
+	return newListCreateOperation(_CDT_LIST_GET_BY_VALUE, CDT_READ, binName, IntegerValue(returnType), value)
+}
+
+// ListGetByValueListOp creates a list get by value list operation.
+// Server selects list items identified by values and returns selected data specified by returnType.
+func ListGetByValueListOp(binName string, values []interface{}, returnType listReturnType) *Operation {
+	return newListCreateOperation(_CDT_LIST_GET_BY_VALUE_LIST, CDT_READ, binName, IntegerValue(returnType), ListValue(values))
+}
+
+// ListGetByValueRangeOp creates a list get by value range operation.
+// Server selects list items identified by value range (valueBegin inclusive, valueEnd exclusive)
+// If valueBegin is nil, the range is less than valueEnd.
+// If valueEnd is nil, the range is greater than equal to valueBegin.
+//
+// Server returns selected data specified by returnType.
+func ListGetByValueRangeOp(binName string, valueBegin interface{}, valueEnd interface{}, returnType listReturnType) *Operation {
+	return newListCreateRangeOperation(_CDT_LIST_GET_BY_VALUE_INTERVAL, CDT_READ, binName, valueBegin, valueEnd, returnType)
+}
+
+// ListGetByValueRelativeRankRangeOp creates a list get by value relative to rank range operation.
+// Server selects list items nearest to value and greater by relative rank.
+// Server returns selected data specified by returnType.
+//
+// Examples for ordered list [0,4,5,9,11,15]:
+//
+//  (value,rank) = [selected items]
+//  (5,0) = [5,9,11,15]
+//  (5,1) = [9,11,15]
+//  (5,-1) = [4,5,9,11,15]
+//  (3,0) = [4,5,9,11,15]
+//  (3,3) = [11,15]
+//  (3,-3) = [0,4,5,9,11,15]
+func ListGetByValueRelativeRankRangeOp(binName string, value interface{}, rank int, returnType listReturnType) *Operation {
+	return newListCreateOperation(_CDT_LIST_GET_BY_VALUE_REL_RANK_RANGE, CDT_READ, binName, IntegerValue(returnType), value, IntegerValue(rank))
+}
+
+// ListGetByValueRelativeRankRangeCountOp creates a list get by value relative to rank range operation.
+// Server selects "count" list items nearest to value and greater by relative rank.
+// Server returns selected data specified by returnType.
+//
+// Examples for ordered list [0,4,5,9,11,15]:
+//
+//  (value,rank,count) = [selected items]
+//  (5,0,2) = [5,9]
+//  (5,1,1) = [9]
+//  (5,-1,2) = [4,5]
+//  (3,0,1) = [4]
+//  (3,3,7) = [11,15]
+//  (3,-3,2) = []
+func ListGetByValueRelativeRankRangeCountOp(binName string, value interface{}, rank, count int, returnType listReturnType) *Operation {
+	return newListCreateOperation(_CDT_LIST_GET_BY_VALUE_REL_RANK_RANGE, CDT_READ, binName, IntegerValue(returnType), value, IntegerValue(rank), IntegerValue(count))
+}
+
+// ListGetByIndexOp creates a list get by index operation.
+// Server selects list item identified by index and returns selected data specified by returnType.
+func ListGetByIndexOp(binName string, index int, returnType listReturnType) *Operation {
+	return newListCreateOperation(_CDT_LIST_GET_BY_INDEX, CDT_READ, binName, IntegerValue(returnType), IntegerValue(index))
+}
+
+// ListGetByIndexRangeOp creates a list get by index range operation.
+// Server selects list items starting at specified index to the end of list and returns selected
+// data specified by returnType.
+func ListGetByIndexRangeOp(binName string, index int, returnType listReturnType) *Operation {
+	return newListCreateOperation(_CDT_LIST_GET_BY_INDEX_RANGE, CDT_READ, binName, IntegerValue(returnType), IntegerValue(index))
+}
+
+// ListGetByIndexRangeCountOp creates a list get by index range operation.
+// Server selects "count" list items starting at specified index and returns selected data specified by returnType.
+func ListGetByIndexRangeCountOp(binName string, index, count int, returnType listReturnType) *Operation {
+	return newListCreateOperation(_CDT_LIST_GET_BY_INDEX_RANGE, CDT_READ, binName, IntegerValue(returnType), IntegerValue(index), IntegerValue(count))
+}
+
+// ListGetByRankOp creates a list get by rank operation.
+// Server selects list item identified by rank and returns selected data specified by returnType.
+func ListGetByRankOp(binName string, rank int, returnType listReturnType) *Operation {
+	return newListCreateOperation(_CDT_LIST_GET_BY_RANK, CDT_READ, binName, IntegerValue(returnType), IntegerValue(rank))
+}
+
+// ListGetByRankRangeOp creates a list get by rank range operation.
+// Server selects list items starting at specified rank to the last ranked item and returns selected
+// data specified by returnType.
+func ListGetByRankRangeOp(binName string, rank int, returnType listReturnType) *Operation {
+	return newListCreateOperation(_CDT_LIST_GET_BY_RANK_RANGE, CDT_READ, binName, IntegerValue(returnType), IntegerValue(rank))
+}
+
+// ListGetByRankRangeCountOp creates a list get by rank range operation.
+// Server selects "count" list items starting at specified rank and returns selected data specified by returnType.
+func ListGetByRankRangeCountOp(binName string, rank, count int, returnType listReturnType) *Operation {
+	return newListCreateOperation(_CDT_LIST_GET_BY_RANK_RANGE, CDT_READ, binName, IntegerValue(returnType), IntegerValue(rank), IntegerValue(count))
+}
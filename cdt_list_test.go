@@ -350,4 +350,97 @@ var _ = Describe("CDT List Test", func() {
 
 	})
 
+	Describe("CDT List Selectors", func() {
+
+		// operate runs a single operation and returns its result
+		operate := func(op *as.Operation) interface{} {
+			rec, err := client.Operate(wpolicy, key, op)
+			Expect(err).ToNot(HaveOccurred())
+			return rec.Bins[cdtBinName]
+		}
+
+		BeforeEach(func() {
+			operate(as.ListAppendWithPolicyOp(as.NewListPolicy(as.ListOrder.ORDERED, as.ListWriteFlags.DEFAULT), cdtBinName, 9, 0, 15, 4, 11, 5))
+		})
+
+		It("should keep ordered lists sorted", func() {
+			Expect(operate(as.ListGetRangeFromOp(cdtBinName, 0))).To(Equal([]interface{}{0, 4, 5, 9, 11, 15}))
+
+			operate(as.ListAppendWithPolicyOp(as.DefaultListPolicy(), cdtBinName, 7))
+			Expect(operate(as.ListGetRangeFromOp(cdtBinName, 0))).To(Equal([]interface{}{0, 4, 5, 7, 9, 11, 15}))
+		})
+
+		It("should only add unique values with ADD_UNIQUE and NO_FAIL", func() {
+			policy := as.NewListPolicy(as.ListOrder.ORDERED, as.ListWriteFlags.ADD_UNIQUE|as.ListWriteFlags.NO_FAIL)
+			operate(as.ListAppendWithPolicyOp(policy, cdtBinName, 4))
+			Expect(operate(as.ListSizeOp(cdtBinName))).To(Equal(6))
+
+			policy = as.NewListPolicy(as.ListOrder.ORDERED, as.ListWriteFlags.ADD_UNIQUE)
+			_, err := client.Operate(wpolicy, key, as.ListAppendWithPolicyOp(policy, cdtBinName, 4))
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should change the order and sort lists", func() {
+			operate(as.ListSetOrderOp(cdtBinName, as.ListOrder.UNORDERED))
+			operate(as.ListAppendOp(cdtBinName, 1, 1))
+			Expect(operate(as.ListGetRangeFromOp(cdtBinName, 0))).To(Equal([]interface{}{0, 4, 5, 9, 11, 15, 1, 1}))
+
+			operate(as.ListSortOp(cdtBinName, as.ListSortFlags.DROP_DUPLICATES))
+			Expect(operate(as.ListGetRangeFromOp(cdtBinName, 0))).To(Equal([]interface{}{0, 1, 4, 5, 9, 11, 15}))
+		})
+
+		It("should get items by value, rank and index", func() {
+			Expect(operate(as.ListGetByValueOp(cdtBinName, 5, as.ListReturnType.INDEX))).To(Equal([]interface{}{2}))
+			Expect(operate(as.ListGetByValueListOp(cdtBinName, []interface{}{4, 11}, as.ListReturnType.RANK))).To(Equal([]interface{}{1, 4}))
+			Expect(operate(as.ListGetByValueRangeOp(cdtBinName, 4, 10, as.ListReturnType.VALUE))).To(Equal([]interface{}{4, 5, 9}))
+			Expect(operate(as.ListGetByValueRangeOp(cdtBinName, 10, nil, as.ListReturnType.COUNT))).To(Equal(2))
+			Expect(operate(as.ListGetByValueRelativeRankRangeOp(cdtBinName, 5, 1, as.ListReturnType.VALUE))).To(Equal([]interface{}{9, 11, 15}))
+			Expect(operate(as.ListGetByValueRelativeRankRangeCountOp(cdtBinName, 5, -1, 2, as.ListReturnType.VALUE))).To(Equal([]interface{}{4, 5}))
+			Expect(operate(as.ListGetByRankOp(cdtBinName, -1, as.ListReturnType.VALUE))).To(Equal(15))
+			Expect(operate(as.ListGetByRankRangeCountOp(cdtBinName, 0, 2, as.ListReturnType.VALUE))).To(Equal([]interface{}{0, 4}))
+			Expect(operate(as.ListGetByIndexOp(cdtBinName, 1, as.ListReturnType.REVERSE_INDEX))).To(Equal(4))
+			Expect(operate(as.ListGetByIndexRangeOp(cdtBinName, 4, as.ListReturnType.VALUE))).To(Equal([]interface{}{11, 15}))
+		})
+
+		It("should get and remove inverted selections", func() {
+			Expect(operate(as.ListGetByIndexRangeCountOp(cdtBinName, 1, 4, as.ListReturnType.VALUE|as.ListReturnType.INVERTED))).To(Equal([]interface{}{0, 15}))
+			Expect(operate(as.ListRemoveByValueRangeOp(cdtBinName, 4, 12, as.ListReturnType.COUNT|as.ListReturnType.INVERTED))).To(Equal(2))
+			Expect(operate(as.ListGetRangeFromOp(cdtBinName, 0))).To(Equal([]interface{}{4, 5, 9, 11}))
+		})
+
+		It("should remove items by value, rank and index", func() {
+			Expect(operate(as.ListRemoveByValueOp(cdtBinName, 9, as.ListReturnType.COUNT))).To(Equal(1))
+			Expect(operate(as.ListRemoveByValueListOp(cdtBinName, []interface{}{0, 15}, as.ListReturnType.VALUE))).To(Equal([]interface{}{0, 15}))
+			Expect(operate(as.ListGetRangeFromOp(cdtBinName, 0))).To(Equal([]interface{}{4, 5, 11}))
+
+			Expect(operate(as.ListRemoveByRankOp(cdtBinName, 0, as.ListReturnType.VALUE))).To(Equal(4))
+			Expect(operate(as.ListRemoveByIndexOp(cdtBinName, -1, as.ListReturnType.VALUE))).To(Equal(11))
+			Expect(operate(as.ListGetRangeFromOp(cdtBinName, 0))).To(Equal([]interface{}{5}))
+		})
+
+		It("should remove item ranges by value, rank and index", func() {
+			Expect(operate(as.ListRemoveByValueRelativeRankRangeCountOp(cdtBinName, 5, 0, 2, as.ListReturnType.VALUE))).To(Equal([]interface{}{5, 9}))
+			Expect(operate(as.ListRemoveByRankRangeCountOp(cdtBinName, -1, 1, as.ListReturnType.VALUE))).To(Equal([]interface{}{15}))
+			Expect(operate(as.ListRemoveByIndexRangeCountOp(cdtBinName, 0, 1, as.ListReturnType.VALUE))).To(Equal([]interface{}{0}))
+			Expect(operate(as.ListGetRangeFromOp(cdtBinName, 0))).To(Equal([]interface{}{4, 11}))
+
+			Expect(operate(as.ListRemoveByValueRelativeRankRangeOp(cdtBinName, 5, 0, as.ListReturnType.COUNT))).To(Equal(1))
+			Expect(operate(as.ListRemoveByRankRangeOp(cdtBinName, 0, as.ListReturnType.VALUE))).To(Equal([]interface{}{4}))
+			Expect(operate(as.ListRemoveByIndexRangeOp(cdtBinName, 0, as.ListReturnType.COUNT))).To(Equal(0))
+		})
+
+		It("should insert, set and increment with policies", func() {
+			operate(as.ListSetOrderOp(cdtBinName, as.ListOrder.UNORDERED))
+
+			policy := as.NewListPolicy(as.ListOrder.UNORDERED, as.ListWriteFlags.INSERT_BOUNDED)
+			_, err := client.Operate(wpolicy, key, as.ListInsertWithPolicyOp(policy, cdtBinName, 10, 1))
+			Expect(err).To(HaveOccurred())
+
+			Expect(operate(as.ListInsertWithPolicyOp(policy, cdtBinName, 1, 1, 2))).To(Equal(8))
+			operate(as.ListSetWithPolicyOp(policy, cdtBinName, 0, 100))
+			Expect(operate(as.ListIncrementWithPolicyOp(policy, cdtBinName, 0, 5))).To(Equal(105))
+			Expect(operate(as.ListGetRangeOp(cdtBinName, 0, 3))).To(Equal([]interface{}{105, 1, 2}))
+		})
+	})
+
 }) // describe
@@ -160,7 +160,7 @@ func DefaultMapPolicy() *MapPolicy {
 }
 
 func newMapSetPolicyEncoder(op *Operation, packer BufferEx) (int, error) {
-	return packCDTParamsAsArray(packer, _CDT_MAP_SET_TYPE, op.binValue.(IntegerValue))
+	return packCDTParamsAsArray(packer, _CDT_MAP_SET_TYPE, op.ctx, op.binValue.(IntegerValue))
 }
 
 func newMapSetPolicy(binName string, attributes mapOrderType) *Operation {
@@ -173,7 +173,7 @@ func newMapSetPolicy(binName string, attributes mapOrderType) *Operation {
 }
 
 func newMapCreatePutEncoder(op *Operation, packer BufferEx) (int, error) {
-	return packCDTIfcParamsAsArray(packer, int16(*op.opSubType), op.binValue.(ListValue))
+	return packCDTIfcParamsAsArray(packer, int16(*op.opSubType), op.ctx, op.binValue.(ListValue))
 }
 
 func newMapCreatePut(command int, attributes mapOrderType, binName string, value1 interface{}, value2 interface{}) *Operation {
@@ -200,10 +200,10 @@ func newMapCreatePut(command int, attributes mapOrderType, binName string, value
 func newMapCreateOperationEncoder(op *Operation, packer BufferEx) (int, error) {
 	if op.binValue != nil {
 		if params := op.binValue.(ListValue); len(params) > 0 {
-			return packCDTIfcParamsAsArray(packer, int16(*op.opSubType), op.binValue.(ListValue))
+			return packCDTIfcParamsAsArray(packer, int16(*op.opSubType), op.ctx, op.binValue.(ListValue))
 		}
 	}
-	return packCDTParamsAsArray(packer, int16(*op.opSubType))
+	return packCDTParamsAsArray(packer, int16(*op.opSubType), op.ctx)
 }
 
 func newMapCreateOperationValues2(command int, attributes mapOrderType, binName string, value1 interface{}, value2 interface{}) *Operation {
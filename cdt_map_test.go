@@ -510,4 +510,39 @@ var _ = Describe("CDT Map Test", func() {
 		}
 	})
 
+	It("should apply operations to nested lists and maps with a context", func() {
+		profile := map[interface{}]interface{}{
+			"name": "joe",
+			"addresses": []interface{}{
+				map[interface{}]interface{}{"city": "a", "zip": 1000},
+				map[interface{}]interface{}{"city": "b", "zip": 2000},
+				map[interface{}]interface{}{"city": "c", "zip": 3000},
+			},
+		}
+		err := client.PutBins(wpolicy, key, as.NewBin(cdtBinName, profile))
+		Expect(err).ToNot(HaveOccurred())
+
+		rec, err := client.Operate(wpolicy, key, as.MapIncrementOp(putMode, cdtBinName, "zip", 5).WithContext(as.CtxMapKey("addresses"), as.CtxListIndex(2)))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(rec.Bins[cdtBinName]).To(Equal(3005))
+
+		rec, err = client.Operate(wpolicy, key, as.MapGetByKeyOp(cdtBinName, "city", as.MapReturnType.VALUE).WithContext(as.CtxMapKey("addresses"), as.CtxListIndex(-1)))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(rec.Bins[cdtBinName]).To(Equal("c"))
+
+		rec, err = client.Operate(wpolicy, key, as.ListSizeOp(cdtBinName).WithContext(as.CtxMapKey("addresses")))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(rec.Bins[cdtBinName]).To(Equal(3))
+
+		// create the missing nested map
+		_, err = client.Operate(wpolicy, key, as.MapPutOp(putMode, cdtBinName, "theme", "dark").WithContext(as.CtxMapKeyCreate("settings", as.MapOrder.KEY_ORDERED)))
+		Expect(err).ToNot(HaveOccurred())
+
+		rec, err = client.Get(nil, key, cdtBinName)
+		Expect(err).ToNot(HaveOccurred())
+		bin := rec.Bins[cdtBinName].(map[interface{}]interface{})
+		Expect(bin["addresses"].([]interface{})[2]).To(Equal(map[interface{}]interface{}{"city": "c", "zip": 3005}))
+		Expect(bin["settings"]).To(Equal(map[interface{}]interface{}{"theme": "dark"}))
+	})
+
 }) // describe
@@ -16,12 +16,13 @@ package aerospike
 
 import (
 	"bytes"
+	"context"
 	"encoding/base64"
 	"encoding/json"
-	"errors"
 	"fmt"
 	"io/ioutil"
 	"runtime"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
@@ -39,6 +40,12 @@ import (
 type Client struct {
 	cluster *Cluster
 
+	// ctx is the context all commands issued through this client are bound to.
+	ctx context.Context
+	// origin references the client this one was derived from via WithContext,
+	// and keeps its finalizer from closing the cluster while in use.
+	origin *Client
+
 	// DefaultPolicy is used for all read commands without a specific policy.
 	DefaultPolicy *BasePolicy
 	// DefaultBatchPolicy is used for all batch commands without a specific policy.
@@ -51,6 +58,8 @@ type Client struct {
 	DefaultQueryPolicy *QueryPolicy
 	// DefaultAdminPolicy is used for all security commands without a specific policy.
 	DefaultAdminPolicy *AdminPolicy
+	// DefaultInfoPolicy is used for all info commands without a specific policy.
+	DefaultInfoPolicy *InfoPolicy
 }
 
 func clientFinalizer(f *Client) {
@@ -80,6 +89,10 @@ func NewClientWithPolicyAndHost(policy *ClientPolicy, hosts ...*Host) (*Client,
 		policy = NewClientPolicy()
 	}
 
+	if err := policy.validate(); err != nil {
+		return nil, err
+	}
+
 	cluster, err := NewCluster(policy, hosts)
 	if err != nil && policy.FailIfNotConnected {
 		if aerr, ok := err.(AerospikeError); ok {
@@ -91,12 +104,14 @@ func NewClientWithPolicyAndHost(policy *ClientPolicy, hosts ...*Host) (*Client,
 
 	client := &Client{
 		cluster:            cluster,
+		ctx:                context.Background(),
 		DefaultPolicy:      NewPolicy(),
 		DefaultBatchPolicy: NewBatchPolicy(),
 		DefaultWritePolicy: NewWritePolicy(0, 0),
 		DefaultScanPolicy:  NewScanPolicy(),
 		DefaultQueryPolicy: NewQueryPolicy(),
 		DefaultAdminPolicy: NewAdminPolicy(),
+		DefaultInfoPolicy:  NewInfoPolicy(),
 	}
 
 	runtime.SetFinalizer(client, clientFinalizer)
@@ -104,6 +119,42 @@ func NewClientWithPolicyAndHost(policy *ClientPolicy, hosts ...*Host) (*Client,
 
 }
 
+//-------------------------------------------------------
+// Context
+//-------------------------------------------------------
+
+// WithContext returns a shallow copy of the client, with all of its commands
+// bound to the provided context. Cancelling the context, or reaching its deadline,
+// aborts commands in progress including their socket I/O and retries, and
+// stops scans and queries feeding a Recordset.
+// The returned client shares the cluster and the default policies with the original.
+// Closing either of them closes the cluster for both.
+//
+// Example:
+//
+//  rec, err := client.WithContext(ctx).Get(nil, key)
+func (clnt *Client) WithContext(ctx context.Context) *Client {
+	if ctx == nil {
+		panic("nil context")
+	}
+
+	res := *clnt
+	res.ctx = ctx
+	if res.origin == nil {
+		res.origin = clnt
+	}
+	return &res
+}
+
+// Context returns the context the client commands are bound to.
+// The returned context is always non-nil; it defaults to the background context.
+func (clnt *Client) Context() context.Context {
+	if clnt.ctx == nil {
+		return context.Background()
+	}
+	return clnt.ctx
+}
+
 //-------------------------------------------------------
 // Cluster Connection Management
 //-------------------------------------------------------
@@ -145,6 +196,7 @@ func (clnt *Client) GetNodeNames() []string {
 func (clnt *Client) Put(policy *WritePolicy, key *Key, binMap BinMap) error {
 	policy = clnt.getUsableWritePolicy(policy)
 	command := newWriteCommand(clnt.cluster, policy, key, nil, binMap, WRITE)
+	command.ctx = clnt.Context()
 	return command.Execute()
 }
 
@@ -156,6 +208,7 @@ func (clnt *Client) Put(policy *WritePolicy, key *Key, binMap BinMap) error {
 func (clnt *Client) PutBins(policy *WritePolicy, key *Key, bins ...*Bin) error {
 	policy = clnt.getUsableWritePolicy(policy)
 	command := newWriteCommand(clnt.cluster, policy, key, bins, nil, WRITE)
+	command.ctx = clnt.Context()
 	return command.Execute()
 }
 
@@ -171,6 +224,7 @@ func (clnt *Client) PutBins(policy *WritePolicy, key *Key, bins ...*Bin) error {
 func (clnt *Client) Append(policy *WritePolicy, key *Key, binMap BinMap) error {
 	policy = clnt.getUsableWritePolicy(policy)
 	command := newWriteCommand(clnt.cluster, policy, key, nil, binMap, APPEND)
+	command.ctx = clnt.Context()
 	return command.Execute()
 }
 
@@ -178,6 +232,7 @@ func (clnt *Client) Append(policy *WritePolicy, key *Key, binMap BinMap) error {
 func (clnt *Client) AppendBins(policy *WritePolicy, key *Key, bins ...*Bin) error {
 	policy = clnt.getUsableWritePolicy(policy)
 	command := newWriteCommand(clnt.cluster, policy, key, bins, nil, APPEND)
+	command.ctx = clnt.Context()
 	return command.Execute()
 }
 
@@ -189,6 +244,7 @@ func (clnt *Client) AppendBins(policy *WritePolicy, key *Key, bins ...*Bin) erro
 func (clnt *Client) Prepend(policy *WritePolicy, key *Key, binMap BinMap) error {
 	policy = clnt.getUsableWritePolicy(policy)
 	command := newWriteCommand(clnt.cluster, policy, key, nil, binMap, PREPEND)
+	command.ctx = clnt.Context()
 	return command.Execute()
 }
 
@@ -196,6 +252,7 @@ func (clnt *Client) Prepend(policy *WritePolicy, key *Key, binMap BinMap) error
 func (clnt *Client) PrependBins(policy *WritePolicy, key *Key, bins ...*Bin) error {
 	policy = clnt.getUsableWritePolicy(policy)
 	command := newWriteCommand(clnt.cluster, policy, key, bins, nil, PREPEND)
+	command.ctx = clnt.Context()
 	return command.Execute()
 }
 
@@ -211,6 +268,7 @@ func (clnt *Client) PrependBins(policy *WritePolicy, key *Key, bins ...*Bin) err
 func (clnt *Client) Add(policy *WritePolicy, key *Key, binMap BinMap) error {
 	policy = clnt.getUsableWritePolicy(policy)
 	command := newWriteCommand(clnt.cluster, policy, key, nil, binMap, ADD)
+	command.ctx = clnt.Context()
 	return command.Execute()
 }
 
@@ -218,6 +276,7 @@ func (clnt *Client) Add(policy *WritePolicy, key *Key, binMap BinMap) error {
 func (clnt *Client) AddBins(policy *WritePolicy, key *Key, bins ...*Bin) error {
 	policy = clnt.getUsableWritePolicy(policy)
 	command := newWriteCommand(clnt.cluster, policy, key, bins, nil, ADD)
+	command.ctx = clnt.Context()
 	return command.Execute()
 }
 
@@ -231,6 +290,7 @@ func (clnt *Client) AddBins(policy *WritePolicy, key *Key, bins ...*Bin) error {
 func (clnt *Client) Delete(policy *WritePolicy, key *Key) (bool, error) {
 	policy = clnt.getUsableWritePolicy(policy)
 	command := newDeleteCommand(clnt.cluster, policy, key)
+	command.ctx = clnt.Context()
 	err := command.Execute()
 	return command.Existed(), err
 }
@@ -246,6 +306,7 @@ func (clnt *Client) Delete(policy *WritePolicy, key *Key) (bool, error) {
 func (clnt *Client) Touch(policy *WritePolicy, key *Key) error {
 	policy = clnt.getUsableWritePolicy(policy)
 	command := newTouchCommand(clnt.cluster, policy, key)
+	command.ctx = clnt.Context()
 	return command.Execute()
 }
 
@@ -259,6 +320,7 @@ func (clnt *Client) Touch(policy *WritePolicy, key *Key) error {
 func (clnt *Client) Exists(policy *BasePolicy, key *Key) (bool, error) {
 	policy = clnt.getUsablePolicy(policy)
 	command := newExistsCommand(clnt.cluster, policy, key)
+	command.ctx = clnt.Context()
 	err := command.Execute()
 	return command.Exists(), err
 }
@@ -276,6 +338,7 @@ func (clnt *Client) BatchExists(policy *BatchPolicy, keys []*Key) ([]bool, error
 
 	// pass nil to make sure it will be cloned and prepared
 	cmd := newBatchCommandExists(nil, nil, nil, policy, keys, existsArray)
+	cmd.ctx = clnt.Context()
 	if err := clnt.batchExecute(policy, keys, cmd); err != nil {
 		return nil, err
 	}
@@ -294,6 +357,7 @@ func (clnt *Client) Get(policy *BasePolicy, key *Key, binNames ...string) (*Reco
 	policy = clnt.getUsablePolicy(policy)
 
 	command := newReadCommand(clnt.cluster, policy, key, binNames)
+	command.ctx = clnt.Context()
 	if err := command.Execute(); err != nil {
 		return nil, err
 	}
@@ -308,6 +372,7 @@ func (clnt *Client) GetHeader(policy *BasePolicy, key *Key) (*Record, error) {
 	policy = clnt.getUsablePolicy(policy)
 
 	command := newReadHeaderCommand(clnt.cluster, policy, key)
+	command.ctx = clnt.Context()
 	if err := command.Execute(); err != nil {
 		return nil, err
 	}
@@ -331,6 +396,7 @@ func (clnt *Client) BatchGet(policy *BatchPolicy, keys []*Key, binNames ...strin
 	records := make([]*Record, len(keys))
 
 	cmd := newBatchCommandGet(nil, nil, nil, policy, keys, binNames, records, _INFO1_READ)
+	cmd.ctx = clnt.Context()
 	err := clnt.batchExecute(policy, keys, cmd)
 	if err != nil {
 		return nil, err
@@ -349,6 +415,7 @@ func (clnt *Client) BatchGetComplex(policy *BatchPolicy, records []*BatchRead) e
 	policy = clnt.getUsableBatchPolicy(policy)
 
 	cmd := newBatchIndexCommandGet(nil, policy, records)
+	cmd.ctx = clnt.Context()
 	if err := clnt.batchIndexExecute(policy, records, cmd); err != nil {
 		return err
 	}
@@ -369,6 +436,7 @@ func (clnt *Client) BatchGetHeader(policy *BatchPolicy, keys []*Key) ([]*Record,
 	records := make([]*Record, len(keys))
 
 	cmd := newBatchCommandGet(nil, nil, nil, policy, keys, nil, records, _INFO1_READ|_INFO1_NOBINDATA)
+	cmd.ctx = clnt.Context()
 	err := clnt.batchExecute(policy, keys, cmd)
 	if err != nil {
 		return nil, err
@@ -377,6 +445,97 @@ func (clnt *Client) BatchGetHeader(policy *BatchPolicy, keys []*Key) ([]*Record,
 	return records, nil
 }
 
+//-------------------------------------------------------
+// Batch Write Operations
+//-------------------------------------------------------
+
+// BatchOperate writes, deletes and executes user defined functions on multiple records.
+// Records can be BatchWrite, BatchDelete or BatchUDF, and each has its own policy.
+// Records are grouped by the node of their master partition, and the nodes are
+// processed in concurrent goroutines, as specified by the policy's ConcurrentNodes.
+// The records of a node are sent as single record commands. If pipelining is enabled,
+// up to ClientPolicy.PipelineDepth of them are in flight at once; otherwise they are
+// sent one after the other. The policy's Timeout bounds the whole batch.
+//
+// The result of each record is set in its BatchRec(), including the error of a record
+// whose node could not be determined; errors of the records are not returned.
+// If the policy is nil, the default relevant policy will be used.
+func (clnt *Client) BatchOperate(policy *BatchPolicy, records []BatchRecordIfc) error {
+	policy = clnt.getUsableBatchPolicy(policy)
+
+	batchNodes, err := newBatchRecordNodeList(clnt.cluster, records)
+	if err != nil {
+		return err
+	}
+
+	// the commands of the records are bound to the deadline of the batch
+	bclnt := clnt
+	if policy.Timeout > 0 {
+		ctx, cancel := context.WithTimeout(clnt.Context(), policy.Timeout)
+		defer cancel()
+		bclnt = clnt.WithContext(ctx)
+	}
+
+	// records without a policy use the timeouts of the batch
+	writePolicy := *clnt.getUsableWritePolicy(nil)
+	writePolicy.BasePolicy = policy.BasePolicy
+
+	recordsInFlight := clnt.cluster.clientPolicy.PipelineDepth
+	if recordsInFlight < 1 {
+		recordsInFlight = 1
+	}
+
+	execute := func(bn *batchNode) {
+		if recordsInFlight == 1 || len(bn.offsets) == 1 {
+			for _, offset := range bn.offsets {
+				records[offset].execute(bclnt, &writePolicy)
+			}
+			return
+		}
+
+		slots := make(chan struct{}, recordsInFlight)
+		var wg sync.WaitGroup
+		wg.Add(len(bn.offsets))
+		for _, offset := range bn.offsets {
+			slots <- struct{}{}
+			go func(record BatchRecordIfc) {
+				defer wg.Done()
+				record.execute(bclnt, &writePolicy)
+				<-slots
+			}(records[offset])
+		}
+		wg.Wait()
+	}
+
+	// process the nodes sequentially in the current goroutine
+	if policy.ConcurrentNodes == 1 || len(batchNodes) <= 1 {
+		for _, bn := range batchNodes {
+			execute(bn)
+		}
+		return nil
+	}
+
+	concurrentNodes := policy.ConcurrentNodes
+	if concurrentNodes <= 0 || concurrentNodes > len(batchNodes) {
+		concurrentNodes = len(batchNodes)
+	}
+	slots := make(chan struct{}, concurrentNodes)
+
+	var wg sync.WaitGroup
+	wg.Add(len(batchNodes))
+	for _, bn := range batchNodes {
+		slots <- struct{}{}
+		go func(bn *batchNode) {
+			defer wg.Done()
+			execute(bn)
+			<-slots
+		}(bn)
+	}
+
+	wg.Wait()
+	return nil
+}
+
 //-------------------------------------------------------
 // Generic Database Operations
 //-------------------------------------------------------
@@ -391,6 +550,7 @@ func (clnt *Client) BatchGetHeader(policy *BatchPolicy, keys []*Key) ([]*Record,
 func (clnt *Client) Operate(policy *WritePolicy, key *Key, operations ...*Operation) (*Record, error) {
 	policy = clnt.getUsableWritePolicy(policy)
 	command := newOperateCommand(clnt.cluster, policy, key, operations)
+	command.ctx = clnt.Context()
 	if err := command.Execute(); err != nil {
 		return nil, err
 	}
@@ -423,6 +583,7 @@ func (clnt *Client) ScanAll(apolicy *ScanPolicy, namespace string, setName strin
 	// result recordset
 	taskId := uint64(xornd.Int64())
 	res := newRecordset(policy.RecordQueueSize, len(nodes), taskId)
+	res.limit = newRecordLimiter(policy.MaxRecords, len(nodes))
 
 	// the whole call should be wrapped in a goroutine
 	if policy.ConcurrentNodes {
@@ -451,6 +612,7 @@ func (clnt *Client) ScanNode(apolicy *ScanPolicy, node *Node, namespace string,
 	// results channel must be async for performance
 	taskId := uint64(xornd.Int64())
 	res := newRecordset(policy.RecordQueueSize, 1, taskId)
+	res.limit = newRecordLimiter(policy.MaxRecords, 1)
 
 	go clnt.scanNode(&policy, node, res, namespace, setName, taskId, binNames...)
 	return res, nil
@@ -468,9 +630,95 @@ func (clnt *Client) scanNode(policy *ScanPolicy, node *Node, recordset *Recordse
 	}
 
 	command := newScanCommand(node, policy, namespace, setName, binNames, recordset, taskId)
+	command.ctx = clnt.Context()
 	return command.Execute()
 }
 
+// ScanPartitions reads the records of the partitions in the filter, in the specified
+// namespace and set, from the nodes that own the partitions in the partition map.
+// The progress of each partition is tracked in the filter, so partitions that fail
+// because of node errors or cluster changes are retried on their current owner,
+// and an interrupted scan can be resumed by passing the same filter again.
+// See PartitionFilter for saving the progress and splitting the work.
+// The filter is required; use NewPartitionFilterAll to scan all partitions.
+//
+// The policy's MaxRetries determines how many times the partitions which are not
+// done are retried, and FailOnClusterChange is ignored.
+// This method requires server support for partition scans.
+// If the policy is nil, the default relevant policy will be used.
+func (clnt *Client) ScanPartitions(apolicy *ScanPolicy, filter *PartitionFilter, namespace string, setName string, binNames ...string) (*Recordset, error) {
+	policy := *clnt.getUsableScanPolicy(apolicy)
+
+	if err := filter.validate(); err != nil {
+		return nil, err
+	}
+
+	if len(clnt.cluster.GetNodes()) == 0 {
+		return nil, NewAerospikeError(SERVER_NOT_AVAILABLE, "Scan failed because cluster is empty.")
+	}
+
+	maxConcurrentNodes := policy.MaxConcurrentNodes
+	if !policy.ConcurrentNodes {
+		maxConcurrentNodes = 1
+	}
+
+	taskId := uint64(xornd.Int64())
+	res := newRecordset(policy.RecordQueueSize, 1, taskId)
+	res.limit = newRecordLimiter(policy.MaxRecords, 1)
+	tracker := &partitionTracker{
+		filter:             filter,
+		namespace:          namespace,
+		policy:             policy.BasePolicy,
+		recordset:          res,
+		maxConcurrentNodes: maxConcurrentNodes,
+		supported:          func(node *Node) bool { return node.supportsPartitionScan.Get() },
+	}
+
+	go func() {
+		defer res.signalEnd()
+		err := tracker.run(clnt.Context(), clnt.cluster, func(np *nodePartitions) error {
+			command := newScanCommand(np.node, &policy, namespace, setName, binNames, res, taskId)
+			command.ctx = clnt.Context()
+			command.partitions = np
+			command.maxRecords = np.recordMax
+			return command.execute(command)
+		})
+		if err != nil {
+			res.sendError(err)
+		}
+	}()
+
+	return res, nil
+}
+
+// ScanPage reads a page of at most pageSize records in the specified namespace and set.
+// The token of the returned page is passed to read the next page; an empty token
+// reads the first page. The token is opaque, and stays valid across cluster changes
+// and client restarts. Pages are read by ScanPartitions, and require the same server support.
+// The policy's MaxRecords is ignored.
+// If the policy is nil, the default relevant policy will be used.
+func (clnt *Client) ScanPage(apolicy *ScanPolicy, token string, pageSize int, namespace string, setName string, binNames ...string) (*RecordPage, error) {
+	if pageSize <= 0 {
+		return nil, NewAerospikeError(PARAMETER_ERROR, "Page size must be positive.")
+	}
+
+	filter, err := pageFilter(token)
+	if err != nil {
+		return nil, err
+	}
+
+	policy := *clnt.getUsableScanPolicy(apolicy)
+	multiPolicy := *policy.MultiPolicy
+	multiPolicy.MaxRecords = int64(pageSize)
+	policy.MultiPolicy = &multiPolicy
+
+	recordset, err := clnt.ScanPartitions(&policy, filter, namespace, setName, binNames...)
+	if err != nil {
+		return nil, err
+	}
+	return readPage(recordset, filter)
+}
+
 //---------------------------------------------------------------
 // User defined functions (Supported by Aerospike 3 servers only)
 //---------------------------------------------------------------
@@ -630,6 +878,7 @@ func (clnt *Client) ListUDF(policy *BasePolicy) ([]*UDF, error) {
 func (clnt *Client) Execute(policy *WritePolicy, key *Key, packageName string, functionName string, args ...Value) (interface{}, error) {
 	policy = clnt.getUsableWritePolicy(policy)
 	command := newExecuteCommand(clnt.cluster, policy, key, packageName, functionName, NewValueArray(args))
+	command.ctx = clnt.Context()
 	if err := command.Execute(); err != nil {
 		return nil, err
 	}
@@ -698,12 +947,13 @@ func (clnt *Client) ExecuteUDF(policy *QueryPolicy,
 	errs := []error{}
 	for i := range nodes {
 		command := newServerCommand(nodes[i], policy, statement)
+		command.ctx = clnt.Context()
 		if err := command.Execute(); err != nil {
 			errs = append(errs, err)
 		}
 	}
 
-	return NewExecuteTask(clnt.cluster, statement), mergeErrors(errs)
+	return NewExecuteTask(clnt.cluster, statement), MergeErrors(errs)
 }
 
 // ExecuteUDFNode applies user defined function on records that match the statement filter on the specified node.
@@ -735,6 +985,7 @@ func (clnt *Client) ExecuteUDFNode(policy *QueryPolicy,
 	statement.SetAggregateFunction(packageName, functionName, functionArgs, false)
 
 	command := newServerCommand(node, policy, statement)
+	command.ctx = clnt.Context()
 	err := command.Execute()
 
 	return NewExecuteTask(clnt.cluster, statement), err
@@ -798,6 +1049,7 @@ func (clnt *Client) QueryAggregate(policy *QueryPolicy, statement *Statement, pa
 		// copy policies to avoid race conditions
 		newPolicy := *policy
 		command := newQueryAggregateCommand(node, &newPolicy, statement, recSet)
+		command.ctx = clnt.Context()
 		command.luaInstance = luaInstance
 		command.inputChan = inputChan
 
@@ -823,6 +1075,9 @@ func (clnt *Client) QueryAggregate(policy *QueryPolicy, statement *Statement, pa
 		}()
 
 		for val := range outputChan {
+			if v, ok := val.(lualib.GeoJSON); ok {
+				val = NewGeoJSONValue(string(v))
+			}
 			recSet.Records <- &Record{Bins: BinMap{"SUCCESS": val}}
 		}
 	}()
@@ -833,7 +1088,7 @@ func (clnt *Client) QueryAggregate(policy *QueryPolicy, statement *Statement, pa
 
 		err := luaInstance.DoFile(lualib.LuaPath() + packageName + ".lua")
 		if err != nil {
-			recSet.Errors <- err
+			recSet.Errors <- lualib.NewError(err)
 			return
 		}
 
@@ -851,7 +1106,7 @@ func (clnt *Client) QueryAggregate(policy *QueryPolicy, statement *Statement, pa
 		},
 			luaArgs...,
 		); err != nil {
-			recSet.Errors <- err
+			recSet.Errors <- lualib.NewError(err)
 			return
 		}
 
@@ -862,6 +1117,92 @@ func (clnt *Client) QueryAggregate(policy *QueryPolicy, statement *Statement, pa
 	return recSet, nil
 }
 
+// Aggregate executes a query and runs the Go-native aggregation pipeline on its records.
+// The pipeline runs concurrently on the records of each node, and the partial results
+// of the nodes are merged into the returned result. Unlike QueryAggregate, no Lua
+// modules are needed on the client or the server.
+//
+// The statement must not have an aggregate function set.
+// If the policy is nil, the default relevant policy will be used.
+func (clnt *Client) Aggregate(policy *QueryPolicy, statement *Statement, aggregation *Aggregation) (interface{}, error) {
+	if err := aggregation.validate(); err != nil {
+		return nil, err
+	}
+
+	if statement.functionName != "" {
+		return nil, NewAerospikeError(PARAMETER_ERROR, "Aggregate cannot be used with a statement that has an aggregate function set.")
+	}
+
+	policy = clnt.getUsableQueryPolicy(policy)
+
+	nodes := clnt.cluster.GetNodes()
+	if len(nodes) == 0 {
+		return nil, NewAerospikeError(SERVER_NOT_AVAILABLE, "Aggregate failed because cluster is empty.")
+	}
+
+	if policy.WaitUntilMigrationsAreOver {
+		// wait until all migrations are finished
+		if err := clnt.cluster.WaitUntillMigrationIsFinished(policy.Timeout); err != nil {
+			return nil, err
+		}
+	}
+
+	type partialResult struct {
+		acc interface{}
+		err error
+	}
+
+	// the nodes share the record limit
+	limit := newRecordLimiter(policy.MaxRecords, len(nodes))
+	partials := make(chan partialResult, len(nodes))
+	recordsets := make([]*Recordset, len(nodes))
+	for i, node := range nodes {
+		recSet := newRecordset(policy.RecordQueueSize, 1, statement.TaskId)
+		recSet.limit = limit
+		recordsets[i] = recSet
+
+		// copy policies to avoid race conditions
+		newPolicy := *policy
+		command := newQueryRecordCommand(node, &newPolicy, statement, recSet)
+		command.ctx = clnt.Context()
+		go func() {
+			command.Execute()
+		}()
+
+		go func() {
+			acc, err := aggregation.partial(recSet.Results())
+			partials <- partialResult{acc: acc, err: err}
+		}()
+	}
+
+	// stop all the nodes when done, or on the first error
+	defer func() {
+		for _, recSet := range recordsets {
+			recSet.Close()
+		}
+	}()
+
+	var acc interface{}
+	for range nodes {
+		partial := <-partials
+		if partial.err != nil {
+			return nil, partial.err
+		}
+
+		if acc == nil {
+			acc = partial.acc
+			continue
+		}
+
+		var err error
+		if acc, err = aggregation.merge(acc, partial.acc); err != nil {
+			return nil, err
+		}
+	}
+
+	return aggregation.result(acc), nil
+}
+
 //--------------------------------------------------------
 // Query functions (Supported by Aerospike 3 servers only)
 //--------------------------------------------------------
@@ -890,12 +1231,14 @@ func (clnt *Client) Query(policy *QueryPolicy, statement *Statement) (*Recordset
 
 	// results channel must be async for performance
 	recSet := newRecordset(policy.RecordQueueSize, len(nodes), statement.TaskId)
+	recSet.limit = newRecordLimiter(policy.MaxRecords, len(nodes))
 
 	// results channel must be async for performance
 	for _, node := range nodes {
 		// copy policies to avoid race conditions
 		newPolicy := *policy
 		command := newQueryRecordCommand(node, &newPolicy, statement, recSet)
+		command.ctx = clnt.Context()
 		go func() {
 			command.Execute()
 		}()
@@ -922,10 +1265,12 @@ func (clnt *Client) QueryNode(policy *QueryPolicy, node *Node, statement *Statem
 
 	// results channel must be async for performance
 	recSet := newRecordset(policy.RecordQueueSize, 1, statement.TaskId)
+	recSet.limit = newRecordLimiter(policy.MaxRecords, 1)
 
 	// copy policies to avoid race conditions
 	newPolicy := *policy
 	command := newQueryRecordCommand(node, &newPolicy, statement, recSet)
+	command.ctx = clnt.Context()
 	go func() {
 		command.Execute()
 	}()
@@ -933,6 +1278,81 @@ func (clnt *Client) QueryNode(policy *QueryPolicy, node *Node, statement *Statem
 	return recSet, nil
 }
 
+// QueryPartitions executes a query on the partitions in the filter, on the nodes
+// that own the partitions in the partition map, and returns a recordset.
+// The progress of each partition is tracked in the filter like in ScanPartitions,
+// which allows retrying partitions after cluster changes and resuming the query.
+//
+// This method requires server support for partition queries.
+// If the policy is nil, the default relevant policy will be used.
+func (clnt *Client) QueryPartitions(policy *QueryPolicy, statement *Statement, filter *PartitionFilter) (*Recordset, error) {
+	policy = clnt.getUsableQueryPolicy(policy)
+
+	if err := filter.validate(); err != nil {
+		return nil, err
+	}
+
+	if len(clnt.cluster.GetNodes()) == 0 {
+		return nil, NewAerospikeError(SERVER_NOT_AVAILABLE, "Query failed because cluster is empty.")
+	}
+
+	// copy policies to avoid race conditions
+	newPolicy := *policy
+	res := newRecordset(policy.RecordQueueSize, 1, statement.TaskId)
+	res.limit = newRecordLimiter(policy.MaxRecords, 1)
+	tracker := &partitionTracker{
+		filter:             filter,
+		namespace:          statement.Namespace,
+		policy:             newPolicy.BasePolicy,
+		recordset:          res,
+		maxConcurrentNodes: newPolicy.MaxConcurrentNodes,
+		supported:          func(node *Node) bool { return node.supportsPartitionQuery.Get() },
+	}
+
+	go func() {
+		defer res.signalEnd()
+		err := tracker.run(clnt.Context(), clnt.cluster, func(np *nodePartitions) error {
+			command := newQueryRecordCommand(np.node, &newPolicy, statement, res)
+			command.ctx = clnt.Context()
+			command.partitions = np
+			command.maxRecords = np.recordMax
+			return command.execute(command)
+		})
+		if err != nil {
+			res.sendError(err)
+		}
+	}()
+
+	return res, nil
+}
+
+// QueryPage executes the query on the nodes, and returns a page of at most pageSize records.
+// The token of the returned page is passed to read the next page; an empty token
+// reads the first page. Pages are read by QueryPartitions, and require the same server support.
+// The policy's MaxRecords is ignored.
+// If the policy is nil, the default relevant policy will be used.
+func (clnt *Client) QueryPage(policy *QueryPolicy, statement *Statement, token string, pageSize int) (*RecordPage, error) {
+	if pageSize <= 0 {
+		return nil, NewAerospikeError(PARAMETER_ERROR, "Page size must be positive.")
+	}
+
+	filter, err := pageFilter(token)
+	if err != nil {
+		return nil, err
+	}
+
+	newPolicy := *clnt.getUsableQueryPolicy(policy)
+	multiPolicy := *newPolicy.MultiPolicy
+	multiPolicy.MaxRecords = int64(pageSize)
+	newPolicy.MultiPolicy = &multiPolicy
+
+	recordset, err := clnt.QueryPartitions(&newPolicy, statement, filter)
+	if err != nil {
+		return nil, err
+	}
+	return readPage(recordset, filter)
+}
+
 // CreateIndex creates a secondary index.
 // This asynchronous server call will return before the command is complete.
 // The user can optionally wait for command completion by using the returned
@@ -1056,6 +1476,400 @@ func (clnt *Client) DropIndex(
 	return NewAerospikeError(INDEX_GENERIC, "Drop index failed: "+response)
 }
 
+// ListIndexes returns the secondary indexes of the namespace, sorted by name.
+// Their state and statistics are aggregated over all nodes of the cluster.
+// If the policy is nil, the default relevant policy will be used.
+func (clnt *Client) ListIndexes(policy *InfoPolicy, namespace string) ([]*IndexInfo, error) {
+	policy = clnt.getUsableInfoPolicy(policy)
+
+	command := "sindex/" + namespace
+	nodes, responses, err := clnt.requestNodesInfo(policy, command)
+	if err != nil {
+		return nil, err
+	}
+
+	var res []*IndexInfo
+	byName := map[string]*IndexInfo{}
+	for i, node := range nodes {
+		response := responses[i][command]
+		if infoFailed(response) {
+			return nil, NewAerospikeError(INDEX_GENERIC, "List indexes failed: "+response)
+		}
+
+		indexes := parseIndexList(response)
+		if len(indexes) == 0 {
+			continue
+		}
+
+		// the statistics are requested from the node that listed the indexes
+		commands := make([]string, len(indexes))
+		for j, idx := range indexes {
+			commands[j] = command + "/" + idx.Name
+		}
+
+		statsMap, err := node.requestInfo(policy.Timeout, commands...)
+		if err != nil {
+			return nil, err
+		}
+
+		for j, idx := range indexes {
+			stats := parseIndexStats(statsMap[commands[j]])
+
+			known := byName[idx.Name]
+			if known == nil {
+				idx.LoadPercent = stats.loadPercent
+				idx.Entries = stats.entries
+				byName[idx.Name] = idx
+				res = append(res, idx)
+				continue
+			}
+
+			if known.State == "RW" {
+				known.State = idx.State
+			}
+			if stats.loadPercent < known.LoadPercent {
+				known.LoadPercent = stats.loadPercent
+			}
+			known.Entries += stats.entries
+		}
+	}
+
+	sort.Sort(byIndexName(res))
+	return res, nil
+}
+
+// GetIndexStats returns the statistics of a secondary index on all nodes of the cluster.
+// If the index does not exist, an INDEX_NOTFOUND error is returned.
+// If the policy is nil, the default relevant policy will be used.
+func (clnt *Client) GetIndexStats(policy *InfoPolicy, namespace string, indexName string) (*IndexStats, error) {
+	policy = clnt.getUsableInfoPolicy(policy)
+
+	command := "sindex/" + namespace + "/" + indexName
+	nodes, responses, err := clnt.requestNodesInfo(policy, command)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &IndexStats{LoadPercent: 100, Nodes: make(map[string]map[string]string, len(nodes))}
+	for i, node := range nodes {
+		response := responses[i][command]
+		if strings.Contains(response, "FAIL:201") {
+			// the index does not exist on the node
+			continue
+		} else if infoFailed(response) {
+			return nil, NewAerospikeError(INDEX_GENERIC, "Get index stats failed: "+response)
+		}
+
+		stats := parseIndexStats(response)
+		res.Entries += stats.entries
+		res.MemoryUsed += stats.memoryUsed
+		if stats.loadPercent < res.LoadPercent {
+			res.LoadPercent = stats.loadPercent
+		}
+		res.Nodes[node.GetName()] = stats.params
+	}
+
+	if len(res.Nodes) == 0 {
+		return nil, NewAerospikeError(INDEX_NOTFOUND)
+	}
+	return res, nil
+}
+
+// EnsureIndex creates a secondary index if it does not exist. If an index with the
+// same name exists with another definition, it is dropped and created again.
+// It is idempotent, and returns a task that can be used to wait until the index
+// is built, whether or not it was created by this call.
+// If the policy is nil, the default relevant policy will be used.
+func (clnt *Client) EnsureIndex(policy *WritePolicy, index *IndexDefinition) (*IndexTask, error) {
+	policy = clnt.getUsableWritePolicy(policy)
+
+	migration := &IndexMigration{Indexes: []*IndexDefinition{index}}
+	if _, _, err := clnt.migrateIndexes(policy, migration); err != nil {
+		return nil, err
+	}
+	return NewIndexTask(clnt.cluster, index.Namespace, index.Name), nil
+}
+
+// MigrateIndexes applies a declarative index migration, typically on application
+// startup: indexes to drop are dropped, and declared indexes are created or recreated
+// as in EnsureIndex. It blocks until all declared indexes are built, and returns the
+// changes made, drops first. The wait is bounded by the policy's Timeout if it is set,
+// and by the context of the client.
+// If the policy is nil, the default relevant policy will be used.
+func (clnt *Client) MigrateIndexes(policy *WritePolicy, migration *IndexMigration) ([]*IndexChange, error) {
+	policy = clnt.getUsableWritePolicy(policy)
+
+	changes, ready, err := clnt.migrateIndexes(policy, migration)
+	if err != nil {
+		return changes, err
+	}
+
+	// wait for the indexes being built
+	var tasks []*IndexTask
+	for _, def := range migration.Indexes {
+		if !ready[def.Namespace+"."+def.Name] {
+			tasks = append(tasks, NewIndexTask(clnt.cluster, def.Namespace, def.Name))
+		}
+	}
+
+	return changes, clnt.waitIndexTasks(policy, tasks)
+}
+
+// waitIndexTasks polls the tasks like IndexTask.OnComplete, until they are done,
+// the timeout of the policy expires or the context of the client is done.
+// The tasks are polled in the current goroutine, so nothing is left running on return.
+func (clnt *Client) waitIndexTasks(policy *WritePolicy, tasks []*IndexTask) error {
+	var timeout <-chan time.Time
+	if policy.Timeout > 0 {
+		timer := time.NewTimer(policy.Timeout)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+
+	ctx := clnt.Context()
+	const interval = 1 * time.Second
+	for len(tasks) > 0 {
+		select {
+		case <-time.After(interval):
+		case <-timeout:
+			return NewAerospikeError(TIMEOUT, "Index migration timed out waiting for the indexes to be built. See `Policy.Timeout`")
+		case <-ctx.Done():
+			return contextError(ctx)
+		}
+
+		pending := tasks[:0]
+		for _, task := range tasks {
+			done, err := task.IsDone()
+			task.retries++
+			if err != nil {
+				return err
+			}
+			if !done {
+				pending = append(pending, task)
+			}
+		}
+		tasks = pending
+	}
+	return nil
+}
+
+// migrateIndexes applies the changes of the migration. It returns the changes made,
+// and the existing indexes that were already built and left unchanged.
+func (clnt *Client) migrateIndexes(policy *WritePolicy, migration *IndexMigration) ([]*IndexChange, map[string]bool, error) {
+	infoPolicy := &InfoPolicy{Timeout: policy.Timeout}
+
+	var existing []*IndexInfo
+	for _, namespace := range migration.namespaces() {
+		indexes, err := clnt.ListIndexes(infoPolicy, namespace)
+		if err != nil {
+			return nil, nil, err
+		}
+		existing = append(existing, indexes...)
+	}
+
+	changes, err := planIndexChanges(existing, migration)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ready := make(map[string]bool, len(existing))
+	for _, idx := range existing {
+		ready[idx.Namespace+"."+idx.Name] = idx.Ready()
+	}
+
+	for i, change := range changes {
+		def := change.Index
+		ready[def.Namespace+"."+def.Name] = false
+
+		if change.Action != IndexCreated {
+			if err := clnt.DropIndex(policy, def.Namespace, def.SetName, def.Name); err != nil {
+				return changes[:i], nil, err
+			}
+		}
+
+		if change.Action != IndexDropped {
+			_, err := clnt.CreateComplexIndex(policy, def.Namespace, def.SetName, def.Name, def.BinName, def.Type, def.CollectionType)
+			if aerr, ok := err.(AerospikeError); ok && aerr.ResultCode() == INDEX_FOUND {
+				switch change.Action {
+				case IndexCreated:
+					// the index was created concurrently
+					err = nil
+				case IndexRecreated:
+					// the drop may not have taken effect yet, leaving the old definition;
+					// only an index created concurrently with the new definition is accepted
+					if matches, lerr := clnt.indexMatches(infoPolicy, def); lerr != nil {
+						err = lerr
+					} else if matches {
+						err = nil
+					}
+				}
+			}
+			if err != nil {
+				return changes[:i], nil, err
+			}
+		}
+	}
+	return changes, ready, nil
+}
+
+// indexMatches returns true if the index exists with the definition.
+func (clnt *Client) indexMatches(policy *InfoPolicy, def *IndexDefinition) (bool, error) {
+	indexes, err := clnt.ListIndexes(policy, def.Namespace)
+	if err != nil {
+		return false, err
+	}
+
+	for _, idx := range indexes {
+		if idx.Name == def.Name {
+			return def.matches(idx), nil
+		}
+	}
+	return false, nil
+}
+
+// GetClusterInfo returns the name, nodes, namespaces and features of the cluster.
+// Features are only reported if they are supported by all nodes.
+// If the policy is nil, the default relevant policy will be used.
+func (clnt *Client) GetClusterInfo(policy *InfoPolicy) (*ClusterInfo, error) {
+	policy = clnt.getUsableInfoPolicy(policy)
+
+	nodes, responses, err := clnt.requestNodesInfo(policy, "node", "build", "features", "namespaces", "cluster-name")
+	if err != nil {
+		return nil, err
+	}
+
+	name := ""
+	infos := make([]*NodeInfo, len(nodes))
+	for i, node := range nodes {
+		res := responses[i]
+		infos[i] = &NodeInfo{
+			Name:       res["node"],
+			Address:    node.GetHost().String(),
+			Build:      res["build"],
+			Features:   parseInfoList(res["features"], ";"),
+			Namespaces: parseInfoList(res["namespaces"], ";"),
+		}
+		if len(infos[i].Name) == 0 {
+			infos[i].Name = node.GetName()
+		}
+
+		// servers without a configured cluster name return "null"
+		if cn := res["cluster-name"]; len(name) == 0 && cn != "null" && !infoFailed(cn) {
+			name = cn
+		}
+	}
+
+	return newClusterInfo(name, infos), nil
+}
+
+// ListNamespaces returns the namespaces found on any node of the cluster, sorted.
+// If the policy is nil, the default relevant policy will be used.
+func (clnt *Client) ListNamespaces(policy *InfoPolicy) ([]string, error) {
+	policy = clnt.getUsableInfoPolicy(policy)
+
+	_, responses, err := clnt.requestNodesInfo(policy, "namespaces")
+	if err != nil {
+		return nil, err
+	}
+
+	namespaces := map[string]bool{}
+	for _, res := range responses {
+		for _, ns := range parseInfoList(res["namespaces"], ";") {
+			namespaces[ns] = true
+		}
+	}
+	return sortedKeys(namespaces), nil
+}
+
+// GetNamespaceInfo returns the statistics of the namespace, summed over all nodes of the cluster.
+// The raw statistics of each node are available in NamespaceInfo.Nodes.
+// If the policy is nil, the default relevant policy will be used.
+func (clnt *Client) GetNamespaceInfo(policy *InfoPolicy, namespace string) (*NamespaceInfo, error) {
+	policy = clnt.getUsableInfoPolicy(policy)
+
+	command := "namespace/" + namespace
+	nodes, responses, err := clnt.requestNodesInfo(policy, command)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &NamespaceInfo{Name: namespace, Nodes: map[string]map[string]string{}}
+	for i, node := range nodes {
+		response := responses[i][command]
+		info := parseNamespaceInfo(namespace, response)
+		if info == nil {
+			return nil, NewAerospikeError(INVALID_NAMESPACE, "Namespace "+namespace+" not found on node "+node.GetName())
+		}
+
+		res.merge(info)
+		res.Nodes[node.GetName()] = parseInfoParams(response, ";")
+	}
+	return res, nil
+}
+
+// ListSets returns the sets of the namespace, sorted by name.
+// Their statistics are summed over all nodes of the cluster.
+// If the policy is nil, the default relevant policy will be used.
+func (clnt *Client) ListSets(policy *InfoPolicy, namespace string) ([]*SetInfo, error) {
+	policy = clnt.getUsableInfoPolicy(policy)
+
+	command := "sets/" + namespace
+	_, responses, err := clnt.requestNodesInfo(policy, command)
+	if err != nil {
+		return nil, err
+	}
+
+	var res bySetName
+	byName := map[string]*SetInfo{}
+	for _, responseMap := range responses {
+		response := responseMap[command]
+		if infoFailed(response) {
+			return nil, NewAerospikeError(INVALID_NAMESPACE, "List sets failed: "+response)
+		}
+
+		for _, set := range parseSetList(response) {
+			if known := byName[set.Name]; known != nil {
+				known.merge(set)
+				continue
+			}
+			if len(set.Namespace) == 0 {
+				set.Namespace = namespace
+			}
+			byName[set.Name] = set
+			res = append(res, set)
+		}
+	}
+
+	sort.Sort(res)
+	return res, nil
+}
+
+// ListBins returns the bin names of the namespace found on any node of the cluster, sorted.
+// Namespaces on servers 5.0 and later do not track bin names, and return no bins.
+// If the policy is nil, the default relevant policy will be used.
+func (clnt *Client) ListBins(policy *InfoPolicy, namespace string) ([]string, error) {
+	policy = clnt.getUsableInfoPolicy(policy)
+
+	command := "bins/" + namespace
+	_, responses, err := clnt.requestNodesInfo(policy, command)
+	if err != nil {
+		return nil, err
+	}
+
+	bins := map[string]bool{}
+	for _, responseMap := range responses {
+		response := responseMap[command]
+		if infoFailed(response) {
+			return nil, NewAerospikeError(INVALID_NAMESPACE, "List bins failed: "+response)
+		}
+
+		for _, bin := range parseBinNames(response) {
+			bins[bin] = true
+		}
+	}
+	return sortedKeys(bins), nil
+}
+
 // Remove records in specified namespace/set efficiently.  This method is many orders of magnitude
 // faster than deleting records one at a time.  Works with Aerospike Server versions >= 3.12.
 // This asynchronous server call may return before the truncation is complete.  The user can still
@@ -1105,6 +1919,7 @@ func (clnt *Client) CreateUser(policy *AdminPolicy, user string, password string
 		return err
 	}
 	command := newAdminCommand(nil)
+	command.ctx = clnt.Context()
 	return command.createUser(clnt.cluster, policy, user, hash, roles)
 }
 
@@ -1113,6 +1928,7 @@ func (clnt *Client) DropUser(policy *AdminPolicy, user string) error {
 	policy = clnt.getUsableAdminPolicy(policy)
 
 	command := newAdminCommand(nil)
+	command.ctx = clnt.Context()
 	return command.dropUser(clnt.cluster, policy, user)
 }
 
@@ -1129,6 +1945,7 @@ func (clnt *Client) ChangePassword(policy *AdminPolicy, user string, password st
 		return err
 	}
 	command := newAdminCommand(nil)
+	command.ctx = clnt.Context()
 
 	if user == clnt.cluster.user {
 		// Change own password.
@@ -1152,6 +1969,7 @@ func (clnt *Client) GrantRoles(policy *AdminPolicy, user string, roles []string)
 	policy = clnt.getUsableAdminPolicy(policy)
 
 	command := newAdminCommand(nil)
+	command.ctx = clnt.Context()
 	return command.grantRoles(clnt.cluster, policy, user, roles)
 }
 
@@ -1160,6 +1978,7 @@ func (clnt *Client) RevokeRoles(policy *AdminPolicy, user string, roles []string
 	policy = clnt.getUsableAdminPolicy(policy)
 
 	command := newAdminCommand(nil)
+	command.ctx = clnt.Context()
 	return command.revokeRoles(clnt.cluster, policy, user, roles)
 }
 
@@ -1168,6 +1987,7 @@ func (clnt *Client) QueryUser(policy *AdminPolicy, user string) (*UserRoles, err
 	policy = clnt.getUsableAdminPolicy(policy)
 
 	command := newAdminCommand(nil)
+	command.ctx = clnt.Context()
 	return command.queryUser(clnt.cluster, policy, user)
 }
 
@@ -1176,6 +1996,7 @@ func (clnt *Client) QueryUsers(policy *AdminPolicy) ([]*UserRoles, error) {
 	policy = clnt.getUsableAdminPolicy(policy)
 
 	command := newAdminCommand(nil)
+	command.ctx = clnt.Context()
 	return command.queryUsers(clnt.cluster, policy)
 }
 
@@ -1184,6 +2005,7 @@ func (clnt *Client) QueryRole(policy *AdminPolicy, role string) (*Role, error) {
 	policy = clnt.getUsableAdminPolicy(policy)
 
 	command := newAdminCommand(nil)
+	command.ctx = clnt.Context()
 	return command.queryRole(clnt.cluster, policy, role)
 }
 
@@ -1192,6 +2014,7 @@ func (clnt *Client) QueryRoles(policy *AdminPolicy) ([]*Role, error) {
 	policy = clnt.getUsableAdminPolicy(policy)
 
 	command := newAdminCommand(nil)
+	command.ctx = clnt.Context()
 	return command.queryRoles(clnt.cluster, policy)
 }
 
@@ -1200,6 +2023,7 @@ func (clnt *Client) CreateRole(policy *AdminPolicy, roleName string, privileges
 	policy = clnt.getUsableAdminPolicy(policy)
 
 	command := newAdminCommand(nil)
+	command.ctx = clnt.Context()
 	return command.createRole(clnt.cluster, policy, roleName, privileges)
 }
 
@@ -1208,6 +2032,7 @@ func (clnt *Client) DropRole(policy *AdminPolicy, roleName string) error {
 	policy = clnt.getUsableAdminPolicy(policy)
 
 	command := newAdminCommand(nil)
+	command.ctx = clnt.Context()
 	return command.dropRole(clnt.cluster, policy, roleName)
 }
 
@@ -1216,6 +2041,7 @@ func (clnt *Client) GrantPrivileges(policy *AdminPolicy, roleName string, privil
 	policy = clnt.getUsableAdminPolicy(policy)
 
 	command := newAdminCommand(nil)
+	command.ctx = clnt.Context()
 	return command.grantPrivileges(clnt.cluster, policy, roleName, privileges)
 }
 
@@ -1224,6 +2050,7 @@ func (clnt *Client) RevokePrivileges(policy *AdminPolicy, roleName string, privi
 	policy = clnt.getUsableAdminPolicy(policy)
 
 	command := newAdminCommand(nil)
+	command.ctx = clnt.Context()
 	return command.revokePrivileges(clnt.cluster, policy, roleName, privileges)
 }
 
@@ -1241,15 +2068,15 @@ func (clnt *Client) String() string {
 }
 
 // Stats returns internal statistics regarding the inner state of the client and the cluster.
+// The statistics are returned in their JSON form; use StatsSnapshot to access them as a Go type.
 func (clnt *Client) Stats() (map[string]interface{}, error) {
-	resStats := clnt.cluster.statsCopy()
+	stats := clnt.StatsSnapshot()
 
-	clusterStats := nodeStats{}
-	for _, stats := range resStats {
-		clusterStats.aggregate(&stats)
+	resStats := make(map[string]interface{}, len(stats.Nodes)+1)
+	for h, nodeStats := range stats.Nodes {
+		resStats[h] = nodeStats
 	}
-
-	resStats["cluster-aggregated-stats"] = clusterStats
+	resStats["cluster-aggregated-stats"] = stats.Cluster
 
 	b, err := json.Marshal(resStats)
 	if err != nil {
@@ -1262,16 +2089,39 @@ func (clnt *Client) Stats() (map[string]interface{}, error) {
 		return nil, err
 	}
 
-	res["open-connections"] = clusterStats.ConnectionsOpen
+	res["open-connections"] = stats.Cluster.ConnectionsOpen
 
 	return res, nil
 }
 
+// StatsSnapshot returns the internal statistics of the client per node, and aggregated for
+// the cluster: connection and tend counters, and the attempts, errors, result codes and
+// latency histogram of each command type.
+// Statistics are aggregated once per tend interval.
+func (clnt *Client) StatsSnapshot() *ClientStats {
+	resStats := clnt.cluster.statsCopy()
+
+	res := &ClientStats{Nodes: make(map[string]*NodeStats, len(resStats))}
+	clusterStats := nodeStats{}
+	for h, stats := range resStats {
+		clusterStats.aggregate(&stats)
+		res.Nodes[h] = stats.export()
+	}
+	res.Cluster = clusterStats.export()
+
+	return res
+}
+
 //-------------------------------------------------------
 // Internal Methods
 //-------------------------------------------------------
 
 func (clnt *Client) sendInfoCommand(timeout time.Duration, command string) (map[string]string, error) {
+	ctx := clnt.Context()
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
 	node, err := clnt.cluster.GetRandomNode()
 	if err != nil {
 		return nil, err
@@ -1284,15 +2134,48 @@ func (clnt *Client) sendInfoCommand(timeout time.Duration, command string) (map[
 		return nil, err
 	}
 
+	attempt := startAttempt(node, CMD_INFO, "", "", 1, ctx)
+	attempt.attach(node.tendConn)
+
+	stopWatch := node.tendConn.watchContext(ctx)
 	results, err := RequestInfo(node.tendConn, command)
+	stopWatch()
 	if err != nil {
 		node.tendConn.Close()
+		if ctx.Err() != nil {
+			err = ctx.Err()
+		}
+		attempt.finish(err)
 		return nil, err
 	}
 
+	attempt.finish(nil)
 	return results, nil
 }
 
+// requestNodesInfo sends the info commands to all nodes of the cluster,
+// and returns the nodes and their responses in the same order.
+func (clnt *Client) requestNodesInfo(policy *InfoPolicy, commands ...string) ([]*Node, []map[string]string, error) {
+	if err := clnt.Context().Err(); err != nil {
+		return nil, nil, err
+	}
+
+	nodes := clnt.cluster.GetNodes()
+	if len(nodes) == 0 {
+		return nil, nil, NewAerospikeError(SERVER_NOT_AVAILABLE, "Info command failed because cluster is empty.")
+	}
+
+	responses := make([]map[string]string, len(nodes))
+	for i, node := range nodes {
+		res, err := node.requestInfo(policy.Timeout, commands...)
+		if err != nil {
+			return nil, nil, err
+		}
+		responses[i] = res
+	}
+	return nodes, responses, nil
+}
+
 // batchExecute Uses sync.WaitGroup to run commands using multiple goroutines,
 // and waits for their return
 func (clnt *Client) batchExecute(policy *BatchPolicy, keys []*Key, cmd batcher) error {
@@ -1331,7 +2214,7 @@ func (clnt *Client) batchExecute(policy *BatchPolicy, keys []*Key, cmd batcher)
 	}
 
 	wg.Wait()
-	return mergeErrors(errs)
+	return MergeErrors(errs)
 }
 
 // batchExecute Uses sync.WaitGroup to run commands using multiple goroutines,
@@ -1363,7 +2246,7 @@ func (clnt *Client) batchIndexExecute(policy *BatchPolicy, records []*BatchRead,
 	}
 
 	wg.Wait()
-	return mergeErrors(errs)
+	return MergeErrors(errs)
 }
 
 func (clnt *Client) getUsablePolicy(policy *BasePolicy) *BasePolicy {
@@ -1426,23 +2309,12 @@ func (clnt *Client) getUsableAdminPolicy(policy *AdminPolicy) *AdminPolicy {
 	return policy
 }
 
-//-------------------------------------------------------
-// Utility Functions
-//-------------------------------------------------------
-
-// mergeErrors merges several errors into one
-func mergeErrors(errs []error) error {
-	if len(errs) == 0 {
-		return nil
-	}
-	var msg bytes.Buffer
-	for _, err := range errs {
-		if _, err := msg.WriteString(err.Error()); err != nil {
-			return err
-		}
-		if _, err := msg.WriteString("\n"); err != nil {
-			return err
+func (clnt *Client) getUsableInfoPolicy(policy *InfoPolicy) *InfoPolicy {
+	if policy == nil {
+		if clnt.DefaultInfoPolicy != nil {
+			return clnt.DefaultInfoPolicy
 		}
+		return NewInfoPolicy()
 	}
-	return errors.New(msg.String())
+	return policy
 }
@@ -0,0 +1,242 @@
+// Copyright 2013-2017 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aerospike
+
+import (
+	"context"
+	"time"
+
+	. "github.com/aerospike/aerospike-client-go/types"
+)
+
+// asyncPipeline caps the number of asynchronous commands in flight on a node.
+// A nil pipeline does not cap the commands.
+type asyncPipeline chan struct{}
+
+func newAsyncPipeline(size int) asyncPipeline {
+	if size <= 0 {
+		return nil
+	}
+	return make(asyncPipeline, size)
+}
+
+// acquire waits for a free slot until the context is done or the deadline passes.
+// A zero deadline waits until the context is done.
+func (p asyncPipeline) acquire(ctx context.Context, deadline time.Time) error {
+	if p == nil {
+		return nil
+	}
+
+	// fast path
+	select {
+	case p <- struct{}{}:
+		return nil
+	default:
+	}
+
+	var timeout <-chan time.Time
+	if !deadline.IsZero() {
+		timer := time.NewTimer(deadline.Sub(time.Now()))
+		defer timer.Stop()
+		timeout = timer.C
+	}
+
+	select {
+	case p <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return contextError(ctx)
+	case <-timeout:
+		return NewAerospikeError(TIMEOUT, "command execution timed out on client: no free slot in the node's async pipeline. See `ClientPolicy.AsyncMaxCommandsPerNode`")
+	}
+}
+
+func (p asyncPipeline) release() {
+	<-p
+}
+
+// RecordFuture is the pending result of an asynchronous command that returns a record.
+type RecordFuture struct {
+	done   chan struct{}
+	record *Record
+	err    error
+}
+
+func newRecordFuture() *RecordFuture {
+	return &RecordFuture{done: make(chan struct{})}
+}
+
+func (f *RecordFuture) complete(record *Record, err error) {
+	f.record, f.err = record, err
+	close(f.done)
+}
+
+// Done returns a channel that is closed when the command has completed.
+func (f *RecordFuture) Done() <-chan struct{} {
+	return f.done
+}
+
+// Result waits for the command to complete and returns its result.
+// The record is nil if it does not exist.
+func (f *RecordFuture) Result() (*Record, error) {
+	<-f.done
+	return f.record, f.err
+}
+
+// RecordsFuture is the pending result of an asynchronous batch command.
+type RecordsFuture struct {
+	done    chan struct{}
+	records []*Record
+	err     error
+}
+
+func newRecordsFuture() *RecordsFuture {
+	return &RecordsFuture{done: make(chan struct{})}
+}
+
+func (f *RecordsFuture) complete(records []*Record, err error) {
+	f.records, f.err = records, err
+	close(f.done)
+}
+
+// Done returns a channel that is closed when the command has completed.
+func (f *RecordsFuture) Done() <-chan struct{} {
+	return f.done
+}
+
+// Result waits for the command to complete and returns its result.
+// Records that do not exist are nil.
+func (f *RecordsFuture) Result() ([]*Record, error) {
+	<-f.done
+	return f.records, f.err
+}
+
+// WriteFuture is the pending result of an asynchronous write command.
+type WriteFuture struct {
+	done chan struct{}
+	err  error
+}
+
+func newWriteFuture() *WriteFuture {
+	return &WriteFuture{done: make(chan struct{})}
+}
+
+func (f *WriteFuture) complete(err error) {
+	f.err = err
+	close(f.done)
+}
+
+// Done returns a channel that is closed when the command has completed.
+func (f *WriteFuture) Done() <-chan struct{} {
+	return f.done
+}
+
+// Result waits for the command to complete and returns its error, if any.
+func (f *WriteFuture) Result() error {
+	<-f.done
+	return f.err
+}
+
+// GetAsync reads a record asynchronously. See Get.
+// Each node runs at most ClientPolicy.AsyncMaxCommandsPerNode async commands at a time;
+// the other commands wait for their turn, up to the policy timeout.
+func (clnt *Client) GetAsync(policy *BasePolicy, key *Key, binNames ...string) *RecordFuture {
+	policy = clnt.getUsablePolicy(policy)
+
+	command := newReadCommand(clnt.cluster, policy, key, binNames)
+	command.ctx = clnt.Context()
+	command.async = true
+
+	future := newRecordFuture()
+	go func() {
+		if err := command.Execute(); err != nil {
+			future.complete(nil, err)
+			return
+		}
+		future.complete(command.GetRecord(), nil)
+	}()
+	return future
+}
+
+// PutAsync writes record bin(s) asynchronously. See Put and GetAsync.
+func (clnt *Client) PutAsync(policy *WritePolicy, key *Key, binMap BinMap) *WriteFuture {
+	policy = clnt.getUsableWritePolicy(policy)
+
+	command := newWriteCommand(clnt.cluster, policy, key, nil, binMap, WRITE)
+	command.ctx = clnt.Context()
+	command.async = true
+
+	future := newWriteFuture()
+	go func() {
+		future.complete(command.Execute())
+	}()
+	return future
+}
+
+// PutBinsAsync writes record bin(s) asynchronously. See PutBins and GetAsync.
+func (clnt *Client) PutBinsAsync(policy *WritePolicy, key *Key, bins ...*Bin) *WriteFuture {
+	policy = clnt.getUsableWritePolicy(policy)
+
+	command := newWriteCommand(clnt.cluster, policy, key, bins, nil, WRITE)
+	command.ctx = clnt.Context()
+	command.async = true
+
+	future := newWriteFuture()
+	go func() {
+		future.complete(command.Execute())
+	}()
+	return future
+}
+
+// OperateAsync performs multiple read/write operations on a single key asynchronously.
+// See Operate and GetAsync.
+func (clnt *Client) OperateAsync(policy *WritePolicy, key *Key, operations ...*Operation) *RecordFuture {
+	policy = clnt.getUsableWritePolicy(policy)
+
+	command := newOperateCommand(clnt.cluster, policy, key, operations)
+	command.ctx = clnt.Context()
+	command.async = true
+
+	future := newRecordFuture()
+	go func() {
+		if err := command.Execute(); err != nil {
+			future.complete(nil, err)
+			return
+		}
+		future.complete(command.GetRecord(), nil)
+	}()
+	return future
+}
+
+// BatchGetAsync reads multiple records asynchronously. See BatchGet and GetAsync.
+// The command sent to each node holds a slot in that node's async pipeline.
+func (clnt *Client) BatchGetAsync(policy *BatchPolicy, keys []*Key, binNames ...string) *RecordsFuture {
+	policy = clnt.getUsableBatchPolicy(policy)
+
+	records := make([]*Record, len(keys))
+	cmd := newBatchCommandGet(nil, nil, nil, policy, keys, binNames, records, _INFO1_READ)
+	cmd.ctx = clnt.Context()
+	cmd.async = true
+
+	future := newRecordsFuture()
+	go func() {
+		if err := clnt.batchExecute(policy, keys, cmd); err != nil {
+			future.complete(nil, err)
+			return
+		}
+		future.complete(records, nil)
+	}()
+	return future
+}
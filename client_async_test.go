@@ -0,0 +1,86 @@
+// Copyright 2013-2017 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aerospike
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	. "github.com/aerospike/aerospike-client-go/types"
+
+	. "github.com/onsi/ginkgo"
+	gm "github.com/onsi/gomega"
+)
+
+var _ = Describe("Async pipeline test", func() {
+
+	It("must not cap commands when the size is zero", func() {
+		pipeline := newAsyncPipeline(0)
+		gm.Expect(pipeline).To(gm.BeNil())
+		gm.Expect(pipeline.acquire(context.Background(), time.Time{})).ToNot(gm.HaveOccurred())
+	})
+
+	It("must cap the commands in flight", func() {
+		pipeline := newAsyncPipeline(2)
+		gm.Expect(pipeline.acquire(context.Background(), time.Time{})).ToNot(gm.HaveOccurred())
+		gm.Expect(pipeline.acquire(context.Background(), time.Time{})).ToNot(gm.HaveOccurred())
+
+		err := pipeline.acquire(context.Background(), time.Now().Add(10*time.Millisecond))
+		gm.Expect(err).To(gm.HaveOccurred())
+		gm.Expect(err.(AerospikeError).ResultCode()).To(gm.Equal(TIMEOUT))
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		gm.Expect(errors.Is(pipeline.acquire(ctx, time.Time{}), context.Canceled)).To(gm.BeTrue())
+
+		acquired := make(chan error)
+		go func() {
+			acquired <- pipeline.acquire(context.Background(), time.Time{})
+		}()
+		gm.Consistently(acquired, 20*time.Millisecond).ShouldNot(gm.Receive())
+
+		pipeline.release()
+		gm.Eventually(acquired).Should(gm.Receive(gm.BeNil()))
+	})
+
+	It("must release the slot when the attempt finishes", func() {
+		pipeline := newAsyncPipeline(1)
+
+		attempt := startAttempt(nil, CMD_READ, "", "", 1, context.Background())
+		gm.Expect(attempt.acquire(pipeline, context.Background(), time.Time{})).ToNot(gm.HaveOccurred())
+		gm.Expect(len(pipeline)).To(gm.Equal(1))
+
+		attempt.finish(nil)
+		gm.Expect(len(pipeline)).To(gm.Equal(0))
+
+		// finishing twice does not release another slot
+		attempt.finish(nil)
+		gm.Expect(len(pipeline)).To(gm.Equal(0))
+	})
+
+	It("must complete futures", func() {
+		future := newRecordFuture()
+		gm.Expect(future.Done()).ToNot(gm.BeClosed())
+
+		record := &Record{}
+		future.complete(record, nil)
+		gm.Expect(future.Done()).To(gm.BeClosed())
+
+		res, err := future.Result()
+		gm.Expect(err).ToNot(gm.HaveOccurred())
+		gm.Expect(res).To(gm.BeIdenticalTo(record))
+	})
+})
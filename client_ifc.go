@@ -0,0 +1,135 @@
+// Copyright 2013-2017 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aerospike
+
+import (
+	"time"
+)
+
+// ClusterClient manages the connection to the cluster.
+type ClusterClient interface {
+	Close()
+	IsConnected() bool
+	GetNodes() []*Node
+	GetNodeNames() []string
+	Stats() (map[string]interface{}, error)
+	StatsSnapshot() *ClientStats
+	String() string
+}
+
+// InfoReader introspects the cluster, its namespaces and sets.
+type InfoReader interface {
+	GetClusterInfo(policy *InfoPolicy) (*ClusterInfo, error)
+	ListNamespaces(policy *InfoPolicy) ([]string, error)
+	GetNamespaceInfo(policy *InfoPolicy, namespace string) (*NamespaceInfo, error)
+	ListSets(policy *InfoPolicy, namespace string) ([]*SetInfo, error)
+	ListBins(policy *InfoPolicy, namespace string) ([]string, error)
+}
+
+// RecordReader reads single records.
+type RecordReader interface {
+	Exists(policy *BasePolicy, key *Key) (bool, error)
+	Get(policy *BasePolicy, key *Key, binNames ...string) (*Record, error)
+	GetHeader(policy *BasePolicy, key *Key) (*Record, error)
+}
+
+// RecordWriter writes, operates on and deletes single records, and truncates sets.
+type RecordWriter interface {
+	Put(policy *WritePolicy, key *Key, binMap BinMap) error
+	PutBins(policy *WritePolicy, key *Key, bins ...*Bin) error
+	Append(policy *WritePolicy, key *Key, binMap BinMap) error
+	AppendBins(policy *WritePolicy, key *Key, bins ...*Bin) error
+	Prepend(policy *WritePolicy, key *Key, binMap BinMap) error
+	PrependBins(policy *WritePolicy, key *Key, bins ...*Bin) error
+	Add(policy *WritePolicy, key *Key, binMap BinMap) error
+	AddBins(policy *WritePolicy, key *Key, bins ...*Bin) error
+	Delete(policy *WritePolicy, key *Key) (bool, error)
+	Touch(policy *WritePolicy, key *Key) error
+	Operate(policy *WritePolicy, key *Key, operations ...*Operation) (*Record, error)
+	Truncate(policy *WritePolicy, namespace, set string, beforeLastUpdate *time.Time) error
+}
+
+// BatchReader reads multiple records in batches.
+type BatchReader interface {
+	BatchExists(policy *BatchPolicy, keys []*Key) ([]bool, error)
+	BatchGet(policy *BatchPolicy, keys []*Key, binNames ...string) ([]*Record, error)
+	BatchGetComplex(policy *BatchPolicy, records []*BatchRead) error
+	BatchGetHeader(policy *BatchPolicy, keys []*Key) ([]*Record, error)
+}
+
+// BatchWriter writes, deletes and executes user defined functions on multiple records.
+type BatchWriter interface {
+	BatchOperate(policy *BatchPolicy, records []BatchRecordIfc) error
+}
+
+// Scanner scans namespaces and sets.
+type Scanner interface {
+	ScanAll(policy *ScanPolicy, namespace string, setName string, binNames ...string) (*Recordset, error)
+	ScanNode(policy *ScanPolicy, node *Node, namespace string, setName string, binNames ...string) (*Recordset, error)
+	ScanPartitions(policy *ScanPolicy, filter *PartitionFilter, namespace string, setName string, binNames ...string) (*Recordset, error)
+	ScanPage(policy *ScanPolicy, token string, pageSize int, namespace string, setName string, binNames ...string) (*RecordPage, error)
+}
+
+// Querier runs queries and aggregations.
+type Querier interface {
+	Query(policy *QueryPolicy, statement *Statement) (*Recordset, error)
+	QueryNode(policy *QueryPolicy, node *Node, statement *Statement) (*Recordset, error)
+	QueryPartitions(policy *QueryPolicy, statement *Statement, filter *PartitionFilter) (*Recordset, error)
+	QueryPage(policy *QueryPolicy, statement *Statement, token string, pageSize int) (*RecordPage, error)
+	QueryAggregate(policy *QueryPolicy, statement *Statement, packageName, functionName string, functionArgs ...interface{}) (*Recordset, error)
+	Aggregate(policy *QueryPolicy, statement *Statement, aggregation *Aggregation) (interface{}, error)
+}
+
+// IndexManager creates, drops and lists secondary indexes.
+type IndexManager interface {
+	CreateIndex(policy *WritePolicy, namespace string, setName string, indexName string, binName string, indexType IndexType) (*IndexTask, error)
+	CreateComplexIndex(policy *WritePolicy, namespace string, setName string, indexName string, binName string, indexType IndexType, indexCollectionType IndexCollectionType) (*IndexTask, error)
+	DropIndex(policy *WritePolicy, namespace string, setName string, indexName string) error
+	ListIndexes(policy *InfoPolicy, namespace string) ([]*IndexInfo, error)
+	GetIndexStats(policy *InfoPolicy, namespace string, indexName string) (*IndexStats, error)
+	EnsureIndex(policy *WritePolicy, index *IndexDefinition) (*IndexTask, error)
+	MigrateIndexes(policy *WritePolicy, migration *IndexMigration) ([]*IndexChange, error)
+}
+
+// UDFManager registers user defined functions and executes them on records and queries.
+type UDFManager interface {
+	RegisterUDFFromFile(policy *WritePolicy, clientPath string, serverPath string, language Language) (*RegisterTask, error)
+	RegisterUDF(policy *WritePolicy, udfBody []byte, serverPath string, language Language) (*RegisterTask, error)
+	RemoveUDF(policy *WritePolicy, udfName string) (*RemoveTask, error)
+	ListUDF(policy *BasePolicy) ([]*UDF, error)
+	Execute(policy *WritePolicy, key *Key, packageName string, functionName string, args ...Value) (interface{}, error)
+	ExecuteUDF(policy *QueryPolicy, statement *Statement, packageName string, functionName string, functionArgs ...Value) (*ExecuteTask, error)
+	ExecuteUDFNode(policy *QueryPolicy, node *Node, statement *Statement, packageName string, functionName string, functionArgs ...Value) (*ExecuteTask, error)
+}
+
+// AdminClient manages users and roles.
+type AdminClient interface {
+	CreateUser(policy *AdminPolicy, user string, password string, roles []string) error
+	DropUser(policy *AdminPolicy, user string) error
+	ChangePassword(policy *AdminPolicy, user string, password string) error
+	GrantRoles(policy *AdminPolicy, user string, roles []string) error
+	RevokeRoles(policy *AdminPolicy, user string, roles []string) error
+	QueryUser(policy *AdminPolicy, user string) (*UserRoles, error)
+	QueryUsers(policy *AdminPolicy) ([]*UserRoles, error)
+	QueryRole(policy *AdminPolicy, role string) (*Role, error)
+	QueryRoles(policy *AdminPolicy) ([]*Role, error)
+	CreateRole(policy *AdminPolicy, roleName string, privileges []Privilege) error
+	DropRole(policy *AdminPolicy, roleName string) error
+	GrantPrivileges(policy *AdminPolicy, roleName string, privileges []Privilege) error
+	RevokePrivileges(policy *AdminPolicy, roleName string, privileges []Privilege) error
+}
+
+// Client implements all the interfaces.
+var _ ClientIfc = &Client{}
@@ -0,0 +1,35 @@
+// +build as_performance
+
+// Copyright 2013-2017 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aerospike
+
+// ClientIfc is the interface implemented by Client. It can be used to replace
+// the client with a fake in tests, or to wrap it with decorators.
+// Methods may be added to ClientIfc in future releases; embed it in decorators
+// to stay compatible.
+type ClientIfc interface {
+	ClusterClient
+	InfoReader
+	RecordReader
+	RecordWriter
+	BatchReader
+	BatchWriter
+	Scanner
+	Querier
+	IndexManager
+	UDFManager
+	AdminClient
+}
@@ -16,7 +16,10 @@ package aerospike
 
 import (
 	"crypto/tls"
+	"fmt"
 	"time"
+
+	. "github.com/aerospike/aerospike-client-go/types"
 )
 
 const defaultIdleTimeout = 14 * time.Second
@@ -51,6 +54,22 @@ type ClientPolicy struct {
 	// to the node if there are already `ConnectionQueueSize` active connections.
 	LimitConnectionsToQueueSize bool //= true
 
+	// AsyncMaxCommandsPerNode caps the number of asynchronous commands in flight on each
+	// node. Async commands wait for a free slot before taking a connection from the pool,
+	// so it should not be larger than ConnectionQueueSize. Async commands are not capped
+	// if it is zero.
+	AsyncMaxCommandsPerNode int //= 64
+
+	// PipelineDepth enables pipelining of single record commands if positive.
+	// Pipelined commands are written back-to-back on a single connection per node
+	// instead of taking a connection from the pool, and their responses are read
+	// in order. PipelineDepth caps the number of commands in flight on the connection.
+	// A failed or timed out command closes the connection, failing all the commands
+	// in flight behind it with a TIMEOUT error. Writes among them are in doubt and
+	// are not retried; the other commands are retried as the policy allows.
+	// Cancelling the context of a pipelined command does not interrupt its I/O.
+	PipelineDepth int //= 0
+
 	// Throw exception if host connection fails during addHost().
 	FailIfNotConnected bool //= true
 
@@ -82,6 +101,15 @@ type ClientPolicy struct {
 	// The default is false (only request master replicas and never prole replicas).
 	RequestProleReplicas bool // false
 
+	// RackAware determines if the rack of each node is requested from the server in
+	// the cluster tend goroutine. It is required by the PREFER_RACK replica policy.
+	// "racks:" is available with Aerospike Server versions >= 3.13.
+	RackAware bool // false
+
+	// RackId is the rack of the client, like its availability zone.
+	// It is only used if RackAware is enabled. See PREFER_RACK.
+	RackId int // 0
+
 	// TlsConfig specifies TLS secure connection policy for TLS enabled servers.
 	// For better performance, we suggest prefering the server-side ciphers by
 	// setting PreferServerCipherSuites = true.
@@ -89,6 +117,20 @@ type ClientPolicy struct {
 
 	// IgnoreOtherSubnetAliases helps to ignore aliases that are outside main subnet
 	IgnoreOtherSubnetAliases bool //= false
+
+	// LatencyColumns and LatencyShift determine the layout of the command latency histograms
+	// in the client statistics, like the benchmark tool's -L option. The first column counts
+	// commands that took up to 1ms, and the upper bound of each following column is
+	// 2^LatencyShift times that of the previous one. The last column has no upper bound.
+	// Latency histograms are not kept if LatencyColumns is zero.
+	// LatencyColumns must be between 0 and 64, and LatencyShift must be positive if there
+	// is more than one column.
+	LatencyColumns int //= 7
+	LatencyShift   int //= 1
+
+	// CommandListener is notified at the start and end of every command attempt.
+	// It can be used to collect metrics or trace commands. See CommandListener.
+	CommandListener CommandListener //= nil
 }
 
 // NewClientPolicy generates a new ClientPolicy with default values.
@@ -100,8 +142,11 @@ func NewClientPolicy() *ClientPolicy {
 		FailIfNotConnected:          true,
 		TendInterval:                time.Second,
 		LimitConnectionsToQueueSize: true,
+		AsyncMaxCommandsPerNode:     64,
 		RequestProleReplicas:        false,
 		IgnoreOtherSubnetAliases:    false,
+		LatencyColumns:              7,
+		LatencyShift:                1,
 	}
 }
 
@@ -110,6 +155,17 @@ func (cp *ClientPolicy) RequiresAuthentication() bool {
 	return (cp.User != "") || (cp.Password != "")
 }
 
+// validate checks the settings which would make the client misbehave.
+func (cp *ClientPolicy) validate() error {
+	if cp.LatencyColumns < 0 || cp.LatencyColumns > 64 {
+		return NewAerospikeError(PARAMETER_ERROR, fmt.Sprintf("Invalid LatencyColumns %d. See `ClientPolicy.LatencyColumns`", cp.LatencyColumns))
+	}
+	if cp.LatencyColumns > 1 && cp.LatencyShift < 1 {
+		return NewAerospikeError(PARAMETER_ERROR, fmt.Sprintf("Invalid LatencyShift %d. See `ClientPolicy.LatencyShift`", cp.LatencyShift))
+	}
+	return nil
+}
+
 func (cp *ClientPolicy) serviceString() string {
 	if cp.UseServicesAlternate {
 		return "services-alternate"
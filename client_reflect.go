@@ -31,8 +31,20 @@ import (
 func (clnt *Client) PutObject(policy *WritePolicy, key *Key, obj interface{}) (err error) {
 	policy = clnt.getUsableWritePolicy(policy)
 
-	bins := marshal(obj, clnt.cluster.supportsFloat.Get())
+	if marshaler, ok := obj.(BinMarshaler); ok {
+		binMap, err := marshaler.MarshalBins()
+		if err != nil {
+			return err
+		}
+		return clnt.Put(policy, key, binMap)
+	}
+
+	bins, err := marshal(obj, clnt.cluster.supportsFloat.Get())
+	if err != nil {
+		return err
+	}
 	command := newWriteCommand(clnt.cluster, policy, key, bins, nil, WRITE)
+	command.ctx = clnt.Context()
 	res := command.Execute()
 	binPool.Put(bins)
 	return res
@@ -44,10 +56,22 @@ func (clnt *Client) PutObject(policy *WritePolicy, key *Key, obj interface{}) (e
 func (clnt *Client) GetObject(policy *BasePolicy, key *Key, obj interface{}) error {
 	policy = clnt.getUsablePolicy(policy)
 
+	if unmarshaler, ok := obj.(BinUnmarshaler); ok {
+		rec, err := clnt.Get(policy, key)
+		if err != nil {
+			return err
+		}
+		if rec == nil {
+			return NewAerospikeError(KEY_NOT_FOUND_ERROR)
+		}
+		return unmarshaler.UnmarshalBins(rec.Bins, rec.Generation, rec.Expiration)
+	}
+
 	rval := reflect.ValueOf(obj)
 	binNames := objectMappings.getFields(rval.Type())
 
 	command := newReadCommand(clnt.cluster, policy, key, binNames)
+	command.ctx = clnt.Context()
 	command.object = &rval
 	return command.Execute()
 }
@@ -65,6 +89,25 @@ func (clnt *Client) BatchGetObjects(policy *BatchPolicy, keys []*Key, objects []
 		return nil, errors.New("Wrong Number of arguments to BatchGetObject. Number of keys and objects do not match.")
 	}
 
+	if unmarshalers := binUnmarshalers(objects); unmarshalers != nil {
+		records, err := clnt.BatchGet(policy, keys)
+		if err != nil {
+			return nil, err
+		}
+
+		found = make([]bool, len(keys))
+		for i, rec := range records {
+			if rec == nil {
+				continue
+			}
+			if err := unmarshalers[i].UnmarshalBins(rec.Bins, rec.Generation, rec.Expiration); err != nil {
+				return nil, err
+			}
+			found[i] = true
+		}
+		return found, nil
+	}
+
 	binSet := map[string]struct{}{}
 	objectsVal := make([]*reflect.Value, len(objects))
 	for i := range objects {
@@ -81,6 +124,7 @@ func (clnt *Client) BatchGetObjects(policy *BatchPolicy, keys []*Key, objects []
 
 	objectsFound := make([]bool, len(keys))
 	cmd := newBatchCommandGet(nil, nil, nil, policy, keys, binNames, nil, _INFO1_READ)
+	cmd.ctx = clnt.Context()
 	cmd.objects = objectsVal
 	cmd.objectsFound = objectsFound
 
@@ -91,6 +135,20 @@ func (clnt *Client) BatchGetObjects(policy *BatchPolicy, keys []*Key, objects []
 	return objectsFound, nil
 }
 
+// binUnmarshalers returns the objects as BinUnmarshalers,
+// or nil if any of them does not implement the interface.
+func binUnmarshalers(objects []interface{}) []BinUnmarshaler {
+	res := make([]BinUnmarshaler, len(objects))
+	for i := range objects {
+		unmarshaler, ok := objects[i].(BinUnmarshaler)
+		if !ok {
+			return nil
+		}
+		res[i] = unmarshaler
+	}
+	return res
+}
+
 // ScanAllObjects reads all records in specified namespace and set from all nodes.
 // If the policy's concurrentNodes is specified, each server node will be read in
 // parallel. Otherwise, server nodes are read sequentially.
@@ -115,6 +173,7 @@ func (clnt *Client) ScanAllObjects(apolicy *ScanPolicy, objChan interface{}, nam
 	res := &Recordset{
 		objectset: *newObjectset(reflect.ValueOf(objChan), len(nodes), taskId),
 	}
+	res.limit = newRecordLimiter(policy.MaxRecords, len(nodes))
 
 	// the whole call should be wrapped in a goroutine
 	if policy.ConcurrentNodes {
@@ -150,6 +209,7 @@ func (clnt *Client) ScanNodeObjects(apolicy *ScanPolicy, node *Node, objChan int
 	res := &Recordset{
 		objectset: *newObjectset(reflect.ValueOf(objChan), 1, taskId),
 	}
+	res.limit = newRecordLimiter(policy.MaxRecords, 1)
 
 	go clnt.scanNodeObjects(&policy, node, res, namespace, setName, taskId, binNames...)
 	return res, nil
@@ -168,6 +228,7 @@ func (clnt *Client) scanNodeObjects(policy *ScanPolicy, node *Node, recordset *R
 	}
 
 	command := newScanObjectsCommand(node, policy, namespace, setName, binNames, recordset, taskId)
+	command.ctx = clnt.Context()
 	return command.Execute()
 }
 
@@ -196,6 +257,7 @@ func (clnt *Client) QueryObjects(policy *QueryPolicy, statement *Statement, objC
 	recSet := &Recordset{
 		objectset: *newObjectset(reflect.ValueOf(objChan), len(nodes), statement.TaskId),
 	}
+	recSet.limit = newRecordLimiter(policy.MaxRecords, len(nodes))
 
 	// the whole call sho
 	// results channel must be async for performance
@@ -203,6 +265,7 @@ func (clnt *Client) QueryObjects(policy *QueryPolicy, statement *Statement, objC
 		// copy policies to avoid race conditions
 		newPolicy := *policy
 		command := newQueryObjectsCommand(node, &newPolicy, statement, recSet)
+		command.ctx = clnt.Context()
 		go func() {
 			// Do not send the error to the channel; it is already handled in the Execute method
 			command.Execute()
@@ -231,10 +294,12 @@ func (clnt *Client) QueryNodeObjects(policy *QueryPolicy, node *Node, statement
 	recSet := &Recordset{
 		objectset: *newObjectset(reflect.ValueOf(objChan), 1, statement.TaskId),
 	}
+	recSet.limit = newRecordLimiter(policy.MaxRecords, 1)
 
 	// copy policies to avoid race conditions
 	newPolicy := *policy
 	command := newQueryRecordCommand(node, &newPolicy, statement, recSet)
+	command.ctx = clnt.Context()
 	go func() {
 		command.Execute()
 	}()
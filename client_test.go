@@ -16,6 +16,8 @@ package aerospike_test
 
 import (
 	"bytes"
+	"context"
+	"errors"
 	"fmt"
 	"math"
 	"math/rand"
@@ -114,6 +116,228 @@ var _ = Describe("Aerospike", func() {
 
 	})
 
+	Describe("Commands bound to a context", func() {
+		var ns = "test"
+		var set = randString(50)
+
+		It("must not run commands on an already cancelled context", func() {
+			key, err := as.NewKey(ns, set, randString(50))
+			Expect(err).ToNot(HaveOccurred())
+
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+
+			cclient := client.WithContext(ctx)
+			Expect(cclient.Context()).To(Equal(ctx))
+
+			err = cclient.PutBins(nil, key, as.NewBin("Aerospike", "value"))
+			Expect(errors.Is(err, context.Canceled)).To(BeTrue())
+			// the write was not sent
+			Expect(err.(AerospikeError).InDoubt()).To(BeFalse())
+
+			_, err = cclient.Get(nil, key)
+			Expect(errors.Is(err, context.Canceled)).To(BeTrue())
+
+			_, err = cclient.BatchGet(nil, []*as.Key{key})
+			Expect(err).To(HaveOccurred())
+
+			// the original client is not affected
+			exists, err := client.Exists(nil, key)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(exists).To(BeFalse())
+		})
+
+		It("must report the context error when the deadline is exceeded", func() {
+			key, err := as.NewKey(ns, set, randString(50))
+			Expect(err).ToNot(HaveOccurred())
+
+			ctx, cancel := context.WithTimeout(context.Background(), time.Nanosecond)
+			defer cancel()
+			time.Sleep(time.Millisecond)
+
+			_, err = client.WithContext(ctx).Get(nil, key)
+			Expect(errors.Is(err, context.DeadlineExceeded)).To(BeTrue())
+		})
+
+		It("must end a scan when the context is cancelled", func() {
+			ctx, cancel := context.WithCancel(context.Background())
+
+			policy := as.NewScanPolicy()
+			policy.RecordQueueSize = 1
+			recordset, err := client.WithContext(ctx).ScanAll(policy, ns, set)
+			Expect(err).ToNot(HaveOccurred())
+			cancel()
+
+			for res := range recordset.Results() {
+				if res.Err != nil {
+					Expect(errors.Is(res.Err, context.Canceled)).To(BeTrue())
+				}
+			}
+		})
+	})
+
+	Describe("Command listener", func() {
+		var ns = "test"
+		var set = randString(50)
+
+		It("must notify the listener at the start and end of each command", func() {
+			listener := &recordingListener{}
+			cpolicy := *clientPolicy
+			cpolicy.CommandListener = listener
+			nclient, err := as.NewClientWithPolicy(&cpolicy, *host, *port)
+			Expect(err).ToNot(HaveOccurred())
+			defer nclient.Close()
+
+			key, err := as.NewKey(ns, set, randString(50))
+			Expect(err).ToNot(HaveOccurred())
+
+			err = nclient.PutBins(nil, key, as.NewBin("Aerospike", "value"))
+			Expect(err).ToNot(HaveOccurred())
+			_, err = nclient.Get(nil, key)
+			Expect(err).ToNot(HaveOccurred())
+			_, err = nclient.BatchGet(nil, []*as.Key{key})
+			Expect(err).ToNot(HaveOccurred())
+			wpolicy := as.NewWritePolicy(100, 0)
+			wpolicy.GenerationPolicy = as.EXPECT_GEN_EQUAL
+			err = nclient.PutBins(wpolicy, key, as.NewBin("Aerospike", "value"))
+			Expect(err).To(HaveOccurred())
+
+			events := listener.finished()
+			Expect(len(events)).To(Equal(4))
+
+			Expect(events[0].Type).To(Equal(as.CMD_WRITE))
+			Expect(events[1].Type).To(Equal(as.CMD_READ))
+			Expect(events[2].Type).To(Equal(as.CMD_BATCH))
+			for _, event := range events {
+				Expect(event.Namespace).To(Equal(ns))
+				Expect(event.SetName).To(Equal(set))
+				Expect(event.Node).ToNot(BeNil())
+				Expect(event.Attempt).To(Equal(1))
+				Expect(event.BytesSent).To(BeNumerically(">", 0))
+				Expect(event.BytesReceived).To(BeNumerically(">", 0))
+				Expect(event.Latency).To(BeNumerically(">", 0))
+				Expect(event.UserData).To(Equal("started"))
+			}
+			Expect(events[0].ResultCode).To(Equal(OK))
+			Expect(events[3].ResultCode).To(Equal(GENERATION_ERROR))
+		})
+	})
+
+	Describe("Command statistics", func() {
+		var ns = "test"
+		var set = randString(50)
+
+		It("must keep the latency and result codes of commands per node", func() {
+			cpolicy := *clientPolicy
+			cpolicy.TendInterval = 10 * time.Millisecond
+			nclient, err := as.NewClientWithPolicy(&cpolicy, *host, *port)
+			Expect(err).ToNot(HaveOccurred())
+			defer nclient.Close()
+
+			key, err := as.NewKey(ns, set, randString(50))
+			Expect(err).ToNot(HaveOccurred())
+
+			err = nclient.PutBins(nil, key, as.NewBin("Aerospike", "value"))
+			Expect(err).ToNot(HaveOccurred())
+			_, err = nclient.Get(nil, key)
+			Expect(err).ToNot(HaveOccurred())
+			time.Sleep(100 * time.Millisecond)
+
+			stats := nclient.StatsSnapshot()
+			Expect(len(stats.Nodes)).To(BeNumerically(">", 0))
+
+			for _, cmdType := range []as.CommandType{as.CMD_WRITE, as.CMD_READ} {
+				cmdStats := stats.Cluster.Commands[cmdType]
+				Expect(cmdStats).ToNot(BeNil())
+				Expect(cmdStats.Attempts).To(Equal(int64(1)))
+				Expect(cmdStats.Errors).To(Equal(int64(0)))
+				Expect(cmdStats.ResultCodes[OK]).To(Equal(int64(1)))
+				Expect(len(cmdStats.Latency.Buckets)).To(Equal(cpolicy.LatencyColumns))
+			}
+		})
+	})
+
+	Describe("Cluster introspection", func() {
+		var ns = "test"
+		var set = randString(50)
+
+		It("must describe the cluster, its namespaces and sets", func() {
+			info, err := client.GetClusterInfo(nil)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(len(info.Nodes)).To(Equal(len(client.GetNodes())))
+			Expect(info.Namespaces).To(ContainElement(ns))
+			Expect(info.Builds()).ToNot(BeEmpty())
+
+			key, err := as.NewKey(ns, set, randString(50))
+			Expect(err).ToNot(HaveOccurred())
+			err = client.PutBins(nil, key, as.NewBin("Aerospike", "value"))
+			Expect(err).ToNot(HaveOccurred())
+
+			nsInfo, err := client.GetNamespaceInfo(nil, ns)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(nsInfo.MasterObjects).To(BeNumerically(">", 0))
+			Expect(len(nsInfo.Nodes)).To(Equal(len(info.Nodes)))
+
+			sets, err := client.ListSets(nil, ns)
+			Expect(err).ToNot(HaveOccurred())
+
+			var found *as.SetInfo
+			for _, s := range sets {
+				if s.Name == set {
+					found = s
+				}
+			}
+			Expect(found).ToNot(BeNil())
+			Expect(found.Objects).To(BeNumerically(">", 0))
+
+			_, err = client.GetNamespaceInfo(nil, randString(10))
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Describe("Async commands", func() {
+		var ns = "test"
+		var set = randString(50)
+
+		It("must execute commands asynchronously through the node pipelines", func() {
+			cpolicy := *clientPolicy
+			cpolicy.AsyncMaxCommandsPerNode = 2
+			nclient, err := as.NewClientWithPolicy(&cpolicy, *host, *port)
+			Expect(err).ToNot(HaveOccurred())
+			defer nclient.Close()
+
+			keys := make([]*as.Key, 100)
+			writes := make([]*as.WriteFuture, len(keys))
+			for i := range keys {
+				keys[i], err = as.NewKey(ns, set, i)
+				Expect(err).ToNot(HaveOccurred())
+				writes[i] = nclient.PutAsync(nil, keys[i], as.BinMap{"i": i})
+			}
+			for i := range writes {
+				Expect(writes[i].Result()).ToNot(HaveOccurred())
+			}
+
+			reads := make([]*as.RecordFuture, len(keys))
+			for i := range keys {
+				reads[i] = nclient.GetAsync(nil, keys[i])
+			}
+			for i := range reads {
+				rec, err := reads[i].Result()
+				Expect(err).ToNot(HaveOccurred())
+				Expect(rec.Bins["i"]).To(Equal(i))
+			}
+
+			rec, err := nclient.OperateAsync(nil, keys[0], as.AddOp(as.NewBin("i", 1)), as.GetOp()).Result()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(rec.Bins["i"]).To(Equal(1))
+
+			records, err := nclient.BatchGetAsync(nil, keys).Result()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(len(records)).To(Equal(len(keys)))
+			Expect(records[1].Bins["i"]).To(Equal(1))
+		})
+	})
+
 	Describe("Data operations on native types", func() {
 		// connection data
 		var err error
@@ -1122,6 +1346,57 @@ var _ = Describe("Aerospike", func() {
 			}
 		}) // Batch Get Header context
 
+		Context("Batch Operate operations", func() {
+
+			It("must write, operate on and delete records across nodes", func() {
+				const keyCount = 100
+				keys := make([]*as.Key, keyCount)
+				records := make([]as.BatchRecordIfc, 0, keyCount)
+				for i := range keys {
+					keys[i], err = as.NewKey(ns, set, randString(50))
+					Expect(err).ToNot(HaveOccurred())
+					records = append(records, as.NewBatchPut(nil, keys[i], as.NewBin("i", i), as.NewBin("list", []interface{}{i})))
+				}
+
+				bpolicy := as.NewBatchPolicy()
+				bpolicy.ConcurrentNodes = 0
+				Expect(client.BatchOperate(bpolicy, records)).ToNot(HaveOccurred())
+				for _, r := range records {
+					Expect(r.BatchRec().Err).ToNot(HaveOccurred())
+					Expect(r.BatchRec().ResultCode).To(Equal(OK))
+				}
+
+				cpolicy := as.NewWritePolicy(0, 0)
+				cpolicy.RecordExistsAction = as.CREATE_ONLY
+				records = []as.BatchRecordIfc{
+					as.NewBatchWrite(nil, keys[0], as.ListAppendOp("list", "a"), as.AddOp(as.NewBin("i", 10))),
+					as.NewBatchDelete(nil, keys[1]),
+					as.NewBatchPut(cpolicy, keys[2], as.NewBin("i", -1)),
+					as.NewBatchDelete(nil, keys[1]),
+				}
+				Expect(client.BatchOperate(nil, records)).ToNot(HaveOccurred())
+
+				Expect(records[0].BatchRec().Err).ToNot(HaveOccurred())
+				rec, err = client.Get(nil, keys[0])
+				Expect(err).ToNot(HaveOccurred())
+				Expect(rec.Bins["i"]).To(Equal(10))
+				Expect(rec.Bins["list"]).To(Equal([]interface{}{0, "a"}))
+
+				Expect(records[1].BatchRec().ResultCode).To(Equal(OK))
+
+				Expect(records[2].BatchRec().ResultCode).To(Equal(KEY_EXISTS_ERROR))
+				Expect(records[2].BatchRec().Err).To(HaveOccurred())
+				Expect(records[2].BatchRec().InDoubt).To(BeFalse())
+
+				Expect(records[3].BatchRec().ResultCode).To(Equal(KEY_NOT_FOUND_ERROR))
+				Expect(records[3].BatchRec().Err).ToNot(HaveOccurred())
+
+				exists, err := client.BatchExists(nil, keys[:3])
+				Expect(err).ToNot(HaveOccurred())
+				Expect(exists).To(Equal([]bool{true, false, true}))
+			})
+		})
+
 		Context("Operate operations", func() {
 			bin1 := as.NewBin("Aerospike1", rand.Intn(math.MaxInt16))
 			bin2 := as.NewBin("Aerospike2", randString(100))
@@ -139,6 +139,10 @@ type Cluster struct {
 
 // NewCluster generates a Cluster instance.
 func NewCluster(policy *ClientPolicy, hosts []*Host) (*Cluster, error) {
+	if err := policy.validate(); err != nil {
+		return nil, err
+	}
+
 	// Default TLS names when TLS enabled.
 	newHosts := make([]*Host, 0, len(hosts))
 	if policy.TlsConfig != nil && !policy.TlsConfig.InsecureSkipVerify {
@@ -818,6 +822,10 @@ func (clstr *Cluster) getReadNode(partition *Partition, replica ReplicaPolicy, s
 		return clstr.getMasterNode(partition)
 	case MASTER_PROLES:
 		return clstr.getMasterProleNode(partition)
+	case PREFER_RACK:
+		return clstr.getRackNode(partition, seq)
+	case LEAST_LATENCY:
+		return clstr.getLeastLatencyNode(partition, seq)
 	default:
 		// includes case RANDOM:
 		return clstr.GetRandomNode()
@@ -872,6 +880,60 @@ func (clstr *Cluster) getMasterProleNode(partition *Partition) (*Node, error) {
 	return clstr.GetRandomNode()
 }
 
+// getRackNode returns the seq-th active node containing the partition, in the order
+// of the replicas, the ones on the client's rack before the others.
+func (clstr *Cluster) getRackNode(partition *Partition, seq *int) (*Node, error) {
+	var buf [8]*Node
+	nodes := clstr.getReplicaNodes(partition, buf[:0])
+
+	rack := clstr.clientPolicy.RackId
+	onRack := 0
+	for i, node := range nodes {
+		if node.hasRack(partition.Namespace, rack) {
+			// move it behind the other nodes on the rack, keeping their order
+			copy(nodes[onRack+1:i+1], nodes[onRack:i])
+			nodes[onRack] = node
+			onRack++
+		}
+	}
+
+	if len(nodes) > 0 {
+		return nodes[*seq%len(nodes)], nil
+	}
+	return clstr.GetRandomNode()
+}
+
+// getLeastLatencyNode returns the active node containing the partition with the
+// seq-th lowest average latency. Nodes with the same average are in the order of the replicas.
+func (clstr *Cluster) getLeastLatencyNode(partition *Partition, seq *int) (*Node, error) {
+	var buf [8]*Node
+	nodes := clstr.getReplicaNodes(partition, buf[:0])
+
+	// insertion sort; there are only a few replicas
+	for i := 1; i < len(nodes); i++ {
+		for j := i; j > 0 && nodes[j].latency.get() < nodes[j-1].latency.get(); j-- {
+			nodes[j], nodes[j-1] = nodes[j-1], nodes[j]
+		}
+	}
+
+	if len(nodes) > 0 {
+		return nodes[*seq%len(nodes)], nil
+	}
+	return clstr.GetRandomNode()
+}
+
+// getReplicaNodes appends the active nodes containing the partition to nodes,
+// master first.
+func (clstr *Cluster) getReplicaNodes(partition *Partition, nodes []*Node) []*Node {
+	pmap := clstr.getPartitions()
+	for _, replicas := range pmap[partition.Namespace] {
+		if node := replicas[partition.PartitionId]; node != nil && node.IsActive() {
+			nodes = append(nodes, node)
+		}
+	}
+	return nodes
+}
+
 // GetRandomNode returns a random node on the cluster
 func (clstr *Cluster) GetRandomNode() (*Node, error) {
 	// Must copy array reference for copy on write semantics to work.
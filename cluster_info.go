@@ -0,0 +1,166 @@
+// Copyright 2013-2017 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aerospike
+
+import (
+	"sort"
+	"time"
+)
+
+// NamespaceInfo describes a namespace, as returned by GetNamespaceInfo.
+// Counts and sizes are summed over all nodes of the cluster.
+type NamespaceInfo struct {
+	Name              string
+	ReplicationFactor int
+	// Objects includes the replicas; MasterObjects does not.
+	Objects       int64
+	MasterObjects int64
+	Tombstones    int64
+	// MemoryUsed and MemorySize are in bytes.
+	MemoryUsed int64
+	MemorySize int64
+	// DiskUsed and DiskSize are in bytes, and zero for in-memory namespaces.
+	DiskUsed int64
+	DiskSize int64
+	// StopWrites and HWMBreached are true if they are true on any node.
+	StopWrites  bool
+	HWMBreached bool
+	// Nodes holds the raw statistics of each node, by node name.
+	Nodes map[string]map[string]string
+}
+
+// merge adds the statistics of a node to the namespace.
+func (ns *NamespaceInfo) merge(other *NamespaceInfo) {
+	if other.ReplicationFactor > ns.ReplicationFactor {
+		ns.ReplicationFactor = other.ReplicationFactor
+	}
+	ns.Objects += other.Objects
+	ns.MasterObjects += other.MasterObjects
+	ns.Tombstones += other.Tombstones
+	ns.MemoryUsed += other.MemoryUsed
+	ns.MemorySize += other.MemorySize
+	ns.DiskUsed += other.DiskUsed
+	ns.DiskSize += other.DiskSize
+	ns.StopWrites = ns.StopWrites || other.StopWrites
+	ns.HWMBreached = ns.HWMBreached || other.HWMBreached
+}
+
+// SetInfo describes a set, as returned by ListSets.
+// Counts and sizes are summed over all nodes of the cluster.
+type SetInfo struct {
+	Namespace  string
+	Name       string
+	Objects    int64
+	Tombstones int64
+	// MemoryUsed is in bytes.
+	MemoryUsed int64
+	// TruncateLUT is the cutoff of the last truncate of the set,
+	// or the zero time if the set was never truncated.
+	TruncateLUT time.Time
+}
+
+// merge adds the statistics of a node to the set.
+func (set *SetInfo) merge(other *SetInfo) {
+	set.Objects += other.Objects
+	set.Tombstones += other.Tombstones
+	set.MemoryUsed += other.MemoryUsed
+	if other.TruncateLUT.After(set.TruncateLUT) {
+		set.TruncateLUT = other.TruncateLUT
+	}
+}
+
+type bySetName []*SetInfo
+
+func (s bySetName) Len() int           { return len(s) }
+func (s bySetName) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
+func (s bySetName) Less(i, j int) bool { return s[i].Name < s[j].Name }
+
+// NodeInfo describes a node of the cluster, as returned by GetClusterInfo.
+type NodeInfo struct {
+	Name    string
+	Address string
+	// Build is the server version, e.g. "4.5.0.5".
+	Build      string
+	Features   []string
+	Namespaces []string
+}
+
+// ClusterInfo describes the cluster, as returned by GetClusterInfo.
+type ClusterInfo struct {
+	// Name is empty if the cluster name is not configured.
+	Name  string
+	Nodes []*NodeInfo
+	// Namespaces are the namespaces found on any node.
+	Namespaces []string
+	// Features are the features supported by all nodes.
+	Features []string
+}
+
+// HasFeature returns true if the feature is supported by all nodes of the cluster.
+func (ci *ClusterInfo) HasFeature(feature string) bool {
+	for _, f := range ci.Features {
+		if f == feature {
+			return true
+		}
+	}
+	return false
+}
+
+// Builds returns the distinct server versions of the nodes, sorted.
+// More than one build means the cluster is in the middle of an upgrade.
+func (ci *ClusterInfo) Builds() []string {
+	builds := map[string]bool{}
+	for _, node := range ci.Nodes {
+		builds[node.Build] = true
+	}
+	return sortedKeys(builds)
+}
+
+// newClusterInfo aggregates the descriptions of the nodes.
+func newClusterInfo(name string, nodes []*NodeInfo) *ClusterInfo {
+	namespaces := map[string]bool{}
+	features := map[string]int{}
+	for _, node := range nodes {
+		for _, ns := range node.Namespaces {
+			namespaces[ns] = true
+		}
+		for _, f := range node.Features {
+			features[f]++
+		}
+	}
+
+	common := map[string]bool{}
+	for f, count := range features {
+		if count == len(nodes) {
+			common[f] = true
+		}
+	}
+
+	return &ClusterInfo{
+		Name:       name,
+		Nodes:      nodes,
+		Namespaces: sortedKeys(namespaces),
+		Features:   sortedKeys(common),
+	}
+}
+
+func sortedKeys(m map[string]bool) []string {
+	res := make([]string, 0, len(m))
+	for k := range m {
+		res = append(res, k)
+	}
+	sort.Strings(res)
+	return res
+}
@@ -15,6 +15,7 @@
 package aerospike
 
 import (
+	"context"
 	"encoding/binary"
 	"errors"
 	"fmt"
@@ -64,6 +65,8 @@ const (
 	_INFO3_LAST int = (1 << 0)
 	// Commit to master only before declaring success.
 	_INFO3_COMMIT_MASTER int = (1 << 1)
+	// Partition is complete response in scan or query.
+	_INFO3_PARTITION_DONE int = (1 << 2)
 	// Update only. Merge bins.
 	_INFO3_UPDATE_ONLY int = (1 << 3)
 
@@ -87,6 +90,9 @@ type command interface {
 
 	writeBuffer(ifc command) error
 	getNode(ifc command) (*Node, error)
+	// commandType and target describe the command to CommandListener
+	commandType() CommandType
+	target() (namespace, setName string)
 	getConnection(timeout time.Duration) (*Connection, error)
 	putConnection(conn *Connection)
 	parseResult(ifc command, conn *Connection) error
@@ -97,17 +103,39 @@ type command interface {
 	Execute() error
 }
 
+// keyedCommand is implemented by single record commands, to describe their key in errors.
+type keyedCommand interface {
+	digest() []byte
+}
+
 // Holds data buffer for the command
 type baseCommand struct {
 	node *Node
 	conn *Connection
 
+	// ctx is the context the command is bound to. Cancelling it aborts
+	// the command, including any in-flight socket I/O.
+	ctx context.Context
+
 	dataBuffer []byte
 	dataOffset int
 
 	// oneShot determines if streaming commands like query, scan or queryAggregate
 	// are not retried if they error out mid-parsing
 	oneShot bool
+
+	// async determines if the command waits for a free slot in the node's
+	// async pipeline before each attempt
+	async bool
+
+	// pipelined determines if the command is sent on the node's pipelined
+	// connection instead of a pooled one
+	pipelined bool
+
+	// attemptErrors holds the errors of the attempts of the last execution,
+	// and inDoubt whether any of them may have applied a write
+	attemptErrors []error
+	inDoubt       bool
 }
 
 // Writes the command for write operations
@@ -118,6 +146,9 @@ func (cmd *baseCommand) setWrite(policy *WritePolicy, operation OperationType, k
 		return err
 	}
 
+	predExpFieldCount, predExpSize := cmd.estimatePredExpSize(policy.PredExp)
+	fieldCount += predExpFieldCount
+
 	if binMap == nil {
 		for i := range bins {
 			if err := cmd.estimateOperationSizeForBin(bins[i]); err != nil {
@@ -143,6 +174,9 @@ func (cmd *baseCommand) setWrite(policy *WritePolicy, operation OperationType, k
 	}
 
 	cmd.writeKey(key, policy.SendKey)
+	if err := cmd.writePredExps(policy.PredExp, predExpSize); err != nil {
+		return err
+	}
 
 	if binMap == nil {
 		for i := range bins {
@@ -170,11 +204,16 @@ func (cmd *baseCommand) setDelete(policy *WritePolicy, key *Key) error {
 	if err != nil {
 		return err
 	}
+	predExpFieldCount, predExpSize := cmd.estimatePredExpSize(policy.PredExp)
+	fieldCount += predExpFieldCount
 	if err := cmd.sizeBuffer(); err != nil {
 		return err
 	}
 	cmd.writeHeaderWithPolicy(policy, 0, _INFO2_WRITE|_INFO2_DELETE, fieldCount, 0)
 	cmd.writeKey(key, false)
+	if err := cmd.writePredExps(policy.PredExp, predExpSize); err != nil {
+		return err
+	}
 	cmd.end()
 	return nil
 
@@ -188,12 +227,18 @@ func (cmd *baseCommand) setTouch(policy *WritePolicy, key *Key) error {
 		return err
 	}
 
+	predExpFieldCount, predExpSize := cmd.estimatePredExpSize(policy.PredExp)
+	fieldCount += predExpFieldCount
+
 	cmd.estimateOperationSize()
 	if err := cmd.sizeBuffer(); err != nil {
 		return err
 	}
 	cmd.writeHeaderWithPolicy(policy, 0, _INFO2_WRITE, fieldCount, 1)
 	cmd.writeKey(key, policy.SendKey)
+	if err := cmd.writePredExps(policy.PredExp, predExpSize); err != nil {
+		return err
+	}
 	cmd.writeOperationForOperationType(TOUCH)
 	cmd.end()
 	return nil
@@ -207,11 +252,16 @@ func (cmd *baseCommand) setExists(policy *BasePolicy, key *Key) error {
 	if err != nil {
 		return err
 	}
+	predExpFieldCount, predExpSize := cmd.estimatePredExpSize(policy.PredExp)
+	fieldCount += predExpFieldCount
 	if err := cmd.sizeBuffer(); err != nil {
 		return err
 	}
 	cmd.writeHeader(policy, _INFO1_READ|_INFO1_NOBINDATA, 0, fieldCount, 0)
 	cmd.writeKey(key, false)
+	if err := cmd.writePredExps(policy.PredExp, predExpSize); err != nil {
+		return err
+	}
 	cmd.end()
 	return nil
 
@@ -224,11 +274,16 @@ func (cmd *baseCommand) setReadForKeyOnly(policy *BasePolicy, key *Key) error {
 	if err != nil {
 		return err
 	}
+	predExpFieldCount, predExpSize := cmd.estimatePredExpSize(policy.PredExp)
+	fieldCount += predExpFieldCount
 	if err := cmd.sizeBuffer(); err != nil {
 		return err
 	}
 	cmd.writeHeader(policy, _INFO1_READ|_INFO1_GET_ALL, 0, fieldCount, 0)
 	cmd.writeKey(key, false)
+	if err := cmd.writePredExps(policy.PredExp, predExpSize); err != nil {
+		return err
+	}
 	cmd.end()
 	return nil
 
@@ -243,6 +298,9 @@ func (cmd *baseCommand) setRead(policy *BasePolicy, key *Key, binNames []string)
 			return err
 		}
 
+		predExpFieldCount, predExpSize := cmd.estimatePredExpSize(policy.PredExp)
+		fieldCount += predExpFieldCount
+
 		for i := range binNames {
 			cmd.estimateOperationSizeForBinName(binNames[i])
 		}
@@ -251,6 +309,9 @@ func (cmd *baseCommand) setRead(policy *BasePolicy, key *Key, binNames []string)
 		}
 		cmd.writeHeader(policy, _INFO1_READ, 0, fieldCount, len(binNames))
 		cmd.writeKey(key, false)
+		if err = cmd.writePredExps(policy.PredExp, predExpSize); err != nil {
+			return err
+		}
 
 		for i := range binNames {
 			cmd.writeOperationForBinName(binNames[i], READ)
@@ -270,6 +331,8 @@ func (cmd *baseCommand) setReadHeader(policy *BasePolicy, key *Key) error {
 	if err != nil {
 		return err
 	}
+	predExpFieldCount, predExpSize := cmd.estimatePredExpSize(policy.PredExp)
+	fieldCount += predExpFieldCount
 	cmd.estimateOperationSizeForBinName("")
 	if err := cmd.sizeBuffer(); err != nil {
 		return err
@@ -278,6 +341,9 @@ func (cmd *baseCommand) setReadHeader(policy *BasePolicy, key *Key) error {
 	cmd.writeHeader(policy, _INFO1_READ|_INFO1_NOBINDATA, 0, fieldCount, 1)
 
 	cmd.writeKey(key, false)
+	if err := cmd.writePredExps(policy.PredExp, predExpSize); err != nil {
+		return err
+	}
 	cmd.writeOperationForBinName("", READ)
 	cmd.end()
 	return nil
@@ -306,7 +372,7 @@ func (cmd *baseCommand) setOperate(policy *WritePolicy, key *Key, operations []*
 			RespondPerEachOp = true
 			// Fall through to read.
 			fallthrough
-		case READ, CDT_READ:
+		case READ, CDT_READ, BIT_READ, HLL_READ:
 			if !operations[i].headerOnly {
 				readAttr |= _INFO1_READ
 
@@ -337,6 +403,9 @@ func (cmd *baseCommand) setOperate(policy *WritePolicy, key *Key, operations []*
 	}
 	fieldCount += ksz
 
+	predExpFieldCount, predExpSize := cmd.estimatePredExpSize(policy.PredExp)
+	fieldCount += predExpFieldCount
+
 	if err := cmd.sizeBuffer(); err != nil {
 		return hasWrite, err
 	}
@@ -355,6 +424,9 @@ func (cmd *baseCommand) setOperate(policy *WritePolicy, key *Key, operations []*
 		cmd.writeHeader(&policy.BasePolicy, readAttr, writeAttr, fieldCount, len(operations))
 	}
 	cmd.writeKey(key, policy.SendKey && writeAttr != 0)
+	if err := cmd.writePredExps(policy.PredExp, predExpSize); err != nil {
+		return hasWrite, err
+	}
 
 	for _, operation := range operations {
 		if err := cmd.writeOperationForOperation(operation); err != nil {
@@ -380,12 +452,18 @@ func (cmd *baseCommand) setUdf(policy *WritePolicy, key *Key, packageName string
 	}
 	fieldCount += fc
 
+	predExpFieldCount, predExpSize := cmd.estimatePredExpSize(policy.PredExp)
+	fieldCount += predExpFieldCount
+
 	if err := cmd.sizeBuffer(); err != nil {
 		return err
 	}
 
 	cmd.writeHeaderWithPolicy(policy, 0, _INFO2_WRITE, fieldCount, 0)
 	cmd.writeKey(key, policy.SendKey)
+	if err := cmd.writePredExps(policy.PredExp, predExpSize); err != nil {
+		return err
+	}
 	cmd.writeFieldString(packageName, UDF_PACKAGE_NAME)
 	cmd.writeFieldString(functionName, UDF_FUNCTION)
 	cmd.writeUdfArgs(args)
@@ -465,6 +543,8 @@ func (cmd *baseCommand) setBatchIndexReadCompat(policy *BatchPolicy, keys []*Key
 	// Estimate buffer size
 	cmd.begin()
 
+	predExpFieldCount, predExpSize := cmd.estimatePredExpSize(policy.PredExp)
+
 	cmd.dataOffset += int(_FIELD_HEADER_SIZE) + 5
 
 	var prev *Key
@@ -501,7 +581,10 @@ func (cmd *baseCommand) setBatchIndexReadCompat(policy *BatchPolicy, keys []*Key
 		readAttr |= _INFO1_GET_ALL
 	}
 
-	cmd.writeHeader(&policy.BasePolicy, readAttr|_INFO1_BATCH, 0, 1, 0)
+	cmd.writeHeader(&policy.BasePolicy, readAttr|_INFO1_BATCH, 0, 1+predExpFieldCount, 0)
+	if err := cmd.writePredExps(policy.PredExp, predExpSize); err != nil {
+		return err
+	}
 
 	// Write real field size.
 	fieldSizeOffset := cmd.dataOffset
@@ -568,6 +651,8 @@ func (cmd *baseCommand) setBatchIndexRead(policy *BatchPolicy, records []*BatchR
 	// Estimate buffer size
 	cmd.begin()
 
+	predExpFieldCount, predExpSize := cmd.estimatePredExpSize(policy.PredExp)
+
 	cmd.dataOffset += int(_FIELD_HEADER_SIZE) + 5
 
 	var prev *BatchRead
@@ -611,8 +696,11 @@ func (cmd *baseCommand) setBatchIndexRead(policy *BatchPolicy, records []*BatchR
 		readAttr |= _INFO1_CONSISTENCY_ALL
 	}
 
-	cmd.writeHeader(&policy.BasePolicy, readAttr|_INFO1_BATCH, 0, 1, 0)
-	cmd.writeHeader(&policy.BasePolicy, _INFO1_READ|_INFO1_BATCH, 0, 1, 0)
+	cmd.writeHeader(&policy.BasePolicy, readAttr|_INFO1_BATCH, 0, 1+predExpFieldCount, 0)
+	cmd.writeHeader(&policy.BasePolicy, _INFO1_READ|_INFO1_BATCH, 0, 1+predExpFieldCount, 0)
+	if err := cmd.writePredExps(policy.PredExp, predExpSize); err != nil {
+		return err
+	}
 
 	// Write real field size.
 	fieldSizeOffset := cmd.dataOffset
@@ -728,10 +816,15 @@ func (cmd *baseCommand) setBatchRead(policy *BatchPolicy, keys []*Key, batch *ba
 	return nil
 }
 
-func (cmd *baseCommand) setScan(policy *ScanPolicy, namespace *string, setName *string, binNames []string, taskId uint64) error {
+func (cmd *baseCommand) setScan(policy *ScanPolicy, namespace *string, setName *string, binNames []string, taskId uint64, partitions *nodePartitions, maxRecords int64) error {
 	cmd.begin()
 	fieldCount := 0
-	// predExpsSize := 0
+
+	fieldCount += cmd.estimatePartitionsSize(partitions)
+	fieldCount += cmd.estimateLimitsSize(policy.MultiPolicy, maxRecords)
+
+	predExpFieldCount, predExpSize := cmd.estimatePredExpSize(policy.PredExp)
+	fieldCount += predExpFieldCount
 
 	if namespace != nil {
 		cmd.dataOffset += len(*namespace) + int(_FIELD_HEADER_SIZE)
@@ -784,11 +877,15 @@ func (cmd *baseCommand) setScan(policy *ScanPolicy, namespace *string, setName *
 		cmd.writeFieldString(*setName, TABLE)
 	}
 
+	cmd.writePartitions(partitions)
+	cmd.writeLimits(policy.MultiPolicy, maxRecords)
+
 	cmd.writeFieldHeader(2, SCAN_OPTIONS)
 	priority := byte(policy.Priority)
 	priority <<= 4
 
-	if policy.FailOnClusterChange {
+	// Partition scans survive cluster changes
+	if policy.FailOnClusterChange && partitions == nil {
 		priority |= 0x08
 	}
 
@@ -806,6 +903,10 @@ func (cmd *baseCommand) setScan(policy *ScanPolicy, namespace *string, setName *
 	cmd.writeFieldHeader(8, TRAN_ID)
 	cmd.WriteUint64(taskId)
 
+	if err := cmd.writePredExps(policy.PredExp, predExpSize); err != nil {
+		return err
+	}
+
 	if binNames != nil {
 		for i := range binNames {
 			cmd.writeOperationForBinName(binNames[i], READ)
@@ -817,14 +918,22 @@ func (cmd *baseCommand) setScan(policy *ScanPolicy, namespace *string, setName *
 	return nil
 }
 
-func (cmd *baseCommand) setQuery(policy *QueryPolicy, statement *Statement, write bool) (err error) {
+func (cmd *baseCommand) setQuery(policy *QueryPolicy, statement *Statement, write bool, partitions *nodePartitions, maxRecords int64) (err error) {
 	fieldCount := 0
 	filterSize := 0
 	binNameSize := 0
-	predExpsSize := 0
+
+	// Statement predicate expressions take precedence over the policy's.
+	predExps := statement.predExps
+	if len(predExps) == 0 {
+		predExps = policy.PredExp
+	}
 
 	cmd.begin()
 
+	fieldCount += cmd.estimatePartitionsSize(partitions)
+	fieldCount += cmd.estimateLimitsSize(policy.MultiPolicy, maxRecords)
+
 	if statement.Namespace != "" {
 		cmd.dataOffset += len(statement.Namespace) + int(_FIELD_HEADER_SIZE)
 		fieldCount++
@@ -887,14 +996,8 @@ func (cmd *baseCommand) setQuery(policy *QueryPolicy, statement *Statement, writ
 		fieldCount++
 	}
 
-	if len(statement.predExps) > 0 {
-		cmd.dataOffset += int(_FIELD_HEADER_SIZE)
-		for _, predexp := range statement.predExps {
-			predExpsSize += predexp.marshaledSize()
-		}
-		cmd.dataOffset += predExpsSize
-		fieldCount++
-	}
+	predExpFieldCount, predExpSize := cmd.estimatePredExpSize(predExps)
+	fieldCount += predExpFieldCount
 
 	var functionArgs *ValueArray
 	if statement.functionName != "" {
@@ -957,6 +1060,9 @@ func (cmd *baseCommand) setQuery(policy *QueryPolicy, statement *Statement, writ
 	cmd.writeFieldHeader(8, TRAN_ID)
 	cmd.WriteUint64(statement.TaskId)
 
+	cmd.writePartitions(partitions)
+	cmd.writeLimits(policy.MultiPolicy, maxRecords)
+
 	if len(statement.Filters) > 0 {
 		if len(statement.Filters) >= 1 {
 			idxType := statement.Filters[0].IndexCollectionType()
@@ -996,13 +1102,8 @@ func (cmd *baseCommand) setQuery(policy *QueryPolicy, statement *Statement, writ
 		cmd.WriteByte(byte(100))
 	}
 
-	if len(statement.predExps) > 0 {
-		cmd.writeFieldHeader(predExpsSize, PREDEXP)
-		for _, predexp := range statement.predExps {
-			if err := predexp.marshal(cmd); err != nil {
-				return err
-			}
-		}
+	if err := cmd.writePredExps(predExps, predExpSize); err != nil {
+		return err
 	}
 
 	if statement.functionName != "" {
@@ -1033,6 +1134,115 @@ func (cmd *baseCommand) setQuery(policy *QueryPolicy, statement *Statement, writ
 	return nil
 }
 
+// estimatePredExpSize estimates the size of the predicate expression field,
+// and returns the number of fields and the size of the expressions.
+func (cmd *baseCommand) estimatePredExpSize(predExps []PredExp) (int, int) {
+	if len(predExps) == 0 {
+		return 0, 0
+	}
+
+	size := 0
+	for _, predexp := range predExps {
+		size += predexp.marshaledSize()
+	}
+	cmd.dataOffset += size + int(_FIELD_HEADER_SIZE)
+	return 1, size
+}
+
+// writePredExps writes the predicate expression field, if there are any expressions.
+func (cmd *baseCommand) writePredExps(predExps []PredExp, size int) error {
+	if len(predExps) == 0 {
+		return nil
+	}
+
+	cmd.writeFieldHeader(size, PREDEXP)
+	for _, predexp := range predExps {
+		if err := predexp.marshal(cmd); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// estimatePartitionsSize estimates the size of the partition fields of a partition
+// scan or query, and returns the number of fields.
+func (cmd *baseCommand) estimatePartitionsSize(partitions *nodePartitions) int {
+	if partitions == nil {
+		return 0
+	}
+
+	fieldCount := 0
+	pids, digests := partitions.counts()
+	if pids > 0 {
+		cmd.dataOffset += pids*2 + int(_FIELD_HEADER_SIZE)
+		fieldCount++
+	}
+
+	if digests > 0 {
+		cmd.dataOffset += digests*int(_DIGEST_SIZE) + int(_FIELD_HEADER_SIZE)
+		fieldCount++
+	}
+	return fieldCount
+}
+
+// writePartitions writes the ids of the partitions to read from the beginning,
+// and the digests to resume the other partitions after.
+func (cmd *baseCommand) writePartitions(partitions *nodePartitions) {
+	if partitions == nil {
+		return
+	}
+
+	pids, digests := partitions.counts()
+	if pids > 0 {
+		cmd.writeFieldHeader(pids*2, PID_ARRAY)
+		for _, ps := range partitions.parts {
+			if ps.Digest == nil {
+				cmd.WriteByte(byte(ps.Id))
+				cmd.WriteByte(byte(ps.Id >> 8))
+			}
+		}
+	}
+
+	if digests > 0 {
+		cmd.writeFieldHeader(digests*int(_DIGEST_SIZE), DIGEST_ARRAY)
+		for _, ps := range partitions.parts {
+			if ps.Digest != nil {
+				copy(cmd.dataBuffer[cmd.dataOffset:], ps.Digest)
+				cmd.dataOffset += int(_DIGEST_SIZE)
+			}
+		}
+	}
+}
+
+// estimateLimitsSize estimates the size of the fields which limit the records
+// returned by a scan or query, and returns the number of fields.
+func (cmd *baseCommand) estimateLimitsSize(policy *MultiPolicy, maxRecords int64) int {
+	fieldCount := 0
+	if maxRecords > 0 {
+		cmd.dataOffset += 8 + int(_FIELD_HEADER_SIZE)
+		fieldCount++
+	}
+
+	if policy.RecordsPerSecond > 0 {
+		cmd.dataOffset += 4 + int(_FIELD_HEADER_SIZE)
+		fieldCount++
+	}
+	return fieldCount
+}
+
+// writeLimits writes the fields which limit the records returned by a scan or query.
+func (cmd *baseCommand) writeLimits(policy *MultiPolicy, maxRecords int64) {
+	if maxRecords > 0 {
+		cmd.writeFieldHeader(8, MAX_RECORDS)
+		cmd.WriteUint64(uint64(maxRecords))
+	}
+
+	if policy.RecordsPerSecond > 0 {
+		cmd.writeFieldHeader(4, RECORDS_PER_SECOND)
+		cmd.WriteUint32(uint32(policy.RecordsPerSecond))
+	}
+}
+
 func (cmd *baseCommand) estimateKeySize(key *Key, sendKey bool) (int, error) {
 	fieldCount := 0
 
@@ -1545,12 +1755,122 @@ func SetCommandBufferPool(poolSize, initBufSize, maxBufferSize int) {
 	panic("There is no need to optimize the buffer pool anymore. Buffers have moved to Connection object.")
 }
 
+// executePipelined runs an attempt of the command on the node's pipelined connection.
+// It returns false if the attempt must not be retried, and if the command was sent.
+func (cmd *baseCommand) executePipelined(ifc command, attempt *commandAttempt, policy *BasePolicy, socketTimeout time.Duration) (retry bool, sent bool, err error) {
+	pipeline, err := cmd.node.getPipeline(cmd.context(), socketTimeout)
+	if err != nil {
+		Logger.Warn("Node " + cmd.node.String() + ": " + err.Error())
+		return true, false, err
+	}
+
+	cmd.dataBuffer = pipelineBufferPool.Get()
+	defer func() {
+		// in case it has grown and re-allocated
+		pipelineBufferPool.Put(cmd.dataBuffer)
+		cmd.dataBuffer = nil
+	}()
+
+	// All runtime exceptions are considered fatal. Do not retry.
+	if err = ifc.writeBuffer(ifc); err != nil {
+		return false, false, err
+	}
+	binary.BigEndian.PutUint32(cmd.dataBuffer[22:], uint32(policy.Timeout/time.Millisecond))
+
+	response, sent, err := pipeline.exchange(cmd.context(), cmd.dataBuffer[:cmd.dataOffset], socketTimeout)
+	if err != nil {
+		Logger.Warn("Node " + cmd.node.String() + ": " + err.Error())
+
+		// A write that may have been applied is never replayed.
+		if sent && writeInDoubt(ifc, err) {
+			cmd.inDoubt = true
+			return false, true, err
+		}
+
+		// IO errors are considered temporary anomalies. Retry.
+		return true, sent, err
+	}
+
+	// The response has been read whole, so the parser can not
+	// leave the connection out of sync.
+	conn := newResponseConnection(response)
+	attempt.attach(conn)
+	conn.bytesSent = int64(cmd.dataOffset)
+
+	if err = ifc.parseResult(ifc, conn); err != nil && writeInDoubt(ifc, err) {
+		cmd.inDoubt = true
+		return false, true, err
+	}
+	return true, true, err
+}
+
+// markInDoubt records that the command may have been applied, if it is a write
+// and the attempt failed without a definite answer from the server after it was sent.
+func (cmd *baseCommand) markInDoubt(ifc command, err error) {
+	if writeInDoubt(ifc, err) {
+		cmd.inDoubt = true
+	}
+}
+
+// writeInDoubt determines if a sent attempt of the command, failing with err,
+// may have applied a write.
+func writeInDoubt(ifc command, err error) bool {
+	switch ifc.commandType() {
+	case CMD_WRITE, CMD_DELETE, CMD_TOUCH, CMD_OPERATE, CMD_UDF:
+		code := resultCodeOf(err)
+		return code == TIMEOUT || code <= 0
+	}
+	return false
+}
+
+// commandError adds the node, the target, the attempts and the in doubt status
+// of the command to the error it failed with.
+func (cmd *baseCommand) commandError(ifc command, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	info := &CommandInfo{InDoubt: cmd.inDoubt, Attempts: cmd.attemptErrors}
+	info.Namespace, _ = ifc.target()
+	if cmd.node != nil {
+		info.Node = cmd.node.String()
+	}
+	if kc, ok := ifc.(keyedCommand); ok {
+		info.Digest = kc.digest()
+	}
+	return WithCommandInfo(err, info)
+}
+
+// contextError is returned when the command is aborted because its context is done.
+// It wraps the context error, so that errors.Is(err, context.Canceled) holds.
+func contextError(ctx context.Context) error {
+	return NewAerospikeErrorWithCause(TIMEOUT, ctx.Err(), "command aborted by its context: "+ctx.Err().Error())
+}
+
+// context returns the context the command is bound to.
+// Commands without a context are never cancelled.
+func (cmd *baseCommand) context() context.Context {
+	if cmd.ctx == nil {
+		return context.Background()
+	}
+	return cmd.ctx
+}
+
 func (cmd *baseCommand) execute(ifc command) error {
+	cmd.attemptErrors = nil
+	cmd.inDoubt = false
+	return cmd.commandError(ifc, cmd.executeAttempts(ifc))
+}
+
+// executeAttempts sends the command until it succeeds, or the retry policy gives up.
+func (cmd *baseCommand) executeAttempts(ifc command) error {
 	policy := ifc.getPolicy(ifc).GetBasePolicy()
-	iterations := -1
+	ctx := cmd.context()
+	retryPolicy := policy.retryPolicy()
 
-	// for exponential backoff
-	interval := policy.SleepBetweenRetries
+	// number of failed attempts so far, and the delay before the next one
+	attempts := 0
+	var delay time.Duration
 
 	// set timeout outside the loop
 	deadline := time.Now().Add(policy.Timeout)
@@ -1560,18 +1880,24 @@ func (cmd *baseCommand) execute(ifc command) error {
 		return err
 	}
 
-	// Execute command until successful, timed out or maximum iterations have been reached.
+	// retriesExceeded is returned when a transient failure is not retried anymore
+	retriesExceeded := func() error {
+		return NewAerospikeErrorWithCause(TIMEOUT, err, fmt.Sprintf("command execution timed out on client: Exceeded number of retries. See `Policy.MaxRetries` and `Policy.RetryPolicy`. (last error: %s)", err))
+	}
+
+	// Execute command until successful, timed out or the retry policy gives up.
 	for {
-		// too many retries
-		if iterations++; (policy.MaxRetries <= 0 && iterations > 0) || (policy.MaxRetries > 0 && iterations > policy.MaxRetries) {
-			return NewAerospikeError(TIMEOUT, fmt.Sprintf("command execution timed out on client: Exceeded number of retries. See `Policy.MaxRetries`. (last error: %s)", err))
+		// context was cancelled or its deadline passed; do not try again
+		if ctx.Err() != nil {
+			return contextError(ctx)
 		}
 
-		// Sleep before trying again, after the first iteration
-		if iterations > 0 && policy.SleepBetweenRetries > 0 {
-			time.Sleep(interval)
-			if policy.SleepMultiplier > 1 {
-				interval = time.Duration(float64(interval) * policy.SleepMultiplier)
+		// Sleep before trying again, after the first attempt
+		if attempts > 0 && delay > 0 {
+			select {
+			case <-time.After(delay):
+			case <-ctx.Done():
+				return contextError(ctx)
 			}
 		}
 
@@ -1582,17 +1908,63 @@ func (cmd *baseCommand) execute(ifc command) error {
 
 		// set command node, so when you return a record it has the node
 		cmd.node, err = ifc.getNode(ifc)
+		attempt := cmd.startAttempt(ifc, attempts+1)
 		if cmd.node == nil || !cmd.node.IsActive() || err != nil {
 			// Node is currently inactive. Retry.
+			if err == nil {
+				err = NewAerospikeError(INVALID_NODE_ERROR)
+			}
+			attempt.finish(err)
+			if attempts++; !cmd.retry(ifc, retryPolicy, attempts, err, false, &delay) {
+				return retriesExceeded()
+			}
 			continue
 		}
 
+		// Async commands wait for a free slot in the node's pipeline.
+		if cmd.async {
+			var wait time.Time
+			if policy.Timeout > 0 {
+				wait = deadline
+			}
+			if err = attempt.acquire(cmd.node.asyncPipeline, ctx, wait); err != nil {
+				attempt.finish(err)
+				return err
+			}
+		}
+
+		// Pipelined commands share a connection of the node.
+		if cmd.pipelined {
+			var retry, sent bool
+			retry, sent, err = cmd.executePipelined(ifc, &attempt, policy, socketTimeout)
+			attempt.finish(err)
+			if err == nil {
+				return nil
+			}
+
+			if retry {
+				if attempts++; cmd.retry(ifc, retryPolicy, attempts, err, sent, &delay) {
+					continue
+				}
+			}
+			return err
+		}
+
 		cmd.conn, err = ifc.getConnection(socketTimeout)
 		if err != nil {
+			attempt.finish(err)
 			Logger.Warn("Node " + cmd.node.String() + ": " + err.Error())
+			if attempts++; !cmd.retry(ifc, retryPolicy, attempts, err, false, &delay) {
+				return retriesExceeded()
+			}
 			continue
 		}
 
+		attempt.attach(cmd.conn)
+
+		// Abort socket I/O as soon as the context is done
+		stopWatch := cmd.conn.watchContext(ctx)
+
 		// Assign the connection buffer to the command buffer
 		cmd.dataBuffer = cmd.conn.dataBuffer
 
@@ -1601,7 +1973,9 @@ func (cmd *baseCommand) execute(ifc command) error {
 		if err != nil {
 			// All runtime exceptions are considered fatal. Do not retry.
 			// Close socket to flush out possible garbage. Do not put back in pool.
+			stopWatch()
 			cmd.conn.Close()
+			attempt.finish(err)
 			return err
 		}
 
@@ -1614,15 +1988,34 @@ func (cmd *baseCommand) execute(ifc command) error {
 		if err != nil {
 			// IO errors are considered temporary anomalies. Retry.
 			// Close socket to flush out possible garbage. Do not put back in pool.
+			stopWatch()
 			cmd.conn.Close()
+			attempt.finish(err)
 
 			Logger.Warn("Node " + cmd.node.String() + ": " + err.Error())
+			if attempts++; !cmd.retry(ifc, retryPolicy, attempts, err, false, &delay) {
+				return retriesExceeded()
+			}
 			continue
 		}
 
 		// Parse results.
 		err = ifc.parseResult(ifc, cmd.conn)
+		stopWatch()
 		if err != nil {
+			cmd.markInDoubt(ifc, err)
+
+			// the I/O was interrupted because the context is done;
+			// the connection is in an undefined state
+			if ctx.Err() != nil {
+				cmd.conn.Close()
+				err = contextError(ctx)
+				cmd.markInDoubt(ifc, err)
+				attempt.finish(err)
+				return err
+			}
+			attempt.finish(err)
+
 			if err == io.EOF {
 				// IO errors are considered temporary anomalies. Retry.
 				// Close socket to flush out possible garbage. Do not put back in pool.
@@ -1632,6 +2025,9 @@ func (cmd *baseCommand) execute(ifc command) error {
 
 				// retry only for non-streaming commands
 				if !cmd.oneShot {
+					if attempts++; !cmd.retry(ifc, retryPolicy, attempts, err, true, &delay) {
+						return retriesExceeded()
+					}
 					continue
 				}
 			}
@@ -1647,12 +2043,29 @@ func (cmd *baseCommand) execute(ifc command) error {
 				cmd.conn.Close()
 
 			}
+
+			// Streaming commands cannot be retried once they have started
+			// parsing. Other commands are retried if the retry policy allows.
+			if !cmd.oneShot && err != io.EOF {
+				if attempts++; cmd.retry(ifc, retryPolicy, attempts, err, true, &delay) {
+					continue
+				}
+			}
 			return err
 		}
 
+		attempt.finish(nil)
+
 		// in case it has grown and re-allocated
 		cmd.conn.dataBuffer = cmd.dataBuffer
 
+		// The context may have been cancelled right after the command
+		// completed, leaving an expired deadline on the socket. Do not pool it.
+		if ctx.Err() != nil {
+			cmd.conn.Close()
+			return nil
+		}
+
 		// Put connection back in pool.
 		// cmd.node.PutConnection(cmd.conn)
 		ifc.putConnection(cmd.conn)
@@ -1666,6 +2079,35 @@ func (cmd *baseCommand) execute(ifc command) error {
 	return NewAerospikeError(TIMEOUT, "command execution timed out on client: See `Policy.Timeout`")
 }
 
+// retry consults the retry policy after a failed attempt; sent tells if the
+// command was sent to the node before the attempt failed.
+// If the attempt should be retried, it sets the delay before the next attempt,
+// and directs the command to another replica if requested, or if the replica
+// policy always retries on the next replica.
+func (cmd *baseCommand) retry(ifc command, retryPolicy RetryPolicy, attempts int, err error, sent bool, delay *time.Duration) bool {
+	decision := retryPolicy.Retry(&RetryAttempt{
+		Attempt:    attempts,
+		Node:       cmd.node,
+		ResultCode: resultCodeOf(err),
+		Err:        err,
+		LastDelay:  *delay,
+		Sent:       sent,
+		InDoubt:    sent && writeInDoubt(ifc, err),
+	})
+
+	if !decision.Retry {
+		return false
+	}
+
+	*delay = decision.Delay
+	if decision.SwitchReplica || ifc.getPolicy(ifc).GetBasePolicy().ReplicaPolicy.retriesOnNextReplica() {
+		if rs, ok := ifc.(replicaSwitcher); ok {
+			rs.switchReplica()
+		}
+	}
+	return true
+}
+
 func (cmd *baseCommand) parseRecordResults(ifc command, receiveSize int) (bool, error) {
 	panic(errors.New("Abstract method. Should not end up here"))
 }
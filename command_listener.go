@@ -0,0 +1,229 @@
+// Copyright 2013-2017 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aerospike
+
+import (
+	"context"
+	"time"
+
+	. "github.com/aerospike/aerospike-client-go/types"
+)
+
+// CommandType identifies the kind of a command sent to the server.
+type CommandType string
+
+const (
+	// CMD_READ reads a record or its header.
+	CMD_READ CommandType = "read"
+	// CMD_EXISTS checks existence of a record.
+	CMD_EXISTS CommandType = "exists"
+	// CMD_WRITE puts, appends, prepends or adds to bins of a record.
+	CMD_WRITE CommandType = "write"
+	// CMD_DELETE deletes a record.
+	CMD_DELETE CommandType = "delete"
+	// CMD_TOUCH resets the TTL of a record.
+	CMD_TOUCH CommandType = "touch"
+	// CMD_OPERATE applies multiple operations to a record.
+	CMD_OPERATE CommandType = "operate"
+	// CMD_UDF executes a user defined function on a record, or on a query in the background.
+	CMD_UDF CommandType = "udf"
+	// CMD_BATCH reads multiple records from a node.
+	CMD_BATCH CommandType = "batch"
+	// CMD_SCAN scans a namespace or set on a node.
+	CMD_SCAN CommandType = "scan"
+	// CMD_QUERY runs a query on a node.
+	CMD_QUERY CommandType = "query"
+	// CMD_INFO sends info commands to a node.
+	CMD_INFO CommandType = "info"
+	// CMD_ADMIN manages users and roles.
+	CMD_ADMIN CommandType = "admin"
+)
+
+// CommandEvent describes a command attempt sent to a node.
+// The same instance is passed to CommandStarted and CommandFinished.
+type CommandEvent struct {
+	// Type is the kind of the command.
+	Type CommandType
+
+	// Namespace and SetName are the targets of the command, if any.
+	// Batch commands report the namespace of their first key.
+	Namespace string
+	SetName   string
+
+	// Node is the node the attempt is sent to.
+	Node *Node
+
+	// Attempt is the attempt number, starting from 1.
+	Attempt int
+
+	// Context is the context the command is bound to.
+	Context context.Context
+
+	// The following fields are only valid in CommandFinished.
+
+	// BytesSent and BytesReceived count the bytes written to and read from the socket.
+	BytesSent     int64
+	BytesReceived int64
+
+	// Latency is the duration of the attempt.
+	Latency time.Duration
+
+	// ResultCode is OK if the attempt succeeded.
+	ResultCode ResultCode
+
+	// Err is the error the attempt failed with, if any.
+	Err error
+
+	// UserData can be set by the listener in CommandStarted
+	// to pass state, like a tracing span, to CommandFinished.
+	UserData interface{}
+}
+
+// CommandListener is notified when an attempt of a command starts and ends.
+// Attempts of all commands are reported: single record, batch, scan, query,
+// client issued info commands and user administration commands.
+// Cluster tending is not reported.
+//
+// The listener is called synchronously on the goroutine executing the command,
+// and should not block. It must be safe for concurrent use.
+// Attempts that fail before a node is selected are not reported.
+type CommandListener interface {
+	// CommandStarted is called before an attempt is sent to the node.
+	CommandStarted(event *CommandEvent)
+
+	// CommandFinished is called after the attempt has finished.
+	CommandFinished(event *CommandEvent)
+}
+
+// commandAttempt tracks an attempt of a command, to update the node's
+// statistics and notify the command listener.
+type commandAttempt struct {
+	node    *Node
+	cmdType CommandType
+	started time.Time
+
+	// event is nil if there is no command listener
+	event *CommandEvent
+
+	conn                  *Connection
+	bytesSent, bytesRecvd int64
+
+	// pipeline is the async pipeline the attempt holds a slot of, if any
+	pipeline asyncPipeline
+
+	// errs collects the errors of the attempts of the command, if any
+	errs *[]error
+}
+
+// startAttempt starts tracking an attempt of a command on the node,
+// and notifies the command listener, if any.
+func startAttempt(node *Node, cmdType CommandType, namespace, setName string, attempt int, ctx context.Context) commandAttempt {
+	ca := commandAttempt{
+		node:    node,
+		cmdType: cmdType,
+		started: time.Now(),
+	}
+
+	if node == nil || node.cluster == nil {
+		return ca
+	}
+
+	if listener := node.cluster.clientPolicy.CommandListener; listener != nil {
+		ca.event = &CommandEvent{
+			Type:      cmdType,
+			Namespace: namespace,
+			SetName:   setName,
+			Node:      node,
+			Attempt:   attempt,
+			Context:   ctx,
+		}
+		listener.CommandStarted(ca.event)
+	}
+	return ca
+}
+
+// attach sets the connection the attempt is using, to count the transferred bytes.
+func (ca *commandAttempt) attach(conn *Connection) {
+	if ca.event == nil || conn == nil {
+		return
+	}
+	ca.conn = conn
+	ca.bytesSent = conn.bytesSent
+	ca.bytesRecvd = conn.bytesReceived
+}
+
+// acquire waits for a free slot in the async pipeline, which is released when the
+// attempt finishes.
+func (ca *commandAttempt) acquire(pipeline asyncPipeline, ctx context.Context, deadline time.Time) error {
+	if err := pipeline.acquire(ctx, deadline); err != nil {
+		return err
+	}
+	ca.pipeline = pipeline
+	return nil
+}
+
+// finish records the attempt in the node's statistics
+// and notifies the command listener that the attempt has finished with the error.
+func (ca *commandAttempt) finish(err error) {
+	if ca.pipeline != nil {
+		ca.pipeline.release()
+		ca.pipeline = nil
+	}
+
+	if err != nil && ca.errs != nil {
+		*ca.errs = append(*ca.errs, err)
+	}
+
+	if ca.node == nil {
+		return
+	}
+
+	latency := time.Since(ca.started)
+	resultCode := OK
+	if err != nil {
+		resultCode = resultCodeOf(err)
+	}
+
+	ca.node.stats.commands.record(ca.cmdType, latency, resultCode)
+
+	// Attempts that failed on the client are not a measure of the node's
+	// response time, except for timeouts.
+	if tracksLatency(ca.cmdType) && (resultCode >= 0 || resultCode == TIMEOUT) {
+		ca.node.latency.record(latency)
+	}
+
+	if ca.event == nil {
+		return
+	}
+
+	if ca.conn != nil {
+		ca.event.BytesSent = ca.conn.bytesSent - ca.bytesSent
+		ca.event.BytesReceived = ca.conn.bytesReceived - ca.bytesRecvd
+		ca.conn = nil
+	}
+	ca.event.Latency = latency
+	ca.event.ResultCode = resultCode
+	ca.event.Err = err
+
+	ca.node.cluster.clientPolicy.CommandListener.CommandFinished(ca.event)
+}
+
+// startAttempt starts tracking an attempt of the command on cmd.node.
+func (cmd *baseCommand) startAttempt(ifc command, attempt int) commandAttempt {
+	namespace, setName := ifc.target()
+	ca := startAttempt(cmd.node, ifc.commandType(), namespace, setName, attempt, cmd.context())
+	ca.errs = &cmd.attemptErrors
+	return ca
+}
@@ -0,0 +1,90 @@
+// Copyright 2013-2017 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aerospike
+
+import (
+	"context"
+	"io"
+	"net"
+
+	. "github.com/aerospike/aerospike-client-go/types"
+
+	. "github.com/onsi/ginkgo"
+	gm "github.com/onsi/gomega"
+)
+
+type testListener struct {
+	started, finished []*CommandEvent
+}
+
+func (tl *testListener) CommandStarted(event *CommandEvent) {
+	tl.started = append(tl.started, event)
+}
+
+func (tl *testListener) CommandFinished(event *CommandEvent) {
+	tl.finished = append(tl.finished, event)
+}
+
+var _ = Describe("Command Listener test", func() {
+
+	It("must only update the statistics without a listener", func() {
+		node := &Node{cluster: &Cluster{clientPolicy: *NewClientPolicy()}}
+		node.stats.commands = newCommandStats(7, 1)
+
+		attempt := startAttempt(node, CMD_READ, "test", "set", 1, context.Background())
+		gm.Expect(attempt.event).To(gm.BeNil())
+		attempt.attach(&Connection{})
+		attempt.finish(nil)
+
+		gm.Expect(node.stats.commands.export()[CMD_READ].Attempts).To(gm.Equal(int64(1)))
+	})
+
+	It("must report the bytes transferred, latency and result of an attempt", func() {
+		listener := &testListener{}
+		policy := NewClientPolicy()
+		policy.CommandListener = listener
+		node := &Node{cluster: &Cluster{clientPolicy: *policy}}
+
+		client, server := net.Pipe()
+		defer client.Close()
+		defer server.Close()
+		conn := &Connection{conn: client, node: node}
+
+		attempt := startAttempt(node, CMD_WRITE, "test", "set", 2, context.Background())
+		event := attempt.event
+		gm.Expect(listener.started).To(gm.Equal([]*CommandEvent{event}))
+		gm.Expect(event.Type).To(gm.Equal(CMD_WRITE))
+		gm.Expect(event.Attempt).To(gm.Equal(2))
+		attempt.attach(conn)
+
+		go func() {
+			buf := make([]byte, 5)
+			io.ReadFull(server, buf)
+			server.Write([]byte{1, 2, 3})
+		}()
+
+		_, err := conn.Write([]byte("hello"))
+		gm.Expect(err).ToNot(gm.HaveOccurred())
+		_, err = conn.Read(make([]byte, 3), 3)
+		gm.Expect(err).ToNot(gm.HaveOccurred())
+
+		attempt.finish(NewAerospikeError(KEY_EXISTS_ERROR))
+		gm.Expect(listener.finished).To(gm.Equal([]*CommandEvent{event}))
+		gm.Expect(event.BytesSent).To(gm.Equal(int64(5)))
+		gm.Expect(event.BytesReceived).To(gm.Equal(int64(3)))
+		gm.Expect(event.Latency).To(gm.BeNumerically(">", 0))
+		gm.Expect(event.ResultCode).To(gm.Equal(KEY_EXISTS_ERROR))
+	})
+})
@@ -0,0 +1,356 @@
+// Copyright 2013-2017 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aerospike
+
+import (
+	"math"
+	"sync/atomic"
+	"time"
+
+	. "github.com/aerospike/aerospike-client-go/types"
+)
+
+// commandTypes lists the command types tracked in the statistics.
+var commandTypes = []CommandType{
+	CMD_READ, CMD_EXISTS, CMD_WRITE, CMD_DELETE, CMD_TOUCH, CMD_OPERATE,
+	CMD_UDF, CMD_BATCH, CMD_SCAN, CMD_QUERY, CMD_INFO, CMD_ADMIN,
+}
+
+// commandTypeIndex maps a command type to its position in commandTypes.
+var commandTypeIndex = func() map[CommandType]int {
+	res := make(map[CommandType]int, len(commandTypes))
+	for i, t := range commandTypes {
+		res[t] = i
+	}
+	return res
+}()
+
+// Result codes in [_MIN_TRACKED_RESULT_CODE, _MAX_TRACKED_RESULT_CODE] are counted
+// individually. All client and server result codes fit, except for the Lua file codes.
+const (
+	_MIN_TRACKED_RESULT_CODE = -16
+	_MAX_TRACKED_RESULT_CODE = 255
+)
+
+// LatencyHistogram counts command attempts by their latency.
+// Buckets[0] counts attempts that took up to 1ms, and Buckets[i] the attempts that took
+// more than 2^((i-1)*Shift)ms and up to 2^(i*Shift)ms. The last bucket has no upper bound,
+// nor do the buckets whose upper bound would not fit in a time.Duration.
+type LatencyHistogram struct {
+	Shift   int     `json:"shift"`
+	Buckets []int64 `json:"buckets"`
+}
+
+// UpperBound returns the inclusive upper bound of the bucket,
+// or zero if the bucket has no upper bound.
+func (lh *LatencyHistogram) UpperBound(bucket int) time.Duration {
+	if bucket >= len(lh.Buckets)-1 {
+		return 0
+	}
+
+	bound := time.Millisecond
+	for i := 0; i < bucket; i++ {
+		if !canShiftLatency(bound, lh.Shift) {
+			return 0
+		}
+		bound <<= uint(lh.Shift)
+	}
+	return bound
+}
+
+// canShiftLatency returns true if the latency bound can be shifted without overflow.
+func canShiftLatency(bound time.Duration, shift int) bool {
+	return shift >= 0 && shift < 63 && bound <= math.MaxInt64>>uint(shift)
+}
+
+// CommandStats holds the statistics of a type of command.
+// Each attempt of a command is counted separately.
+type CommandStats struct {
+	// Attempts is the number of attempts sent to the node.
+	Attempts int64 `json:"attempts"`
+
+	// Errors is the number of attempts that failed, including Timeouts.
+	Errors int64 `json:"errors"`
+
+	// Timeouts is the number of attempts that timed out.
+	Timeouts int64 `json:"timeouts"`
+
+	// TotalLatency is the sum of the latencies of all the attempts.
+	TotalLatency time.Duration `json:"total-latency"`
+
+	// ResultCodes counts the attempts by their result code.
+	// Successful attempts are counted under OK.
+	ResultCodes map[ResultCode]int64 `json:"result-codes"`
+
+	// Latency is the latency histogram of the attempts.
+	// It is nil if ClientPolicy.LatencyColumns is zero.
+	Latency *LatencyHistogram `json:"latency,omitempty"`
+}
+
+// AverageLatency returns the average latency of the attempts.
+func (cs *CommandStats) AverageLatency() time.Duration {
+	if cs.Attempts == 0 {
+		return 0
+	}
+	return cs.TotalLatency / time.Duration(cs.Attempts)
+}
+
+// commandTypeStats keeps the statistics of a command type.
+// All fields are updated atomically.
+type commandTypeStats struct {
+	attempts, errors, timeouts, latency int64
+	resultCodes                         [_MAX_TRACKED_RESULT_CODE - _MIN_TRACKED_RESULT_CODE + 1]int64
+	buckets                             []int64
+}
+
+// commandStats keeps the statistics of all command types on a node.
+type commandStats struct {
+	shift int
+	types []commandTypeStats
+}
+
+func newCommandStats(columns, shift int) *commandStats {
+	if columns < 0 {
+		columns = 0
+	}
+
+	res := &commandStats{
+		shift: shift,
+		types: make([]commandTypeStats, len(commandTypes)),
+	}
+	for i := range res.types {
+		res.types[i].buckets = make([]int64, columns)
+	}
+	return res
+}
+
+// bucket returns the index of the histogram bucket for the latency.
+func (cs *commandStats) bucket(latency time.Duration, columns int) int {
+	limit := time.Millisecond
+	last := columns - 1
+	for i := 0; i < last; i++ {
+		if latency <= limit {
+			return i
+		}
+		// the following buckets have no upper bound
+		if !canShiftLatency(limit, cs.shift) {
+			break
+		}
+		limit <<= uint(cs.shift)
+	}
+	return last
+}
+
+// record counts an attempt of a command.
+func (cs *commandStats) record(cmdType CommandType, latency time.Duration, resultCode ResultCode) {
+	if cs == nil {
+		return
+	}
+
+	i, exists := commandTypeIndex[cmdType]
+	if !exists {
+		return
+	}
+	ts := &cs.types[i]
+
+	atomic.AddInt64(&ts.attempts, 1)
+	atomic.AddInt64(&ts.latency, int64(latency))
+	if resultCode != OK {
+		atomic.AddInt64(&ts.errors, 1)
+		if resultCode == TIMEOUT {
+			atomic.AddInt64(&ts.timeouts, 1)
+		}
+	}
+
+	if resultCode >= _MIN_TRACKED_RESULT_CODE && resultCode <= _MAX_TRACKED_RESULT_CODE {
+		atomic.AddInt64(&ts.resultCodes[resultCode-_MIN_TRACKED_RESULT_CODE], 1)
+	}
+
+	if len(ts.buckets) > 0 {
+		atomic.AddInt64(&ts.buckets[cs.bucket(latency, len(ts.buckets))], 1)
+	}
+}
+
+// transfer returns a copy of the statistics, using op to read each counter.
+func (cs *commandStats) transfer(op func(*int64) int64) *commandStats {
+	if cs == nil {
+		return nil
+	}
+
+	res := &commandStats{
+		shift: cs.shift,
+		types: make([]commandTypeStats, len(cs.types)),
+	}
+
+	for i := range cs.types {
+		src, dst := &cs.types[i], &res.types[i]
+		dst.attempts = op(&src.attempts)
+		dst.errors = op(&src.errors)
+		dst.timeouts = op(&src.timeouts)
+		dst.latency = op(&src.latency)
+		for j := range src.resultCodes {
+			dst.resultCodes[j] = op(&src.resultCodes[j])
+		}
+		dst.buckets = make([]int64, len(src.buckets))
+		for j := range src.buckets {
+			dst.buckets[j] = op(&src.buckets[j])
+		}
+	}
+	return res
+}
+
+// getAndReset returns the latest values to be used in aggregation and then resets the values
+func (cs *commandStats) getAndReset() *commandStats {
+	return cs.transfer(func(v *int64) int64 { return atomic.SwapInt64(v, 0) })
+}
+
+func (cs *commandStats) clone() *commandStats {
+	return cs.transfer(atomic.LoadInt64)
+}
+
+// aggregate adds the new statistics to cs.
+// Histograms with a different layout are not aggregated.
+func (cs *commandStats) aggregate(newStats *commandStats) {
+	if cs == nil || newStats == nil {
+		return
+	}
+
+	for i := range newStats.types {
+		src, dst := &newStats.types[i], &cs.types[i]
+		atomic.AddInt64(&dst.attempts, src.attempts)
+		atomic.AddInt64(&dst.errors, src.errors)
+		atomic.AddInt64(&dst.timeouts, src.timeouts)
+		atomic.AddInt64(&dst.latency, src.latency)
+		for j := range src.resultCodes {
+			if src.resultCodes[j] != 0 {
+				atomic.AddInt64(&dst.resultCodes[j], src.resultCodes[j])
+			}
+		}
+		if cs.shift == newStats.shift && len(dst.buckets) == len(src.buckets) {
+			for j := range src.buckets {
+				atomic.AddInt64(&dst.buckets[j], src.buckets[j])
+			}
+		}
+	}
+}
+
+// export converts the statistics to their public form.
+// Command types without any attempts are omitted.
+func (cs *commandStats) export() map[CommandType]*CommandStats {
+	res := map[CommandType]*CommandStats{}
+	if cs == nil {
+		return res
+	}
+
+	for i := range cs.types {
+		ts := &cs.types[i]
+		attempts := atomic.LoadInt64(&ts.attempts)
+		if attempts == 0 {
+			continue
+		}
+
+		stats := &CommandStats{
+			Attempts:     attempts,
+			Errors:       atomic.LoadInt64(&ts.errors),
+			Timeouts:     atomic.LoadInt64(&ts.timeouts),
+			TotalLatency: time.Duration(atomic.LoadInt64(&ts.latency)),
+			ResultCodes:  map[ResultCode]int64{},
+		}
+
+		for j := range ts.resultCodes {
+			if count := atomic.LoadInt64(&ts.resultCodes[j]); count > 0 {
+				stats.ResultCodes[ResultCode(j+_MIN_TRACKED_RESULT_CODE)] = count
+			}
+		}
+
+		if len(ts.buckets) > 0 {
+			stats.Latency = &LatencyHistogram{Shift: cs.shift, Buckets: make([]int64, len(ts.buckets))}
+			for j := range ts.buckets {
+				stats.Latency.Buckets[j] = atomic.LoadInt64(&ts.buckets[j])
+			}
+		}
+
+		res[commandTypes[i]] = stats
+	}
+	return res
+}
+
+// latencyAverage is an exponentially weighted moving average of the latencies
+// of a node's single record commands, used by the LEAST_LATENCY replica policy.
+// Each new sample has a weight of 1/8, like the smoothed round trip time of TCP.
+//
+// The average is halved for every latencyHalfLife without a new sample, so a node
+// which was slow once is eventually tried again, and its average recovers.
+type latencyAverage struct {
+	nanos int64
+
+	// updated is the time of the last sample, in unix nanoseconds
+	updated int64
+}
+
+// latencyHalfLife is the time after which the average of a node without commands is halved.
+const latencyHalfLife = 10 * time.Second
+
+// record adds the latency of a command attempt to the average.
+func (la *latencyAverage) record(latency time.Duration) {
+	la.recordAt(latency, time.Now())
+}
+
+func (la *latencyAverage) recordAt(latency time.Duration, now time.Time) {
+	for {
+		old := atomic.LoadInt64(&la.nanos)
+		decayed := int64(la.decay(old, now))
+		avg := int64(latency)
+		if decayed > 0 {
+			avg = decayed + (avg-decayed)/8
+		}
+		if atomic.CompareAndSwapInt64(&la.nanos, old, avg) {
+			atomic.StoreInt64(&la.updated, now.UnixNano())
+			return
+		}
+	}
+}
+
+// get returns the average, or zero if no attempt has been recorded yet.
+func (la *latencyAverage) get() time.Duration {
+	return la.getAt(time.Now())
+}
+
+func (la *latencyAverage) getAt(now time.Time) time.Duration {
+	return la.decay(atomic.LoadInt64(&la.nanos), now)
+}
+
+// decay halves the average for every latencyHalfLife since the last sample.
+func (la *latencyAverage) decay(avg int64, now time.Time) time.Duration {
+	halvings := time.Duration(now.UnixNano()-atomic.LoadInt64(&la.updated)) / latencyHalfLife
+	if halvings <= 0 {
+		return time.Duration(avg)
+	}
+	if halvings >= 63 {
+		return 0
+	}
+	return time.Duration(avg >> uint(halvings))
+}
+
+// tracksLatency returns true if attempts of the command type are recorded in the
+// latency average. Multi record commands are not, since their latency depends on
+// the number of records.
+func tracksLatency(cmdType CommandType) bool {
+	switch cmdType {
+	case CMD_BATCH, CMD_SCAN, CMD_QUERY, CMD_INFO, CMD_ADMIN:
+		return false
+	}
+	return true
+}
@@ -0,0 +1,106 @@
+// Copyright 2013-2017 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aerospike
+
+import (
+	"math"
+	"time"
+
+	. "github.com/aerospike/aerospike-client-go/types"
+
+	. "github.com/onsi/ginkgo"
+	gm "github.com/onsi/gomega"
+)
+
+var _ = Describe("Command Stats test", func() {
+
+	It("must divide latencies into columns like the benchmark tool", func() {
+		cs := newCommandStats(4, 2)
+		for _, latency := range []time.Duration{
+			500 * time.Microsecond, time.Millisecond, // <= 1ms
+			1500 * time.Microsecond, 4 * time.Millisecond, // <= 4ms
+			4*time.Millisecond + time.Microsecond, 16 * time.Millisecond, // <= 16ms
+			16*time.Millisecond + time.Microsecond, time.Second, // > 16ms
+		} {
+			cs.record(CMD_READ, latency, OK)
+		}
+
+		stats := cs.export()[CMD_READ]
+		gm.Expect(stats.Attempts).To(gm.Equal(int64(8)))
+		gm.Expect(stats.Latency.Buckets).To(gm.Equal([]int64{2, 2, 2, 2}))
+		gm.Expect(stats.Latency.UpperBound(0)).To(gm.Equal(time.Millisecond))
+		gm.Expect(stats.Latency.UpperBound(1)).To(gm.Equal(4 * time.Millisecond))
+		gm.Expect(stats.Latency.UpperBound(2)).To(gm.Equal(16 * time.Millisecond))
+		gm.Expect(stats.Latency.UpperBound(3)).To(gm.Equal(time.Duration(0)))
+	})
+
+	It("must not overflow the bucket bounds", func() {
+		cs := newCommandStats(64, 40)
+		cs.record(CMD_READ, time.Millisecond, OK)
+		cs.record(CMD_READ, time.Duration(math.MaxInt64), OK)
+
+		stats := cs.export()[CMD_READ]
+		gm.Expect(stats.Latency.Buckets[0]).To(gm.Equal(int64(1)))
+		gm.Expect(stats.Latency.Buckets[63]).To(gm.Equal(int64(1)))
+		gm.Expect(stats.Latency.UpperBound(1)).To(gm.Equal(time.Millisecond << 40))
+		gm.Expect(stats.Latency.UpperBound(2)).To(gm.Equal(time.Duration(0)))
+	})
+
+	It("must validate the latency settings of the client policy", func() {
+		gm.Expect(NewClientPolicy().validate()).ToNot(gm.HaveOccurred())
+		gm.Expect((&ClientPolicy{}).validate()).ToNot(gm.HaveOccurred())
+		gm.Expect((&ClientPolicy{LatencyColumns: -1, LatencyShift: 1}).validate()).To(gm.HaveOccurred())
+		gm.Expect((&ClientPolicy{LatencyColumns: 65, LatencyShift: 1}).validate()).To(gm.HaveOccurred())
+		gm.Expect((&ClientPolicy{LatencyColumns: 7, LatencyShift: -1}).validate()).To(gm.HaveOccurred())
+	})
+
+	It("must count attempts by result code", func() {
+		cs := newCommandStats(0, 1)
+		cs.record(CMD_WRITE, time.Millisecond, OK)
+		cs.record(CMD_WRITE, time.Millisecond, TIMEOUT)
+		cs.record(CMD_WRITE, 4*time.Millisecond, KEY_EXISTS_ERROR)
+		cs.record(CMD_UDF, time.Millisecond, AEROSPIKE_ERR_UDF_NOT_FOUND)
+
+		res := cs.export()
+		gm.Expect(len(res)).To(gm.Equal(2))
+
+		stats := res[CMD_WRITE]
+		gm.Expect(stats.Attempts).To(gm.Equal(int64(3)))
+		gm.Expect(stats.Errors).To(gm.Equal(int64(2)))
+		gm.Expect(stats.Timeouts).To(gm.Equal(int64(1)))
+		gm.Expect(stats.AverageLatency()).To(gm.Equal(2 * time.Millisecond))
+		gm.Expect(stats.ResultCodes).To(gm.Equal(map[ResultCode]int64{OK: 1, TIMEOUT: 1, KEY_EXISTS_ERROR: 1}))
+		gm.Expect(stats.Latency).To(gm.BeNil())
+
+		gm.Expect(res[CMD_UDF].Errors).To(gm.Equal(int64(1)))
+		gm.Expect(res[CMD_UDF].ResultCodes).To(gm.BeEmpty())
+	})
+
+	It("must aggregate node statistics", func() {
+		node := nodeStats{commands: newCommandStats(3, 1)}
+		node.commands.record(CMD_BATCH, time.Millisecond, OK)
+
+		total := nodeStats{}
+		total.aggregate(node.getAndReset())
+		gm.Expect(node.commands.export()).To(gm.BeEmpty())
+
+		node.commands.record(CMD_BATCH, time.Second, OK)
+		total.aggregate(node.getAndReset())
+
+		stats := total.export().Commands[CMD_BATCH]
+		gm.Expect(stats.Attempts).To(gm.Equal(int64(2)))
+		gm.Expect(stats.Latency.Buckets).To(gm.Equal([]int64{1, 0, 1}))
+	})
+})
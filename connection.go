@@ -15,6 +15,7 @@
 package aerospike
 
 import (
+	"context"
 	"crypto/tls"
 	"io"
 	"net"
@@ -45,6 +46,9 @@ type Connection struct {
 	// to avoid having a buffer pool and contention
 	dataBuffer []byte
 
+	// bytes written to and read from the socket, reported to CommandListener
+	bytesSent, bytesReceived int64
+
 	lck sync.Mutex
 }
 
@@ -77,6 +81,12 @@ func shouldClose(err error) bool {
 // If the connection is not established in the specified timeout,
 // an error will be returned
 func NewConnection(address string, timeout time.Duration) (*Connection, error) {
+	return newConnection(context.Background(), address, timeout)
+}
+
+// newConnection creates a connection on the network. Dialing is aborted
+// if the context is done before the connection is established.
+func newConnection(ctx context.Context, address string, timeout time.Duration) (*Connection, error) {
 	newConn := &Connection{dataBuffer: make([]byte, 1024)}
 	runtime.SetFinalizer(newConn, connectionFinalizer)
 
@@ -85,7 +95,8 @@ func NewConnection(address string, timeout time.Duration) (*Connection, error) {
 		timeout = 5 * time.Second
 	}
 
-	conn, err := net.DialTimeout("tcp", address, timeout)
+	dialer := net.Dialer{Timeout: timeout}
+	conn, err := dialer.DialContext(ctx, "tcp", address)
 	if err != nil {
 		Logger.Error("Connection to address `" + address + "` failed to establish with error: " + err.Error())
 		return nil, errToTimeoutErr(err)
@@ -106,8 +117,14 @@ func NewConnection(address string, timeout time.Duration) (*Connection, error) {
 // If the connection is not established in the specified timeout,
 // an error will be returned
 func NewSecureConnection(policy *ClientPolicy, host *Host) (*Connection, error) {
+	return newSecureConnection(context.Background(), policy, host)
+}
+
+// newSecureConnection creates a TLS connection on the network.
+// Dialing is aborted if the context is done before the connection is established.
+func newSecureConnection(ctx context.Context, policy *ClientPolicy, host *Host) (*Connection, error) {
 	address := net.JoinHostPort(host.Name, strconv.Itoa(host.Port))
-	conn, err := NewConnection(address, policy.Timeout)
+	conn, err := newConnection(ctx, address, policy.Timeout)
 	if err != nil {
 		return nil, err
 	}
@@ -150,6 +167,7 @@ func (ctn *Connection) Write(buf []byte) (total int, err error) {
 		}
 		total += r
 	}
+	ctn.bytesSent += int64(total)
 
 	if err == nil {
 		return total, nil
@@ -165,6 +183,7 @@ func (ctn *Connection) ReadN(buf io.Writer, length int64) (total int64, err erro
 	// if all bytes are not read, retry until successful
 	// Don't worry about the loop; we've already set the timeout elsewhere
 	total, err = io.CopyN(buf, ctn.conn, length)
+	ctn.bytesReceived += total
 
 	if err == nil && total == length {
 		return total, nil
@@ -192,6 +211,7 @@ func (ctn *Connection) Read(buf []byte, length int) (total int, err error) {
 			break
 		}
 	}
+	ctn.bytesReceived += int64(total)
 
 	if err == nil && total == length {
 		return total, nil
@@ -233,6 +253,39 @@ func (ctn *Connection) SetTimeout(timeout time.Duration) error {
 	return nil
 }
 
+// watchContext interrupts any blocking read or write on the connection
+// as soon as the context is done. The returned function stops watching and
+// must be called before the connection is reused; it returns after the watcher
+// has exited, so the deadline cannot be altered afterwards.
+func (ctn *Connection) watchContext(ctx context.Context) (stop func()) {
+	done := ctx.Done()
+	if done == nil {
+		// the context can never be cancelled
+		return func() {}
+	}
+
+	stopChan := make(chan struct{})
+	exited := make(chan struct{})
+	go func() {
+		defer close(exited)
+		select {
+		case <-done:
+			ctn.lck.Lock()
+			if ctn.conn != nil {
+				// a deadline in the past unblocks all pending I/O
+				ctn.conn.SetDeadline(time.Now())
+			}
+			ctn.lck.Unlock()
+		case <-stopChan:
+		}
+	}()
+
+	return func() {
+		close(stopChan)
+		<-exited
+	}
+}
+
 // Close closes the connection
 func (ctn *Connection) Close() {
 	ctn.lck.Lock()
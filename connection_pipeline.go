@@ -0,0 +1,228 @@
+// Copyright 2013-2017 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aerospike
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	. "github.com/aerospike/aerospike-client-go/types"
+)
+
+// pipelineBufferPool holds the buffers of pipelined commands, since they
+// do not own a connection and its buffer.
+var pipelineBufferPool = NewBufferPool(512, 1024, 512*1024)
+
+// connectionPipeline writes multiple requests back-to-back on a single connection.
+// The server answers the requests of a connection in order, so responses are
+// matched to requests in the order they were written.
+type connectionPipeline struct {
+	conn    *Connection
+	netConn net.Conn
+
+	// slots caps the number of requests in flight
+	slots chan struct{}
+
+	// mutex serializes writes, and the order in which requests are queued
+	mutex sync.Mutex
+
+	// last is closed when the response of the last queued request has been read
+	last chan struct{}
+
+	// lastUsed is the time the pipeline was used last, in nanoseconds
+	lastUsed    int64
+	idleTimeout time.Duration
+
+	broken    int32
+	closeOnce sync.Once
+}
+
+func newConnectionPipeline(conn *Connection, depth int, idleTimeout time.Duration) *connectionPipeline {
+	// no request is pending initially
+	last := make(chan struct{})
+	close(last)
+
+	// deadlines are set per request
+	conn.conn.SetDeadline(time.Time{})
+
+	return &connectionPipeline{
+		conn:        conn,
+		netConn:     conn.conn,
+		slots:       make(chan struct{}, depth),
+		last:        last,
+		lastUsed:    time.Now().UnixNano(),
+		idleTimeout: idleTimeout,
+	}
+}
+
+// exchange sends the request and waits for its response.
+// The returned slice holds the whole response, including its protocol header.
+// sent is true if the request was written before the exchange failed.
+// If the exchange fails, the pipeline is closed and all pending requests fail;
+// those already written fail with a TIMEOUT error, since they may have been applied.
+func (p *connectionPipeline) exchange(ctx context.Context, request []byte, timeout time.Duration) (response []byte, sent bool, err error) {
+	var deadline time.Time
+	if timeout > 0 {
+		deadline = time.Now().Add(timeout)
+	}
+
+	if err = p.acquire(ctx, deadline); err != nil {
+		return nil, false, err
+	}
+	defer func() { <-p.slots }()
+
+	p.mutex.Lock()
+	if p.isBroken() {
+		p.mutex.Unlock()
+		return nil, false, NewAerospikeError(NO_AVAILABLE_CONNECTIONS_TO_NODE, "pipelined connection is closed")
+	}
+
+	p.netConn.SetWriteDeadline(deadline)
+	for total := 0; total < len(request); {
+		n, err := p.netConn.Write(request[total:])
+		if err != nil {
+			p.mutex.Unlock()
+			p.fail()
+			return nil, false, errToTimeoutErr(err)
+		}
+		total += n
+	}
+
+	// wait for the responses of the requests written before
+	turn := p.last
+	done := make(chan struct{})
+	p.last = done
+	p.mutex.Unlock()
+
+	// let the next request read its response, even if this one fails
+	defer close(done)
+	<-turn
+
+	// the request was sent, so the server may have applied it
+	if p.isBroken() {
+		return nil, true, NewAerospikeError(TIMEOUT, "pipelined connection failed before the response was read")
+	}
+
+	if response, err = p.readResponse(deadline); err != nil {
+		p.fail()
+		return nil, true, errToTimeoutErr(err)
+	}
+
+	atomic.StoreInt64(&p.lastUsed, time.Now().UnixNano())
+	return response, true, nil
+}
+
+// acquire waits for a free slot until the context is done or the deadline passes.
+func (p *connectionPipeline) acquire(ctx context.Context, deadline time.Time) error {
+	select {
+	case p.slots <- struct{}{}:
+		return nil
+	default:
+	}
+
+	var timeout <-chan time.Time
+	if !deadline.IsZero() {
+		timer := time.NewTimer(deadline.Sub(time.Now()))
+		defer timer.Stop()
+		timeout = timer.C
+	}
+
+	select {
+	case p.slots <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return contextError(ctx)
+	case <-timeout:
+		return NewAerospikeError(TIMEOUT, "command execution timed out on client: no free slot in the pipelined connection. See `ClientPolicy.PipelineDepth`")
+	}
+}
+
+// readResponse reads the next message from the connection.
+func (p *connectionPipeline) readResponse(deadline time.Time) ([]byte, error) {
+	p.netConn.SetReadDeadline(deadline)
+
+	var header [8]byte
+	if _, err := io.ReadFull(p.netConn, header[:]); err != nil {
+		return nil, err
+	}
+
+	size := int64(binary.BigEndian.Uint64(header[:]) & 0xFFFFFFFFFFFF)
+	if size > int64(MaxBufferSize) {
+		return nil, NewAerospikeError(PARSE_ERROR, fmt.Sprintf("Invalid size for buffer: %d", size))
+	}
+
+	response := make([]byte, 8+size)
+	copy(response, header[:])
+	if _, err := io.ReadFull(p.netConn, response[8:]); err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+// isBroken returns true if the connection has failed or has been closed.
+func (p *connectionPipeline) isBroken() bool {
+	return atomic.LoadInt32(&p.broken) != 0
+}
+
+// isIdle returns true if no request is in flight, and the pipeline
+// has not been used for longer than the idle timeout.
+func (p *connectionPipeline) isIdle() bool {
+	if p.idleTimeout <= 0 || len(p.slots) > 0 {
+		return false
+	}
+	return time.Since(time.Unix(0, atomic.LoadInt64(&p.lastUsed))) >= p.idleTimeout
+}
+
+// fail closes the pipeline after an I/O error.
+// The stream can not be resynchronized, so all pending requests will fail.
+func (p *connectionPipeline) fail() {
+	if p.conn.node != nil {
+		atomic.AddInt64(&p.conn.node.stats.ConnectionsFailed, 1)
+	}
+	p.close()
+}
+
+// close closes the connection, which interrupts any pending I/O.
+func (p *connectionPipeline) close() {
+	p.closeOnce.Do(func() {
+		atomic.StoreInt32(&p.broken, 1)
+		p.conn.Close()
+	})
+}
+
+// responseConn serves a response already read by a pipeline
+// to the parsers of the commands, which read from a *Connection.
+type responseConn struct {
+	*bytes.Reader
+}
+
+func newResponseConnection(response []byte) *Connection {
+	return &Connection{conn: responseConn{bytes.NewReader(response)}}
+}
+
+func (rc responseConn) Write(b []byte) (int, error)        { return 0, io.ErrClosedPipe }
+func (rc responseConn) Close() error                       { return nil }
+func (rc responseConn) LocalAddr() net.Addr                { return nil }
+func (rc responseConn) RemoteAddr() net.Addr               { return nil }
+func (rc responseConn) SetDeadline(t time.Time) error      { return nil }
+func (rc responseConn) SetReadDeadline(t time.Time) error  { return nil }
+func (rc responseConn) SetWriteDeadline(t time.Time) error { return nil }
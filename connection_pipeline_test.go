@@ -0,0 +1,135 @@
+// Copyright 2013-2017 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aerospike
+
+import (
+	"context"
+	"encoding/binary"
+	"io"
+	"io/ioutil"
+	"net"
+	"time"
+
+	. "github.com/aerospike/aerospike-client-go/types"
+
+	. "github.com/onsi/ginkgo"
+	gm "github.com/onsi/gomega"
+)
+
+// pipelineMessage frames the payload as a protocol message.
+func pipelineMessage(payload string) []byte {
+	msg := make([]byte, 8+len(payload))
+	binary.BigEndian.PutUint64(msg, uint64(int64(len(payload))|(_CL_MSG_VERSION<<56)|(_AS_MSG_TYPE<<48)))
+	copy(msg[8:], payload)
+	return msg
+}
+
+// echoMessages answers each message with itself, until the connection is closed.
+func echoMessages(conn net.Conn) {
+	defer conn.Close()
+	for {
+		header := make([]byte, 8)
+		if _, err := io.ReadFull(conn, header); err != nil {
+			return
+		}
+		body := make([]byte, binary.BigEndian.Uint64(header)&0xFFFFFFFFFFFF)
+		if _, err := io.ReadFull(conn, body); err != nil {
+			return
+		}
+		if _, err := conn.Write(append(header, body...)); err != nil {
+			return
+		}
+	}
+}
+
+var _ = Describe("Connection pipeline test", func() {
+
+	var client, server net.Conn
+	var pipeline *connectionPipeline
+
+	BeforeEach(func() {
+		client, server = net.Pipe()
+		pipeline = newConnectionPipeline(&Connection{conn: client}, 4, 0)
+	})
+
+	AfterEach(func() {
+		pipeline.close()
+		server.Close()
+	})
+
+	It("must match the responses to the requests in order", func() {
+		go echoMessages(server)
+
+		const count = 100
+		errs := make(chan error, count)
+		for i := 0; i < count; i++ {
+			go func(i int) {
+				request := pipelineMessage(string(rune('a'+i%26)) + "-request")
+				response, _, err := pipeline.exchange(context.Background(), request, time.Second)
+				if err == nil && string(response) != string(request) {
+					err = NewAerospikeError(PARSE_ERROR, "mismatched response")
+				}
+				errs <- err
+			}(i)
+		}
+
+		for i := 0; i < count; i++ {
+			gm.Expect(<-errs).ToNot(gm.HaveOccurred())
+		}
+		gm.Expect(pipeline.isBroken()).To(gm.BeFalse())
+		gm.Expect(len(pipeline.slots)).To(gm.Equal(0))
+	})
+
+	It("must fail all pending requests when the connection breaks", func() {
+		// read the requests, but never answer them
+		go io.Copy(ioutil.Discard, server)
+
+		errs := make(chan error, 3)
+		for i := 0; i < 3; i++ {
+			go func() {
+				_, sent, err := pipeline.exchange(context.Background(), pipelineMessage("request"), 50*time.Millisecond)
+				if !sent {
+					err = NewAerospikeError(PARSE_ERROR, "request not sent")
+				}
+				errs <- err
+			}()
+		}
+
+		// the requests were written, so they fail as in doubt, not as never sent
+		for i := 0; i < 3; i++ {
+			var err error
+			gm.Eventually(errs).Should(gm.Receive(&err))
+			gm.Expect(resultCodeOf(err)).To(gm.Equal(TIMEOUT))
+		}
+		gm.Expect(pipeline.isBroken()).To(gm.BeTrue())
+
+		_, _, err := pipeline.exchange(context.Background(), pipelineMessage("request"), time.Second)
+		gm.Expect(err).To(gm.HaveOccurred())
+	})
+
+	It("must parse responses from a response connection", func() {
+		response := pipelineMessage("response")
+		conn := newResponseConnection(response)
+
+		buf := make([]byte, len(response))
+		_, err := conn.Read(buf, len(response))
+		gm.Expect(err).ToNot(gm.HaveOccurred())
+		gm.Expect(buf).To(gm.Equal(response))
+		gm.Expect(conn.bytesReceived).To(gm.Equal(int64(len(response))))
+
+		_, err = conn.Read(buf, 1)
+		gm.Expect(err).To(gm.Equal(io.EOF))
+	})
+})
@@ -50,6 +50,10 @@ func (cmd *deleteCommand) getNode(ifc command) (*Node, error) {
 	return cmd.cluster.getMasterNode(&cmd.partition)
 }
 
+func (cmd *deleteCommand) commandType() CommandType {
+	return CMD_DELETE
+}
+
 func (cmd *deleteCommand) parseResult(ifc command, conn *Connection) error {
 	// Read header.
 	if _, err := conn.Read(cmd.dataBuffer, int(_MSG_TOTAL_HEADER_SIZE)); err != nil {
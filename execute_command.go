@@ -49,6 +49,10 @@ func (cmd *executeCommand) getNode(ifc command) (*Node, error) {
 	return cmd.cluster.getMasterNode(&cmd.partition)
 }
 
+func (cmd *executeCommand) commandType() CommandType {
+	return CMD_UDF
+}
+
 func (cmd *executeCommand) Execute() error {
 	return cmd.execute(cmd)
 }
@@ -50,6 +50,15 @@ func (cmd *existsCommand) getNode(ifc command) (*Node, error) {
 	return cmd.cluster.getReadNode(&cmd.partition, cmd.policy.ReplicaPolicy, &cmd.replicaSequence)
 }
 
+func (cmd *existsCommand) commandType() CommandType {
+	return CMD_EXISTS
+}
+
+// switchReplica directs the next attempt to the next replica of the partition.
+func (cmd *existsCommand) switchReplica() {
+	cmd.replicaSequence++
+}
+
 func (cmd *existsCommand) parseResult(ifc command, conn *Connection) error {
 	// Read header.
 	if _, err := conn.Read(cmd.dataBuffer, int(_MSG_TOTAL_HEADER_SIZE)); err != nil {
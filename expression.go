@@ -0,0 +1,426 @@
+// Copyright 2013-2017 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aerospike
+
+import (
+	"bytes"
+	"fmt"
+
+	. "github.com/aerospike/aerospike-client-go/types"
+)
+
+type exprType int
+
+const (
+	exprBool exprType = iota
+	exprInteger
+	exprString
+	exprGeoJSON
+	exprList
+	exprMap
+)
+
+func (t exprType) String() string {
+	switch t {
+	case exprBool:
+		return "boolean"
+	case exprInteger:
+		return "integer"
+	case exprString:
+		return "string"
+	case exprGeoJSON:
+		return "GeoJSON"
+	case exprList:
+		return "list"
+	case exprMap:
+		return "map"
+	}
+	return "unknown"
+}
+
+type exprKind int
+
+const (
+	exprLeaf exprKind = iota
+	exprCompare
+	exprLogical
+	exprNot
+	exprIterate
+)
+
+// Expression is a node of a predicate expression tree built with the Expr builder.
+// Expressions are type checked as they are built, and compile down to predicate
+// expressions in postfix notation.
+// Expressions are immutable and can be shared between statements and policies.
+type Expression struct {
+	kind     exprKind
+	typ      exprType
+	predExp  PredExp
+	children []*Expression
+
+	// variable name bound by an iterator, or referenced by a variable
+	varName string
+
+	// the first error found while building the expression tree
+	err error
+}
+
+func newExpr(kind exprKind, typ exprType, predExp PredExp, children ...*Expression) *Expression {
+	e := &Expression{kind: kind, typ: typ, predExp: predExp, children: children}
+	for _, c := range children {
+		if c == nil {
+			e.err = NewAerospikeError(PARAMETER_ERROR, "Expression operand cannot be nil.")
+			break
+		}
+		if c.err != nil {
+			e.err = c.err
+			break
+		}
+	}
+	return e
+}
+
+func newExprError(format string, args ...interface{}) *Expression {
+	return &Expression{err: NewAerospikeError(PARAMETER_ERROR, fmt.Sprintf(format, args...))}
+}
+
+// Err returns the first error found while building the expression, if any.
+func (e *Expression) Err() error {
+	return e.err
+}
+
+// Compile validates the expression tree and returns its predicate expressions
+// in postfix notation. The expression must evaluate to a boolean, and every
+// variable must be bound by an enclosing iterator.
+func (e *Expression) Compile() ([]PredExp, error) {
+	if e == nil {
+		return nil, NewAerospikeError(PARAMETER_ERROR, "Expression cannot be nil.")
+	}
+
+	if e.err != nil {
+		return nil, e.err
+	}
+
+	if e.typ != exprBool {
+		return nil, NewAerospikeError(PARAMETER_ERROR, fmt.Sprintf("Expression `%s` must evaluate to a boolean, but evaluates to %s.", e, e.typ))
+	}
+
+	res := make([]PredExp, 0, 8)
+	return e.compile(res, nil)
+}
+
+func (e *Expression) compile(res []PredExp, scope []string) ([]PredExp, error) {
+	switch e.kind {
+	case exprLeaf:
+		if e.varName != "" && !inScope(scope, e.varName) {
+			return nil, NewAerospikeError(PARAMETER_ERROR, fmt.Sprintf("Variable `%s` is not bound by an enclosing iterator.", e.varName))
+		}
+	case exprIterate:
+		// the predicate is evaluated for each element bound to the variable
+		scope = append(scope[:len(scope):len(scope)], e.varName)
+	}
+
+	var err error
+	for _, c := range e.children {
+		if res, err = c.compile(res, scope); err != nil {
+			return nil, err
+		}
+	}
+	return append(res, e.predExp), nil
+}
+
+func inScope(scope []string, name string) bool {
+	for _, s := range scope {
+		if s == name {
+			return true
+		}
+	}
+	return false
+}
+
+// String implements the Stringer interface, and returns the expression in infix notation.
+func (e *Expression) String() string {
+	if e.err != nil {
+		return "<invalid expression: " + e.err.Error() + ">"
+	}
+
+	switch e.kind {
+	case exprCompare:
+		return "(" + e.children[0].String() + " " + e.predExp.String() + " " + e.children[1].String() + ")"
+	case exprLogical:
+		var buf bytes.Buffer
+		buf.WriteString("(")
+		for i, c := range e.children {
+			if i > 0 {
+				buf.WriteString(" " + e.predExp.String() + " ")
+			}
+			buf.WriteString(c.String())
+		}
+		buf.WriteString(")")
+		return buf.String()
+	case exprNot:
+		return "NOT " + e.children[0].String()
+	case exprIterate:
+		return e.predExp.String() + " " + e.children[1].String() + " " + e.children[0].String()
+	}
+	return e.predExp.String()
+}
+
+// Expr is the builder for predicate expression trees.
+// Expressions are written in their natural order, and are compiled to
+// the postfix predicate expressions the server expects.
+// Operand types are checked while building; an invalid expression
+// reports its error when it is compiled or attached to a statement or policy.
+//
+// Example: (c >= 11 and c <= 20) or (d > 3 and d < 5)
+//
+//  exp := Expr.Or(
+//    Expr.And(
+//      Expr.GE(Expr.IntBin("c"), Expr.Int(11)),
+//      Expr.LE(Expr.IntBin("c"), Expr.Int(20)),
+//    ),
+//    Expr.And(
+//      Expr.GT(Expr.IntBin("d"), Expr.Int(3)),
+//      Expr.LT(Expr.IntBin("d"), Expr.Int(5)),
+//    ),
+//  )
+//  err := stmt.SetExpression(exp)
+var Expr = struct {
+	// Int creates an integer value.
+	Int func(val int64) *Expression
+	// String creates a string value.
+	String func(val string) *Expression
+	// GeoJSON creates a GeoJSON value.
+	GeoJSON func(val string) *Expression
+
+	// IntBin creates an integer bin reference.
+	IntBin func(name string) *Expression
+	// StringBin creates a string bin reference.
+	StringBin func(name string) *Expression
+	// GeoJSONBin creates a GeoJSON bin reference.
+	GeoJSONBin func(name string) *Expression
+	// ListBin creates a list bin reference. List bins can only be iterated.
+	ListBin func(name string) *Expression
+	// MapBin creates a map bin reference. Map bins can only be iterated.
+	MapBin func(name string) *Expression
+
+	// IntVar references the integer variable bound by an enclosing iterator.
+	IntVar func(name string) *Expression
+	// StringVar references the string variable bound by an enclosing iterator.
+	StringVar func(name string) *Expression
+	// GeoJSONVar references the GeoJSON variable bound by an enclosing iterator.
+	GeoJSONVar func(name string) *Expression
+
+	// DeviceSize returns the record size on disk.
+	// If server storage-engine is memory, it returns zero.
+	DeviceSize func() *Expression
+	// LastUpdate returns the record last update time in nanoseconds since the Unix epoch.
+	LastUpdate func() *Expression
+	// VoidTime returns the record expiration time in nanoseconds since the Unix epoch.
+	// Zero means the record never expires.
+	VoidTime func() *Expression
+	// DigestModulo returns the record digest modulo mod. Useful to sample records.
+	DigestModulo func(mod int32) *Expression
+
+	// EQ is true if both integer or both string operands are equal.
+	EQ func(left, right *Expression) *Expression
+	// NE is true if both integer or both string operands are not equal.
+	NE func(left, right *Expression) *Expression
+	// GT is true if the left integer operand is greater than the right one.
+	GT func(left, right *Expression) *Expression
+	// GE is true if the left integer operand is greater than or equal to the right one.
+	GE func(left, right *Expression) *Expression
+	// LT is true if the left integer operand is less than the right one.
+	LT func(left, right *Expression) *Expression
+	// LE is true if the left integer operand is less than or equal to the right one.
+	LE func(left, right *Expression) *Expression
+	// Regex is true if the string operand matches the POSIX regular expression.
+	// Flags are the POSIX regcomp cflags; pass 0 for default.
+	Regex func(left *Expression, pattern string, cflags uint32) *Expression
+	// GeoJSONWithin is true if the left GeoJSON operand is within the right region.
+	GeoJSONWithin func(left, right *Expression) *Expression
+	// GeoJSONContains is true if the left GeoJSON region contains the right point.
+	GeoJSONContains func(left, right *Expression) *Expression
+
+	// And is true if all boolean operands are true.
+	And func(exps ...*Expression) *Expression
+	// Or is true if any boolean operand is true.
+	Or func(exps ...*Expression) *Expression
+	// Not negates the boolean operand.
+	Not func(exp *Expression) *Expression
+
+	// ListIterateOr is true if the predicate is true for any list element bound to varName.
+	ListIterateOr func(bin *Expression, varName string, predicate *Expression) *Expression
+	// ListIterateAnd is true if the predicate is true for all list elements bound to varName.
+	ListIterateAnd func(bin *Expression, varName string, predicate *Expression) *Expression
+	// MapKeyIterateOr is true if the predicate is true for any map key bound to varName.
+	MapKeyIterateOr func(bin *Expression, varName string, predicate *Expression) *Expression
+	// MapKeyIterateAnd is true if the predicate is true for all map keys bound to varName.
+	MapKeyIterateAnd func(bin *Expression, varName string, predicate *Expression) *Expression
+	// MapValIterateOr is true if the predicate is true for any map value bound to varName.
+	MapValIterateOr func(bin *Expression, varName string, predicate *Expression) *Expression
+	// MapValIterateAnd is true if the predicate is true for all map values bound to varName.
+	MapValIterateAnd func(bin *Expression, varName string, predicate *Expression) *Expression
+}{
+	Int:     func(val int64) *Expression { return newExpr(exprLeaf, exprInteger, NewPredExpIntegerValue(val)) },
+	String:  func(val string) *Expression { return newExpr(exprLeaf, exprString, NewPredExpStringValue(val)) },
+	GeoJSON: func(val string) *Expression { return newExpr(exprLeaf, exprGeoJSON, NewPredExpGeoJSONValue(val)) },
+
+	IntBin:     func(name string) *Expression { return newExpr(exprLeaf, exprInteger, NewPredExpIntegerBin(name)) },
+	StringBin:  func(name string) *Expression { return newExpr(exprLeaf, exprString, NewPredExpStringBin(name)) },
+	GeoJSONBin: func(name string) *Expression { return newExpr(exprLeaf, exprGeoJSON, NewPredExpGeoJSONBin(name)) },
+	ListBin:    func(name string) *Expression { return newExpr(exprLeaf, exprList, NewPredExpListBin(name)) },
+	MapBin:     func(name string) *Expression { return newExpr(exprLeaf, exprMap, NewPredExpMapBin(name)) },
+
+	IntVar:     func(name string) *Expression { return newExprVar(exprInteger, name, NewPredExpIntegerVar(name)) },
+	StringVar:  func(name string) *Expression { return newExprVar(exprString, name, NewPredExpStringVar(name)) },
+	GeoJSONVar: func(name string) *Expression { return newExprVar(exprGeoJSON, name, NewPredExpGeoJSONVar(name)) },
+
+	DeviceSize:   func() *Expression { return newExpr(exprLeaf, exprInteger, NewPredExpRecDeviceSize()) },
+	LastUpdate:   func() *Expression { return newExpr(exprLeaf, exprInteger, NewPredExpRecLastUpdate()) },
+	VoidTime:     func() *Expression { return newExpr(exprLeaf, exprInteger, NewPredExpRecVoidTime()) },
+	DigestModulo: func(mod int32) *Expression { return newExpr(exprLeaf, exprInteger, NewPredExpRecDigestModulo(mod)) },
+
+	EQ: func(left, right *Expression) *Expression {
+		return newExprEquality(left, right, NewPredExpIntegerEqual(), NewPredExpStringEqual())
+	},
+	NE: func(left, right *Expression) *Expression {
+		return newExprEquality(left, right, NewPredExpIntegerUnequal(), NewPredExpStringUnequal())
+	},
+	GT: func(left, right *Expression) *Expression {
+		return newExprCompare(exprInteger, left, right, NewPredExpIntegerGreater())
+	},
+	GE: func(left, right *Expression) *Expression {
+		return newExprCompare(exprInteger, left, right, NewPredExpIntegerGreaterEq())
+	},
+	LT: func(left, right *Expression) *Expression {
+		return newExprCompare(exprInteger, left, right, NewPredExpIntegerLess())
+	},
+	LE: func(left, right *Expression) *Expression {
+		return newExprCompare(exprInteger, left, right, NewPredExpIntegerLessEq())
+	},
+	Regex: func(left *Expression, pattern string, cflags uint32) *Expression {
+		return newExprCompare(exprString, left, newExpr(exprLeaf, exprString, NewPredExpStringValue(pattern)), NewPredExpStringRegex(cflags))
+	},
+	GeoJSONWithin: func(left, right *Expression) *Expression {
+		return newExprCompare(exprGeoJSON, left, right, NewPredExpGeoJSONWithin())
+	},
+	GeoJSONContains: func(left, right *Expression) *Expression {
+		return newExprCompare(exprGeoJSON, left, right, NewPredExpGeoJSONContains())
+	},
+
+	And: func(exps ...*Expression) *Expression { return newExprLogical(exps, NewPredExpAnd(uint16(len(exps)))) },
+	Or:  func(exps ...*Expression) *Expression { return newExprLogical(exps, NewPredExpOr(uint16(len(exps)))) },
+	Not: func(exp *Expression) *Expression {
+		e := newExpr(exprNot, exprBool, NewPredExpNot(), exp)
+		if e.err == nil && exp.typ != exprBool {
+			return newExprError("Operand `%s` of NOT must be a boolean, not %s.", exp, exp.typ)
+		}
+		return e
+	},
+
+	ListIterateOr: func(bin *Expression, varName string, predicate *Expression) *Expression {
+		return newExprIterate(exprList, bin, varName, predicate, NewPredExpListIterateOr(varName))
+	},
+	ListIterateAnd: func(bin *Expression, varName string, predicate *Expression) *Expression {
+		return newExprIterate(exprList, bin, varName, predicate, NewPredExpListIterateAnd(varName))
+	},
+	MapKeyIterateOr: func(bin *Expression, varName string, predicate *Expression) *Expression {
+		return newExprIterate(exprMap, bin, varName, predicate, NewPredExpMapKeyIterateOr(varName))
+	},
+	MapKeyIterateAnd: func(bin *Expression, varName string, predicate *Expression) *Expression {
+		return newExprIterate(exprMap, bin, varName, predicate, NewPredExpMapKeyIterateAnd(varName))
+	},
+	MapValIterateOr: func(bin *Expression, varName string, predicate *Expression) *Expression {
+		return newExprIterate(exprMap, bin, varName, predicate, NewPredExpMapValIterateOr(varName))
+	},
+	MapValIterateAnd: func(bin *Expression, varName string, predicate *Expression) *Expression {
+		return newExprIterate(exprMap, bin, varName, predicate, NewPredExpMapValIterateAnd(varName))
+	},
+}
+
+func newExprVar(typ exprType, name string, predExp PredExp) *Expression {
+	if name == "" {
+		return newExprError("Variable name cannot be empty.")
+	}
+	e := newExpr(exprLeaf, typ, predExp)
+	e.varName = name
+	return e
+}
+
+// newExprEquality picks the integer or string comparison depending on the operand types.
+func newExprEquality(left, right *Expression, intPredExp, stringPredExp PredExp) *Expression {
+	if left != nil && left.typ == exprString {
+		return newExprCompare(exprString, left, right, stringPredExp)
+	}
+	return newExprCompare(exprInteger, left, right, intPredExp)
+}
+
+func newExprCompare(typ exprType, left, right *Expression, predExp PredExp) *Expression {
+	e := newExpr(exprCompare, exprBool, predExp, left, right)
+	if e.err != nil {
+		return e
+	}
+
+	if left.typ != typ || right.typ != typ {
+		return newExprError("Operands of `%s` must both be %s, not %s and %s.", predExp, typ, left.typ, right.typ)
+	}
+	return e
+}
+
+func newExprLogical(exps []*Expression, predExp PredExp) *Expression {
+	if len(exps) == 0 {
+		return newExprError("%s requires at least one operand.", predExp)
+	}
+
+	if len(exps) == 1 {
+		return exps[0]
+	}
+
+	e := newExpr(exprLogical, exprBool, predExp, exps...)
+	if e.err != nil {
+		return e
+	}
+
+	for _, exp := range exps {
+		if exp.typ != exprBool {
+			return newExprError("Operand `%s` of %s must be a boolean, not %s.", exp, predExp, exp.typ)
+		}
+	}
+	return e
+}
+
+func newExprIterate(typ exprType, bin *Expression, varName string, predicate *Expression, predExp PredExp) *Expression {
+	if varName == "" {
+		return newExprError("Iterator variable name cannot be empty.")
+	}
+
+	// the predicate is packed before the bin and the iterator
+	e := newExpr(exprIterate, exprBool, predExp, predicate, bin)
+	if e.err != nil {
+		return e
+	}
+
+	if bin.kind != exprLeaf || bin.typ != typ || bin.varName != "" {
+		return newExprError("`%s` must iterate a %s bin, not `%s`.", predExp, typ, bin)
+	}
+
+	if predicate.typ != exprBool {
+		return newExprError("Predicate `%s` of `%s` must be a boolean, not %s.", predicate, predExp, predicate.typ)
+	}
+
+	e.varName = varName
+	return e
+}
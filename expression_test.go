@@ -0,0 +1,164 @@
+// Copyright 2013-2017 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aerospike
+
+import (
+	. "github.com/aerospike/aerospike-client-go/types"
+
+	. "github.com/onsi/ginkgo"
+	gm "github.com/onsi/gomega"
+)
+
+var _ = Describe("Expression builder test", func() {
+
+	compile := func(exp *Expression) []PredExp {
+		res, err := exp.Compile()
+		gm.Expect(err).ToNot(gm.HaveOccurred())
+		return res
+	}
+
+	compileError := func(exp *Expression) string {
+		_, err := exp.Compile()
+		gm.Expect(err).To(gm.HaveOccurred())
+		gm.Expect(err.(AerospikeError).ResultCode()).To(gm.Equal(PARAMETER_ERROR))
+		return err.Error()
+	}
+
+	It("must compile to predicate expressions in postfix notation", func() {
+		exp := Expr.Or(
+			Expr.And(
+				Expr.GE(Expr.IntBin("c"), Expr.Int(11)),
+				Expr.LE(Expr.IntBin("c"), Expr.Int(20)),
+			),
+			Expr.Not(Expr.EQ(Expr.StringBin("s"), Expr.String("a"))),
+		)
+
+		gm.Expect(compile(exp)).To(gm.Equal([]PredExp{
+			NewPredExpIntegerBin("c"),
+			NewPredExpIntegerValue(11),
+			NewPredExpIntegerGreaterEq(),
+			NewPredExpIntegerBin("c"),
+			NewPredExpIntegerValue(20),
+			NewPredExpIntegerLessEq(),
+			NewPredExpAnd(2),
+			NewPredExpStringBin("s"),
+			NewPredExpStringValue("a"),
+			NewPredExpStringEqual(),
+			NewPredExpNot(),
+			NewPredExpOr(2),
+		}))
+
+		gm.Expect(exp.String()).To(gm.Equal("(((c >= 11) AND (c <= 20)) OR NOT (s = 'a'))"))
+	})
+
+	It("must compile iterators with the predicate before the bin", func() {
+		exp := Expr.ListIterateOr(Expr.ListBin("l"), "v", Expr.EQ(Expr.IntVar("v"), Expr.Int(17)))
+
+		gm.Expect(compile(exp)).To(gm.Equal([]PredExp{
+			NewPredExpIntegerVar("v"),
+			NewPredExpIntegerValue(17),
+			NewPredExpIntegerEqual(),
+			NewPredExpListBin("l"),
+			NewPredExpListIterateOr("v"),
+		}))
+	})
+
+	It("must compile metadata and regular expressions", func() {
+		exp := Expr.And(
+			Expr.GT(Expr.LastUpdate(), Expr.Int(1000)),
+			Expr.Regex(Expr.StringBin("s"), "^a.*", 0),
+		)
+
+		gm.Expect(compile(exp)).To(gm.Equal([]PredExp{
+			NewPredExpRecLastUpdate(),
+			NewPredExpIntegerValue(1000),
+			NewPredExpIntegerGreater(),
+			NewPredExpStringBin("s"),
+			NewPredExpStringValue("^a.*"),
+			NewPredExpStringRegex(0),
+			NewPredExpAnd(2),
+		}))
+
+		// a single operand needs no AND
+		gm.Expect(compile(Expr.And(Expr.Not(Expr.GT(Expr.VoidTime(), Expr.Int(0)))))).To(gm.HaveLen(4))
+	})
+
+	It("must reject mismatched operand types", func() {
+		gm.Expect(compileError(Expr.GT(Expr.StringBin("s"), Expr.Int(1)))).To(gm.ContainSubstring("must both be integer"))
+		gm.Expect(compileError(Expr.EQ(Expr.StringBin("s"), Expr.Int(1)))).To(gm.ContainSubstring("must both be string"))
+		gm.Expect(compileError(Expr.GeoJSONWithin(Expr.GeoJSONBin("g"), Expr.String("x")))).To(gm.ContainSubstring("must both be GeoJSON"))
+		gm.Expect(compileError(Expr.And(Expr.Int(1), Expr.GT(Expr.IntBin("a"), Expr.Int(1))))).To(gm.ContainSubstring("must be a boolean"))
+		gm.Expect(compileError(Expr.Not(Expr.IntBin("a")))).To(gm.ContainSubstring("must be a boolean"))
+		gm.Expect(compileError(Expr.MapKeyIterateOr(Expr.ListBin("l"), "v", Expr.EQ(Expr.IntVar("v"), Expr.Int(1))))).To(gm.ContainSubstring("map bin"))
+
+		// errors propagate to the root of the tree
+		gm.Expect(compileError(Expr.Or(Expr.And(Expr.LT(Expr.Int(1), Expr.String("a")))))).To(gm.ContainSubstring("must both be integer"))
+	})
+
+	It("must reject invalid arity, unbound variables and non-boolean roots", func() {
+		gm.Expect(compileError(Expr.And())).To(gm.ContainSubstring("at least one operand"))
+		gm.Expect(compileError(Expr.EQ(nil, Expr.Int(1)))).To(gm.ContainSubstring("cannot be nil"))
+		gm.Expect(compileError(Expr.IntBin("a"))).To(gm.ContainSubstring("must evaluate to a boolean"))
+		gm.Expect(compileError(Expr.EQ(Expr.IntVar("v"), Expr.Int(1)))).To(gm.ContainSubstring("`v` is not bound"))
+		gm.Expect(compileError(Expr.ListIterateOr(Expr.ListBin("l"), "w", Expr.EQ(Expr.IntVar("v"), Expr.Int(1))))).To(gm.ContainSubstring("`v` is not bound"))
+	})
+
+	It("must attach expressions to policies and statements", func() {
+		policy := NewPolicy()
+		gm.Expect(policy.SetExpression(Expr.EQ(Expr.IntBin("a"), Expr.Int(1)))).ToNot(gm.HaveOccurred())
+		gm.Expect(policy.PredExp).To(gm.HaveLen(3))
+
+		// invalid expressions leave the policy unchanged
+		gm.Expect(policy.SetExpression(Expr.IntBin("a"))).To(gm.HaveOccurred())
+		gm.Expect(policy.PredExp).To(gm.HaveLen(3))
+
+		gm.Expect(policy.SetExpression(nil)).ToNot(gm.HaveOccurred())
+		gm.Expect(policy.PredExp).To(gm.BeNil())
+
+		scanPolicy := NewScanPolicy()
+		gm.Expect(scanPolicy.SetExpression(Expr.GT(Expr.DigestModulo(3), Expr.Int(0)))).ToNot(gm.HaveOccurred())
+		gm.Expect(scanPolicy.PredExp).To(gm.HaveLen(3))
+
+		stmt := NewStatement("test", "test")
+		gm.Expect(stmt.SetExpression(Expr.EQ(Expr.IntBin("a"), Expr.Int(1)))).ToNot(gm.HaveOccurred())
+		gm.Expect(stmt.predExps).To(gm.HaveLen(3))
+
+		gm.Expect(stmt.SetExpression(Expr.IntBin("a"))).To(gm.HaveOccurred())
+		gm.Expect(stmt.predExps).To(gm.HaveLen(3))
+
+		gm.Expect(stmt.SetExpression(nil)).ToNot(gm.HaveOccurred())
+		gm.Expect(stmt.predExps).To(gm.BeNil())
+	})
+
+	It("must write the predicate expressions field in single record commands", func() {
+		key, err := NewKey("test", "test", 1)
+		gm.Expect(err).ToNot(gm.HaveOccurred())
+
+		sizeOf := func(policy *BasePolicy) int {
+			cmd := newReadCommand(nil, policy, key, nil)
+			gm.Expect(cmd.setRead(policy, key, nil)).ToNot(gm.HaveOccurred())
+			return cmd.dataOffset
+		}
+
+		policy := NewPolicy()
+		size := sizeOf(policy)
+
+		gm.Expect(policy.SetExpression(Expr.EQ(Expr.IntBin("a"), Expr.Int(1)))).ToNot(gm.HaveOccurred())
+
+		// field header + bin + value + compare
+		gm.Expect(sizeOf(policy)).To(gm.Equal(size + 5 + (6 + 1) + (6 + 8) + 6))
+	})
+
+})
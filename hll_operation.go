@@ -0,0 +1,202 @@
+// Copyright 2013-2017 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aerospike
+
+// HyperLogLog (HLL) operations.
+// HLL operations are performed on HLL bins and allow the estimation of the
+// cardinality, union, intersection and similarity of large sets.
+//
+// HyperLogLog operations on HLL items nested in lists/maps are not currently
+// supported by the server.
+
+const (
+	_HLL_INIT            = 0
+	_HLL_ADD             = 1
+	_HLL_SET_UNION       = 2
+	_HLL_SET_COUNT       = 3
+	_HLL_FOLD            = 4
+	_HLL_COUNT           = 50
+	_HLL_UNION           = 51
+	_HLL_UNION_COUNT     = 52
+	_HLL_INTERSECT_COUNT = 53
+	_HLL_SIMILARITY      = 54
+	_HLL_DESCRIBE        = 55
+)
+
+// HLL write flags. The flags can be combined with a bitwise OR.
+var HLLWriteFlags = struct {
+	// Default. Allow create or update.
+	DEFAULT int
+
+	// If the bin already exists, the operation will be denied.
+	// If the bin does not exist, a new bin will be created.
+	CREATE_ONLY int
+
+	// If the bin already exists, the bin will be overwritten.
+	// If the bin does not exist, the operation will be denied.
+	UPDATE_ONLY int
+
+	// Do not raise error if operation is denied.
+	NO_FAIL int
+
+	// Allow the resulting set to be the minimum of provided index bits.
+	// Also, allow the usage of less precise HLL algorithms when minHash bits
+	// of all participating sets do not match.
+	ALLOW_FOLD int
+}{0, 1, 2, 4, 8}
+
+// HLLPolicy determines the HyperLogLog operation behavior.
+type HLLPolicy struct {
+	flags int
+}
+
+// NewHLLPolicy creates a policy with the specified write flags.
+func NewHLLPolicy(flags int) *HLLPolicy {
+	return &HLLPolicy{flags: flags}
+}
+
+// DefaultHLLPolicy returns the default HLL policy
+func DefaultHLLPolicy() *HLLPolicy {
+	return NewHLLPolicy(HLLWriteFlags.DEFAULT)
+}
+
+func newHLLCreateOperation(command int, typ OperationType, binName string, params ...interface{}) *Operation {
+	return &Operation{
+		opType:    typ,
+		opSubType: &command,
+		binName:   binName,
+		binValue:  ListValue(params),
+		encoder:   newBitCreateOperationEncoder,
+	}
+}
+
+func hllValuesToList(hlls []HLLValue) ListValue {
+	list := make(ListValue, len(hlls))
+	for i := range hlls {
+		list[i] = hlls[i]
+	}
+	return list
+}
+
+// HLLInitOp creates HLL init operation with minhash bits.
+// Server creates a new HLL or resets an existing HLL.
+// Server does not return a value.
+//
+//  policy           write policy, use DefaultHLLPolicy for default
+//  binName          name of bin
+//  indexBitCount    number of index bits. Must be between 4 and 16 inclusive. Pass -1 for default.
+//  minHashBitCount  number of min hash bits. Must be between 4 and 58 inclusive. Pass -1 for default.
+func HLLInitOp(policy *HLLPolicy, binName string, indexBitCount, minHashBitCount int) *Operation {
+	return newHLLCreateOperation(_HLL_INIT, HLL_MODIFY, binName, IntegerValue(indexBitCount), IntegerValue(minHashBitCount), IntegerValue(policy.flags))
+}
+
+// HLLAddOp creates HLL add operation with minhash bits.
+// Server adds values to HLL set. If HLL bin does not exist, use indexBitCount and minHashBitCount
+// to create HLL bin. Server returns number of entries that caused HLL to update a register.
+//
+//  policy           write policy, use DefaultHLLPolicy for default
+//  binName          name of bin
+//  list             list of values to be added
+//  indexBitCount    number of index bits. Must be between 4 and 16 inclusive. Pass -1 for default.
+//  minHashBitCount  number of min hash bits. Must be between 4 and 58 inclusive. Pass -1 for default.
+func HLLAddOp(policy *HLLPolicy, binName string, list []interface{}, indexBitCount, minHashBitCount int) *Operation {
+	return newHLLCreateOperation(_HLL_ADD, HLL_MODIFY, binName, ListValue(list), IntegerValue(indexBitCount), IntegerValue(minHashBitCount), IntegerValue(policy.flags))
+}
+
+// HLLSetUnionOp creates HLL set union operation.
+// Server sets union of specified HLL objects with HLL bin.
+// Server does not return a value.
+//
+//  policy           write policy, use DefaultHLLPolicy for default
+//  binName          name of bin
+//  list             list of HLL objects
+func HLLSetUnionOp(policy *HLLPolicy, binName string, list []HLLValue) *Operation {
+	return newHLLCreateOperation(_HLL_SET_UNION, HLL_MODIFY, binName, hllValuesToList(list), IntegerValue(policy.flags))
+}
+
+// HLLRefreshCountOp creates HLL refresh operation.
+// Server updates the cached count (if stale) and returns the count.
+//
+//  binName          name of bin
+func HLLRefreshCountOp(binName string) *Operation {
+	return newHLLCreateOperation(_HLL_SET_COUNT, HLL_MODIFY, binName)
+}
+
+// HLLFoldOp creates HLL fold operation.
+// Servers folds indexBitCount to the specified value.
+// This can only be applied when minHashBitCount on the HLL bin is 0.
+// Server does not return a value.
+//
+//  binName          name of bin
+//  indexBitCount    number of index bits. Must be between 4 and 16 inclusive.
+func HLLFoldOp(binName string, indexBitCount int) *Operation {
+	return newHLLCreateOperation(_HLL_FOLD, HLL_MODIFY, binName, IntegerValue(indexBitCount))
+}
+
+// HLLGetCountOp creates HLL getCount operation.
+// Server returns estimated number of elements in the HLL bin.
+//
+//  binName          name of bin
+func HLLGetCountOp(binName string) *Operation {
+	return newHLLCreateOperation(_HLL_COUNT, HLL_READ, binName)
+}
+
+// HLLGetUnionOp creates HLL getUnion operation.
+// Server returns an HLL object that is the union of all specified HLL objects in the list
+// with the HLL bin.
+//
+//  binName          name of bin
+//  list             list of HLL objects
+func HLLGetUnionOp(binName string, list []HLLValue) *Operation {
+	return newHLLCreateOperation(_HLL_UNION, HLL_READ, binName, hllValuesToList(list))
+}
+
+// HLLGetUnionCountOp creates HLL getUnionCount operation.
+// Server returns estimated number of elements that would be contained by the union of these
+// HLL objects.
+//
+//  binName          name of bin
+//  list             list of HLL objects
+func HLLGetUnionCountOp(binName string, list []HLLValue) *Operation {
+	return newHLLCreateOperation(_HLL_UNION_COUNT, HLL_READ, binName, hllValuesToList(list))
+}
+
+// HLLGetIntersectCountOp creates HLL getIntersectCount operation.
+// Server returns estimated number of elements that would be contained by the intersection of
+// these HLL objects.
+//
+//  binName          name of bin
+//  list             list of HLL objects
+func HLLGetIntersectCountOp(binName string, list []HLLValue) *Operation {
+	return newHLLCreateOperation(_HLL_INTERSECT_COUNT, HLL_READ, binName, hllValuesToList(list))
+}
+
+// HLLGetSimilarityOp creates HLL getSimilarity operation.
+// Server returns estimated similarity of these HLL objects. Return type is a float64.
+//
+//  binName          name of bin
+//  list             list of HLL objects
+func HLLGetSimilarityOp(binName string, list []HLLValue) *Operation {
+	return newHLLCreateOperation(_HLL_SIMILARITY, HLL_READ, binName, hllValuesToList(list))
+}
+
+// HLLDescribeOp creates HLL describe operation.
+// Server returns indexBitCount and minHashBitCount used to create HLL bin in a list of longs.
+// The list size is 2.
+//
+//  binName          name of bin
+func HLLDescribeOp(binName string) *Operation {
+	return newHLLCreateOperation(_HLL_DESCRIBE, HLL_READ, binName)
+}
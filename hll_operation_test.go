@@ -0,0 +1,60 @@
+// Copyright 2013-2017 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aerospike
+
+import (
+	ParticleType "github.com/aerospike/aerospike-client-go/types/particle_type"
+
+	. "github.com/onsi/ginkgo"
+	gm "github.com/onsi/gomega"
+)
+
+var _ = Describe("HLL operations test", func() {
+
+	encode := func(op *Operation) []byte {
+		packer := newPacker()
+		_, err := op.encoder(op, packer)
+		gm.Expect(err).ToNot(gm.HaveOccurred())
+		return packer.Bytes()
+	}
+
+	It("must pack the operation type inside the params array", func() {
+		op := HLLInitOp(DefaultHLLPolicy(), "h", 10, -1)
+		gm.Expect(op.opType).To(gm.Equal(HLL_MODIFY))
+		gm.Expect(encode(op)).To(gm.Equal([]byte{0x94, 0x00, 0x0a, 0xff, 0x00}))
+
+		op = HLLGetCountOp("h")
+		gm.Expect(op.opType).To(gm.Equal(HLL_READ))
+		gm.Expect(encode(op)).To(gm.Equal([]byte{0x91, 0x32}))
+
+		gm.Expect(encode(HLLAddOp(NewHLLPolicy(HLLWriteFlags.CREATE_ONLY), "h", []interface{}{1, "a"}, 8, 0))).To(gm.Equal([]byte{
+			0x95, 0x01,
+			0x92, 0x01, 0xa2, 0x03, 'a',
+			0x08, 0x00, 0x01,
+		}))
+	})
+
+	It("must pack HLL values with the HLL particle type", func() {
+		hll := NewHLLValue([]byte{0x01, 0x02})
+		gm.Expect(encode(HLLGetUnionOp("h", []HLLValue{hll}))).To(gm.Equal([]byte{
+			0x92, 0x33,
+			0x91, 0xa3, 0x12, 0x01, 0x02,
+		}))
+
+		gm.Expect(hll.GetType()).To(gm.Equal(ParticleType.HLL))
+		gm.Expect(bytesToParticle(ParticleType.HLL, []byte{0x01, 0x02}, 0, 2)).To(gm.Equal(hll))
+	})
+
+})
@@ -14,7 +14,10 @@
 
 package aerospike
 
-import "fmt"
+import (
+	"fmt"
+	"strings"
+)
 
 // IndexCollectionType is the secondary index collection type.
 type IndexCollectionType int
@@ -51,3 +54,22 @@ func ictToString(ict IndexCollectionType) string {
 		panic(fmt.Sprintf("Unknown IndexCollectionType value %v", ict))
 	}
 }
+
+// ictFromString converts the collection type reported by the sindex info command
+// to IndexCollectionType. Unknown types are reported as ICT_DEFAULT.
+func ictFromString(ict string) IndexCollectionType {
+	switch strings.ToUpper(ict) {
+
+	case "LIST":
+		return ICT_LIST
+
+	case "MAPKEYS":
+		return ICT_MAPKEYS
+
+	case "MAPVALUES":
+		return ICT_MAPVALUES
+
+	default:
+		return ICT_DEFAULT
+	}
+}
@@ -0,0 +1,260 @@
+// Copyright 2013-2017 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aerospike
+
+import (
+	"strconv"
+	"strings"
+
+	. "github.com/aerospike/aerospike-client-go/types"
+)
+
+// IndexInfo describes a secondary index, as returned by ListIndexes.
+// Its state and statistics are aggregated over all nodes of the cluster.
+type IndexInfo struct {
+	Namespace string
+	// SetName is empty if the index is on all the records of the namespace.
+	SetName        string
+	Name           string
+	BinName        string
+	Type           IndexType
+	CollectionType IndexCollectionType
+
+	// State is "RW" if the index is readable and writable on all nodes. Otherwise it
+	// is the state of a node where it is not, e.g. "WO" while the index is built.
+	State string
+
+	// LoadPercent is the lowest progress of building the index on the nodes, from 0 to 100.
+	LoadPercent int
+
+	// Entries is the number of entries of the index on all nodes.
+	Entries int64
+}
+
+// Ready returns true if the index is built on all nodes and can be queried.
+func (idx *IndexInfo) Ready() bool {
+	return idx.State == "RW" && idx.LoadPercent >= 100
+}
+
+// IndexStats holds the statistics of a secondary index, as returned by GetIndexStats.
+type IndexStats struct {
+	// Entries is the number of entries of the index on all nodes.
+	Entries int64
+
+	// MemoryUsed is the memory used by the index on all nodes, in bytes.
+	MemoryUsed int64
+
+	// LoadPercent is the lowest progress of building the index on the nodes, from 0 to 100.
+	LoadPercent int
+
+	// Nodes holds the statistics reported by each node, by node name.
+	Nodes map[string]map[string]string
+}
+
+// IndexDefinition declares a secondary index for EnsureIndex and MigrateIndexes.
+type IndexDefinition struct {
+	Namespace string
+	// SetName is empty to index all the records of the namespace.
+	SetName        string
+	Name           string
+	BinName        string
+	Type           IndexType
+	CollectionType IndexCollectionType
+}
+
+// matches returns true if the index has the definition.
+func (def *IndexDefinition) matches(idx *IndexInfo) bool {
+	return def.Namespace == idx.Namespace &&
+		def.SetName == idx.SetName &&
+		def.Name == idx.Name &&
+		def.BinName == idx.BinName &&
+		def.Type == idx.Type &&
+		def.CollectionType == idx.CollectionType
+}
+
+func (def *IndexDefinition) validate() error {
+	if def.Namespace == "" || def.Name == "" || def.BinName == "" || def.Type == "" {
+		return NewAerospikeError(PARAMETER_ERROR, "Index definition requires a namespace, a name, a bin name and a type: "+def.Name)
+	}
+	return nil
+}
+
+// IndexChangeAction is the action taken on an index by MigrateIndexes.
+type IndexChangeAction string
+
+const (
+	// IndexCreated means the index did not exist and was created.
+	IndexCreated IndexChangeAction = "create"
+
+	// IndexRecreated means the index existed with another definition, and was dropped
+	// and created again.
+	IndexRecreated IndexChangeAction = "recreate"
+
+	// IndexDropped means the index was dropped.
+	IndexDropped IndexChangeAction = "drop"
+)
+
+// IndexChange is a change made to the indexes of the cluster by MigrateIndexes.
+type IndexChange struct {
+	Action IndexChangeAction
+	Index  *IndexDefinition
+}
+
+// IndexMigration declares the secondary indexes an application requires,
+// to be applied by MigrateIndexes on startup.
+type IndexMigration struct {
+	// Indexes are created if they do not exist, and dropped and created again
+	// if they exist with another definition. Indexes are identified by namespace and name.
+	Indexes []*IndexDefinition
+
+	// Drop lists the indexes to drop if they exist, e.g. indexes not used anymore.
+	// Only their namespace, set name and name are used.
+	Drop []*IndexDefinition
+}
+
+// namespaces returns the namespaces of the indexes of the migration.
+func (m *IndexMigration) namespaces() []string {
+	var res []string
+	seen := map[string]bool{}
+	for _, defs := range [][]*IndexDefinition{m.Indexes, m.Drop} {
+		for _, def := range defs {
+			if !seen[def.Namespace] {
+				seen[def.Namespace] = true
+				res = append(res, def.Namespace)
+			}
+		}
+	}
+	return res
+}
+
+// planIndexChanges diffs the migration against the existing indexes.
+// Indexes are dropped before they are created.
+func planIndexChanges(existing []*IndexInfo, migration *IndexMigration) ([]*IndexChange, error) {
+	find := func(namespace, name string) *IndexInfo {
+		for _, idx := range existing {
+			if idx.Namespace == namespace && idx.Name == name {
+				return idx
+			}
+		}
+		return nil
+	}
+
+	var drops, creates []*IndexChange
+	for _, def := range migration.Drop {
+		if def.Namespace == "" || def.Name == "" {
+			return nil, NewAerospikeError(PARAMETER_ERROR, "Index to drop requires a namespace and a name: "+def.Name)
+		}
+		if find(def.Namespace, def.Name) != nil {
+			drops = append(drops, &IndexChange{Action: IndexDropped, Index: def})
+		}
+	}
+
+	declared := map[string]bool{}
+	for _, def := range migration.Indexes {
+		if err := def.validate(); err != nil {
+			return nil, err
+		}
+
+		id := def.Namespace + "." + def.Name
+		if declared[id] {
+			return nil, NewAerospikeError(PARAMETER_ERROR, "Index is declared more than once: "+id)
+		}
+		declared[id] = true
+
+		if idx := find(def.Namespace, def.Name); idx == nil {
+			creates = append(creates, &IndexChange{Action: IndexCreated, Index: def})
+		} else if !def.matches(idx) {
+			creates = append(creates, &IndexChange{Action: IndexRecreated, Index: def})
+		}
+	}
+
+	return append(drops, creates...), nil
+}
+
+// parseIndexList parses the response of the sindex/<namespace> info command, e.g.
+// "ns=test:set=demo:indexname=idx_a:num_bins=1:bins=a:type=NUMERIC:indextype=NONE:state=RW;".
+// Servers report the bin as either bins or bin, and the set of namespace indexes as NULL.
+func parseIndexList(response string) []*IndexInfo {
+	var res []*IndexInfo
+	for _, entry := range strings.Split(response, ";") {
+		params := parseInfoParams(entry, ":")
+		name := params["indexname"]
+		if name == "" {
+			continue
+		}
+
+		idx := &IndexInfo{
+			Namespace:      params["ns"],
+			SetName:        params["set"],
+			Name:           name,
+			BinName:        params["bin"],
+			Type:           IndexType(strings.ToUpper(params["type"])),
+			CollectionType: ictFromString(params["indextype"]),
+			State:          params["state"],
+		}
+		if idx.SetName == "NULL" {
+			idx.SetName = ""
+		}
+		if idx.BinName == "" {
+			idx.BinName = params["bins"]
+		}
+		res = append(res, idx)
+	}
+	return res
+}
+
+// indexNodeStats are the statistics of an index on a node, parsed from the response
+// of the sindex/<namespace>/<index> info command.
+type indexNodeStats struct {
+	entries     int64
+	memoryUsed  int64
+	loadPercent int
+	params      map[string]string
+}
+
+func parseIndexStats(response string) *indexNodeStats {
+	params := parseInfoParams(response, ";")
+	stats := &indexNodeStats{params: params}
+
+	stats.entries, _ = strconv.ParseInt(params["entries"], 10, 64)
+
+	// newer servers report the memory used by the index, older ones
+	// the memory of its two trees
+	if v, exists := params["memory_used"]; exists {
+		stats.memoryUsed, _ = strconv.ParseInt(v, 10, 64)
+	} else {
+		ibtr, _ := strconv.ParseInt(params["ibtr_memory_used"], 10, 64)
+		nbtr, _ := strconv.ParseInt(params["nbtr_memory_used"], 10, 64)
+		stats.memoryUsed = ibtr + nbtr
+	}
+
+	stats.loadPercent = 100
+	if v, exists := params["load_pct"]; exists {
+		stats.loadPercent, _ = strconv.Atoi(v)
+	}
+	return stats
+}
+
+// byIndexName sorts indexes by namespace and name.
+type byIndexName []*IndexInfo
+
+func (s byIndexName) Len() int      { return len(s) }
+func (s byIndexName) Swap(i, j int) { s[i], s[j] = s[j], s[i] }
+func (s byIndexName) Less(i, j int) bool {
+	if s[i].Namespace != s[j].Namespace {
+		return s[i].Namespace < s[j].Namespace
+	}
+	return s[i].Name < s[j].Name
+}
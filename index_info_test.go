@@ -0,0 +1,93 @@
+// Copyright 2013-2017 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aerospike
+
+import (
+	. "github.com/aerospike/aerospike-client-go/types"
+
+	. "github.com/onsi/ginkgo"
+	gm "github.com/onsi/gomega"
+)
+
+var _ = Describe("Index info test", func() {
+
+	It("must parse index lists of all server versions", func() {
+		// 3.x servers
+		indexes := parseIndexList("ns=test:set=demo:indexname=idx_a:num_bins=1:bins=a:type=NUMERIC:indextype=NONE:path=a:sync_state=synced:state=RW;" +
+			"ns=test:set=NULL:indexname=idx_l:num_bins=1:bins=l:type=STRING:indextype=LIST:path=l:sync_state=synced:state=WO;")
+		gm.Expect(indexes).To(gm.Equal([]*IndexInfo{
+			{Namespace: "test", SetName: "demo", Name: "idx_a", BinName: "a", Type: NUMERIC, CollectionType: ICT_DEFAULT, State: "RW"},
+			{Namespace: "test", Name: "idx_l", BinName: "l", Type: STRING, CollectionType: ICT_LIST, State: "WO"},
+		}))
+
+		// 5.x servers
+		indexes = parseIndexList("ns=test:indexname=idx_g:set=demo:bin=g:type=geo2dsphere:indextype=mapvalues:context=NULL:state=RW")
+		gm.Expect(indexes).To(gm.Equal([]*IndexInfo{
+			{Namespace: "test", SetName: "demo", Name: "idx_g", BinName: "g", Type: GEO2DSPHERE, CollectionType: ICT_MAPVALUES, State: "RW"},
+		}))
+
+		gm.Expect(parseIndexList("")).To(gm.BeEmpty())
+	})
+
+	It("must parse index stats", func() {
+		stats := parseIndexStats("keys=2;entries=5;ibtr_memory_used=100;nbtr_memory_used=20;si_accounted_memory=120;load_pct=42;loadtime=7")
+		gm.Expect(stats.entries).To(gm.Equal(int64(5)))
+		gm.Expect(stats.memoryUsed).To(gm.Equal(int64(120)))
+		gm.Expect(stats.loadPercent).To(gm.Equal(42))
+		gm.Expect(stats.params).To(gm.HaveKeyWithValue("loadtime", "7"))
+
+		stats = parseIndexStats("entries=1;memory_used=64;load_pct=100")
+		gm.Expect(stats.memoryUsed).To(gm.Equal(int64(64)))
+
+		// the build progress is not reported once done by some servers
+		gm.Expect(parseIndexStats("entries=1").loadPercent).To(gm.Equal(100))
+	})
+
+	It("must plan index migrations", func() {
+		existing := []*IndexInfo{
+			{Namespace: "test", Name: "same", BinName: "a", Type: NUMERIC},
+			{Namespace: "test", Name: "changed", BinName: "b", Type: NUMERIC},
+			{Namespace: "test", Name: "old", BinName: "c", Type: STRING},
+		}
+
+		same := &IndexDefinition{Namespace: "test", Name: "same", BinName: "a", Type: NUMERIC}
+		changed := &IndexDefinition{Namespace: "test", Name: "changed", BinName: "b", Type: STRING}
+		added := &IndexDefinition{Namespace: "test", Name: "added", BinName: "d", Type: NUMERIC, CollectionType: ICT_MAPKEYS}
+		old := &IndexDefinition{Namespace: "test", Name: "old"}
+		missing := &IndexDefinition{Namespace: "other", Name: "old"}
+
+		changes, err := planIndexChanges(existing, &IndexMigration{
+			Indexes: []*IndexDefinition{same, changed, added},
+			Drop:    []*IndexDefinition{old, missing},
+		})
+		gm.Expect(err).ToNot(gm.HaveOccurred())
+		gm.Expect(changes).To(gm.Equal([]*IndexChange{
+			{Action: IndexDropped, Index: old},
+			{Action: IndexRecreated, Index: changed},
+			{Action: IndexCreated, Index: added},
+		}))
+
+		_, err = planIndexChanges(existing, &IndexMigration{Indexes: []*IndexDefinition{same, same}})
+		gm.Expect(err).To(gm.HaveOccurred())
+		gm.Expect(err.(AerospikeError).ResultCode()).To(gm.Equal(PARAMETER_ERROR))
+
+		_, err = planIndexChanges(existing, &IndexMigration{Indexes: []*IndexDefinition{{Namespace: "test", Name: "x"}}})
+		gm.Expect(err).To(gm.HaveOccurred())
+
+		_, err = planIndexChanges(existing, &IndexMigration{Drop: []*IndexDefinition{{Name: "x"}}})
+		gm.Expect(err).To(gm.HaveOccurred())
+	})
+
+})
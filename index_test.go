@@ -105,6 +105,37 @@ var _ = Describe("Index operations test", func() {
 				Expect(err).ToNot(HaveOccurred())
 			})
 
+			It("must ensure an Index, and list it with its statistics", func() {
+				def := &as.IndexDefinition{Namespace: ns, SetName: set, Name: set + bin1.Name, BinName: bin1.Name, Type: as.NUMERIC}
+				idxTask, err := client.EnsureIndex(wpolicy, def)
+				Expect(err).ToNot(HaveOccurred())
+				defer client.DropIndex(wpolicy, ns, set, def.Name)
+				Expect(<-idxTask.OnComplete()).ToNot(HaveOccurred())
+
+				// ensuring it again is a no-op
+				_, err = client.EnsureIndex(wpolicy, def)
+				Expect(err).ToNot(HaveOccurred())
+
+				indexes, err := client.ListIndexes(nil, ns)
+				Expect(err).ToNot(HaveOccurred())
+
+				var found *as.IndexInfo
+				for _, idx := range indexes {
+					if idx.Name == def.Name {
+						found = idx
+					}
+				}
+				Expect(found).NotTo(BeNil())
+				Expect(found.BinName).To(Equal(bin1.Name))
+				Expect(found.Type).To(Equal(as.NUMERIC))
+				Expect(found.Ready()).To(BeTrue())
+				Expect(found.Entries).To(BeNumerically(">=", keyCount))
+
+				stats, err := client.GetIndexStats(nil, ns, def.Name)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(stats.Entries).To(Equal(found.Entries))
+			})
+
 		})
 
 	})
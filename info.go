@@ -16,6 +16,7 @@ package aerospike
 
 import (
 	"bytes"
+	"context"
 	"encoding/binary"
 	"strings"
 	"time"
@@ -36,17 +37,23 @@ type info struct {
 
 // RequestNodeInfo gets info values by name from the specified database server node.
 func RequestNodeInfo(node *Node, name ...string) (map[string]string, error) {
+	attempt := startAttempt(node, CMD_INFO, "", "", 1, context.Background())
+
 	conn, err := node.GetConnection(_DEFAULT_TIMEOUT)
 	if err != nil {
+		attempt.finish(err)
 		return nil, err
 	}
+	attempt.attach(conn)
 
 	response, err := RequestInfo(conn, name...)
 	if err != nil {
 		conn.Close()
+		attempt.finish(err)
 		return nil, err
 	}
 	node.PutConnection(conn)
+	attempt.finish(nil)
 	return response, nil
 }
 
@@ -0,0 +1,159 @@
+// Copyright 2013-2017 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aerospike
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	. "github.com/aerospike/aerospike-client-go/types"
+)
+
+// parseInfoParams parses "name=value" pairs separated by sep, e.g. "a=1;b=2".
+// Pairs without a value are ignored.
+func parseInfoParams(response string, sep string) map[string]string {
+	res := map[string]string{}
+	for _, pair := range strings.Split(response, sep) {
+		kv := strings.SplitN(pair, "=", 2)
+		if len(kv) == 2 {
+			res[strings.TrimSpace(kv[0])] = kv[1]
+		}
+	}
+	return res
+}
+
+// parseInfoList parses values separated by sep, e.g. "test;bar".
+// Empty values are ignored.
+func parseInfoList(response string, sep string) []string {
+	var res []string
+	for _, value := range strings.Split(response, sep) {
+		if value = strings.TrimSpace(value); len(value) > 0 {
+			res = append(res, value)
+		}
+	}
+	return res
+}
+
+// infoFailed returns true if the info response reports an error.
+func infoFailed(response string) bool {
+	response = strings.ToUpper(response)
+	return strings.HasPrefix(response, "FAIL") || strings.HasPrefix(response, "ERROR")
+}
+
+// infoInt returns the integer value of the first param found among names.
+// Servers renamed most statistics over time, so the names are tried in order.
+func infoInt(params map[string]string, names ...string) int64 {
+	for _, name := range names {
+		if value, exists := params[name]; exists {
+			n, _ := strconv.ParseInt(value, 10, 64)
+			return n
+		}
+	}
+	return 0
+}
+
+// infoBool returns the boolean value of the first param found among names.
+func infoBool(params map[string]string, names ...string) bool {
+	for _, name := range names {
+		if value, exists := params[name]; exists {
+			return value == "true"
+		}
+	}
+	return false
+}
+
+// infoString returns the value of the first param found among names.
+func infoString(params map[string]string, names ...string) string {
+	for _, name := range names {
+		if value, exists := params[name]; exists {
+			return value
+		}
+	}
+	return ""
+}
+
+// citrusleafTime converts milliseconds since the Citrusleaf epoch to time.
+// Zero means the value was never set, and is returned as the zero time.
+func citrusleafTime(millis int64) time.Time {
+	if millis <= 0 {
+		return time.Time{}
+	}
+	return time.Unix(CITRUSLEAF_EPOCH, 0).Add(time.Duration(millis) * time.Millisecond)
+}
+
+// parseNamespaceInfo parses the response of the namespace/<ns> info command
+// of a single node. It returns nil if the namespace is unknown to the node.
+// Format: objects=10;master_objects=5;...;stop_writes=false;...
+func parseNamespaceInfo(namespace, response string) *NamespaceInfo {
+	if len(response) == 0 || infoFailed(response) {
+		return nil
+	}
+
+	params := parseInfoParams(response, ";")
+	if params["type"] == "unknown" || params["ns_type"] == "unknown" {
+		return nil
+	}
+
+	return &NamespaceInfo{
+		Name:              namespace,
+		ReplicationFactor: int(infoInt(params, "effective_replication_factor", "replication-factor", "repl-factor")),
+		Objects:           infoInt(params, "objects"),
+		MasterObjects:     infoInt(params, "master_objects", "master-objects"),
+		Tombstones:        infoInt(params, "tombstones"),
+		MemoryUsed:        infoInt(params, "memory_used_bytes", "used-bytes-memory"),
+		MemorySize:        infoInt(params, "memory-size", "total-bytes-memory"),
+		DiskUsed:          infoInt(params, "device_used_bytes", "used-bytes-disk", "data_used_bytes"),
+		DiskSize:          infoInt(params, "device_total_bytes", "total-bytes-disk", "data_total_bytes"),
+		StopWrites:        infoBool(params, "stop_writes", "stop-writes"),
+		HWMBreached:       infoBool(params, "hwm_breached", "hwm-breached"),
+	}
+}
+
+// parseSetList parses the response of the sets/<ns> info command of a single node.
+// Sets are separated by ';' and their fields by ':'. Servers before 3.9 use
+// ns_name, set_name and n_objects instead of ns, set and objects.
+func parseSetList(response string) []*SetInfo {
+	var res []*SetInfo
+	for _, set := range parseInfoList(response, ";") {
+		params := parseInfoParams(set, ":")
+		name := infoString(params, "set", "set_name")
+		if len(name) == 0 {
+			continue
+		}
+
+		res = append(res, &SetInfo{
+			Namespace:   infoString(params, "ns", "ns_name"),
+			Name:        name,
+			Objects:     infoInt(params, "objects", "n_objects"),
+			Tombstones:  infoInt(params, "tombstones"),
+			MemoryUsed:  infoInt(params, "memory_data_bytes", "data_used_bytes", "n-bytes-memory"),
+			TruncateLUT: citrusleafTime(infoInt(params, "truncate_lut")),
+		})
+	}
+	return res
+}
+
+// parseBinNames parses the response of the bins/<ns> info command.
+// Format: bin_names=2,bin_names_quota=32768,a,b
+func parseBinNames(response string) []string {
+	var res []string
+	for _, name := range parseInfoList(response, ",") {
+		if !strings.Contains(name, "=") {
+			res = append(res, name)
+		}
+	}
+	return res
+}
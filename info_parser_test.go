@@ -0,0 +1,131 @@
+// Copyright 2013-2017 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aerospike
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	gm "github.com/onsi/gomega"
+)
+
+var _ = Describe("Info parser test", func() {
+
+	It("must parse namespace statistics of all server versions", func() {
+		// 3.x servers, in memory
+		info := parseNamespaceInfo("test", "type=memory;objects=10;sub-objects=0;master-objects=5;prole-objects=5;"+
+			"used-bytes-memory=2048;total-bytes-memory=4294967296;repl-factor=2;stop-writes=false;hwm-breached=true")
+		gm.Expect(info).To(gm.Equal(&NamespaceInfo{
+			Name:              "test",
+			ReplicationFactor: 2,
+			Objects:           10,
+			MasterObjects:     5,
+			MemoryUsed:        2048,
+			MemorySize:        4294967296,
+			HWMBreached:       true,
+		}))
+
+		// 4.x servers, on disk
+		info = parseNamespaceInfo("bar", "ns_cluster_size=3;effective_replication_factor=2;objects=300;tombstones=4;"+
+			"master_objects=150;prole_objects=150;stop_writes=true;hwm_breached=false;memory_used_bytes=19200;"+
+			"device_total_bytes=17179869184;device_used_bytes=38400;replication-factor=3;memory-size=1073741824")
+		gm.Expect(info).To(gm.Equal(&NamespaceInfo{
+			Name:              "bar",
+			ReplicationFactor: 2,
+			Objects:           300,
+			MasterObjects:     150,
+			Tombstones:        4,
+			MemoryUsed:        19200,
+			MemorySize:        1073741824,
+			DiskUsed:          38400,
+			DiskSize:          17179869184,
+			StopWrites:        true,
+		}))
+
+		// 7.x servers report the storage engine as data
+		info = parseNamespaceInfo("ssd", "objects=1;data_used_bytes=512;data_total_bytes=1024")
+		gm.Expect(info.DiskUsed).To(gm.Equal(int64(512)))
+		gm.Expect(info.DiskSize).To(gm.Equal(int64(1024)))
+
+		// unknown namespaces
+		gm.Expect(parseNamespaceInfo("none", "type=unknown")).To(gm.BeNil())
+		gm.Expect(parseNamespaceInfo("none", "ns_type=unknown")).To(gm.BeNil())
+		gm.Expect(parseNamespaceInfo("none", "ERROR::namespace not found")).To(gm.BeNil())
+		gm.Expect(parseNamespaceInfo("none", "")).To(gm.BeNil())
+	})
+
+	It("must merge namespace statistics of nodes", func() {
+		ns := &NamespaceInfo{Name: "test", ReplicationFactor: 2, Objects: 10, MemoryUsed: 100}
+		ns.merge(&NamespaceInfo{Name: "test", ReplicationFactor: 1, Objects: 5, MemoryUsed: 50, StopWrites: true})
+		gm.Expect(ns.ReplicationFactor).To(gm.Equal(2))
+		gm.Expect(ns.Objects).To(gm.Equal(int64(15)))
+		gm.Expect(ns.MemoryUsed).To(gm.Equal(int64(150)))
+		gm.Expect(ns.StopWrites).To(gm.BeTrue())
+	})
+
+	It("must parse set lists of all server versions", func() {
+		// 3.x servers before 3.9
+		sets := parseSetList("ns_name=test:set_name=demo:n_objects=3:set-stop-writes-count=0:set-evict-hwm-count=0:set-enable-xdr=use-default:set-delete=false;" +
+			"ns_name=test:set_name=users:n_objects=7:set-stop-writes-count=0:set-evict-hwm-count=0:set-enable-xdr=use-default:set-delete=false;")
+		gm.Expect(sets).To(gm.Equal([]*SetInfo{
+			{Namespace: "test", Name: "demo", Objects: 3},
+			{Namespace: "test", Name: "users", Objects: 7},
+		}))
+
+		// 4.x servers
+		sets = parseSetList("ns=test:set=demo:objects=2:tombstones=1:memory_data_bytes=256:truncate_lut=275230604123:stop-writes-count=0:set-enable-xdr=use-default:disable-eviction=false;")
+		gm.Expect(len(sets)).To(gm.Equal(1))
+		gm.Expect(sets[0].Objects).To(gm.Equal(int64(2)))
+		gm.Expect(sets[0].Tombstones).To(gm.Equal(int64(1)))
+		gm.Expect(sets[0].MemoryUsed).To(gm.Equal(int64(256)))
+		gm.Expect(sets[0].TruncateLUT.Equal(time.Unix(1262304000+275230604, 123*int64(time.Millisecond)))).To(gm.BeTrue())
+
+		// 7.x servers
+		sets = parseSetList("ns=test:set=demo:objects=1:tombstones=0:data_used_bytes=64:truncate_lut=0:sindexes=0:index_populating=false")
+		gm.Expect(sets[0].MemoryUsed).To(gm.Equal(int64(64)))
+		gm.Expect(sets[0].TruncateLUT.IsZero()).To(gm.BeTrue())
+
+		gm.Expect(parseSetList("")).To(gm.BeEmpty())
+	})
+
+	It("must merge set statistics of nodes", func() {
+		lut := time.Unix(1500000000, 0)
+		set := &SetInfo{Name: "demo", Objects: 2, TruncateLUT: lut}
+		set.merge(&SetInfo{Name: "demo", Objects: 3, Tombstones: 1})
+		set.merge(&SetInfo{Name: "demo", TruncateLUT: lut.Add(time.Second)})
+		gm.Expect(set.Objects).To(gm.Equal(int64(5)))
+		gm.Expect(set.Tombstones).To(gm.Equal(int64(1)))
+		gm.Expect(set.TruncateLUT).To(gm.Equal(lut.Add(time.Second)))
+	})
+
+	It("must parse bin names", func() {
+		gm.Expect(parseBinNames("bin_names=2,bin_names_quota=32768,name,age")).To(gm.Equal([]string{"name", "age"}))
+		gm.Expect(parseBinNames("bin_names=0,bin_names_quota=32768")).To(gm.BeEmpty())
+	})
+
+	It("must aggregate the cluster", func() {
+		info := newClusterInfo("prod", []*NodeInfo{
+			{Name: "A", Build: "4.5.0.5", Features: []string{"geo", "pscans", "float"}, Namespaces: []string{"test"}},
+			{Name: "B", Build: "4.6.0.2", Features: []string{"float", "geo"}, Namespaces: []string{"test", "bar"}},
+		})
+		gm.Expect(info.Name).To(gm.Equal("prod"))
+		gm.Expect(info.Namespaces).To(gm.Equal([]string{"bar", "test"}))
+		gm.Expect(info.Features).To(gm.Equal([]string{"float", "geo"}))
+		gm.Expect(info.HasFeature("geo")).To(gm.BeTrue())
+		gm.Expect(info.HasFeature("pscans")).To(gm.BeFalse())
+		gm.Expect(info.Builds()).To(gm.Equal([]string{"4.5.0.5", "4.6.0.2"}))
+	})
+
+})
@@ -45,6 +45,9 @@ func newInstance(params ...interface{}) interface{} {
 	registerLuaStreamType(L)
 	registerLuaListType(L)
 	registerLuaMapType(L)
+	registerLuaRecordType(L)
+	registerLuaBytesType(L)
+	registerLuaGeoJSONType(L)
 
 	if err := L.DoString(luaLib.LibStreamOps); err != nil {
 		logger.Logger.Error(err.Error())
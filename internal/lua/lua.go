@@ -17,7 +17,10 @@ package lua
 import (
 	"fmt"
 	"reflect"
+	"strings"
 
+	"github.com/aerospike/aerospike-client-go/types"
+	ParticleType "github.com/aerospike/aerospike-client-go/types/particle_type"
 	"github.com/yuin/gopher-lua"
 )
 
@@ -62,6 +65,10 @@ func NewValue(L *lua.LState, value interface{}) lua.LValue {
 		return lua.LNumber(v)
 	case bool:
 		return lua.LBool(v)
+	case []byte:
+		return NewLuaBytes(L, v)
+	case GeoJSON:
+		return NewLuaGeoJSON(L, v)
 	case map[interface{}]interface{}:
 		luaMap := &LuaMap{m: v}
 		ud := L.NewUserData()
@@ -79,8 +86,21 @@ func NewValue(L *lua.LState, value interface{}) lua.LValue {
 
 	// check for array and map
 	rv := reflect.ValueOf(value)
+
+	// values of the client, e.g. GeoJSONValue
+	if v, ok := value.(interface {
+		GetType() int
+	}); ok && v.GetType() == ParticleType.GEOJSON && rv.Kind() == reflect.String {
+		return NewLuaGeoJSON(L, GeoJSON(rv.String()))
+	}
+
 	switch rv.Kind() {
-	case reflect.Array, reflect.Slice:
+	case reflect.Slice:
+		if rv.Type().Elem().Kind() == reflect.Uint8 {
+			return NewLuaBytes(L, rv.Bytes())
+		}
+		fallthrough
+	case reflect.Array:
 		l := rv.Len()
 		arr := make([]interface{}, l)
 		for i := 0; i < l; i++ {
@@ -129,6 +149,10 @@ func LValueToInterface(val lua.LValue) interface{} {
 			return v.m
 		case *LuaList:
 			return v.l
+		case *LuaBytes:
+			return v.b
+		case *LuaGeoJSON:
+			return v.s
 		default:
 			return v
 		}
@@ -143,6 +167,30 @@ func LValueToInterface(val lua.LValue) interface{} {
 	}
 }
 
+// NewError converts an error returned by the interpreter to a UDF_BAD_RESPONSE
+// AerospikeError, caused by a *types.LuaError holding the Lua stack trace.
+func NewError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	lerr := &types.LuaError{Message: err.Error()}
+	if apiErr, ok := err.(*lua.ApiError); ok {
+		if apiErr.Object != nil {
+			lerr.Message = apiErr.Object.String()
+		}
+		lerr.StackTrace = apiErr.StackTrace
+	}
+
+	// errors raised in UDFs carry the stack trace of where they were raised
+	if i := strings.Index(lerr.Message, "\nstack traceback:"); i >= 0 {
+		lerr.StackTrace = lerr.Message[i+1:]
+		lerr.Message = lerr.Message[:i]
+	}
+
+	return types.NewAerospikeErrorWithCause(types.UDF_BAD_RESPONSE, lerr, lerr.Message)
+}
+
 func allToString(L *lua.LState) int {
 	ud := L.CheckUserData(1)
 	value := ud.Value
@@ -33,6 +33,10 @@ func registerLuaAerospikeType(L *lua.LState) {
 
 	// static attributes
 	L.SetField(mt, "log", L.NewFunction(luaAerospikeLog))
+	L.SetField(mt, "exists", L.NewFunction(luaAerospikeExists))
+	L.SetField(mt, "create", L.NewFunction(luaAerospikeCreate))
+	L.SetField(mt, "update", L.NewFunction(luaAerospikeUpdate))
+	L.SetField(mt, "remove", L.NewFunction(luaAerospikeRemove))
 
 	L.SetMetatable(mt, mt)
 }
@@ -63,3 +67,67 @@ func luaAerospikeLog(L *lua.LState) int {
 
 	return 0
 }
+
+// checkRecordArg returns the record passed to the aerospike record functions,
+// which can be called either as aerospike:fn(rec) or aerospike.fn(rec).
+func checkRecordArg(L *lua.LState, name string) *LuaRecord {
+	if L.GetTop() < 1 || L.GetTop() > 2 {
+		L.ArgError(1, "1 argument is expected for aerospike:"+name+" method")
+		return nil
+	}
+	return checkLuaRecord(L, L.GetTop())
+}
+
+func luaAerospikeExists(L *lua.LState) int {
+	rec := checkRecordArg(L, "exists")
+	L.Push(lua.LBool(rec.exists))
+	return 1
+}
+
+// luaAerospikeCreate stores a new record. It returns 0 on success,
+// or 1 if the record already exists.
+func luaAerospikeCreate(L *lua.LState) int {
+	rec := checkRecordArg(L, "create")
+	if rec.exists {
+		L.Push(lua.LNumber(1))
+		return 1
+	}
+
+	rec.store()
+	rec.created = true
+	L.Push(lua.LNumber(0))
+	return 1
+}
+
+// luaAerospikeUpdate stores the record, and creates it if it does not exist.
+// It returns 0 on success.
+func luaAerospikeUpdate(L *lua.LState) int {
+	rec := checkRecordArg(L, "update")
+	if rec.exists {
+		rec.updated = true
+	} else {
+		rec.created = true
+	}
+
+	rec.store()
+	L.Push(lua.LNumber(0))
+	return 1
+}
+
+// luaAerospikeRemove removes the record. It returns 0 on success,
+// or 2 if the record does not exist.
+func luaAerospikeRemove(L *lua.LState) int {
+	rec := checkRecordArg(L, "remove")
+	if !rec.exists {
+		L.Push(lua.LNumber(2))
+		return 1
+	}
+
+	rec.bins = map[string]interface{}{}
+	rec.stored = map[string]interface{}{}
+	rec.exists = false
+	rec.generation = 0
+	rec.removed = true
+	L.Push(lua.LNumber(0))
+	return 1
+}
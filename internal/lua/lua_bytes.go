@@ -0,0 +1,428 @@
+// Copyright 2013-2017 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lua
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	ParticleType "github.com/aerospike/aerospike-client-go/types/particle_type"
+	"github.com/yuin/gopher-lua"
+)
+
+// LuaBytes is the bytes type of the UDF API. Positions are 1-based.
+type LuaBytes struct {
+	b []byte
+	t int
+}
+
+const luaLuaBytesTypeName = "LuaBytes"
+
+// Registers the bytes type and the bytes package to given L.
+func registerLuaBytesType(L *lua.LState) {
+	mt := L.NewTypeMetatable(luaLuaBytesTypeName)
+
+	L.SetGlobal("bytes", mt)
+
+	// static attributes
+	L.SetField(mt, "__call", L.NewFunction(newLuaBytes))
+
+	L.SetField(mt, "size", L.NewFunction(luaBytesSize))
+	L.SetField(mt, "set_size", L.NewFunction(luaBytesSetSize))
+	L.SetField(mt, "get_type", L.NewFunction(luaBytesGetType))
+	L.SetField(mt, "set_type", L.NewFunction(luaBytesSetType))
+
+	L.SetField(mt, "get_byte", L.NewFunction(luaBytesGetByte))
+	L.SetField(mt, "set_byte", L.NewFunction(luaBytesSetByte))
+	L.SetField(mt, "append_byte", L.NewFunction(luaBytesAppendByte))
+
+	L.SetField(mt, "get_string", L.NewFunction(luaBytesGetString))
+	L.SetField(mt, "set_string", L.NewFunction(luaBytesSetString))
+	L.SetField(mt, "append_string", L.NewFunction(luaBytesAppendString))
+
+	L.SetField(mt, "get_bytes", L.NewFunction(luaBytesGetBytes))
+	L.SetField(mt, "set_bytes", L.NewFunction(luaBytesSetBytes))
+	L.SetField(mt, "append_bytes", L.NewFunction(luaBytesAppendBytes))
+
+	L.SetField(mt, "get_var_int", L.NewFunction(luaBytesGetVarInt))
+	L.SetField(mt, "set_var_int", L.NewFunction(luaBytesSetVarInt))
+	L.SetField(mt, "append_var_int", L.NewFunction(luaBytesAppendVarInt))
+
+	for _, f := range []struct {
+		suffix string
+		size   int
+		order  binary.ByteOrder
+	}{
+		{"int16_be", 2, binary.BigEndian},
+		{"int16_le", 2, binary.LittleEndian},
+		{"int32_be", 4, binary.BigEndian},
+		{"int32_le", 4, binary.LittleEndian},
+		{"int64_be", 8, binary.BigEndian},
+		{"int64_le", 8, binary.LittleEndian},
+	} {
+		L.SetField(mt, "get_"+f.suffix, L.NewFunction(luaBytesGetInt(f.size, f.order)))
+		L.SetField(mt, "set_"+f.suffix, L.NewFunction(luaBytesSetInt(f.size, f.order)))
+		L.SetField(mt, "append_"+f.suffix, L.NewFunction(luaBytesAppendInt(f.size, f.order)))
+	}
+
+	// methods
+	L.SetFuncs(mt, map[string]lua.LGFunction{
+		"__index":    luaBytesIndex,
+		"__newindex": luaBytesNewIndex,
+		"__len":      luaBytesLen,
+		"__tostring": luaBytesToString,
+	})
+
+	L.SetMetatable(mt, mt)
+}
+
+// NewLuaBytes wraps a copy of the bytes to be passed to a UDF.
+func NewLuaBytes(L *lua.LState, b []byte) *lua.LUserData {
+	return newLuaBytesUserData(L, &LuaBytes{b: append([]byte{}, b...), t: ParticleType.BLOB})
+}
+
+func newLuaBytesUserData(L *lua.LState, p *LuaBytes) *lua.LUserData {
+	ud := L.NewUserData()
+	ud.Value = p
+	L.SetMetatable(ud, L.GetTypeMetatable(luaLuaBytesTypeName))
+	return ud
+}
+
+// Constructor: bytes([size]) creates size zero bytes.
+func newLuaBytes(L *lua.LState) int {
+	size := 0
+	if L.GetTop() == 2 {
+		size = L.CheckInt(2)
+		if size < 0 {
+			L.ArgError(2, "size must not be negative for bytes constructor")
+			return 0
+		}
+	} else if L.GetTop() != 1 {
+		L.ArgError(1, "Only one argument expected for bytes constructor")
+		return 0
+	}
+
+	L.Push(newLuaBytesUserData(L, &LuaBytes{b: make([]byte, size), t: ParticleType.BLOB}))
+	return 1
+}
+
+// Checks whether the lua argument is a *LUserData with *LuaBytes and returns this *LuaBytes.
+func checkLuaBytes(L *lua.LState, arg int) *LuaBytes {
+	ud := L.CheckUserData(arg)
+	if v, ok := ud.Value.(*LuaBytes); ok {
+		return v
+	}
+	L.ArgError(arg, "bytes expected")
+	return nil
+}
+
+// checkBytesArgs checks the number of arguments of a bytes function, including the bytes.
+func checkBytesArgs(L *lua.LState, n int, name string) *LuaBytes {
+	if L.GetTop() != n {
+		L.ArgError(1, fmt.Sprintf("%d arguments expected for bytes.%s method", n, name))
+		return nil
+	}
+	return checkLuaBytes(L, 1)
+}
+
+// checkBytesPos returns the 0-based offset of a 1-based position argument.
+func checkBytesPos(L *lua.LState, arg int) int {
+	pos := L.CheckInt(arg)
+	if pos < 1 {
+		L.ArgError(arg, "position must be positive")
+		return 0
+	}
+	return pos - 1
+}
+
+// set writes b at offset, and returns false if it does not fit in the bytes.
+func (p *LuaBytes) set(offset int, b []byte) bool {
+	if offset+len(b) > len(p.b) {
+		return false
+	}
+	copy(p.b[offset:], b)
+	return true
+}
+
+func (p *LuaBytes) String() string {
+	return fmt.Sprintf("%v", p.b)
+}
+
+func luaBytesSize(L *lua.LState) int {
+	p := checkBytesArgs(L, 1, "size")
+	L.Push(lua.LNumber(len(p.b)))
+	return 1
+}
+
+func luaBytesSetSize(L *lua.LState) int {
+	p := checkBytesArgs(L, 2, "set_size")
+	size := L.CheckInt(2)
+	if size < 0 {
+		L.ArgError(2, "size must not be negative for bytes.set_size method")
+		return 0
+	}
+
+	if size <= len(p.b) {
+		p.b = p.b[:size]
+	} else {
+		p.b = append(p.b, make([]byte, size-len(p.b))...)
+	}
+	L.Push(lua.LTrue)
+	return 1
+}
+
+func luaBytesGetType(L *lua.LState) int {
+	p := checkBytesArgs(L, 1, "get_type")
+	L.Push(lua.LNumber(p.t))
+	return 1
+}
+
+func luaBytesSetType(L *lua.LState) int {
+	p := checkBytesArgs(L, 2, "set_type")
+	p.t = L.CheckInt(2)
+	L.Push(lua.LTrue)
+	return 1
+}
+
+func luaBytesGetByte(L *lua.LState) int {
+	p := checkBytesArgs(L, 2, "get_byte")
+	offset := checkBytesPos(L, 2)
+	if offset >= len(p.b) {
+		L.Push(lua.LNil)
+		return 1
+	}
+	L.Push(lua.LNumber(p.b[offset]))
+	return 1
+}
+
+func luaBytesSetByte(L *lua.LState) int {
+	p := checkBytesArgs(L, 3, "set_byte")
+	offset := checkBytesPos(L, 2)
+	L.Push(lua.LBool(p.set(offset, []byte{byte(L.CheckInt(3))})))
+	return 1
+}
+
+func luaBytesAppendByte(L *lua.LState) int {
+	p := checkBytesArgs(L, 2, "append_byte")
+	p.b = append(p.b, byte(L.CheckInt(2)))
+	L.Push(lua.LTrue)
+	return 1
+}
+
+// slice returns the length bytes at the position arguments, or nil if they are out of range.
+func (p *LuaBytes) slice(L *lua.LState) []byte {
+	offset := checkBytesPos(L, 2)
+	length := L.CheckInt(3)
+	if length < 0 || offset+length > len(p.b) {
+		return nil
+	}
+	return p.b[offset : offset+length]
+}
+
+func luaBytesGetString(L *lua.LState) int {
+	p := checkBytesArgs(L, 3, "get_string")
+	if b := p.slice(L); b != nil {
+		L.Push(lua.LString(b))
+	} else {
+		L.Push(lua.LNil)
+	}
+	return 1
+}
+
+func luaBytesSetString(L *lua.LState) int {
+	p := checkBytesArgs(L, 3, "set_string")
+	offset := checkBytesPos(L, 2)
+	L.Push(lua.LBool(p.set(offset, []byte(L.CheckString(3)))))
+	return 1
+}
+
+func luaBytesAppendString(L *lua.LState) int {
+	p := checkBytesArgs(L, 2, "append_string")
+	p.b = append(p.b, L.CheckString(2)...)
+	L.Push(lua.LTrue)
+	return 1
+}
+
+func luaBytesGetBytes(L *lua.LState) int {
+	p := checkBytesArgs(L, 3, "get_bytes")
+	if b := p.slice(L); b != nil {
+		L.Push(NewLuaBytes(L, b))
+	} else {
+		L.Push(lua.LNil)
+	}
+	return 1
+}
+
+// srcBytes returns the first length bytes of the bytes argument.
+func srcBytes(L *lua.LState, arg int, length int) []byte {
+	src := checkLuaBytes(L, arg)
+	if length < 0 || length > len(src.b) {
+		L.ArgError(arg+1, "length out of range")
+		return nil
+	}
+	return src.b[:length]
+}
+
+func luaBytesSetBytes(L *lua.LState) int {
+	p := checkBytesArgs(L, 4, "set_bytes")
+	offset := checkBytesPos(L, 2)
+	L.Push(lua.LBool(p.set(offset, srcBytes(L, 3, L.CheckInt(4)))))
+	return 1
+}
+
+func luaBytesAppendBytes(L *lua.LState) int {
+	p := checkBytesArgs(L, 3, "append_bytes")
+	p.b = append(p.b, srcBytes(L, 2, L.CheckInt(3))...)
+	L.Push(lua.LTrue)
+	return 1
+}
+
+func encodeInt(size int, order binary.ByteOrder, v int64) []byte {
+	b := make([]byte, size)
+	switch size {
+	case 2:
+		order.PutUint16(b, uint16(v))
+	case 4:
+		order.PutUint32(b, uint32(v))
+	default:
+		order.PutUint64(b, uint64(v))
+	}
+	return b
+}
+
+func luaBytesGetInt(size int, order binary.ByteOrder) lua.LGFunction {
+	return func(L *lua.LState) int {
+		p := checkBytesArgs(L, 2, "get_int")
+		offset := checkBytesPos(L, 2)
+		if offset+size > len(p.b) {
+			L.Push(lua.LNil)
+			return 1
+		}
+
+		b := p.b[offset : offset+size]
+		switch size {
+		case 2:
+			L.Push(lua.LNumber(int16(order.Uint16(b))))
+		case 4:
+			L.Push(lua.LNumber(int32(order.Uint32(b))))
+		default:
+			L.Push(lua.LNumber(int64(order.Uint64(b))))
+		}
+		return 1
+	}
+}
+
+func luaBytesSetInt(size int, order binary.ByteOrder) lua.LGFunction {
+	return func(L *lua.LState) int {
+		p := checkBytesArgs(L, 3, "set_int")
+		offset := checkBytesPos(L, 2)
+		L.Push(lua.LBool(p.set(offset, encodeInt(size, order, L.CheckInt64(3)))))
+		return 1
+	}
+}
+
+func luaBytesAppendInt(size int, order binary.ByteOrder) lua.LGFunction {
+	return func(L *lua.LState) int {
+		p := checkBytesArgs(L, 2, "append_int")
+		p.b = append(p.b, encodeInt(size, order, L.CheckInt64(2))...)
+		L.Push(lua.LTrue)
+		return 1
+	}
+}
+
+func encodeVarInt(v int64) []byte {
+	b := make([]byte, binary.MaxVarintLen32)
+	n := binary.PutUvarint(b, uint64(uint32(v)))
+	return b[:n]
+}
+
+// luaBytesGetVarInt returns the variable length integer at the position and its size,
+// or 0 and 0 if there is none.
+func luaBytesGetVarInt(L *lua.LState) int {
+	p := checkBytesArgs(L, 2, "get_var_int")
+	offset := checkBytesPos(L, 2)
+
+	var v uint64
+	var n int
+	if offset < len(p.b) {
+		v, n = binary.Uvarint(p.b[offset:])
+	}
+	if n <= 0 || n > binary.MaxVarintLen32 {
+		v, n = 0, 0
+	}
+
+	L.Push(lua.LNumber(uint32(v)))
+	L.Push(lua.LNumber(n))
+	return 2
+}
+
+// luaBytesSetVarInt writes the variable length integer at the position and returns its size,
+// or 0 if it does not fit.
+func luaBytesSetVarInt(L *lua.LState) int {
+	p := checkBytesArgs(L, 3, "set_var_int")
+	offset := checkBytesPos(L, 2)
+	b := encodeVarInt(L.CheckInt64(3))
+	if !p.set(offset, b) {
+		L.Push(lua.LNumber(0))
+		return 1
+	}
+	L.Push(lua.LNumber(len(b)))
+	return 1
+}
+
+func luaBytesAppendVarInt(L *lua.LState) int {
+	p := checkBytesArgs(L, 2, "append_var_int")
+	b := encodeVarInt(L.CheckInt64(2))
+	p.b = append(p.b, b...)
+	L.Push(lua.LNumber(len(b)))
+	return 1
+}
+
+func luaBytesIndex(L *lua.LState) int {
+	p := checkLuaBytes(L, 1)
+	index := L.CheckInt(2)
+
+	if index <= 0 || index > len(p.b) {
+		L.Push(lua.LNil)
+		return 1
+	}
+	L.Push(lua.LNumber(p.b[index-1]))
+	return 1
+}
+
+func luaBytesNewIndex(L *lua.LState) int {
+	p := checkLuaBytes(L, 1)
+	index := L.CheckInt(2)
+	value := L.CheckInt(3)
+
+	if index <= 0 || index > len(p.b) {
+		L.ArgError(2, "index out of range for bytes")
+		return 0
+	}
+	p.b[index-1] = byte(value)
+	return 0
+}
+
+func luaBytesLen(L *lua.LState) int {
+	p := checkLuaBytes(L, 1)
+	L.Push(lua.LNumber(len(p.b)))
+	return 1
+}
+
+func luaBytesToString(L *lua.LState) int {
+	p := checkLuaBytes(L, 1)
+	L.Push(lua.LString(p.String()))
+	return 1
+}
@@ -0,0 +1,120 @@
+// Copyright 2013-2017 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lua_test
+
+import (
+	"github.com/yuin/gopher-lua"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/aerospike/aerospike-client-go/internal/lua"
+)
+
+var _ = Describe("Lua Bytes API Test", func() {
+
+	// code vs result
+	testMatrix := map[string]interface{}{
+		"b = bytes()\n return b":                []byte{},
+		"b = bytes(3)\n return b":               []byte{0, 0, 0},
+		"b = bytes(3)\n return bytes.size(b)":   float64(3),
+		"b = bytes(3)\n return #b":              float64(3),
+		"b = bytes(3)\n b[2] = 7\n return b":    []byte{0, 7, 0},
+		"b = bytes(3)\n b[2] = 7\n return b[2]": float64(7),
+		"b = bytes(3)\n return b[4] == nil":     true,
+
+		"b = bytes(1)\n bytes.set_size(b, 3)\n return b": []byte{0, 0, 0},
+		"b = bytes(3)\n bytes.set_size(b, 1)\n return b": []byte{0},
+		"b = bytes()\n return bytes.get_type(b)":         float64(4),
+
+		"b = bytes(2)\n bytes.set_byte(b, 1, 255)\n return bytes.get_byte(b, 1)": float64(255),
+		"b = bytes(2)\n return bytes.set_byte(b, 3, 1)":                          false,
+		"b = bytes()\n bytes.append_byte(b, 1)\n return b":                       []byte{1},
+
+		"b = bytes()\n bytes.append_string(b, 'abc')\n return bytes.get_string(b, 2, 2)": "bc",
+		"b = bytes(3)\n bytes.set_string(b, 1, 'ab')\n return b":                         []byte{'a', 'b', 0},
+		"b = bytes(3)\n return bytes.set_string(b, 3, 'ab')":                             false,
+		"b = bytes(3)\n return bytes.get_string(b, 3, 2) == nil":                         true,
+
+		"b = bytes()\n bytes.append_string(b, 'abc')\n return bytes.get_bytes(b, 1, 2)":              []byte{'a', 'b'},
+		"b = bytes(3)\n s = bytes(2)\n s[1] = 1\n s[2] = 2\n bytes.set_bytes(b, 2, s, 2)\n return b": []byte{0, 1, 2},
+		"b = bytes(1)\n s = bytes(2)\n s[1] = 1\n bytes.append_bytes(b, s, 1)\n return b":            []byte{0, 1},
+
+		"b = bytes()\n bytes.append_int16_be(b, 258)\n return b":                             []byte{1, 2},
+		"b = bytes()\n bytes.append_int16_le(b, 258)\n return b":                             []byte{2, 1},
+		"b = bytes()\n bytes.append_int32_be(b, -2)\n return b":                              []byte{0xff, 0xff, 0xff, 0xfe},
+		"b = bytes(8)\n bytes.set_int64_le(b, 1, 1)\n return b":                              []byte{1, 0, 0, 0, 0, 0, 0, 0},
+		"b = bytes()\n bytes.append_int32_le(b, -2)\n return bytes.get_int32_le(b, 1)":       float64(-2),
+		"b = bytes()\n bytes.append_int16_be(b, 1)\n return bytes.get_int32_be(b, 1) == nil": true,
+
+		"b = bytes()\n return bytes.append_var_int(b, 300)":                                      float64(2),
+		"b = bytes()\n bytes.append_var_int(b, 300)\n return b":                                  []byte{0xac, 0x02},
+		"b = bytes()\n bytes.append_var_int(b, 300)\n v, n = bytes.get_var_int(b, 1)\n return v": float64(300),
+		"b = bytes(1)\n return bytes.set_var_int(b, 1, 300)":                                     float64(0),
+	}
+
+	// following expressions should return an error
+	errMatrix := []string{
+		"b = bytes(-1)",
+		"b = bytes(1)\n b[2] = 1",
+		"b = bytes(1)\n bytes.get_byte(b, 0)",
+		"b = bytes(1)\n bytes.size(b, 1)",
+		"b = bytes(1)\n s = bytes(1)\n bytes.append_bytes(b, s, 2)",
+	}
+
+	It("must run all code blocks", func() {
+		instance := LuaPool.Get().(*lua.LState)
+		defer instance.Close()
+		for source, expected := range testMatrix {
+
+			err := instance.DoString(source)
+			Expect(err).NotTo(HaveOccurred())
+
+			By(source)
+			Expect(LValueToInterface(instance.CheckAny(-1))).To(Equal(expected))
+			instance.Pop(1) // remove received value
+		}
+
+	})
+
+	It("must fail all code blocks", func() {
+		instance := LuaPool.Get().(*lua.LState)
+		defer instance.Close()
+		for _, source := range errMatrix {
+			By(source)
+
+			err := instance.DoString(source)
+			Expect(err).To(HaveOccurred())
+
+		}
+
+	})
+
+	It("must pass bytes and geojson values to Lua", func() {
+		instance := LuaPool.Get().(*lua.LState)
+		defer instance.Close()
+
+		instance.SetGlobal("b", NewValue(instance, []byte{1, 2}))
+		instance.SetGlobal("g", NewValue(instance, GeoJSON(`{"type":"Point"}`)))
+
+		err := instance.DoString("return bytes.size(b), geojson.tostring(g), tostring(geojson('{}'))")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(LValueToInterface(instance.Get(-3))).To(Equal(float64(2)))
+		Expect(LValueToInterface(instance.Get(-2))).To(Equal(`{"type":"Point"}`))
+		Expect(LValueToInterface(instance.Get(-1))).To(Equal("{}"))
+		Expect(LValueToInterface(instance.GetGlobal("g"))).To(Equal(GeoJSON(`{"type":"Point"}`)))
+	})
+
+})
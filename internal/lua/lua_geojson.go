@@ -0,0 +1,92 @@
+// Copyright 2013-2017 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lua
+
+import (
+	"github.com/yuin/gopher-lua"
+)
+
+// GeoJSON is the Go value of the geojson type of the UDF API.
+type GeoJSON string
+
+// LuaGeoJSON is the geojson type of the UDF API.
+type LuaGeoJSON struct {
+	s GeoJSON
+}
+
+const luaLuaGeoJSONTypeName = "LuaGeoJSON"
+
+// Registers the geojson type and the geojson package to given L.
+func registerLuaGeoJSONType(L *lua.LState) {
+	mt := L.NewTypeMetatable(luaLuaGeoJSONTypeName)
+
+	L.SetGlobal("geojson", mt)
+
+	// static attributes
+	L.SetField(mt, "__call", L.NewFunction(newLuaGeoJSON))
+	L.SetField(mt, "new", L.NewFunction(luaGeoJSONNew))
+	L.SetField(mt, "tostring", L.NewFunction(luaGeoJSONToString))
+
+	// methods
+	L.SetFuncs(mt, map[string]lua.LGFunction{
+		"__tostring": luaGeoJSONToString,
+	})
+
+	L.SetMetatable(mt, mt)
+}
+
+// NewLuaGeoJSON wraps a GeoJSON value to be passed to a UDF.
+func NewLuaGeoJSON(L *lua.LState, s GeoJSON) *lua.LUserData {
+	ud := L.NewUserData()
+	ud.Value = &LuaGeoJSON{s: s}
+	L.SetMetatable(ud, L.GetTypeMetatable(luaLuaGeoJSONTypeName))
+	return ud
+}
+
+// Constructor: geojson(str)
+func newLuaGeoJSON(L *lua.LState) int {
+	if L.GetTop() != 2 {
+		L.ArgError(1, "Only one argument expected for geojson constructor")
+		return 0
+	}
+	L.Push(NewLuaGeoJSON(L, GeoJSON(L.CheckString(2))))
+	return 1
+}
+
+// geojson.new(str)
+func luaGeoJSONNew(L *lua.LState) int {
+	if L.GetTop() != 1 {
+		L.ArgError(1, "Only one argument expected for geojson.new method")
+		return 0
+	}
+	L.Push(NewLuaGeoJSON(L, GeoJSON(L.CheckString(1))))
+	return 1
+}
+
+// Checks whether the lua argument is a *LUserData with *LuaGeoJSON and returns this *LuaGeoJSON.
+func checkLuaGeoJSON(L *lua.LState, arg int) *LuaGeoJSON {
+	ud := L.CheckUserData(arg)
+	if v, ok := ud.Value.(*LuaGeoJSON); ok {
+		return v
+	}
+	L.ArgError(arg, "geojson expected")
+	return nil
+}
+
+func luaGeoJSONToString(L *lua.LState) int {
+	p := checkLuaGeoJSON(L, 1)
+	L.Push(lua.LString(p.s))
+	return 1
+}
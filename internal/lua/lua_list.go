@@ -16,6 +16,8 @@ package lua
 
 import (
 	"fmt"
+	"sort"
+	"strings"
 
 	"github.com/yuin/gopher-lua"
 )
@@ -57,6 +59,7 @@ func registerLuaListType(L *lua.LState) {
 	L.SetField(mt, "concat", L.NewFunction(luaListConcat))
 	L.SetField(mt, "merge", L.NewFunction(luaListMerge))
 	L.SetField(mt, "iterator", L.NewFunction(luaListIterator))
+	L.SetField(mt, "sort", L.NewFunction(luaListSort))
 
 	// methods
 	L.SetFuncs(mt, map[string]lua.LGFunction{
@@ -151,6 +154,8 @@ func luaListRemove(L *lua.LState) int {
 	return 0
 }
 
+// luaListInsert inserts the value at the position. Positions past the end of
+// the list are filled with nils.
 func luaListInsert(L *lua.LState) int {
 	p := checkLuaList(L, 1)
 	if L.GetTop() != 3 {
@@ -160,27 +165,30 @@ func luaListInsert(L *lua.LState) int {
 	index := L.CheckInt(2)
 	value := LValueToInterface(L.CheckAny(3))
 
-	if cap(p.l) > len(p.l) {
-		for i := len(p.l); i >= index; i-- {
-			p.l[i] = p.l[i-1]
-		}
-		p.l[index-1] = value
-	} else {
-		ln := len(p.l) * 2
-		if ln > 256 {
-			ln = 256
-		}
-		newList := make([]interface{}, len(p.l)+1, ln)
+	if index < 1 {
+		L.ArgError(2, "index out of range for list#insert")
+		return 0
+	}
 
-		copy(newList, p.l[:index-1])
-		newList[index-1] = value
-		copy(newList[index:], p.l[index-1:len(p.l)])
-		p.l = newList
+	if index > len(p.l) {
+		p.extend(index - 1)
+		p.l = append(p.l, value)
+		return 0
 	}
 
+	p.l = append(p.l, nil)
+	copy(p.l[index:], p.l[index-1:])
+	p.l[index-1] = value
 	return 0
 }
 
+// extend grows the list to size with nils.
+func (p *LuaList) extend(size int) {
+	for len(p.l) < size {
+		p.l = append(p.l, nil)
+	}
+}
+
 func luaListAppend(L *lua.LState) int {
 	p := checkLuaList(L, 1)
 	if L.GetTop() != 2 {
@@ -230,6 +238,11 @@ func luaListTake(L *lua.LState) int {
 	}
 
 	count := L.CheckInt(2)
+	if count < 0 {
+		L.ArgError(2, "count must not be negative for take method")
+		return 0
+	}
+
 	items := p.l
 	if count <= len(p.l) {
 		items = p.l[:count]
@@ -251,6 +264,11 @@ func luaListDrop(L *lua.LState) int {
 	}
 
 	count := L.CheckInt(2)
+	if count < 0 {
+		L.ArgError(2, "count must not be negative for drop method")
+		return 0
+	}
+
 	var items []interface{}
 	if count < len(p.l) {
 		items = p.l[count:]
@@ -274,7 +292,14 @@ func luaListTrim(L *lua.LState) int {
 	}
 
 	count := L.CheckInt(2)
-	p.l = p.l[:count-1]
+	if count < 1 {
+		L.ArgError(2, "index out of range for list#trim")
+		return 0
+	}
+
+	if count <= len(p.l) {
+		p.l = p.l[:count-1]
+	}
 
 	return 0
 }
@@ -371,6 +396,12 @@ func luaListNewIndex(L *lua.LState) int {
 	index := L.CheckInt(2)
 	value := L.CheckAny(3)
 
+	if index < 1 {
+		L.ArgError(2, "index out of range for list")
+		return 0
+	}
+
+	ref.extend(index)
 	ref.l[index-1] = LValueToInterface(value)
 	return 0
 }
@@ -398,3 +429,88 @@ func luaListIterator(L *lua.LState) int {
 	L.Push(L.NewFunction(fn))
 	return 1
 }
+
+// luaListSorter sorts a list with a Lua comparison function, or in the natural
+// order of its values if there is none.
+type luaListSorter struct {
+	L  *lua.LState
+	l  []interface{}
+	fn *lua.LFunction
+}
+
+func (s *luaListSorter) Len() int      { return len(s.l) }
+func (s *luaListSorter) Swap(i, j int) { s.l[i], s.l[j] = s.l[j], s.l[i] }
+
+func (s *luaListSorter) Less(i, j int) bool {
+	if s.fn == nil {
+		return compareValues(s.l[i], s.l[j]) < 0
+	}
+
+	s.L.CallByParam(lua.P{Fn: s.fn, NRet: 1, Protect: false}, NewValue(s.L, s.l[i]), NewValue(s.L, s.l[j]))
+	ret := s.L.Get(-1)
+	s.L.Pop(1) // remove received value
+	return lua.LVAsBool(ret)
+}
+
+// luaListSort sorts the list in place, with an optional function returning true
+// if its first argument is less than the second, like table.sort.
+func luaListSort(L *lua.LState) int {
+	p := checkLuaList(L, 1)
+	if L.GetTop() < 1 || L.GetTop() > 2 {
+		L.ArgError(1, "Only one argument expected for list#sort method")
+		return 0
+	}
+
+	sorter := &luaListSorter{L: L, l: p.l}
+	if L.GetTop() == 2 {
+		sorter.fn = L.CheckFunction(2)
+	}
+
+	sort.Stable(sorter)
+	return 0
+}
+
+// compareValues orders values by type first: nil, booleans, numbers, strings,
+// and then other values, which are considered equal.
+func compareValues(a, b interface{}) int {
+	ra, rb := valueRank(a), valueRank(b)
+	if ra != rb {
+		return ra - rb
+	}
+
+	switch va := a.(type) {
+	case bool:
+		vb := b.(bool)
+		if va == vb {
+			return 0
+		} else if !va {
+			return -1
+		}
+		return 1
+	case float64:
+		vb := b.(float64)
+		if va < vb {
+			return -1
+		} else if va > vb {
+			return 1
+		}
+		return 0
+	case string:
+		return strings.Compare(va, b.(string))
+	}
+	return 0
+}
+
+func valueRank(v interface{}) int {
+	switch v.(type) {
+	case nil:
+		return 0
+	case bool:
+		return 1
+	case float64:
+		return 2
+	case string:
+		return 3
+	}
+	return 4
+}
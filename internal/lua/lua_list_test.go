@@ -44,9 +44,13 @@ var _ = Describe("Lua List API Test", func() {
 		"l = list.create(100)\n return list.size(l)": float64(0),
 		"l = list({1,2})\n return list.size(l)":      float64(2),
 
-		"l = list{1,2}\n list.insert(l, 1, 0)\n return l": []interface{}{float64(0), float64(1), float64(2)},
-		"l = list{1,2}\n list.insert(l, 2, 0)\n return l": []interface{}{float64(1), float64(0), float64(2)},
-		"l = list{1,2}\n list.insert(l, 3, 0)\n return l": []interface{}{float64(1), float64(2), float64(0)},
+		"l = list{1,2}\n list.insert(l, 1, 0)\n return l":                          []interface{}{float64(0), float64(1), float64(2)},
+		"l = list{1,2}\n list.insert(l, 2, 0)\n return l":                          []interface{}{float64(1), float64(0), float64(2)},
+		"l = list{1,2}\n list.insert(l, 3, 0)\n return l":                          []interface{}{float64(1), float64(2), float64(0)},
+		"l = list{1,2}\n list.insert(l, 4, 0)\n return l":                          []interface{}{float64(1), float64(2), nil, float64(0)},
+		"l = list.create(4)\n list.append(l, 1)\n list.insert(l, 1, 0)\n return l": []interface{}{float64(0), float64(1)},
+
+		"l = list{1}\n l[3] = 3\n return l": []interface{}{float64(1), nil, float64(3)},
 
 		"l = list{1,2}\n list.append(l, 3)\n return l":                    []interface{}{float64(1), float64(2), float64(3)},
 		"l = list{1,2}\n list.append(l, 3)\nlist.append(l, 4)\n return l": []interface{}{float64(1), float64(2), float64(3), float64(4)},
@@ -71,6 +75,11 @@ var _ = Describe("Lua List API Test", func() {
 
 		"l = list{1,2}\n list.trim(l, 1)\n return l": []interface{}{},
 		"l = list{1,2}\n list.trim(l, 2)\n return l": []interface{}{float64(1)},
+		"l = list{1,2}\n list.trim(l, 5)\n return l": []interface{}{float64(1), float64(2)},
+
+		"l = list{3,1,2}\n list.sort(l)\n return l":                                      []interface{}{float64(1), float64(2), float64(3)},
+		"l = list{'b',2,'a',true,1}\n list.sort(l)\n return l":                           []interface{}{true, float64(1), float64(2), "a", "b"},
+		"l = list{3,1,2}\n list.sort(l, function(a, b)\n return a > b\n end)\n return l": []interface{}{float64(3), float64(2), float64(1)},
 
 		"l = list{1,2}\n return list.clone(l)": []interface{}{float64(1), float64(2)},
 
@@ -88,6 +97,12 @@ var _ = Describe("Lua List API Test", func() {
 	// following expressions should return an error
 	errMatrix := []string{
 		"l = list{1,2}\n list.remove(l, 3)",
+		"l = list{1,2}\n list.insert(l, 0, 1)",
+		"l = list{1,2}\n list.trim(l, 0)",
+		"l = list{1,2}\n list.take(l, -1)",
+		"l = list{1,2}\n list.drop(l, -1)",
+		"l = list{1,2}\n l[0] = 1",
+		"l = list{1,2}\n list.sort(l, function(a, b)\n error('sort failed')\n end)",
 	}
 
 	It("must run all code blocks", func() {
@@ -189,7 +189,8 @@ func luaMapMerge(L *lua.LState) int {
 		newMap := &LuaMap{m: make(map[interface{}]interface{}, len(p.m)+len(sp.m))}
 		for k, v := range p.m {
 			if v2, exists := sp.m[k]; exists {
-				L.CallByParam(lua.P{Fn: fn, NRet: 1, Protect: true, Handler: nil}, NewValue(L, v), NewValue(L, v2))
+				// errors of fn are raised to the caller
+				L.CallByParam(lua.P{Fn: fn, NRet: 1, Protect: false}, NewValue(L, v), NewValue(L, v2))
 				ret := L.CheckAny(-1)
 				L.Pop(1) // remove received value
 				newMap.m[k] = LValueToInterface(ret)
@@ -296,24 +297,18 @@ func luaMapLen(L *lua.LState) int {
 func luaMapPairs(L *lua.LState) int {
 	ref := checkMap(L, 1)
 
-	// make an iterator
-	iter := make(chan *struct{ k, v interface{} })
-
-	go func() {
-		for k, v := range ref.m {
-			iter <- &struct{ k, v interface{} }{k, v}
-		}
-		close(iter)
-	}()
-
+	// make an iterator over a snapshot of the map
+	keys := ref.keys()
+	idx := 0
 	fn := func(L *lua.LState) int {
-		tuple := <-iter
-		if tuple == nil {
+		if idx >= len(keys) {
 			return 0
 		}
 
-		L.Push(NewValue(L, tuple.k))
-		L.Push(NewValue(L, tuple.v))
+		k := keys[idx]
+		idx++
+		L.Push(NewValue(L, k))
+		L.Push(NewValue(L, ref.m[k]))
 		return 2
 	}
 	L.Push(L.NewFunction(fn))
@@ -323,23 +318,16 @@ func luaMapPairs(L *lua.LState) int {
 func luaMapKeys(L *lua.LState) int {
 	ref := checkMap(L, 1)
 
-	// make an iterator
-	iter := make(chan interface{})
-
-	go func() {
-		for k := range ref.m {
-			iter <- k
-		}
-		close(iter)
-	}()
-
+	// make an iterator over a snapshot of the map
+	keys := ref.keys()
+	idx := 0
 	fn := func(L *lua.LState) int {
-		tuple := <-iter
-		if tuple == nil {
+		if idx >= len(keys) {
 			return 0
 		}
 
-		L.Push(NewValue(L, tuple))
+		L.Push(NewValue(L, keys[idx]))
+		idx++
 		return 1
 	}
 	L.Push(L.NewFunction(fn))
@@ -349,29 +337,35 @@ func luaMapKeys(L *lua.LState) int {
 func luaMapValues(L *lua.LState) int {
 	ref := checkMap(L, 1)
 
-	// make an iterator
-	iter := make(chan interface{})
-
-	go func() {
-		for _, v := range ref.m {
-			iter <- v
-		}
-		close(iter)
-	}()
+	// make an iterator over a snapshot of the map
+	values := make([]interface{}, 0, len(ref.m))
+	for _, v := range ref.m {
+		values = append(values, v)
+	}
 
+	idx := 0
 	fn := func(L *lua.LState) int {
-		tuple := <-iter
-		if tuple == nil {
+		if idx >= len(values) {
 			return 0
 		}
 
-		L.Push(NewValue(L, tuple))
+		L.Push(NewValue(L, values[idx]))
+		idx++
 		return 1
 	}
 	L.Push(L.NewFunction(fn))
 	return 1
 }
 
+// keys returns the keys of the map.
+func (p *LuaMap) keys() []interface{} {
+	keys := make([]interface{}, 0, len(p.m))
+	for k := range p.m {
+		keys = append(keys, k)
+	}
+	return keys
+}
+
 func luaMapEq(L *lua.LState) int {
 	map1 := checkMap(L, 1)
 	map2 := checkMap(L, 2)
@@ -380,10 +374,10 @@ func luaMapEq(L *lua.LState) int {
 }
 
 func checkMap(L *lua.LState, idx int) *LuaMap {
-	ud := L.CheckUserData(1)
+	ud := L.CheckUserData(idx)
 	if v, ok := ud.Value.(*LuaMap); ok {
 		return v
 	}
-	L.ArgError(1, "luaMap expected")
+	L.ArgError(idx, "luaMap expected")
 	return nil
 }
@@ -56,6 +56,12 @@ var _ = Describe("Lua Map API Test", func() {
 		"m1 = map{x=1,y=2}\n m2 = map{x=3,y=4}\n return map.merge(m1, m2, function(v1, v2)\n return v1 + v2\n end)": map[interface{}]interface{}{"x": float64(4), "y": float64(6)},
 	}
 
+	// following expressions should return an error
+	errMatrix := []string{
+		"m1 = map{x=1}\n m2 = map{x=2}\n return map.merge(m1, m2, function(v1, v2)\n error('merge failed')\n end)",
+		"m = map{x=1}\n return map.size(m, 1)",
+	}
+
 	It("must run all code blocks", func() {
 		instance := LuaPool.Get().(*lua.LState)
 		defer instance.Close()
@@ -71,4 +77,17 @@ var _ = Describe("Lua Map API Test", func() {
 
 	})
 
+	It("must fail all code blocks", func() {
+		instance := LuaPool.Get().(*lua.LState)
+		defer instance.Close()
+		for _, source := range errMatrix {
+			By(source)
+
+			err := instance.DoString(source)
+			Expect(err).To(HaveOccurred())
+
+		}
+
+	})
+
 })
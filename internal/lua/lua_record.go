@@ -0,0 +1,248 @@
+// Copyright 2013-2017 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lua
+
+import (
+	"fmt"
+
+	"github.com/yuin/gopher-lua"
+)
+
+// LuaRecord is the record passed to record UDFs.
+// Bin changes are only stored when the UDF calls aerospike:create or aerospike:update.
+type LuaRecord struct {
+	// bins as seen by the UDF
+	bins map[string]interface{}
+
+	// bins of the stored record
+	stored map[string]interface{}
+
+	exists     bool
+	generation uint32
+	ttl        uint32
+	pendingTTL *uint32
+
+	key     interface{}
+	setName string
+	digest  []byte
+
+	created, updated, removed bool
+}
+
+const luaLuaRecordTypeName = "LuaRecord"
+
+// NewLuaRecord creates a record for a record UDF. A nil bins map means the record does not exist.
+func NewLuaRecord(key interface{}, setName string, digest []byte, bins map[string]interface{}, generation, ttl uint32) *LuaRecord {
+	rec := &LuaRecord{
+		stored:     map[string]interface{}{},
+		exists:     bins != nil,
+		generation: generation,
+		ttl:        ttl,
+		key:        key,
+		setName:    setName,
+		digest:     digest,
+	}
+
+	for name, value := range bins {
+		rec.stored[name] = copyValue(value)
+	}
+	rec.bins = copyBins(rec.stored)
+	return rec
+}
+
+// Bins returns the bins of the stored record.
+func (rec *LuaRecord) Bins() map[string]interface{} { return rec.stored }
+
+// Exists returns true if the record exists.
+func (rec *LuaRecord) Exists() bool { return rec.exists }
+
+// Generation returns the generation of the stored record.
+func (rec *LuaRecord) Generation() uint32 { return rec.generation }
+
+// TTL returns the time to live of the stored record in seconds.
+func (rec *LuaRecord) TTL() uint32 { return rec.ttl }
+
+// Created returns true if the UDF created the record.
+func (rec *LuaRecord) Created() bool { return rec.created }
+
+// Updated returns true if the UDF updated the record.
+func (rec *LuaRecord) Updated() bool { return rec.updated }
+
+// Removed returns true if the UDF removed the record.
+func (rec *LuaRecord) Removed() bool { return rec.removed }
+
+// NewUserData wraps the record to be passed to a UDF.
+func (rec *LuaRecord) NewUserData(L *lua.LState) *lua.LUserData {
+	ud := L.NewUserData()
+	ud.Value = rec
+	L.SetMetatable(ud, L.GetTypeMetatable(luaLuaRecordTypeName))
+	return ud
+}
+
+// store saves the bins seen by the UDF to the stored record.
+func (rec *LuaRecord) store() {
+	rec.stored = copyBins(rec.bins)
+	rec.exists = true
+	rec.generation++
+	if rec.pendingTTL != nil {
+		rec.ttl = *rec.pendingTTL
+		rec.pendingTTL = nil
+	}
+}
+
+func (rec *LuaRecord) String() string {
+	return fmt.Sprintf("%v", rec.bins)
+}
+
+func copyBins(bins map[string]interface{}) map[string]interface{} {
+	res := make(map[string]interface{}, len(bins))
+	for name, value := range bins {
+		res[name] = copyValue(value)
+	}
+	return res
+}
+
+// copyValue copies lists and maps, which the UDF can change in place.
+func copyValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case []interface{}:
+		res := make([]interface{}, len(v))
+		for i := range v {
+			res[i] = copyValue(v[i])
+		}
+		return res
+	case map[interface{}]interface{}:
+		res := make(map[interface{}]interface{}, len(v))
+		for k, e := range v {
+			res[k] = copyValue(e)
+		}
+		return res
+	}
+	return value
+}
+
+// Registers the record type and the record package to given L.
+func registerLuaRecordType(L *lua.LState) {
+	mt := L.NewTypeMetatable(luaLuaRecordTypeName)
+
+	L.SetGlobal("record", mt)
+
+	// static attributes
+	L.SetField(mt, "ttl", L.NewFunction(luaRecordTTL))
+	L.SetField(mt, "gen", L.NewFunction(luaRecordGen))
+	L.SetField(mt, "set_ttl", L.NewFunction(luaRecordSetTTL))
+	L.SetField(mt, "bin_names", L.NewFunction(luaRecordBinNames))
+	L.SetField(mt, "numbins", L.NewFunction(luaRecordNumBins))
+	L.SetField(mt, "key", L.NewFunction(luaRecordKey))
+	L.SetField(mt, "setname", L.NewFunction(luaRecordSetName))
+	L.SetField(mt, "digest", L.NewFunction(luaRecordDigest))
+
+	// methods
+	L.SetFuncs(mt, map[string]lua.LGFunction{
+		"__index":    luaRecordIndex,
+		"__newindex": luaRecordNewIndex,
+		"__tostring": allToString,
+	})
+
+	L.SetMetatable(mt, mt)
+}
+
+// Checks whether the lua argument is a *LUserData with *LuaRecord and returns this *LuaRecord.
+func checkLuaRecord(L *lua.LState, arg int) *LuaRecord {
+	ud := L.CheckUserData(arg)
+	if v, ok := ud.Value.(*LuaRecord); ok {
+		return v
+	}
+	L.ArgError(arg, "record expected")
+	return nil
+}
+
+func luaRecordIndex(L *lua.LState) int {
+	rec := checkLuaRecord(L, 1)
+	name := L.CheckString(2)
+
+	L.Push(NewValue(L, rec.bins[name]))
+	return 1
+}
+
+func luaRecordNewIndex(L *lua.LState) int {
+	rec := checkLuaRecord(L, 1)
+	name := L.CheckString(2)
+	value := L.CheckAny(3)
+
+	if value == lua.LNil {
+		delete(rec.bins, name)
+	} else {
+		rec.bins[name] = LValueToInterface(value)
+	}
+	return 0
+}
+
+func luaRecordTTL(L *lua.LState) int {
+	rec := checkLuaRecord(L, 1)
+	L.Push(lua.LNumber(rec.ttl))
+	return 1
+}
+
+func luaRecordGen(L *lua.LState) int {
+	rec := checkLuaRecord(L, 1)
+	L.Push(lua.LNumber(rec.generation))
+	return 1
+}
+
+func luaRecordSetTTL(L *lua.LState) int {
+	rec := checkLuaRecord(L, 1)
+	ttl := uint32(L.CheckInt64(2))
+	rec.pendingTTL = &ttl
+	return 0
+}
+
+func luaRecordBinNames(L *lua.LState) int {
+	rec := checkLuaRecord(L, 1)
+	names := make([]interface{}, 0, len(rec.bins))
+	for name := range rec.bins {
+		names = append(names, name)
+	}
+	L.Push(NewValue(L, names))
+	return 1
+}
+
+func luaRecordNumBins(L *lua.LState) int {
+	rec := checkLuaRecord(L, 1)
+	L.Push(lua.LNumber(len(rec.bins)))
+	return 1
+}
+
+func luaRecordKey(L *lua.LState) int {
+	rec := checkLuaRecord(L, 1)
+	L.Push(NewValue(L, rec.key))
+	return 1
+}
+
+func luaRecordSetName(L *lua.LState) int {
+	rec := checkLuaRecord(L, 1)
+	L.Push(lua.LString(rec.setName))
+	return 1
+}
+
+func luaRecordDigest(L *lua.LState) int {
+	rec := checkLuaRecord(L, 1)
+	if rec.digest == nil {
+		L.Push(lua.LNil)
+	} else {
+		L.Push(lua.LString(rec.digest))
+	}
+	return 1
+}
@@ -0,0 +1,82 @@
+// Copyright 2013-2017 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lua_test
+
+import (
+	"github.com/yuin/gopher-lua"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/aerospike/aerospike-client-go/internal/lua"
+)
+
+var _ = Describe("Lua Record API Test", func() {
+
+	var instance *lua.LState
+
+	BeforeEach(func() {
+		instance = LuaPool.Get().(*lua.LState)
+	})
+
+	AfterEach(func() {
+		instance.Close()
+	})
+
+	run := func(rec *LuaRecord, source string) lua.LValue {
+		instance.SetGlobal("rec", rec.NewUserData(instance))
+		err := instance.DoString(source)
+		Expect(err).NotTo(HaveOccurred())
+		ret := instance.Get(-1)
+		instance.Pop(1)
+		return ret
+	}
+
+	It("must read bins and metadata", func() {
+		rec := NewLuaRecord(1, "set", nil, map[string]interface{}{"a": 1, "b": "x"}, 3, 100)
+		Expect(run(rec, "return rec.b")).To(Equal(lua.LString("x")))
+		Expect(run(rec, "return record.gen(rec)")).To(Equal(lua.LNumber(3)))
+		Expect(run(rec, "return record.ttl(rec)")).To(Equal(lua.LNumber(100)))
+		Expect(run(rec, "return record.numbins(rec)")).To(Equal(lua.LNumber(2)))
+		Expect(run(rec, "return record.setname(rec)")).To(Equal(lua.LString("set")))
+		Expect(run(rec, "return aerospike:exists(rec)")).To(Equal(lua.LTrue))
+	})
+
+	It("must store bin changes only on create or update", func() {
+		rec := NewLuaRecord(nil, "", nil, nil, 0, 0)
+		Expect(run(rec, "rec.a = 1; return aerospike:exists(rec)")).To(Equal(lua.LFalse))
+		Expect(rec.Exists()).To(BeFalse())
+		Expect(rec.Bins()).To(BeEmpty())
+
+		Expect(run(rec, "return aerospike:create(rec)")).To(Equal(lua.LNumber(0)))
+		Expect(rec.Created()).To(BeTrue())
+		Expect(rec.Generation()).To(Equal(uint32(1)))
+		Expect(rec.Bins()).To(Equal(map[string]interface{}{"a": float64(1)}))
+
+		Expect(run(rec, "return aerospike:create(rec)")).To(Equal(lua.LNumber(1)))
+
+		Expect(run(rec, "rec.a = nil; record.set_ttl(rec, 10); return aerospike:update(rec)")).To(Equal(lua.LNumber(0)))
+		Expect(rec.Updated()).To(BeTrue())
+		Expect(rec.Generation()).To(Equal(uint32(2)))
+		Expect(rec.TTL()).To(Equal(uint32(10)))
+		Expect(rec.Bins()).To(BeEmpty())
+
+		Expect(run(rec, "return aerospike:remove(rec)")).To(Equal(lua.LNumber(0)))
+		Expect(rec.Removed()).To(BeTrue())
+		Expect(rec.Exists()).To(BeFalse())
+		Expect(run(rec, "return aerospike:remove(rec)")).To(Equal(lua.LNumber(2)))
+	})
+
+})
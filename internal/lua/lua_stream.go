@@ -104,7 +104,7 @@ func luaStreamWrite(L *lua.LState) int {
 	}
 
 	p.s <- LValueToInterface(L.CheckAny(2))
-	return 1
+	return 0
 }
 
 func luaStreamReadable(L *lua.LState) int {
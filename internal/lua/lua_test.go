@@ -0,0 +1,77 @@
+// Copyright 2013-2017 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lua_test
+
+import (
+	"github.com/yuin/gopher-lua"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/aerospike/aerospike-client-go/internal/lua"
+	. "github.com/aerospike/aerospike-client-go/types"
+)
+
+var _ = Describe("Lua Errors Test", func() {
+
+	It("must return UDF errors with their stack trace", func() {
+		instance := LuaPool.Get().(*lua.LState)
+		defer instance.Close()
+
+		err := instance.DoString(`
+local function inner(rec)
+	error("inner failed")
+end
+
+function outer(rec)
+	inner(rec)
+end
+`)
+		Expect(err).NotTo(HaveOccurred())
+
+		rec := NewLuaRecord(nil, "", nil, nil, 0, 0)
+		err = instance.CallByParam(lua.P{
+			Fn:      instance.GetGlobal("apply_record"),
+			NRet:    1,
+			Protect: true,
+		}, instance.GetGlobal("outer"), rec.NewUserData(instance))
+		Expect(err).To(HaveOccurred())
+
+		err = NewError(err)
+		Expect(err.(AerospikeError).ResultCode()).To(Equal(UDF_BAD_RESPONSE))
+		Expect(err.Error()).To(ContainSubstring("inner failed"))
+
+		lerr, ok := err.(AerospikeError).Unwrap().(*LuaError)
+		Expect(ok).To(BeTrue())
+		Expect(lerr.Message).To(ContainSubstring("inner failed"))
+		Expect(lerr.Message).NotTo(ContainSubstring("stack traceback"))
+		Expect(lerr.StackTrace).To(HavePrefix("stack traceback:"))
+		Expect(lerr.StackTrace).To(ContainSubstring("inner"))
+		Expect(lerr.StackTrace).To(ContainSubstring(":7:"))
+	})
+
+	It("must return syntax errors", func() {
+		instance := LuaPool.Get().(*lua.LState)
+		defer instance.Close()
+
+		err := NewError(instance.DoString("function ("))
+		Expect(err.(AerospikeError).ResultCode()).To(Equal(UDF_BAD_RESPONSE))
+		_, ok := err.(AerospikeError).Unwrap().(*LuaError)
+		Expect(ok).To(BeTrue())
+
+		Expect(NewError(nil)).To(BeNil())
+	})
+
+})
@@ -54,6 +54,13 @@ end
 --
 -- ############################################################################
 
+--
+-- Adds the stack trace of where an error was raised in a UDF to its message.
+--
+function udf_traceback(err)
+    return ldebug.traceback(tostring(err), 2)
+end
+
 --
 -- Creates a new environment for use in apply_record functions
 --
@@ -66,6 +73,7 @@ function env_record()
         ["list"] = list,
         ["map"] = map,
         ["bytes"] = bytes,
+        ["geojson"] = geojson,
         ["aerospike"] = aerospike,
 
         ["putX"] = putX,
@@ -141,11 +149,13 @@ function apply_record(f, r, ...)
         sandboxed[f] = true
     end
 
-    success, result = pcall(f, r, ...)
+    local args = {...}
+    local n = select('#', ...)
+    local success, result = xpcall(function() return f(r, unpack(args, 1, n)) end, udf_traceback)
     if success then
         return result
     else
-        error(result, 2)
+        error(result, 0)
         return nil
     end
 end
@@ -172,14 +182,14 @@ function apply_stream(f, scope, istream, ostream, ...)
         sandboxed[f] = true
     end
 
-    local stream_ops = StreamOps_create();
-    success, result = pcall(f, stream_ops, ...)
-
-    -- info("apply_stream: success=%s, result=%s", tostring(success), tostring(result))
+    local args = {...}
+    local n = select('#', ...)
+    local success, result = xpcall(function()
+        local stream_ops = StreamOps_create();
+        local result = f(stream_ops, unpack(args, 1, n))
 
-    if success then
         local ops = StreamOps_select(result.ops, scope);
-        
+
         -- Apply server operations to the stream
         -- result => a stream_ops object
         local values = StreamOps_apply(stream_iterator(istream), ops);
@@ -190,11 +200,13 @@ function apply_stream(f, scope, istream, ostream, ...)
             -- info("value = %s", tostring(value))
             stream.write(ostream, value)
         end
+    end, udf_traceback)
 
+    if success then
         -- 0 is success
         return 0
     else
-        error(result, 2)
+        error(result, 0)
         return 2
     end
 end
@@ -41,69 +41,103 @@ func SetAerospikeTag(tag string) {
 	aerospikeTag = tag
 }
 
-func valueToInterface(f reflect.Value, clusterSupportsFloat bool) interface{} {
+func valueToInterface(f reflect.Value, clusterSupportsFloat bool) (interface{}, error) {
+	if encoded, exists, err := encodeField(f); exists || err != nil {
+		return encoded, err
+	}
+
 	// get to the core value
 	for f.Kind() == reflect.Ptr {
 		if f.IsNil() {
-			return nil
+			return nil, nil
 		}
 		f = reflect.Indirect(f)
+
+		if encoded, exists, err := encodeField(f); exists || err != nil {
+			return encoded, err
+		}
 	}
 
 	switch f.Kind() {
 	case reflect.Uint64:
-		return int64(f.Uint())
+		return int64(f.Uint()), nil
 	case reflect.Float64, reflect.Float32:
 		// support floats through integer encoding if
 		// server doesn't support floats
 		if clusterSupportsFloat {
-			return f.Float()
+			return f.Float(), nil
 		}
-		return int(math.Float64bits(f.Float()))
+		return int(math.Float64bits(f.Float())), nil
 
 	case reflect.Struct:
 		if f.Type().PkgPath() == "time" && f.Type().Name() == "Time" {
-			return f.Interface().(time.Time).UTC().UnixNano()
+			return f.Interface().(time.Time).UTC().UnixNano(), nil
 		}
 		return structToMap(f, clusterSupportsFloat)
 	case reflect.Bool:
 		if f.Bool() {
-			return int64(1)
+			return int64(1), nil
 		}
-		return int64(0)
+		return int64(0), nil
 	case reflect.Map:
 		if f.IsNil() {
-			return nil
+			return nil, nil
 		}
 
 		newMap := make(map[interface{}]interface{}, f.Len())
 		for _, mk := range f.MapKeys() {
-			newMap[valueToInterface(mk, clusterSupportsFloat)] = valueToInterface(f.MapIndex(mk), clusterSupportsFloat)
+			k, err := valueToInterface(mk, clusterSupportsFloat)
+			if err != nil {
+				return nil, err
+			}
+			v, err := valueToInterface(f.MapIndex(mk), clusterSupportsFloat)
+			if err != nil {
+				return nil, err
+			}
+			newMap[k] = v
 		}
 
-		return newMap
+		return newMap, nil
 	case reflect.Slice, reflect.Array:
 		if f.Kind() == reflect.Slice && f.IsNil() {
-			return nil
+			return nil, nil
 		}
 		if f.Kind() == reflect.Slice && reflect.TypeOf(f.Interface()).Elem().Kind() == reflect.Uint8 {
 			// handle blobs
-			return f.Interface().([]byte)
+			return f.Interface().([]byte), nil
 		}
 		// convert to primitives recursively
 		newSlice := make([]interface{}, f.Len(), f.Cap())
 		for i := 0; i < len(newSlice); i++ {
-			newSlice[i] = valueToInterface(f.Index(i), clusterSupportsFloat)
+			v, err := valueToInterface(f.Index(i), clusterSupportsFloat)
+			if err != nil {
+				return nil, err
+			}
+			newSlice[i] = v
 		}
-		return newSlice
+		return newSlice, nil
 	case reflect.Interface:
 		if f.IsNil() {
-			return nil
+			return nil, nil
 		}
-		return f.Interface()
+		return f.Interface(), nil
 	default:
-		return f.Interface()
+		return f.Interface(), nil
+	}
+}
+
+// encodeField converts the value with the codec registered for its type.
+// It returns false if no codec is registered for the type.
+func encodeField(f reflect.Value) (interface{}, bool, error) {
+	if !hasValueCodecs() || !f.CanInterface() || valueCodecFor(f.Type()) == nil {
+		return nil, false, nil
+	}
+
+	encoded, _, err := encodeValue(f.Interface())
+	if err != nil {
+		return nil, true, err
 	}
+	return encoded, true, nil
 }
 
 func fieldIsMetadata(f reflect.StructField) bool {
@@ -111,23 +145,53 @@ func fieldIsMetadata(f reflect.StructField) bool {
 	return strings.Trim(meta, " ") != ""
 }
 
-func fieldAlias(f reflect.StructField) string {
-	alias := f.Tag.Get(aerospikeTag)
-	if alias != "" {
-		alias = strings.Trim(alias, " ")
+// parseTag splits a bin tag into the bin name and the omitempty option,
+// like `as:"name,omitempty"`.
+func parseTag(tag string) (name string, omitEmpty bool) {
+	name = strings.Trim(tag, " ")
+	if i := strings.Index(name, ","); i >= 0 {
+		omitEmpty = strings.Trim(name[i+1:], " ") == "omitempty"
+		name = strings.Trim(name[:i], " ")
+	}
+	return name, omitEmpty
+}
 
+// fieldAlias returns the bin name of the field, and if the bin is omitted
+// when the field has its zero value.
+func fieldAlias(f reflect.StructField) (string, bool) {
+	alias, omitEmpty := parseTag(f.Tag.Get(aerospikeTag))
+	if alias != "" {
 		// if tag is -, the field should not be persisted
 		if alias == "-" {
-			return ""
+			return "", false
 		}
-		return alias
+		return alias, omitEmpty
 	}
-	return f.Name
+	return f.Name, omitEmpty
 }
 
-func structToMap(s reflect.Value, clusterSupportsFloat bool) map[string]interface{} {
+// isEmptyValue returns true for the values omitted by the omitempty option.
+func isEmptyValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Bool:
+		return !v.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int() == 0
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return v.Uint() == 0
+	case reflect.Float32, reflect.Float64:
+		return v.Float() == 0
+	case reflect.Interface, reflect.Ptr:
+		return v.IsNil()
+	}
+	return false
+}
+
+func structToMap(s reflect.Value, clusterSupportsFloat bool) (map[string]interface{}, error) {
 	if !s.IsValid() {
-		return nil
+		return nil, nil
 	}
 
 	typeOfT := s.Type()
@@ -145,12 +209,15 @@ func structToMap(s reflect.Value, clusterSupportsFloat bool) map[string]interfac
 		}
 
 		// skip transiet fields tagged `-`
-		alias := fieldAlias(typeOfT.Field(i))
-		if alias == "" {
+		alias, omitEmpty := fieldAlias(typeOfT.Field(i))
+		if alias == "" || (omitEmpty && isEmptyValue(s.Field(i))) {
 			continue
 		}
 
-		binValue := valueToInterface(s.Field(i), clusterSupportsFloat)
+		binValue, err := valueToInterface(s.Field(i), clusterSupportsFloat)
+		if err != nil {
+			return nil, err
+		}
 
 		if binMap == nil {
 			binMap = make(map[string]interface{}, numFields)
@@ -159,16 +226,20 @@ func structToMap(s reflect.Value, clusterSupportsFloat bool) map[string]interfac
 		binMap[alias] = binValue
 	}
 
-	return binMap
+	return binMap, nil
 }
 
-func marshal(v interface{}, clusterSupportsFloat bool) []*Bin {
+func marshal(v interface{}, clusterSupportsFloat bool) ([]*Bin, error) {
 	s := indirect(reflect.ValueOf(v))
+	n, err := structToMap(s, clusterSupportsFloat)
+	if err != nil {
+		return nil, err
+	}
+
 	numFields := s.NumField()
 	bins := binPool.Get(numFields).([]*Bin)
 
 	binCount := 0
-	n := structToMap(s, clusterSupportsFloat)
 	for k, v := range n {
 		bins[binCount].Name = k
 
@@ -176,7 +247,7 @@ func marshal(v interface{}, clusterSupportsFloat bool) []*Bin {
 		binCount++
 	}
 
-	return bins[:binCount]
+	return bins[:binCount], nil
 }
 
 type syncMap struct {
@@ -283,7 +354,7 @@ func cacheObjectTags(objType reflect.Type) {
 			continue
 		}
 
-		tag := strings.Trim(f.Tag.Get(aerospikeTag), " ")
+		tag, _ := parseTag(f.Tag.Get(aerospikeTag))
 		tagM := strings.Trim(f.Tag.Get(aerospikeMetaTag), " ")
 
 		if tag != "" && tagM != "" {
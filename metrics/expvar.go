@@ -0,0 +1,31 @@
+// Copyright 2013-2017 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import "expvar"
+
+// Publish exports the snapshots of the source as an expvar variable with the given name.
+// A new snapshot is taken every time the variable is read.
+// Like expvar.Publish, it panics if the name is already registered.
+func Publish(name string, source Source) {
+	expvar.Publish(name, Var(source))
+}
+
+// Var returns an expvar variable holding the snapshots of the source.
+func Var(source Source) expvar.Var {
+	return expvar.Func(func() interface{} {
+		return source.Snapshot()
+	})
+}
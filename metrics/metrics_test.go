@@ -0,0 +1,178 @@
+// Copyright 2013-2017 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics_test
+
+import (
+	"encoding/json"
+	"time"
+
+	as "github.com/aerospike/aerospike-client-go"
+	"github.com/aerospike/aerospike-client-go/metrics"
+	. "github.com/aerospike/aerospike-client-go/types"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+// fakeCluster is a Source with a canned snapshot
+type fakeCluster struct {
+	snapshot *metrics.Snapshot
+}
+
+func (fc *fakeCluster) Snapshot() *metrics.Snapshot {
+	return fc.snapshot
+}
+
+func newFakeCluster() *fakeCluster {
+	node := &as.NodeStats{
+		Commands: map[as.CommandType]*as.CommandStats{
+			as.CMD_READ: {
+				Attempts:     3,
+				Errors:       1,
+				Timeouts:     1,
+				TotalLatency: 6 * time.Millisecond,
+				ResultCodes:  map[ResultCode]int64{OK: 2, TIMEOUT: 1},
+				Latency:      &as.LatencyHistogram{Shift: 1, Buckets: []int64{1, 1, 1}},
+			},
+		},
+	}
+	node.ConnectionsOpen = 5
+	node.TendsTotal = 10
+
+	departed := &as.NodeStats{}
+	departed.NodeRemoved = 1
+
+	return &fakeCluster{
+		snapshot: &metrics.Snapshot{
+			Stats: &as.ClientStats{
+				Nodes: map[string]*as.NodeStats{
+					"10.0.0.1:3000": node,
+					"10.0.0.2:3000": departed,
+				},
+				Cluster: node,
+			},
+			Nodes:   []metrics.NodeInfo{{Name: "BB9000000000001", Host: *as.NewHost("10.0.0.1", 3000)}},
+			Seeds:   []as.Host{*as.NewHost("seed.example.com", 3000)},
+			Aliases: map[string]string{"10.0.0.1:3000": "BB9000000000001", "192.168.0.1:3000": "BB9000000000001"},
+		},
+	}
+}
+
+// gather collects the metrics of the collector by name
+func gather(collector prometheus.Collector) map[string]*dto.MetricFamily {
+	registry := prometheus.NewPedanticRegistry()
+	Expect(registry.Register(collector)).To(Succeed())
+
+	families, err := registry.Gather()
+	Expect(err).ToNot(HaveOccurred())
+
+	res := map[string]*dto.MetricFamily{}
+	for _, family := range families {
+		res[family.GetName()] = family
+	}
+	return res
+}
+
+func labels(metric *dto.Metric) map[string]string {
+	res := map[string]string{}
+	for _, label := range metric.GetLabel() {
+		res[label.GetName()] = label.GetValue()
+	}
+	return res
+}
+
+var _ = Describe("Metrics", func() {
+
+	Context("Prometheus collector", func() {
+
+		It("must export the node statistics labeled by node", func() {
+			families := gather(metrics.NewCollector(newFakeCluster()))
+
+			open := families["aerospike_client_connections_open"].GetMetric()
+			Expect(len(open)).To(Equal(2))
+			for _, metric := range open {
+				switch labels(metric)["host"] {
+				case "10.0.0.1:3000":
+					Expect(labels(metric)["node"]).To(Equal("BB9000000000001"))
+					Expect(metric.GetGauge().GetValue()).To(Equal(5.0))
+				case "10.0.0.2:3000":
+					Expect(labels(metric)["node"]).To(Equal(""))
+					Expect(metric.GetGauge().GetValue()).To(Equal(0.0))
+				default:
+					Fail("unexpected host")
+				}
+			}
+
+			removed := families["aerospike_client_node_removed_total"].GetMetric()
+			Expect(len(removed)).To(Equal(2))
+		})
+
+		It("must export the command statistics", func() {
+			families := gather(metrics.NewCollector(newFakeCluster()))
+
+			attempts := families["aerospike_client_command_attempts_total"].GetMetric()
+			Expect(len(attempts)).To(Equal(1))
+			Expect(labels(attempts[0])).To(Equal(map[string]string{"node": "BB9000000000001", "host": "10.0.0.1:3000", "type": "read"}))
+			Expect(attempts[0].GetCounter().GetValue()).To(Equal(3.0))
+
+			Expect(len(families["aerospike_client_command_results_total"].GetMetric())).To(Equal(2))
+
+			histogram := families["aerospike_client_command_latency_seconds"].GetMetric()[0].GetHistogram()
+			Expect(histogram.GetSampleCount()).To(Equal(uint64(3)))
+			Expect(histogram.GetSampleSum()).To(BeNumerically("~", 0.006))
+			buckets := histogram.GetBucket()
+			Expect(len(buckets)).To(Equal(2))
+			Expect(buckets[0].GetUpperBound()).To(Equal(0.001))
+			Expect(buckets[0].GetCumulativeCount()).To(Equal(uint64(1)))
+			Expect(buckets[1].GetUpperBound()).To(Equal(0.002))
+			Expect(buckets[1].GetCumulativeCount()).To(Equal(uint64(2)))
+		})
+
+		It("must export the topology of the cluster", func() {
+			families := gather(metrics.NewCollector(newFakeCluster()))
+
+			Expect(families["aerospike_client_cluster_nodes"].GetMetric()[0].GetGauge().GetValue()).To(Equal(1.0))
+			Expect(families["aerospike_client_cluster_seeds"].GetMetric()[0].GetGauge().GetValue()).To(Equal(1.0))
+
+			seeds := families["aerospike_client_cluster_seed_info"].GetMetric()
+			Expect(labels(seeds[0])["host"]).To(Equal("seed.example.com:3000"))
+
+			aliases := families["aerospike_client_node_alias_info"].GetMetric()
+			Expect(len(aliases)).To(Equal(2))
+			for _, metric := range aliases {
+				Expect(labels(metric)["node"]).To(Equal("BB9000000000001"))
+			}
+		})
+	})
+
+	Context("expvar variable", func() {
+
+		It("must encode the snapshot as JSON", func() {
+			v := metrics.Var(newFakeCluster())
+
+			var res map[string]interface{}
+			Expect(json.Unmarshal([]byte(v.String()), &res)).To(Succeed())
+			Expect(res["aliases"]).To(HaveKeyWithValue("192.168.0.1:3000", "BB9000000000001"))
+
+			nodes := res["stats"].(map[string]interface{})["nodes"].(map[string]interface{})
+			node := nodes["10.0.0.1:3000"].(map[string]interface{})
+			Expect(node["open-connections"]).To(Equal(5.0))
+			Expect(node["commands"]).To(HaveKey("read"))
+		})
+	})
+})
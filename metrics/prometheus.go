@@ -0,0 +1,178 @@
+// Copyright 2013-2017 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import (
+	"strconv"
+
+	as "github.com/aerospike/aerospike-client-go"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const namespace = "aerospike_client"
+
+// nodeLabels identify the node of a metric. Nodes which have left the cluster
+// are exported with an empty node name.
+var nodeLabels = []string{"node", "host"}
+
+// nodeCounter is a metric taken from the statistics of a node.
+type nodeCounter struct {
+	desc      *prometheus.Desc
+	valueType prometheus.ValueType
+	value     func(stats *as.NodeStats) int64
+}
+
+func newNodeCounter(name, help string, valueType prometheus.ValueType, value func(stats *as.NodeStats) int64) nodeCounter {
+	return nodeCounter{
+		desc:      prometheus.NewDesc(prometheus.BuildFQName(namespace, "", name), help, nodeLabels, nil),
+		valueType: valueType,
+		value:     value,
+	}
+}
+
+var nodeCounters = []nodeCounter{
+	newNodeCounter("connections_open", "Number of open connections to the node.", prometheus.GaugeValue,
+		func(stats *as.NodeStats) int64 { return stats.ConnectionsOpen }),
+	newNodeCounter("connections_attempts_total", "Number of attempts to open a connection to the node.", prometheus.CounterValue,
+		func(stats *as.NodeStats) int64 { return stats.ConnectionsAttempts }),
+	newNodeCounter("connections_successful_total", "Number of connections successfully opened to the node.", prometheus.CounterValue,
+		func(stats *as.NodeStats) int64 { return stats.ConnectionsSuccessful }),
+	newNodeCounter("connections_failed_total", "Number of failed connections to the node.", prometheus.CounterValue,
+		func(stats *as.NodeStats) int64 { return stats.ConnectionsFailed }),
+	newNodeCounter("connections_pool_empty_total", "Number of times the connection pool of the node was empty.", prometheus.CounterValue,
+		func(stats *as.NodeStats) int64 { return stats.ConnectionsPoolEmpty }),
+	newNodeCounter("tends_total", "Number of tends of the node.", prometheus.CounterValue,
+		func(stats *as.NodeStats) int64 { return stats.TendsTotal }),
+	newNodeCounter("tends_successful_total", "Number of successful tends of the node.", prometheus.CounterValue,
+		func(stats *as.NodeStats) int64 { return stats.TendsSuccessful }),
+	newNodeCounter("tends_failed_total", "Number of failed tends of the node.", prometheus.CounterValue,
+		func(stats *as.NodeStats) int64 { return stats.TendsFailed }),
+	newNodeCounter("partition_map_updates_total", "Number of partition map updates from the node.", prometheus.CounterValue,
+		func(stats *as.NodeStats) int64 { return stats.PartitionMapUpdates }),
+	newNodeCounter("node_added_total", "Number of times the node was added to the cluster.", prometheus.CounterValue,
+		func(stats *as.NodeStats) int64 { return stats.NodeAdded }),
+	newNodeCounter("node_removed_total", "Number of times the node was removed from the cluster.", prometheus.CounterValue,
+		func(stats *as.NodeStats) int64 { return stats.NodeRemoved }),
+}
+
+var (
+	commandLabels = []string{"node", "host", "type"}
+
+	commandAttemptsDesc = prometheus.NewDesc(prometheus.BuildFQName(namespace, "command", "attempts_total"),
+		"Number of command attempts sent to the node.", commandLabels, nil)
+	commandErrorsDesc = prometheus.NewDesc(prometheus.BuildFQName(namespace, "command", "errors_total"),
+		"Number of command attempts that failed.", commandLabels, nil)
+	commandTimeoutsDesc = prometheus.NewDesc(prometheus.BuildFQName(namespace, "command", "timeouts_total"),
+		"Number of command attempts that timed out.", commandLabels, nil)
+	commandResultsDesc = prometheus.NewDesc(prometheus.BuildFQName(namespace, "command", "results_total"),
+		"Number of command attempts by result code.", []string{"node", "host", "type", "result_code"}, nil)
+	commandLatencyDesc = prometheus.NewDesc(prometheus.BuildFQName(namespace, "command", "latency_seconds"),
+		"Latency of command attempts.", commandLabels, nil)
+
+	clusterNodesDesc = prometheus.NewDesc(prometheus.BuildFQName(namespace, "cluster", "nodes"),
+		"Number of active nodes in the cluster.", nil, nil)
+	clusterSeedsDesc = prometheus.NewDesc(prometheus.BuildFQName(namespace, "cluster", "seeds"),
+		"Number of seed hosts of the cluster.", nil, nil)
+	seedInfoDesc = prometheus.NewDesc(prometheus.BuildFQName(namespace, "cluster", "seed_info"),
+		"Seed hosts of the cluster. The value is always 1.", []string{"host"}, nil)
+	aliasInfoDesc = prometheus.NewDesc(prometheus.BuildFQName(namespace, "node", "alias_info"),
+		"Addresses the nodes are known by. The value is always 1.", []string{"node", "alias"}, nil)
+)
+
+// Collector is a prometheus.Collector exporting the statistics and the topology of a cluster.
+// All metrics are labeled with the node name and host address.
+type Collector struct {
+	source Source
+}
+
+// NewCollector returns a Collector for the source.
+func NewCollector(source Source) *Collector {
+	return &Collector{source: source}
+}
+
+// Describe implements the prometheus.Collector interface.
+func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
+	for _, counter := range nodeCounters {
+		ch <- counter.desc
+	}
+
+	ch <- commandAttemptsDesc
+	ch <- commandErrorsDesc
+	ch <- commandTimeoutsDesc
+	ch <- commandResultsDesc
+	ch <- commandLatencyDesc
+
+	ch <- clusterNodesDesc
+	ch <- clusterSeedsDesc
+	ch <- seedInfoDesc
+	ch <- aliasInfoDesc
+}
+
+// Collect implements the prometheus.Collector interface.
+func (c *Collector) Collect(ch chan<- prometheus.Metric) {
+	snapshot := c.source.Snapshot()
+
+	ch <- prometheus.MustNewConstMetric(clusterNodesDesc, prometheus.GaugeValue, float64(len(snapshot.Nodes)))
+	ch <- prometheus.MustNewConstMetric(clusterSeedsDesc, prometheus.GaugeValue, float64(len(snapshot.Seeds)))
+	for _, seed := range snapshot.Seeds {
+		ch <- prometheus.MustNewConstMetric(seedInfoDesc, prometheus.GaugeValue, 1, seed.String())
+	}
+	for alias, name := range snapshot.Aliases {
+		ch <- prometheus.MustNewConstMetric(aliasInfoDesc, prometheus.GaugeValue, 1, name, alias)
+	}
+
+	if snapshot.Stats == nil {
+		return
+	}
+
+	names := snapshot.nodeNames()
+	for host, stats := range snapshot.Stats.Nodes {
+		name := names[host]
+
+		for _, counter := range nodeCounters {
+			ch <- prometheus.MustNewConstMetric(counter.desc, counter.valueType, float64(counter.value(stats)), name, host)
+		}
+
+		for cmdType, cmdStats := range stats.Commands {
+			collectCommandStats(ch, name, host, string(cmdType), cmdStats)
+		}
+	}
+}
+
+func collectCommandStats(ch chan<- prometheus.Metric, name, host, cmdType string, stats *as.CommandStats) {
+	ch <- prometheus.MustNewConstMetric(commandAttemptsDesc, prometheus.CounterValue, float64(stats.Attempts), name, host, cmdType)
+	ch <- prometheus.MustNewConstMetric(commandErrorsDesc, prometheus.CounterValue, float64(stats.Errors), name, host, cmdType)
+	ch <- prometheus.MustNewConstMetric(commandTimeoutsDesc, prometheus.CounterValue, float64(stats.Timeouts), name, host, cmdType)
+
+	for code, count := range stats.ResultCodes {
+		ch <- prometheus.MustNewConstMetric(commandResultsDesc, prometheus.CounterValue, float64(count), name, host, cmdType, strconv.Itoa(int(code)))
+	}
+
+	if stats.Latency == nil || len(stats.Latency.Buckets) == 0 {
+		return
+	}
+
+	// prometheus buckets are cumulative; the last bucket is +Inf, which is implied by the count
+	buckets := make(map[float64]uint64, len(stats.Latency.Buckets)-1)
+	var count uint64
+	for i, n := range stats.Latency.Buckets {
+		count += uint64(n)
+		if bound := stats.Latency.UpperBound(i); bound > 0 {
+			buckets[bound.Seconds()] = count
+		}
+	}
+	ch <- prometheus.MustNewConstHistogram(commandLatencyDesc, count, stats.TotalLatency.Seconds(), buckets, name, host, cmdType)
+}
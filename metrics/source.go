@@ -0,0 +1,93 @@
+// Copyright 2013-2017 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package metrics exports the statistics and the topology of an Aerospike cluster
+// as Prometheus metrics and as an expvar variable.
+//
+// Example:
+//
+//  source := metrics.NewClientSource(client)
+//  prometheus.MustRegister(metrics.NewCollector(source))
+//  metrics.Publish("aerospike", source)
+package metrics
+
+import (
+	as "github.com/aerospike/aerospike-client-go"
+)
+
+// NodeInfo identifies an active node of the cluster.
+type NodeInfo struct {
+	Name string  `json:"name"`
+	Host as.Host `json:"host"`
+}
+
+// Snapshot is the state of a cluster at a point in time.
+type Snapshot struct {
+	// Stats holds the statistics of the client, per node host address.
+	Stats *as.ClientStats `json:"stats"`
+
+	// Nodes lists the active nodes of the cluster.
+	Nodes []NodeInfo `json:"nodes"`
+
+	// Seeds lists the seed hosts of the cluster.
+	Seeds []as.Host `json:"seeds"`
+
+	// Aliases maps the addresses the nodes are known by to the node names.
+	Aliases map[string]string `json:"aliases"`
+}
+
+// nodeNames maps the host addresses of the active nodes to their names.
+func (s *Snapshot) nodeNames() map[string]string {
+	res := make(map[string]string, len(s.Nodes))
+	for _, node := range s.Nodes {
+		res[node.Host.String()] = node.Name
+	}
+	return res
+}
+
+// Source provides snapshots of a cluster.
+// Use NewClientSource for a client, or implement it to export a fake cluster in tests.
+type Source interface {
+	Snapshot() *Snapshot
+}
+
+type clientSource struct {
+	client *as.Client
+}
+
+// NewClientSource returns a Source for the cluster the client is connected to.
+func NewClientSource(client *as.Client) Source {
+	return &clientSource{client: client}
+}
+
+// Snapshot implements the Source interface.
+func (cs *clientSource) Snapshot() *Snapshot {
+	cluster := cs.client.Cluster()
+
+	res := &Snapshot{
+		Stats:   cs.client.StatsSnapshot(),
+		Seeds:   cluster.GetSeeds(),
+		Aliases: map[string]string{},
+	}
+
+	for _, node := range cluster.GetNodes() {
+		res.Nodes = append(res.Nodes, NodeInfo{Name: node.GetName(), Host: *node.GetHost()})
+	}
+
+	for alias, node := range cluster.GetAliases() {
+		res.Aliases[alias.String()] = node.GetName()
+	}
+
+	return res
+}
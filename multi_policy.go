@@ -32,6 +32,17 @@ type MultiPolicy struct {
 	// If the queue is full, the producer goroutines will block until records are consumed.
 	RecordQueueSize int //= 5000
 
+	// MaxRecords is the maximum number of records returned by the scan or query.
+	// The records are requested evenly from the nodes; records that the nodes
+	// return over the maximum are discarded by the client.
+	// Default (0) is to return all records.
+	MaxRecords int64
+
+	// RecordsPerSecond limits the number of records each node returns per second.
+	// The limit is applied by the server.
+	// Default (0) is no limit.
+	RecordsPerSecond int
+
 	// Indicates if bin data is retrieved. If false, only record digests are retrieved.
 	IncludeBinData bool //= true;
 
@@ -15,6 +15,7 @@
 package aerospike
 
 import (
+	"context"
 	"strconv"
 	"strings"
 	"sync"
@@ -38,6 +39,12 @@ type Node struct {
 	aliases atomic.Value //[]*Host
 	stats   nodeStats
 
+	// racks maps namespaces to the rack of the node, if ClientPolicy.RackAware is set
+	racks atomic.Value //map[string]int
+
+	// latency is the moving average of the latency of single record commands on the node
+	latency latencyAverage
+
 	// tendConn reserves a connection for tend so that it won't have to
 	// wait in queue for connections, since that will cause starvation
 	// and the node being dropped under load.
@@ -48,9 +55,15 @@ type Node struct {
 	peersCount      AtomicInt
 
 	connections     connectionQueue //AtomicQueue //ArrayBlockingQueue<*Connection>
+	asyncPipeline   asyncPipeline
 	connectionCount AtomicInt
 	health          AtomicInt //AtomicInteger
 
+	// pipeline is the connection single record commands are pipelined on,
+	// if ClientPolicy.PipelineDepth is positive
+	pipeline     *connectionPipeline
+	pipelineLock sync.Mutex
+
 	partitionMap        partitionMap
 	partitionGeneration AtomicInt
 	referenceCount      AtomicInt
@@ -60,6 +73,7 @@ type Node struct {
 	active AtomicBool
 
 	supportsFloat, supportsBatchIndex, supportsReplicasAll, supportsGeo, supportsPeers AtomicBool
+	supportsPartitionScan, supportsPartitionQuery                                      AtomicBool
 }
 
 // NewNode initializes a server node with connection parameters.
@@ -74,6 +88,7 @@ func newNode(cluster *Cluster, nv *nodeValidator) *Node {
 		// by IP address (not hostname).
 		connections:         *newConnectionQueue(cluster.clientPolicy.ConnectionQueueSize), //*NewAtomicQueue(cluster.clientPolicy.ConnectionQueueSize),
 		connectionCount:     *NewAtomicInt(0),
+		asyncPipeline:       newAsyncPipeline(cluster.clientPolicy.AsyncMaxCommandsPerNode),
 		peersGeneration:     *NewAtomicInt(-1),
 		partitionGeneration: *NewAtomicInt(-2),
 		referenceCount:      *NewAtomicInt(0),
@@ -86,9 +101,14 @@ func newNode(cluster *Cluster, nv *nodeValidator) *Node {
 		supportsReplicasAll: *NewAtomicBool(nv.supportsReplicasAll),
 		supportsGeo:         *NewAtomicBool(nv.supportsGeo),
 		supportsPeers:       *NewAtomicBool(nv.supportsPeers),
+
+		supportsPartitionScan:  *NewAtomicBool(nv.supportsPartitionScan),
+		supportsPartitionQuery: *NewAtomicBool(nv.supportsPartitionQuery),
 	}
 
 	newNode.aliases.Store(nv.aliases)
+	newNode.racks.Store(map[string]int(nil))
+	newNode.stats.commands = newCommandStats(cluster.clientPolicy.LatencyColumns, cluster.clientPolicy.LatencyShift)
 
 	// this will reset to zero on first aggregation on the cluster,
 	// therefore will only be counted once.
@@ -111,7 +131,12 @@ func (nd *Node) Refresh(peers *peers) error {
 	nd.referenceCount.Set(0)
 
 	if peers.usePeers.Get() {
-		infoMap, err := nd.RequestInfo("node", "peers-generation", "partition-generation")
+		commands := []string{"node", "peers-generation", "partition-generation"}
+		if nd.cluster.clientPolicy.RackAware {
+			commands = append(commands, "racks:")
+		}
+
+		infoMap, err := nd.RequestInfo(commands...)
 		if err != nil {
 			nd.refreshFailed(err)
 			return err
@@ -131,8 +156,13 @@ func (nd *Node) Refresh(peers *peers) error {
 			nd.refreshFailed(err)
 			return err
 		}
+
+		nd.updateRacks(infoMap)
 	} else {
 		commands := []string{"node", "partition-generation", nd.cluster.clientPolicy.serviceString()}
+		if nd.cluster.clientPolicy.RackAware {
+			commands = append(commands, "racks:")
+		}
 
 		infoMap, err := nd.RequestInfo(commands...)
 		if err != nil {
@@ -154,6 +184,8 @@ func (nd *Node) Refresh(peers *peers) error {
 			nd.refreshFailed(err)
 			return err
 		}
+
+		nd.updateRacks(infoMap)
 	}
 	nd.failures.Set(0)
 	peers.refreshCount.IncrementAndGet()
@@ -211,6 +243,57 @@ func (nd *Node) verifyPartitionGeneration(infoMap map[string]string) error {
 	return nil
 }
 
+// updateRacks sets the racks of the node from the response to "racks:", which lists
+// the nodes on each rack for every namespace:
+//
+//  ns=test:rack_1=BB9020011AC4202,BB9030011AC4202:rack_2=BB9040011AC4202;ns=bar:...
+//
+// The racks are not changed if the response is missing, like for older servers.
+func (nd *Node) updateRacks(infoMap map[string]string) {
+	racksString, exists := infoMap["racks:"]
+	if !exists || strings.HasPrefix(racksString, "ERROR") {
+		return
+	}
+
+	racks := make(map[string]int)
+	for _, nsRacks := range strings.Split(racksString, ";") {
+		namespace := ""
+		for _, field := range strings.Split(nsRacks, ":") {
+			kv := strings.SplitN(field, "=", 2)
+			if len(kv) != 2 {
+				continue
+			}
+
+			if kv[0] == "ns" {
+				namespace = kv[1]
+				continue
+			}
+
+			if !strings.HasPrefix(kv[0], "rack_") {
+				continue
+			}
+			rack, err := strconv.Atoi(strings.TrimPrefix(kv[0], "rack_"))
+			if err != nil {
+				Logger.Warn("Node %s: invalid rack in racks info: `%s`", nd, field)
+				continue
+			}
+			for _, name := range strings.Split(kv[1], ",") {
+				if name == nd.name {
+					racks[namespace] = rack
+				}
+			}
+		}
+	}
+	nd.racks.Store(racks)
+}
+
+// hasRack returns true if the node is on the rack for the namespace.
+func (nd *Node) hasRack(namespace string, rack int) bool {
+	racks := nd.racks.Load().(map[string]int)
+	r, exists := racks[namespace]
+	return exists && r == rack
+}
+
 func (nd *Node) addFriends(infoMap map[string]string, peers *peers) error {
 	friendString, exists := infoMap[nd.cluster.clientPolicy.serviceString()]
 
@@ -381,13 +464,18 @@ CL:
 // If no pooled connection is available, a new connection will be created.
 // This method does not include logic to retry in case the connection pool is empty
 func (nd *Node) getConnection(timeout time.Duration) (conn *Connection, err error) {
-	return nd.getConnectionWithHint(timeout, 0)
+	return nd.getConnectionWithHint(context.Background(), timeout, 0)
 }
 
 // getConnectionWithHint gets a connection to the node.
 // If no pooled connection is available, a new connection will be created.
-// This method does not include logic to retry in case the connection pool is empty
-func (nd *Node) getConnectionWithHint(timeout time.Duration, hint byte) (conn *Connection, err error) {
+// This method does not include logic to retry in case the connection pool is empty.
+// If the context is done, no connection will be acquired or established.
+func (nd *Node) getConnectionWithHint(ctx context.Context, timeout time.Duration, hint byte) (conn *Connection, err error) {
+	if err = ctx.Err(); err != nil {
+		return nil, err
+	}
+
 	// try to get a valid connection from the connection pool
 	for t := nd.connections.Poll(hint); t != nil; t = nd.connections.Poll(hint) {
 		conn = t //.(*Connection)
@@ -409,7 +497,7 @@ func (nd *Node) getConnectionWithHint(timeout time.Duration, hint byte) (conn *C
 		}
 
 		atomic.AddInt64(&nd.stats.ConnectionsAttempts, 1)
-		if conn, err = NewSecureConnection(&nd.cluster.clientPolicy, nd.host); err != nil {
+		if conn, err = newSecureConnection(ctx, &nd.cluster.clientPolicy, nd.host); err != nil {
 			nd.connectionCount.DecrementAndGet()
 			atomic.AddInt64(&nd.stats.ConnectionsFailed, 1)
 			return nil, err
@@ -442,6 +530,29 @@ func (nd *Node) getConnectionWithHint(timeout time.Duration, hint byte) (conn *C
 	return conn, nil
 }
 
+// getPipeline returns the pipelined connection of the node.
+// A new connection is established if the current one is broken or idle.
+func (nd *Node) getPipeline(ctx context.Context, timeout time.Duration) (*connectionPipeline, error) {
+	nd.pipelineLock.Lock()
+	defer nd.pipelineLock.Unlock()
+
+	if nd.pipeline != nil {
+		if !nd.pipeline.isBroken() && !nd.pipeline.isIdle() {
+			return nd.pipeline, nil
+		}
+		nd.pipeline.close()
+		nd.pipeline = nil
+	}
+
+	conn, err := nd.getConnectionWithHint(ctx, timeout, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	nd.pipeline = newConnectionPipeline(conn, nd.cluster.clientPolicy.PipelineDepth, nd.cluster.clientPolicy.IdleTimeout)
+	return nd.pipeline, nil
+}
+
 // PutConnection puts back a connection to the pool.
 // If connection pool is full, the connection will be
 // closed and discarded.
@@ -515,6 +626,14 @@ func (nd *Node) String() string {
 }
 
 func (nd *Node) closeConnections() {
+	// close the pipelined connection
+	nd.pipelineLock.Lock()
+	if nd.pipeline != nil {
+		nd.pipeline.close()
+		nd.pipeline = nil
+	}
+	nd.pipelineLock.Unlock()
+
 	for conn := nd.connections.Poll(0); conn != nil; conn = nd.connections.Poll(0) {
 		// conn.(*Connection).Close()
 		conn.Close()
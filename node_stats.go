@@ -31,6 +31,8 @@ type nodeStats struct {
 	PartitionMapUpdates   int64 `json:"partition-map-updates"`
 	NodeAdded             int64 `json:"node-added-count"`
 	NodeRemoved           int64 `json:"node-removed-count"`
+
+	commands *commandStats
 }
 
 // latest returns the latest values to be used in aggregation and then resets the values
@@ -47,6 +49,8 @@ func (ns *nodeStats) getAndReset() *nodeStats {
 		PartitionMapUpdates:   atomic.SwapInt64(&ns.PartitionMapUpdates, 0),
 		NodeAdded:             atomic.SwapInt64(&ns.NodeAdded, 0),
 		NodeRemoved:           atomic.SwapInt64(&ns.NodeRemoved, 0),
+
+		commands: ns.commands.getAndReset(),
 	}
 }
 
@@ -64,6 +68,8 @@ func (ns *nodeStats) clone() nodeStats {
 		PartitionMapUpdates:   atomic.LoadInt64(&ns.PartitionMapUpdates),
 		NodeAdded:             atomic.LoadInt64(&ns.NodeAdded),
 		NodeRemoved:           atomic.LoadInt64(&ns.NodeRemoved),
+
+		commands: ns.commands.clone(),
 	}
 }
 
@@ -79,4 +85,38 @@ func (ns *nodeStats) aggregate(newStats *nodeStats) {
 	atomic.AddInt64(&ns.PartitionMapUpdates, newStats.PartitionMapUpdates)
 	atomic.AddInt64(&ns.NodeAdded, newStats.NodeAdded)
 	atomic.AddInt64(&ns.NodeRemoved, newStats.NodeRemoved)
+
+	if ns.commands == nil {
+		ns.commands = newStats.commands.clone()
+	} else {
+		ns.commands.aggregate(newStats.commands)
+	}
+}
+
+// export converts the statistics to their public form.
+func (ns *nodeStats) export() *NodeStats {
+	res := &NodeStats{nodeStats: ns.clone()}
+	res.Commands = res.commands.export()
+	res.commands = nil
+	return res
+}
+
+// NodeStats holds the client's statistics of a node:
+// connection and tend counters, and the statistics of each type of command.
+type NodeStats struct {
+	nodeStats
+
+	// Commands holds the statistics of each command type sent to the node.
+	Commands map[CommandType]*CommandStats `json:"commands"`
+}
+
+// ClientStats is a snapshot of the client's statistics.
+// Node statistics are aggregated once per tend.
+type ClientStats struct {
+	// Nodes holds the statistics of each node by its host address,
+	// including the nodes which have left the cluster.
+	Nodes map[string]*NodeStats `json:"nodes"`
+
+	// Cluster aggregates the statistics of all nodes.
+	Cluster *NodeStats `json:"cluster-aggregated-stats"`
 }
@@ -50,6 +50,7 @@ type nodeValidator struct {
 	primaryHost *Host
 
 	supportsFloat, supportsBatchIndex, supportsReplicasAll, supportsGeo, supportsPeers bool
+	supportsPartitionScan, supportsPartitionQuery                                      bool
 }
 
 func (ndv *nodeValidator) seedNodes(cluster *Cluster, host *Host, nodesToAdd *nodesToAddT) error {
@@ -206,6 +207,10 @@ func (ndv *nodeValidator) setFeatures(features string) {
 			ndv.supportsGeo = true
 		case "peers":
 			ndv.supportsPeers = true
+		case "pscans":
+			ndv.supportsPartitionScan = true
+		case "pquery":
+			ndv.supportsPartitionQuery = true
 		}
 	}
 }
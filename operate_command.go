@@ -44,6 +44,10 @@ func (cmd *operateCommand) getNode(ifc command) (*Node, error) {
 	return cmd.cluster.getMasterNode(&cmd.partition)
 }
 
+func (cmd *operateCommand) commandType() CommandType {
+	return CMD_OPERATE
+}
+
 func (cmd *operateCommand) Execute() error {
 	return cmd.execute(cmd)
 }
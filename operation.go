@@ -37,6 +37,10 @@ var (
 	APPEND     OperationType = &struct{ op byte }{9}
 	PREPEND    OperationType = &struct{ op byte }{10}
 	TOUCH      OperationType = &struct{ op byte }{11}
+	BIT_READ   OperationType = &struct{ op byte }{12}
+	BIT_MODIFY OperationType = &struct{ op byte }{13}
+	HLL_READ   OperationType = &struct{ op byte }{15}
+	HLL_MODIFY OperationType = &struct{ op byte }{16}
 )
 
 // Operation contains operation definition.
@@ -56,6 +60,9 @@ type Operation struct {
 	// binValue (Optional) determines bin value used in operation.
 	binValue Value
 
+	// ctx (Optional) selects the nested element of list and map operations.
+	ctx []*CDTContext
+
 	// will be true ONLY for GetHeader() operation
 	headerOnly bool
 
@@ -78,6 +85,29 @@ func (op *Operation) cache() error {
 	return nil
 }
 
+// Type returns the type of the operation.
+// CDT operations return CDT_READ or CDT_MODIFY, or MAP_READ or MAP_MODIFY for maps.
+func (op *Operation) Type() OperationType {
+	return op.opType
+}
+
+// BinName returns the name of the bin the operation applies to.
+// It is empty for operations on the whole record.
+func (op *Operation) BinName() string {
+	return op.binName
+}
+
+// BinValue returns the value of the operation.
+// The value of CDT operations holds their arguments.
+func (op *Operation) BinValue() Value {
+	return op.binValue
+}
+
+// HeaderOnly returns true if the operation only reads the header of the record.
+func (op *Operation) HeaderOnly() bool {
+	return op.headerOnly
+}
+
 // GetOpForBin creates read bin database operation.
 func GetOpForBin(binName string) *Operation {
 	return &Operation{opType: READ, binName: binName, binValue: NewNullValue()}
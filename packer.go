@@ -179,6 +179,11 @@ func PackBytes(cmd BufferEx, b []byte) (int, error) {
 }
 
 func __PackBytes(cmd BufferEx, b []byte) (int, error) {
+	return __PackParticleBytes(cmd, ParticleType.BLOB, b)
+}
+
+// __PackParticleBytes packs the bytes of a particle, prefixed by its type.
+func __PackParticleBytes(cmd BufferEx, particleType int, b []byte) (int, error) {
 	size := 0
 	n, err := __PackByteArrayBegin(cmd, len(b)+1)
 	if err != nil {
@@ -186,7 +191,7 @@ func __PackBytes(cmd BufferEx, b []byte) (int, error) {
 	}
 	size += n
 
-	n, err = __PackAByte(cmd, ParticleType.BLOB)
+	n, err = __PackAByte(cmd, byte(particleType))
 	if err != nil {
 		return size + n, err
 	}
@@ -212,6 +217,13 @@ func __PackByteArrayBegin(cmd BufferEx, length int) (int, error) {
 }
 
 func __PackObject(cmd BufferEx, obj interface{}, mapKey bool) (int, error) {
+	if encoded, exists, err := encodeValue(obj); exists {
+		if err != nil {
+			return 0, err
+		}
+		return __PackObject(cmd, encoded, mapKey)
+	}
+
 	switch v := obj.(type) {
 	case Value:
 		return v.pack(cmd)
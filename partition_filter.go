@@ -0,0 +1,278 @@
+// Copyright 2013-2017 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aerospike
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	. "github.com/aerospike/aerospike-client-go/types"
+)
+
+// partitionCursorVersion is the version of the encoding of partition filters.
+const partitionCursorVersion = 1
+
+// Partition states in the encoded cursor.
+const (
+	_CURSOR_PENDING byte = iota
+	_CURSOR_PENDING_AFTER_DIGEST
+	_CURSOR_DONE
+)
+
+// PartitionStatus describes the progress of a partition in a partition scan or query.
+type PartitionStatus struct {
+	// Id is the partition id.
+	Id int
+
+	// Digest is the digest of the last record received from the partition.
+	// The partition is resumed after this digest. It is nil if no record has been received.
+	Digest []byte
+
+	// Done is true if all records of the partition have been received.
+	Done bool
+}
+
+// String implements the Stringer interface.
+func (ps *PartitionStatus) String() string {
+	return fmt.Sprintf("%d:%v:%x", ps.Id, ps.Done, ps.Digest)
+}
+
+// PartitionFilter determines the partitions a scan or query reads, and tracks
+// the progress of each partition while the command is running.
+//
+// A partition scan or query survives cluster changes: partitions that could not be
+// read are retried on the node that owns them. When the command fails or is
+// interrupted, the same filter can be passed to a new command to read the rest
+// of the records. The filter can also be saved with MarshalBinary to resume later,
+// possibly in another process, and divided with Split to share the work between workers.
+//
+// A filter must not be used by more than one command at the same time, and its
+// progress must not be read until the recordset of the command is done.
+// Records in the queue of a recordset that is closed before they are read are
+// considered received, and will not be returned on resume.
+type PartitionFilter struct {
+	begin int
+	count int
+
+	// partitions is nil if the range is invalid
+	partitions []*PartitionStatus
+}
+
+// NewPartitionFilterAll creates a filter to read all partitions.
+func NewPartitionFilterAll() *PartitionFilter {
+	return newPartitionFilter(0, _PARTITIONS)
+}
+
+// NewPartitionFilterById creates a filter to read one partition.
+func NewPartitionFilterById(partitionId int) *PartitionFilter {
+	return newPartitionFilter(partitionId, 1)
+}
+
+// NewPartitionFilterByRange creates a filter to read count partitions, starting from begin.
+func NewPartitionFilterByRange(begin, count int) *PartitionFilter {
+	return newPartitionFilter(begin, count)
+}
+
+// NewPartitionFilterAfterKey creates a filter to read the records of the
+// partition of the key, which come after the key's digest.
+func NewPartitionFilterAfterKey(key *Key) *PartitionFilter {
+	pf := newPartitionFilter(newPartitionByKey(key).PartitionId, 1)
+	pf.partitions[0].Digest = append([]byte(nil), key.digest[:]...)
+	return pf
+}
+
+// newPartitionFilter creates a filter for the range. The partitions are only
+// allocated if the range is valid; otherwise validate returns the error.
+func newPartitionFilter(begin, count int) *PartitionFilter {
+	pf := &PartitionFilter{begin: begin, count: count}
+	if begin < 0 || begin >= _PARTITIONS || count <= 0 || count > _PARTITIONS-begin {
+		return pf
+	}
+
+	pf.partitions = make([]*PartitionStatus, 0, count)
+	for i := 0; i < count; i++ {
+		pf.partitions = append(pf.partitions, &PartitionStatus{Id: begin + i})
+	}
+	return pf
+}
+
+// validate checks the range of the filter.
+// The filter is required, since it tracks the progress of the partitions.
+func (pf *PartitionFilter) validate() error {
+	if pf == nil {
+		return NewAerospikeError(PARAMETER_ERROR, "Partition filter is required. See `NewPartitionFilterAll`")
+	}
+
+	if pf.begin < 0 || pf.begin >= _PARTITIONS {
+		return NewAerospikeError(PARAMETER_ERROR, fmt.Sprintf("Invalid partition begin %d. Valid range: 0-%d", pf.begin, _PARTITIONS-1))
+	}
+
+	if pf.count <= 0 {
+		return NewAerospikeError(PARAMETER_ERROR, fmt.Sprintf("Invalid partition count %d", pf.count))
+	}
+
+	if pf.count > _PARTITIONS-pf.begin {
+		return NewAerospikeError(PARAMETER_ERROR, fmt.Sprintf("Invalid partition range (%d,%d)", pf.begin, pf.count))
+	}
+	return nil
+}
+
+// Begin returns the id of the first partition of the filter.
+func (pf *PartitionFilter) Begin() int {
+	return pf.begin
+}
+
+// Count returns the number of partitions in the filter.
+func (pf *PartitionFilter) Count() int {
+	return len(pf.partitions)
+}
+
+// Partitions returns a copy of the progress of the partitions of the filter.
+func (pf *PartitionFilter) Partitions() []PartitionStatus {
+	res := make([]PartitionStatus, len(pf.partitions))
+	for i, ps := range pf.partitions {
+		res[i] = *ps
+	}
+	return res
+}
+
+// IsDone returns true if all the records of all partitions in the filter have been received.
+func (pf *PartitionFilter) IsDone() bool {
+	for _, ps := range pf.partitions {
+		if !ps.Done {
+			return false
+		}
+	}
+	return true
+}
+
+// Split divides the filter into at most n filters of consecutive partitions,
+// which keep the progress of the partitions. The filters can be run concurrently,
+// for example by different processes.
+func (pf *PartitionFilter) Split(n int) []*PartitionFilter {
+	count := len(pf.partitions)
+	if n > count {
+		n = count
+	}
+	if n < 1 {
+		n = 1
+	}
+
+	res := make([]*PartitionFilter, 0, n)
+	offset := 0
+	for i := 0; i < n; i++ {
+		size := count / n
+		if i < count%n {
+			size++
+		}
+
+		part := &PartitionFilter{
+			begin:      pf.begin + offset,
+			count:      size,
+			partitions: make([]*PartitionStatus, 0, size),
+		}
+		for _, ps := range pf.partitions[offset : offset+size] {
+			cp := *ps
+			part.partitions = append(part.partitions, &cp)
+		}
+		res = append(res, part)
+		offset += size
+	}
+	return res
+}
+
+// MarshalBinary encodes the filter and the progress of its partitions as a cursor
+// which can be stored and decoded with UnmarshalBinary to resume the scan or query.
+// It implements the encoding.BinaryMarshaler interface.
+func (pf *PartitionFilter) MarshalBinary() ([]byte, error) {
+	if err := pf.validate(); err != nil {
+		return nil, err
+	}
+
+	buf := make([]byte, 5, 5+len(pf.partitions))
+	buf[0] = partitionCursorVersion
+	binary.BigEndian.PutUint16(buf[1:], uint16(pf.begin))
+	binary.BigEndian.PutUint16(buf[3:], uint16(len(pf.partitions)))
+
+	for _, ps := range pf.partitions {
+		switch {
+		case ps.Done:
+			buf = append(buf, _CURSOR_DONE)
+		case len(ps.Digest) == int(_DIGEST_SIZE):
+			buf = append(buf, _CURSOR_PENDING_AFTER_DIGEST)
+			buf = append(buf, ps.Digest...)
+		default:
+			buf = append(buf, _CURSOR_PENDING)
+		}
+	}
+	return buf, nil
+}
+
+// UnmarshalBinary decodes a cursor encoded by MarshalBinary into the filter.
+// It implements the encoding.BinaryUnmarshaler interface.
+func (pf *PartitionFilter) UnmarshalBinary(data []byte) error {
+	if len(data) < 5 {
+		return NewAerospikeError(PARSE_ERROR, "Invalid partition cursor: too short")
+	}
+
+	if data[0] != partitionCursorVersion {
+		return NewAerospikeError(PARSE_ERROR, fmt.Sprintf("Invalid partition cursor version %d", data[0]))
+	}
+
+	res := newPartitionFilter(int(binary.BigEndian.Uint16(data[1:])), int(binary.BigEndian.Uint16(data[3:])))
+	if err := res.validate(); err != nil {
+		return err
+	}
+
+	offset := 5
+	for _, ps := range res.partitions {
+		if offset >= len(data) {
+			return NewAerospikeError(PARSE_ERROR, "Invalid partition cursor: too short")
+		}
+
+		state := data[offset]
+		offset++
+
+		switch state {
+		case _CURSOR_PENDING:
+		case _CURSOR_DONE:
+			ps.Done = true
+		case _CURSOR_PENDING_AFTER_DIGEST:
+			if offset+int(_DIGEST_SIZE) > len(data) {
+				return NewAerospikeError(PARSE_ERROR, "Invalid partition cursor: too short")
+			}
+			ps.Digest = append([]byte(nil), data[offset:offset+int(_DIGEST_SIZE)]...)
+			offset += int(_DIGEST_SIZE)
+		default:
+			return NewAerospikeError(PARSE_ERROR, fmt.Sprintf("Invalid partition cursor state %d", state))
+		}
+	}
+
+	if offset != len(data) {
+		return NewAerospikeError(PARSE_ERROR, "Invalid partition cursor: too long")
+	}
+
+	*pf = *res
+	return nil
+}
+
+// NewPartitionFilterFromCursor decodes a cursor encoded by MarshalBinary.
+func NewPartitionFilterFromCursor(cursor []byte) (*PartitionFilter, error) {
+	pf := &PartitionFilter{}
+	if err := pf.UnmarshalBinary(cursor); err != nil {
+		return nil, err
+	}
+	return pf, nil
+}
@@ -0,0 +1,190 @@
+// Copyright 2013-2017 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aerospike
+
+import (
+	"context"
+	"math"
+	"time"
+
+	. "github.com/aerospike/aerospike-client-go/types"
+	. "github.com/aerospike/aerospike-client-go/types/atomic"
+
+	. "github.com/onsi/ginkgo"
+	gm "github.com/onsi/gomega"
+)
+
+var _ = Describe("Partition filter test", func() {
+
+	It("must select the partitions", func() {
+		gm.Expect(NewPartitionFilterAll().Count()).To(gm.Equal(_PARTITIONS))
+
+		pf := NewPartitionFilterById(17)
+		gm.Expect(pf.Begin()).To(gm.Equal(17))
+		gm.Expect(pf.Count()).To(gm.Equal(1))
+
+		pf = NewPartitionFilterByRange(100, 10)
+		gm.Expect(pf.Begin()).To(gm.Equal(100))
+		gm.Expect(pf.Partitions()[9].Id).To(gm.Equal(109))
+
+		key, _ := NewKey("test", "set", 1)
+		pf = NewPartitionFilterAfterKey(key)
+		gm.Expect(pf.Begin()).To(gm.Equal(NewPartitionByKey(key).PartitionId))
+		gm.Expect(pf.Partitions()[0].Digest).To(gm.Equal(key.Digest()))
+
+		gm.Expect(NewPartitionFilterByRange(4000, 100).validate()).To(gm.HaveOccurred())
+		gm.Expect(NewPartitionFilterByRange(-1, 1).validate()).To(gm.HaveOccurred())
+		gm.Expect(NewPartitionFilterByRange(0, 0).validate()).To(gm.HaveOccurred())
+
+		// invalid ranges are reported by validate, without allocating the partitions
+		gm.Expect(NewPartitionFilterByRange(0, -1).validate()).To(gm.HaveOccurred())
+		huge := NewPartitionFilterByRange(1, math.MaxInt32)
+		gm.Expect(huge.validate()).To(gm.HaveOccurred())
+		gm.Expect(huge.Count()).To(gm.Equal(0))
+
+		var nilFilter *PartitionFilter
+		err := nilFilter.validate()
+		gm.Expect(err).To(gm.HaveOccurred())
+		gm.Expect(err.(AerospikeError).ResultCode()).To(gm.Equal(PARAMETER_ERROR))
+	})
+
+	It("must split the partitions and keep their progress", func() {
+		pf := NewPartitionFilterByRange(10, 10)
+		pf.partitions[0].Done = true
+		pf.partitions[9].Digest = make([]byte, _DIGEST_SIZE)
+
+		parts := pf.Split(3)
+		gm.Expect(len(parts)).To(gm.Equal(3))
+		gm.Expect(parts[0].Begin()).To(gm.Equal(10))
+		gm.Expect(parts[0].Count()).To(gm.Equal(4))
+		gm.Expect(parts[1].Begin()).To(gm.Equal(14))
+		gm.Expect(parts[2].Begin()).To(gm.Equal(17))
+		gm.Expect(parts[2].Count()).To(gm.Equal(3))
+		gm.Expect(parts[0].Partitions()[0].Done).To(gm.BeTrue())
+		gm.Expect(parts[2].Partitions()[2].Digest).To(gm.HaveLen(int(_DIGEST_SIZE)))
+
+		// the parts do not share the progress of the filter
+		parts[1].partitions[0].Done = true
+		gm.Expect(pf.partitions[4].Done).To(gm.BeFalse())
+
+		gm.Expect(NewPartitionFilterById(1).Split(5)).To(gm.HaveLen(1))
+	})
+
+	It("must encode and decode the cursor", func() {
+		pf := NewPartitionFilterByRange(200, 3)
+		pf.partitions[0].Done = true
+		pf.partitions[1].Digest = []byte("01234567890123456789")
+
+		cursor, err := pf.MarshalBinary()
+		gm.Expect(err).ToNot(gm.HaveOccurred())
+
+		res, err := NewPartitionFilterFromCursor(cursor)
+		gm.Expect(err).ToNot(gm.HaveOccurred())
+		gm.Expect(res.Partitions()).To(gm.Equal(pf.Partitions()))
+
+		_, err = NewPartitionFilterFromCursor(cursor[:len(cursor)-1])
+		gm.Expect(err).To(gm.HaveOccurred())
+		gm.Expect(err.(AerospikeError).ResultCode()).To(gm.Equal(PARSE_ERROR))
+
+		_, err = NewPartitionFilterFromCursor(append(cursor, 0))
+		gm.Expect(err).To(gm.HaveOccurred())
+
+		cursor[0] = 2
+		_, err = NewPartitionFilterFromCursor(cursor)
+		gm.Expect(err).To(gm.HaveOccurred())
+	})
+
+	It("must track the progress of the partitions of a node", func() {
+		pf := NewPartitionFilterAll()
+		key, _ := NewKey("test", "set", 1)
+		id := NewPartitionByKey(key).PartitionId
+
+		np := newNodePartitions(nil)
+		for _, ps := range pf.partitions {
+			np.add(ps)
+		}
+		gm.Expect(np.counts()).To(gm.Equal(_PARTITIONS))
+
+		np.recordReceived(key)
+		pids, digests := np.counts()
+		gm.Expect(pids).To(gm.Equal(_PARTITIONS - 1))
+		gm.Expect(digests).To(gm.Equal(1))
+		gm.Expect(pf.partitions[id].Digest).To(gm.Equal(key.Digest()))
+
+		np.partitionDone(id)
+		gm.Expect(pf.partitions[id].Done).To(gm.BeTrue())
+		gm.Expect(pf.IsDone()).To(gm.BeFalse())
+	})
+
+	It("must retry rounds as the retry policy allows, until the context is done", func() {
+		cluster := &Cluster{clientPolicy: *NewClientPolicy()}
+		node := &Node{cluster: cluster, name: "A", active: *NewAtomicBool(true)}
+		cluster.nodes = NewSyncVal([]*Node{node})
+		masters := make([]*Node, _PARTITIONS)
+		masters[0] = node
+		cluster.partitionWriteMap.Store(partitionMap{"test": [][]*Node{masters}})
+
+		newTracker := func(policy *BasePolicy) *partitionTracker {
+			return &partitionTracker{
+				filter:    NewPartitionFilterByRange(0, 1),
+				namespace: "test",
+				policy:    policy,
+				recordset: newRecordset(1, 1, 0),
+				supported: func(node *Node) bool { return true },
+			}
+		}
+
+		rounds := 0
+		failRound := func(np *nodePartitions) error {
+			rounds++
+			return NewAerospikeError(SERVER_NOT_AVAILABLE)
+		}
+
+		policy := NewPolicy()
+		policy.RetryPolicy = NewExponentialBackoff(2, time.Millisecond, 0)
+		err := newTracker(policy).run(context.Background(), cluster, failRound)
+		gm.Expect(err.(AerospikeError).ResultCode()).To(gm.Equal(SERVER_NOT_AVAILABLE))
+		gm.Expect(rounds).To(gm.Equal(3))
+
+		// the wait before the next round ends with the context
+		policy = NewPolicy()
+		policy.SleepBetweenRetries = time.Hour
+		ctx, cancel := context.WithCancel(context.Background())
+		time.AfterFunc(50*time.Millisecond, cancel)
+
+		rounds = 0
+		start := time.Now()
+		err = newTracker(policy).run(ctx, cluster, failRound)
+		gm.Expect(err).To(gm.Equal(context.Canceled))
+		gm.Expect(rounds).To(gm.Equal(1))
+		gm.Expect(time.Since(start)).To(gm.BeNumerically("<", time.Second))
+	})
+
+	It("must share the maximum records between the nodes", func() {
+		gm.Expect(newRecordLimiter(0, 3)).To(gm.BeNil())
+		gm.Expect((*recordLimiter)(nil).take()).To(gm.BeTrue())
+
+		rl := newRecordLimiter(10, 3)
+		gm.Expect(rl.nodeMax).To(gm.Equal(int64(4)))
+		for i := 0; i < 10; i++ {
+			gm.Expect(rl.take()).To(gm.BeTrue())
+		}
+		gm.Expect(rl.take()).To(gm.BeFalse())
+		gm.Expect(rl.remaining()).To(gm.Equal(int64(0)))
+
+		gm.Expect(recordShare(7, 1)).To(gm.Equal(int64(7)))
+		gm.Expect(recordShare(1, 4)).To(gm.Equal(int64(1)))
+	})
+})
@@ -0,0 +1,293 @@
+// Copyright 2013-2017 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aerospike
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	. "github.com/aerospike/aerospike-client-go/types"
+)
+
+// nodePartitions holds the partitions of a filter assigned to a node
+// in a round of a partition scan or query.
+type nodePartitions struct {
+	node  *Node
+	parts []*PartitionStatus
+	byId  map[int]*PartitionStatus
+
+	// recordMax is the number of records requested from the node; 0 means all
+	recordMax int64
+
+	// recordCount is the number of records received from the node
+	recordCount int64
+}
+
+func newNodePartitions(node *Node) *nodePartitions {
+	return &nodePartitions{
+		node: node,
+		byId: map[int]*PartitionStatus{},
+	}
+}
+
+func (np *nodePartitions) add(ps *PartitionStatus) {
+	np.parts = append(np.parts, ps)
+	np.byId[ps.Id] = ps
+}
+
+// counts returns the number of partitions to be read from the beginning,
+// and the number of partitions to be resumed after a digest.
+func (np *nodePartitions) counts() (pids, digests int) {
+	for _, ps := range np.parts {
+		if ps.Digest != nil {
+			digests++
+		} else {
+			pids++
+		}
+	}
+	return pids, digests
+}
+
+// isDone returns true if all partitions of the node are done.
+func (np *nodePartitions) isDone() bool {
+	for _, ps := range np.parts {
+		if !ps.Done {
+			return false
+		}
+	}
+	return true
+}
+
+// partitionDone marks the partition as done.
+func (np *nodePartitions) partitionDone(partitionId int) {
+	if ps := np.byId[partitionId]; ps != nil {
+		ps.Done = true
+	}
+}
+
+// recordReceived counts the record, and records its digest as the last
+// digest received from the partition of the key.
+func (np *nodePartitions) recordReceived(key *Key) {
+	np.recordCount++
+	if ps := np.byId[newPartitionByKey(key).PartitionId]; ps != nil {
+		if ps.Digest == nil {
+			ps.Digest = make([]byte, _DIGEST_SIZE)
+		}
+		copy(ps.Digest, key.digest[:])
+	}
+}
+
+// assign groups the partitions of the filter that are not done by the nodes
+// that own them in the current partition map.
+func (pf *PartitionFilter) assign(clstr *Cluster, namespace string) ([]*nodePartitions, error) {
+	var res []*nodePartitions
+
+	for _, ps := range pf.partitions {
+		if ps.Done {
+			continue
+		}
+
+		node, err := clstr.getMasterNode(&Partition{Namespace: namespace, PartitionId: ps.Id})
+		if err != nil {
+			return nil, err
+		}
+
+		var np *nodePartitions
+		for _, p := range res {
+			if p.node == node {
+				np = p
+				break
+			}
+		}
+		if np == nil {
+			np = newNodePartitions(node)
+			res = append(res, np)
+		}
+		np.add(ps)
+	}
+	return res, nil
+}
+
+// partitionTracker runs a partition scan or query in rounds. Every round reads
+// the partitions that are not done from the nodes that own them, until all
+// partitions are done, the maximum number of records is returned, or the retries
+// are exhausted.
+type partitionTracker struct {
+	filter    *PartitionFilter
+	namespace string
+	policy    *BasePolicy
+	recordset *Recordset
+
+	// maxConcurrentNodes is the number of nodes read in parallel; 0 means all.
+	maxConcurrentNodes int
+
+	// supported checks if the node supports the command.
+	supported func(node *Node) bool
+}
+
+// run executes the rounds of the command. execute reads the partitions from their node.
+func (pt *partitionTracker) run(ctx context.Context, clstr *Cluster, execute func(np *nodePartitions) error) error {
+	limit := pt.recordset.limit
+	retryPolicy := pt.retryPolicy()
+	retries := 0
+	var delay time.Duration
+	for {
+		list, err := pt.filter.assign(clstr, pt.namespace)
+		if err != nil {
+			return err
+		}
+
+		if len(list) == 0 {
+			return nil
+		}
+
+		for _, np := range list {
+			if !pt.supported(np.node) {
+				return NewAerospikeError(PARAMETER_ERROR, fmt.Sprintf("Node %s does not support partition scans and queries.", np.node))
+			}
+		}
+
+		// share the records that can still be returned between the nodes
+		if limit != nil {
+			remaining := limit.remaining()
+			if remaining == 0 {
+				return nil
+			}
+			for _, np := range list {
+				np.recordMax = recordShare(remaining, len(list))
+			}
+		}
+
+		errs := pt.executeRound(list, execute)
+
+		// stop if the recordset is closed or the context is done
+		select {
+		case <-pt.recordset.cancelled:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		default:
+		}
+
+		if pt.filter.IsDone() || (limit != nil && limit.remaining() == 0) {
+			return nil
+		}
+
+		// Nodes which returned the records requested from them are read
+		// again in the next round, without counting as a retry.
+		failed := len(errs) > 0
+		for _, np := range list {
+			if !np.isDone() && (np.recordMax == 0 || np.recordCount < np.recordMax) {
+				failed = true
+			}
+		}
+
+		if !failed {
+			continue
+		}
+
+		err = MergeErrors(errs)
+		if err == nil {
+			err = NewAerospikeError(PARTITION_UNAVAILABLE, "Some partitions were not available after the retries.")
+		}
+
+		retries++
+		decision := retryPolicy.Retry(&RetryAttempt{
+			Attempt:    retries,
+			ResultCode: resultCodeOf(err),
+			Err:        err,
+			LastDelay:  delay,
+		})
+		if !decision.Retry {
+			return err
+		}
+
+		if delay = decision.Delay; delay > 0 {
+			select {
+			case <-time.After(delay):
+			case <-pt.recordset.cancelled:
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+	}
+}
+
+// retryPolicy returns the policy deciding if a failed round is retried.
+// Rounds resume the partitions where they stopped, so without a RetryPolicy
+// they are retried on any error, as MaxRetries and SleepBetweenRetries allow.
+func (pt *partitionTracker) retryPolicy() RetryPolicy {
+	if pt.policy.RetryPolicy != nil {
+		return pt.policy.RetryPolicy
+	}
+	return roundRetryPolicy{policy: pt.policy}
+}
+
+// roundRetryPolicy adapts the MaxRetries and SleepBetweenRetries fields
+// of BasePolicy to the retries of partition rounds.
+type roundRetryPolicy struct {
+	policy *BasePolicy
+}
+
+// Retry implements the RetryPolicy interface.
+func (rp roundRetryPolicy) Retry(attempt *RetryAttempt) RetryDecision {
+	if attempt.Attempt > rp.policy.MaxRetries {
+		return RetryDecision{}
+	}
+	return RetryDecision{Retry: true, Delay: rp.policy.SleepBetweenRetries}
+}
+
+// executeRound reads the partitions from the nodes, and returns the errors of the nodes.
+func (pt *partitionTracker) executeRound(list []*nodePartitions, execute func(np *nodePartitions) error) []error {
+	maxConcurrent := pt.maxConcurrentNodes
+	if maxConcurrent <= 0 || maxConcurrent > len(list) {
+		maxConcurrent = len(list)
+	}
+
+	var errs []error
+	if maxConcurrent == 1 {
+		for _, np := range list {
+			if err := execute(np); err != nil {
+				errs = append(errs, err)
+			}
+		}
+		return errs
+	}
+
+	var wg sync.WaitGroup
+	var errLock sync.Mutex
+	sem := make(chan struct{}, maxConcurrent)
+	for _, np := range list {
+		sem <- struct{}{}
+		wg.Add(1)
+		go func(np *nodePartitions) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+
+			if err := execute(np); err != nil {
+				errLock.Lock()
+				errs = append(errs, err)
+				errLock.Unlock()
+			}
+		}(np)
+	}
+	wg.Wait()
+	return errs
+}
@@ -82,12 +82,29 @@ type BasePolicy struct {
 	// Default to (1.0); Only values greater than 1 are valid.
 	SleepMultiplier float64 //= 1.0;
 
+	// RetryPolicy decides if and when a failed attempt is retried, and if the retry should
+	// be directed to another replica. See ExponentialBackoff and DecorrelatedJitterBackoff.
+	// If set, MaxRetries, SleepBetweenRetries and SleepMultiplier are ignored.
+	// Default to nil, which retries network errors according to the fields above.
+	// Commands that were already sent are only retried if the connection was closed
+	// before the response, and writes that may have been applied are never retried.
+	RetryPolicy RetryPolicy //= nil
+
 	// ReplicaPolicy detemines the node to send the read commands containing the key's partition replica type.
 	// Write commands are not affected by this setting, because all writes are directed
 	// to the node containing the key's master partition.
 	// Batch, scan and query are also not affected by replica algorithms.
 	// Default to sending read commands to the node containing the key's master partition.
 	ReplicaPolicy ReplicaPolicy
+
+	// PredExp is an optional predicate expression filter in postfix notation. If the filter
+	// is defined and does not match the record, single record commands fail with a FILTERED_OUT
+	// error, and batch, scan and query commands skip the record.
+	// Use SetExpression to build the filter from an Expression tree.
+	// Batch commands only apply the filter with the batch index protocol.
+	// Supported only by Aerospike Server v4.4+.
+	// Default is nil.
+	PredExp []PredExp
 }
 
 // NewPolicy generates a new BasePolicy instance with default values.
@@ -108,6 +125,23 @@ var _ Policy = &BasePolicy{}
 // GetBasePolicy returns embedded BasePolicy in all types that embed this struct.
 func (p *BasePolicy) GetBasePolicy() *BasePolicy { return p }
 
+// SetExpression compiles the expression and sets it as the predicate expression
+// filter of the policy. An invalid expression returns an error and leaves the
+// policy unchanged. Passing nil removes the filter.
+func (p *BasePolicy) SetExpression(exp *Expression) error {
+	if exp == nil {
+		p.PredExp = nil
+		return nil
+	}
+
+	predExp, err := exp.Compile()
+	if err != nil {
+		return err
+	}
+	p.PredExp = predExp
+	return nil
+}
+
 // socketTimeout validates and then calculates the timeout to be used for the socket
 // based on Timeout and SocketTimeout values.
 func (p *BasePolicy) socketTimeout() (time.Duration, error) {
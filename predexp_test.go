@@ -19,6 +19,7 @@ import (
 	"time"
 
 	as "github.com/aerospike/aerospike-client-go"
+	ast "github.com/aerospike/aerospike-client-go/types"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -625,4 +626,65 @@ var _ = Describe("predexp operations", func() {
 		Expect(cnt).To(BeNumerically("==", 801))
 	})
 
+	It("expressions must additionally filter indexed query results", func() {
+
+		stm := as.NewStatement(ns, set)
+		stm.Addfilter(as.NewRangeFilter("intval", 0, 400))
+		err := stm.SetExpression(as.Expr.GE(as.Expr.IntBin("modval"), as.Expr.Int(8)))
+		Expect(err).ToNot(HaveOccurred())
+
+		recordset, err := client.Query(nil, stm)
+		Expect(err).ToNot(HaveOccurred())
+
+		cnt := 0
+		for res := range recordset.Results() {
+			Expect(res.Err).ToNot(HaveOccurred())
+			cnt++
+		}
+
+		Expect(cnt).To(BeNumerically("==", 80))
+	})
+
+	It("expressions must filter scans", func() {
+
+		// Select all records with a list element of 3 and no map value of 5 ("0x0005").
+		policy := as.NewScanPolicy()
+		err := policy.SetExpression(as.Expr.And(
+			as.Expr.ListIterateOr(as.Expr.ListBin("lstval"), "l", as.Expr.EQ(as.Expr.IntVar("l"), as.Expr.Int(3))),
+			as.Expr.MapValIterateAnd(as.Expr.MapBin("mapval"), "m", as.Expr.NE(as.Expr.StringVar("m"), as.Expr.String("0x0005"))),
+		))
+		Expect(err).ToNot(HaveOccurred())
+
+		recordset, err := client.ScanAll(policy, ns, set)
+		Expect(err).ToNot(HaveOccurred())
+
+		cnt := 0
+		for res := range recordset.Results() {
+			Expect(res.Err).ToNot(HaveOccurred())
+			cnt++
+		}
+
+		// Multiples of 3 in [3, 999] not divisible by 5 = 333 - 66 = 267
+		Expect(cnt).To(BeNumerically("==", 267))
+	})
+
+	It("expressions must filter single record commands", func() {
+
+		key, err := as.NewKey(ns, set, 7)
+		Expect(err).ToNot(HaveOccurred())
+
+		policy := as.NewPolicy()
+		Expect(policy.SetExpression(as.Expr.EQ(as.Expr.IntBin("modval"), as.Expr.Int(7)))).ToNot(HaveOccurred())
+
+		rec, err := client.Get(policy, key)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(rec.Bins["intval"]).To(Equal(7))
+
+		Expect(policy.SetExpression(as.Expr.EQ(as.Expr.IntBin("modval"), as.Expr.Int(8)))).ToNot(HaveOccurred())
+
+		_, err = client.Get(policy, key)
+		Expect(err).To(HaveOccurred())
+		Expect(err.(ast.AerospikeError).ResultCode()).To(Equal(ast.FILTERED_OUT))
+	})
+
 })
@@ -156,6 +156,8 @@ func (cmd *queryAggregateCommand) parseRecordResults(ifc command, receiveSize in
 		case cmd.inputChan <- recs:
 		case <-cmd.recordset.cancelled:
 			return false, NewAerospikeError(QUERY_TERMINATED)
+		case <-cmd.context().Done():
+			return false, cmd.context().Err()
 		}
 	}
 
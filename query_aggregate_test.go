@@ -100,4 +100,15 @@ var _ = Describe("Query Aggregate operations", func() {
 			Expect(rec.Record.Bins["SUCCESS"]).To(Equal(map[interface{}]interface{}{"sum": float64(55), "count": float64(10)}))
 		}
 	})
+
+	It("must return the sum of specified bin with a Go aggregation", func() {
+		stm := as.NewStatement(ns, set, "bin1")
+		agg := as.NewAggregation().
+			Map(func(v interface{}) interface{} { return v.(*as.Record).Bins["bin1"] }).
+			Sum()
+
+		res, err := client.Aggregate(nil, stm, agg)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(res).To(Equal(int64(55)))
+	})
 })
@@ -33,8 +33,16 @@ func (cmd *queryCommand) getPolicy(ifc command) Policy {
 	return cmd.policy
 }
 
+func (cmd *queryCommand) commandType() CommandType {
+	return CMD_QUERY
+}
+
+func (cmd *queryCommand) target() (namespace, setName string) {
+	return cmd.statement.Namespace, cmd.statement.SetName
+}
+
 func (cmd *queryCommand) writeBuffer(ifc command) (err error) {
-	return cmd.setQuery(cmd.policy, cmd.statement, false)
+	return cmd.setQuery(cmd.policy, cmd.statement, false, cmd.partitions, cmd.maxRecords)
 }
 
 func (cmd *queryCommand) parseResult(ifc command, conn *Connection) error {
@@ -72,6 +72,15 @@ func (cmd *readCommand) getNode(ifc command) (*Node, error) {
 	return cmd.cluster.getReadNode(&cmd.partition, cmd.policy.ReplicaPolicy, &cmd.replicaSequence)
 }
 
+func (cmd *readCommand) commandType() CommandType {
+	return CMD_READ
+}
+
+// switchReplica directs the next attempt to the next replica of the partition.
+func (cmd *readCommand) switchReplica() {
+	cmd.replicaSequence++
+}
+
 func (cmd *readCommand) parseResult(ifc command, conn *Connection) error {
 	// Read header.
 	_, err := conn.Read(cmd.dataBuffer, int(_MSG_TOTAL_HEADER_SIZE))
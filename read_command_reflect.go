@@ -146,6 +146,21 @@ func setValue(f reflect.Value, value interface{}) error {
 			return nil
 		}
 
+		if codec := valueCodecFor(f.Type()); codec != nil {
+			return decodeValue(codec, f, value)
+		}
+
+		if f.Kind() == reflect.Ptr {
+			if codec := valueCodecFor(f.Type().Elem()); codec != nil {
+				newObjPtr := reflect.New(f.Type().Elem())
+				if err := decodeValue(codec, newObjPtr.Elem(), value); err != nil {
+					return err
+				}
+				f.Set(newObjPtr)
+				return nil
+			}
+		}
+
 		switch f.Kind() {
 		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
 			f.SetInt(int64(value.(int)))
@@ -385,7 +400,13 @@ func setValue(f reflect.Value, value interface{}) error {
 						newVal = reflect.Zero(f.Type().Elem())
 					}
 
-					if newVal.Type() != f.Type().Elem() {
+					if elem != nil && valueCodecFor(f.Type().Elem()) != nil {
+						newVal = reflect.New(f.Type().Elem())
+						if err := setValue(newVal.Elem(), elem); err != nil {
+							return err
+						}
+						newVal = reflect.Indirect(newVal)
+					} else if newVal.Type() != f.Type().Elem() {
 						switch newVal.Kind() {
 						case reflect.Map, reflect.Slice, reflect.Array:
 							newVal = reflect.New(f.Type().Elem())
@@ -49,6 +49,15 @@ func (cmd *readHeaderCommand) getNode(ifc command) (*Node, error) {
 	return cmd.cluster.getReadNode(&cmd.partition, cmd.policy.ReplicaPolicy, &cmd.replicaSequence)
 }
 
+func (cmd *readHeaderCommand) commandType() CommandType {
+	return CMD_READ
+}
+
+// switchReplica directs the next attempt to the next replica of the partition.
+func (cmd *readHeaderCommand) switchReplica() {
+	cmd.replicaSequence++
+}
+
 func (cmd *readHeaderCommand) parseResult(ifc command, conn *Connection) error {
 	// Read header.
 	if _, err := conn.Read(cmd.dataBuffer, int(_MSG_TOTAL_HEADER_SIZE)); err != nil {
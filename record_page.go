@@ -0,0 +1,68 @@
+// Copyright 2013-2017 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aerospike
+
+import (
+	"encoding/base64"
+
+	. "github.com/aerospike/aerospike-client-go/types"
+)
+
+// RecordPage is a page of records returned by ScanPage or QueryPage.
+type RecordPage struct {
+	// Records are the records of the page.
+	Records []*Record
+
+	// Token is the continuation token to read the next page.
+	// It is empty if all records have been read.
+	// The last page may be empty, when the records ended exactly on the previous page.
+	Token string
+}
+
+// pageFilter decodes the partition filter of a continuation token.
+// An empty token starts from the beginning of all partitions.
+func pageFilter(token string) (*PartitionFilter, error) {
+	if token == "" {
+		return NewPartitionFilterAll(), nil
+	}
+
+	cursor, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, NewAerospikeErrorWithCause(PARAMETER_ERROR, err, "Invalid page token")
+	}
+	return NewPartitionFilterFromCursor(cursor)
+}
+
+// readPage reads the records of the recordset, and encodes the progress of the filter
+// as the token of the next page.
+func readPage(recordset *Recordset, filter *PartitionFilter) (*RecordPage, error) {
+	page := &RecordPage{}
+	for res := range recordset.Results() {
+		if res.Err != nil {
+			recordset.Close()
+			return nil, res.Err
+		}
+		page.Records = append(page.Records, res.Record)
+	}
+
+	if !filter.IsDone() {
+		cursor, err := filter.MarshalBinary()
+		if err != nil {
+			return nil, err
+		}
+		page.Token = base64.RawURLEncoding.EncodeToString(cursor)
+	}
+	return page, nil
+}
@@ -19,6 +19,7 @@ import (
 	"reflect"
 	"runtime"
 	"sync"
+	"sync/atomic"
 
 	. "github.com/aerospike/aerospike-client-go/types"
 	. "github.com/aerospike/aerospike-client-go/types/atomic"
@@ -56,6 +57,9 @@ type objectset struct {
 	chanLock sync.Mutex
 
 	taskId uint64
+
+	// limit is the maximum number of records of the command, nil if there is no maximum
+	limit *recordLimiter
 }
 
 // TaskId returns the transactionId/jobId sent to the server for this recordset.
@@ -63,6 +67,45 @@ func (os *objectset) TaskId() uint64 {
 	return os.taskId
 }
 
+// recordLimiter counts the records returned by the commands of a scan or query,
+// up to the maximum of the policy.
+type recordLimiter struct {
+	max, count int64
+
+	// nodeMax is the number of records requested from each node.
+	nodeMax int64
+}
+
+// newRecordLimiter returns a limiter to request max records from the nodes,
+// or nil if max is not positive.
+func newRecordLimiter(max int64, nodes int) *recordLimiter {
+	if max <= 0 {
+		return nil
+	}
+	return &recordLimiter{max: max, nodeMax: recordShare(max, nodes)}
+}
+
+// recordShare returns the number of records requested from each of the nodes.
+func recordShare(max int64, nodes int) int64 {
+	if nodes <= 1 {
+		return max
+	}
+	return (max + int64(nodes) - 1) / int64(nodes)
+}
+
+// take returns true if a record can be returned.
+func (rl *recordLimiter) take() bool {
+	return rl == nil || atomic.AddInt64(&rl.count, 1) <= rl.max
+}
+
+// remaining returns the number of records which can still be returned.
+func (rl *recordLimiter) remaining() int64 {
+	if count := atomic.LoadInt64(&rl.count); count < rl.max {
+		return rl.max - count
+	}
+	return 0
+}
+
 // Recordset encapsulates the result of Scan and Query commands.
 type Recordset struct {
 	objectset
@@ -43,4 +43,29 @@ const (
 	// This option requires ClientPolicy.RequestProleReplicas to be enabled
 	// in order to function properly.
 	SEQUENCE
+
+	// PREFER_RACK Tries nodes on the client's rack first, see ClientPolicy.RackAware
+	// and ClientPolicy.RackId. Nodes containing the key's partition are tried in the order
+	// of SEQUENCE, the ones on the client's rack before the others. Retries move on to the
+	// next node. Writes remain on master node.
+	//
+	// This option requires ClientPolicy.RequestProleReplicas to be enabled
+	// in order to function properly.
+	PREFER_RACK
+
+	// LEAST_LATENCY Tries the node containing the key's partition with the lowest average
+	// latency first. The client keeps a moving average of the latency of single record
+	// commands on each node, which decays while the node is not used, so that slow nodes
+	// are tried again after a while. Retries move on to the node with the next lowest average.
+	// Writes remain on master node.
+	//
+	// This option requires ClientPolicy.RequestProleReplicas to be enabled
+	// in order to function properly.
+	LEAST_LATENCY
 )
+
+// retriesOnNextReplica returns true if retries of reads move on to the next node,
+// whether or not the retry policy asks to switch the replica.
+func (rp ReplicaPolicy) retriesOnNextReplica() bool {
+	return rp == PREFER_RACK || rp == LEAST_LATENCY
+}
@@ -0,0 +1,182 @@
+// Copyright 2013-2017 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aerospike
+
+import (
+	"context"
+	"time"
+
+	. "github.com/aerospike/aerospike-client-go/types"
+	. "github.com/aerospike/aerospike-client-go/types/atomic"
+
+	. "github.com/onsi/ginkgo"
+	gm "github.com/onsi/gomega"
+)
+
+var _ = Describe("Replica policy test", func() {
+
+	const ns = "test"
+
+	var cluster *Cluster
+	var master, prole1, prole2 *Node
+	var partition *Partition
+
+	newTestNode := func(name string) *Node {
+		node := &Node{cluster: cluster, name: name, active: *NewAtomicBool(true)}
+		node.racks.Store(map[string]int(nil))
+		return node
+	}
+
+	BeforeEach(func() {
+		cluster = &Cluster{clientPolicy: *NewClientPolicy()}
+		master, prole1, prole2 = newTestNode("A"), newTestNode("B"), newTestNode("C")
+		cluster.nodes = NewSyncVal([]*Node{master, prole1, prole2})
+
+		partition = NewPartition(ns, 7)
+		replicas := make([][]*Node, 3)
+		for i, node := range []*Node{master, prole1, prole2} {
+			replicas[i] = make([]*Node, _PARTITIONS)
+			replicas[i][partition.PartitionId] = node
+		}
+		cluster.partitionWriteMap.Store(partitionMap{ns: replicas})
+	})
+
+	readNode := func(replica ReplicaPolicy, seq int) *Node {
+		node, err := cluster.getReadNode(partition, replica, &seq)
+		gm.Expect(err).ToNot(gm.HaveOccurred())
+		return node
+	}
+
+	It("must parse the racks of the node", func() {
+		prole1.updateRacks(map[string]string{
+			"racks:": "ns=test:rack_1=A,C:rack_2=B;ns=bar:rack_3=B,A",
+		})
+		gm.Expect(prole1.hasRack("test", 2)).To(gm.BeTrue())
+		gm.Expect(prole1.hasRack("test", 1)).To(gm.BeFalse())
+		gm.Expect(prole1.hasRack("bar", 3)).To(gm.BeTrue())
+		gm.Expect(prole1.hasRack("baz", 0)).To(gm.BeFalse())
+
+		// older servers do not know the command
+		prole1.updateRacks(map[string]string{"racks:": "ERROR::unrecognized command"})
+		gm.Expect(prole1.hasRack("test", 2)).To(gm.BeTrue())
+	})
+
+	It("must prefer the nodes on the client's rack", func() {
+		cluster.clientPolicy.RackId = 2
+		for _, node := range []*Node{master, prole1, prole2} {
+			node.updateRacks(map[string]string{"racks:": "ns=test:rack_1=A:rack_2=B,C"})
+		}
+
+		gm.Expect(readNode(PREFER_RACK, 0)).To(gm.Equal(prole1))
+		gm.Expect(readNode(PREFER_RACK, 1)).To(gm.Equal(prole2))
+		gm.Expect(readNode(PREFER_RACK, 2)).To(gm.Equal(master))
+		gm.Expect(readNode(PREFER_RACK, 3)).To(gm.Equal(prole1))
+
+		// inactive nodes are skipped
+		prole1.active.Set(false)
+		gm.Expect(readNode(PREFER_RACK, 0)).To(gm.Equal(prole2))
+
+		// no node on the rack
+		cluster.clientPolicy.RackId = 3
+		gm.Expect(readNode(PREFER_RACK, 0)).To(gm.Equal(master))
+	})
+
+	It("must prefer the nodes with the lowest latency", func() {
+		// nodes without latency are tried first, in order
+		gm.Expect(readNode(LEAST_LATENCY, 0)).To(gm.Equal(master))
+
+		master.latency.record(3 * time.Millisecond)
+		prole1.latency.record(time.Millisecond)
+		prole2.latency.record(2 * time.Millisecond)
+
+		gm.Expect(readNode(LEAST_LATENCY, 0)).To(gm.Equal(prole1))
+		gm.Expect(readNode(LEAST_LATENCY, 1)).To(gm.Equal(prole2))
+		gm.Expect(readNode(LEAST_LATENCY, 2)).To(gm.Equal(master))
+
+		prole1.active.Set(false)
+		gm.Expect(readNode(LEAST_LATENCY, 0)).To(gm.Equal(prole2))
+	})
+
+	It("must retry on the next node", func() {
+		key, err := NewKey(ns, "set", 1)
+		gm.Expect(err).ToNot(gm.HaveOccurred())
+
+		for _, replica := range []ReplicaPolicy{PREFER_RACK, LEAST_LATENCY} {
+			policy := NewPolicy()
+			policy.ReplicaPolicy = replica
+			cmd := newReadCommand(cluster, policy, key, nil)
+			cmd.partition = *partition
+
+			first, err := cmd.getNode(&cmd)
+			gm.Expect(err).ToNot(gm.HaveOccurred())
+
+			// the default retry policy does not ask to switch the replica
+			var delay time.Duration
+			gm.Expect(cmd.retry(&cmd, policy.retryPolicy(), 1, NewAerospikeError(TIMEOUT), false, &delay)).To(gm.BeTrue())
+
+			next, err := cmd.getNode(&cmd)
+			gm.Expect(err).ToNot(gm.HaveOccurred())
+			gm.Expect(next).ToNot(gm.Equal(first))
+		}
+	})
+
+	It("must average the latencies", func() {
+		var la latencyAverage
+		gm.Expect(la.get()).To(gm.Equal(time.Duration(0)))
+
+		la.record(8 * time.Millisecond)
+		gm.Expect(la.get()).To(gm.Equal(8 * time.Millisecond))
+
+		la.record(16 * time.Millisecond)
+		gm.Expect(la.get()).To(gm.Equal(9 * time.Millisecond))
+	})
+
+	It("must decay the latencies of nodes without commands", func() {
+		var la latencyAverage
+		start := time.Now()
+		la.recordAt(80*time.Millisecond, start)
+		gm.Expect(la.getAt(start.Add(latencyHalfLife / 2))).To(gm.Equal(80 * time.Millisecond))
+		gm.Expect(la.getAt(start.Add(latencyHalfLife))).To(gm.Equal(40 * time.Millisecond))
+		gm.Expect(la.getAt(start.Add(3 * latencyHalfLife))).To(gm.Equal(10 * time.Millisecond))
+		gm.Expect(la.getAt(start.Add(100 * latencyHalfLife))).To(gm.Equal(time.Duration(0)))
+
+		// new samples are averaged with the decayed value
+		la.recordAt(8*time.Millisecond, start.Add(3*latencyHalfLife))
+		gm.Expect(la.getAt(start.Add(3 * latencyHalfLife))).To(gm.Equal(9750 * time.Microsecond))
+
+		// a node which was slow once is tried again
+		now := time.Now()
+		master.latency.recordAt(time.Second, now.Add(-10*latencyHalfLife))
+		prole1.latency.recordAt(5*time.Millisecond, now)
+		prole2.latency.recordAt(5*time.Millisecond, now)
+		gm.Expect(readNode(LEAST_LATENCY, 0)).To(gm.Equal(master))
+	})
+
+	It("must record the latency of single record commands that reached the node", func() {
+		master.stats.commands = newCommandStats(0, 1)
+
+		attempt := startAttempt(master, CMD_BATCH, ns, "", 1, context.Background())
+		attempt.finish(nil)
+		gm.Expect(master.latency.get()).To(gm.Equal(time.Duration(0)))
+
+		attempt = startAttempt(master, CMD_READ, ns, "", 1, context.Background())
+		attempt.finish(NewAerospikeError(NO_AVAILABLE_CONNECTIONS_TO_NODE))
+		gm.Expect(master.latency.get()).To(gm.Equal(time.Duration(0)))
+
+		attempt = startAttempt(master, CMD_READ, ns, "", 1, context.Background())
+		attempt.finish(NewAerospikeError(KEY_NOT_FOUND_ERROR))
+		gm.Expect(master.latency.get()).To(gm.BeNumerically(">", 0))
+	})
+})
@@ -0,0 +1,294 @@
+// Copyright 2013-2017 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aerospike
+
+import (
+	"io"
+	"math"
+	"net"
+	"time"
+
+	. "github.com/aerospike/aerospike-client-go/types"
+	xornd "github.com/aerospike/aerospike-client-go/types/rand"
+)
+
+// RetryAttempt describes a failed command attempt.
+type RetryAttempt struct {
+	// Attempt is the number of attempts made so far, starting from 1.
+	Attempt int
+
+	// Node is the node the failed attempt was sent to.
+	// It is nil if no node could be selected for the attempt.
+	Node *Node
+
+	// ResultCode is the result code of the failure.
+	// Network errors are reported as TIMEOUT or SERVER_NOT_AVAILABLE.
+	ResultCode ResultCode
+
+	// Err is the error the attempt failed with.
+	Err error
+
+	// LastDelay is the delay that preceded the failed attempt.
+	// It is zero for the first attempt.
+	LastDelay time.Duration
+
+	// Sent is true if the command was sent to the node before the attempt failed.
+	Sent bool
+
+	// InDoubt is true if the attempt of a write command failed after it was sent,
+	// without a definite answer from the server. The write may have been applied,
+	// and retrying it may apply it again.
+	InDoubt bool
+}
+
+// RetryDecision is the verdict of a RetryPolicy on a failed attempt.
+type RetryDecision struct {
+	// Retry determines if the command should be attempted again.
+	Retry bool
+
+	// Delay determines how long to wait before the next attempt.
+	Delay time.Duration
+
+	// SwitchReplica directs the next attempt of a read command to the next
+	// node holding a replica of the key's partition.
+	// Only effective with the SEQUENCE replica policy; reads with the PREFER_RACK
+	// and LEAST_LATENCY replica policies always retry on the next node.
+	SwitchReplica bool
+}
+
+// RetryPolicy decides if, and how, a failed command attempt is retried.
+// The same instance is shared between all commands using the policy,
+// so implementations must be safe for concurrent use.
+//
+// Retries are still bound to BasePolicy.Timeout, and streaming commands
+// (scans and queries) are never retried once they have started receiving results.
+// Writes that may have been applied are only retried if the policy allows it
+// for RetryAttempt.InDoubt; the built-in policies do not, unless RetryInDoubt is set.
+type RetryPolicy interface {
+	// Retry is consulted after every failed attempt.
+	Retry(attempt *RetryAttempt) RetryDecision
+}
+
+// replicaSwitcher is implemented by commands that can direct their
+// next attempt to another replica.
+type replicaSwitcher interface {
+	switchReplica()
+}
+
+// fieldsRetryPolicy adapts the MaxRetries, SleepBetweenRetries and SleepMultiplier
+// fields of BasePolicy to the RetryPolicy interface.
+// It is used when BasePolicy.RetryPolicy is not set.
+//
+// Once the command was sent, it is only retried if the connection was closed
+// before the response arrived, and never if a write may have been applied.
+type fieldsRetryPolicy struct {
+	policy *BasePolicy
+}
+
+// Retry implements the RetryPolicy interface.
+func (rp fieldsRetryPolicy) Retry(attempt *RetryAttempt) RetryDecision {
+	if !isNetworkResultCode(attempt.ResultCode) || attempt.Attempt > rp.policy.MaxRetries {
+		return RetryDecision{}
+	}
+	if attempt.InDoubt || (attempt.Sent && attempt.Err != io.EOF) {
+		return RetryDecision{}
+	}
+
+	delay := rp.policy.SleepBetweenRetries
+	if attempt.LastDelay > 0 && rp.policy.SleepMultiplier > 1 {
+		delay = time.Duration(float64(attempt.LastDelay) * rp.policy.SleepMultiplier)
+	}
+	return RetryDecision{Retry: true, Delay: delay}
+}
+
+// ExponentialBackoff is a RetryPolicy that waits an exponentially growing,
+// randomized duration between attempts ("full jitter"). The delay before
+// attempt N+1 is a random duration in [0, min(MaxDelay, BaseDelay * 2^(N-1))).
+//
+// Network errors, timeouts and transient server errors (KEY_BUSY, DEVICE_OVERLOAD,
+// PARTITION_UNAVAILABLE) are retried; all other errors are returned immediately.
+// Writes that may have been applied are not retried, unless RetryInDoubt is set.
+type ExponentialBackoff struct {
+	// MaxRetries determines maximum number of retries before aborting the command.
+	MaxRetries int
+
+	// BaseDelay is the upper bound of the delay before the first retry.
+	BaseDelay time.Duration
+
+	// MaxDelay caps the delay between attempts. Zero means no cap.
+	MaxDelay time.Duration
+
+	// SwitchReplica directs retries of read commands to the next replica.
+	SwitchReplica bool
+
+	// RetryInDoubt allows retrying writes that may have been applied.
+	// Only set it if all writes using the policy are idempotent.
+	RetryInDoubt bool
+}
+
+// NewExponentialBackoff returns an ExponentialBackoff retry policy.
+func NewExponentialBackoff(maxRetries int, baseDelay, maxDelay time.Duration) *ExponentialBackoff {
+	return &ExponentialBackoff{
+		MaxRetries: maxRetries,
+		BaseDelay:  baseDelay,
+		MaxDelay:   maxDelay,
+	}
+}
+
+// Retry implements the RetryPolicy interface.
+func (eb *ExponentialBackoff) Retry(attempt *RetryAttempt) RetryDecision {
+	if !isTransientResultCode(attempt.ResultCode) || attempt.Attempt > eb.MaxRetries {
+		return RetryDecision{}
+	}
+	if attempt.InDoubt && !eb.RetryInDoubt {
+		return RetryDecision{}
+	}
+
+	ceiling := capDelay(float64(eb.BaseDelay)*math.Pow(2, float64(attempt.Attempt-1)), eb.MaxDelay)
+	return RetryDecision{
+		Retry:         true,
+		Delay:         randomDelay(0, ceiling),
+		SwitchReplica: eb.SwitchReplica,
+	}
+}
+
+// DecorrelatedJitterBackoff is a RetryPolicy that waits a random duration
+// between BaseDelay and three times the previous delay, capped at MaxDelay.
+// It spreads retries of concurrent clients better than the plain exponential
+// backoff, while growing at a similar rate.
+//
+// Network errors, timeouts and transient server errors (KEY_BUSY, DEVICE_OVERLOAD,
+// PARTITION_UNAVAILABLE) are retried; all other errors are returned immediately.
+// Writes that may have been applied are not retried, unless RetryInDoubt is set.
+type DecorrelatedJitterBackoff struct {
+	// MaxRetries determines maximum number of retries before aborting the command.
+	MaxRetries int
+
+	// BaseDelay is the minimum delay between attempts.
+	BaseDelay time.Duration
+
+	// MaxDelay caps the delay between attempts. Zero means no cap.
+	MaxDelay time.Duration
+
+	// SwitchReplica directs retries of read commands to the next replica.
+	SwitchReplica bool
+
+	// RetryInDoubt allows retrying writes that may have been applied.
+	// Only set it if all writes using the policy are idempotent.
+	RetryInDoubt bool
+}
+
+// NewDecorrelatedJitterBackoff returns a DecorrelatedJitterBackoff retry policy.
+func NewDecorrelatedJitterBackoff(maxRetries int, baseDelay, maxDelay time.Duration) *DecorrelatedJitterBackoff {
+	return &DecorrelatedJitterBackoff{
+		MaxRetries: maxRetries,
+		BaseDelay:  baseDelay,
+		MaxDelay:   maxDelay,
+	}
+}
+
+// Retry implements the RetryPolicy interface.
+func (dj *DecorrelatedJitterBackoff) Retry(attempt *RetryAttempt) RetryDecision {
+	if !isTransientResultCode(attempt.ResultCode) || attempt.Attempt > dj.MaxRetries {
+		return RetryDecision{}
+	}
+	if attempt.InDoubt && !dj.RetryInDoubt {
+		return RetryDecision{}
+	}
+
+	last := attempt.LastDelay
+	if last < dj.BaseDelay {
+		last = dj.BaseDelay
+	}
+
+	ceiling := capDelay(float64(last)*3, dj.MaxDelay)
+	floor := dj.BaseDelay
+	if floor > ceiling {
+		floor = ceiling
+	}
+
+	return RetryDecision{
+		Retry:         true,
+		Delay:         randomDelay(floor, ceiling),
+		SwitchReplica: dj.SwitchReplica,
+	}
+}
+
+// retryPolicy returns the RetryPolicy set on the policy, or an adapter
+// for the MaxRetries, SleepBetweenRetries and SleepMultiplier fields.
+func (p *BasePolicy) retryPolicy() RetryPolicy {
+	if p.RetryPolicy != nil {
+		return p.RetryPolicy
+	}
+	return fieldsRetryPolicy{policy: p}
+}
+
+// capDelay converts d to a duration not longer than max; max of zero means no cap.
+func capDelay(d float64, max time.Duration) time.Duration {
+	if d > float64(math.MaxInt64) {
+		d = float64(math.MaxInt64)
+	}
+	if max > 0 && d > float64(max) {
+		return max
+	}
+	return time.Duration(d)
+}
+
+// randomDelay returns a random duration in [min, max).
+func randomDelay(min, max time.Duration) time.Duration {
+	if max <= min {
+		return min
+	}
+	n := uint64(xornd.Int64()) % uint64(max-min)
+	return min + time.Duration(n)
+}
+
+// resultCodeOf returns the result code of an error returned by a command attempt.
+func resultCodeOf(err error) ResultCode {
+	switch e := err.(type) {
+	case AerospikeError:
+		return e.ResultCode()
+	case net.Error:
+		if e.Timeout() {
+			return TIMEOUT
+		}
+		return SERVER_NOT_AVAILABLE
+	}
+
+	if err == io.EOF {
+		return SERVER_NOT_AVAILABLE
+	}
+	return PARSE_ERROR
+}
+
+// isNetworkResultCode determines if the result code signifies a failure
+// to reach or hear back from a node.
+func isNetworkResultCode(code ResultCode) bool {
+	switch code {
+	case TIMEOUT, SERVER_NOT_AVAILABLE, INVALID_NODE_ERROR, NO_AVAILABLE_CONNECTIONS_TO_NODE:
+		return true
+	}
+	return false
+}
+
+// isTransientResultCode determines if the result code signifies a failure
+// that may not repeat on a subsequent attempt.
+func isTransientResultCode(code ResultCode) bool {
+	switch code {
+	case KEY_BUSY, DEVICE_OVERLOAD, PARTITION_UNAVAILABLE:
+		return true
+	}
+	return isNetworkResultCode(code)
+}
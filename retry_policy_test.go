@@ -0,0 +1,117 @@
+// Copyright 2013-2017 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aerospike
+
+import (
+	"errors"
+	"io"
+	"time"
+
+	. "github.com/aerospike/aerospike-client-go/types"
+
+	. "github.com/onsi/ginkgo"
+	gm "github.com/onsi/gomega"
+)
+
+var _ = Describe("Retry Policy test", func() {
+
+	Context("Default policy fields", func() {
+
+		It("must retry network errors up to MaxRetries with growing sleep", func() {
+			policy := NewPolicy()
+			policy.MaxRetries = 2
+			policy.SleepBetweenRetries = 10 * time.Millisecond
+			policy.SleepMultiplier = 2
+			rp := policy.retryPolicy()
+
+			d := rp.Retry(&RetryAttempt{Attempt: 1, ResultCode: TIMEOUT})
+			gm.Expect(d.Retry).To(gm.BeTrue())
+			gm.Expect(d.Delay).To(gm.Equal(10 * time.Millisecond))
+			gm.Expect(d.SwitchReplica).To(gm.BeFalse())
+
+			d = rp.Retry(&RetryAttempt{Attempt: 2, ResultCode: SERVER_NOT_AVAILABLE, LastDelay: d.Delay})
+			gm.Expect(d.Retry).To(gm.BeTrue())
+			gm.Expect(d.Delay).To(gm.Equal(20 * time.Millisecond))
+
+			d = rp.Retry(&RetryAttempt{Attempt: 3, ResultCode: TIMEOUT, LastDelay: d.Delay})
+			gm.Expect(d.Retry).To(gm.BeFalse())
+		})
+
+		It("must not retry server errors", func() {
+			rp := NewPolicy().retryPolicy()
+			gm.Expect(rp.Retry(&RetryAttempt{Attempt: 1, ResultCode: KEY_BUSY}).Retry).To(gm.BeFalse())
+			gm.Expect(rp.Retry(&RetryAttempt{Attempt: 1, ResultCode: KEY_NOT_FOUND_ERROR}).Retry).To(gm.BeFalse())
+		})
+
+		It("must not retry attempts after they were sent, unless the connection was closed", func() {
+			rp := NewPolicy().retryPolicy()
+			gm.Expect(rp.Retry(&RetryAttempt{Attempt: 1, ResultCode: TIMEOUT, Sent: true}).Retry).To(gm.BeFalse())
+			gm.Expect(rp.Retry(&RetryAttempt{Attempt: 1, ResultCode: SERVER_NOT_AVAILABLE, Err: io.EOF, Sent: true}).Retry).To(gm.BeTrue())
+			gm.Expect(rp.Retry(&RetryAttempt{Attempt: 1, ResultCode: SERVER_NOT_AVAILABLE, Err: io.EOF, Sent: true, InDoubt: true}).Retry).To(gm.BeFalse())
+		})
+
+		It("must use the RetryPolicy when set", func() {
+			policy := NewPolicy()
+			eb := NewExponentialBackoff(1, time.Millisecond, 0)
+			policy.RetryPolicy = eb
+			gm.Expect(policy.retryPolicy()).To(gm.BeIdenticalTo(eb))
+		})
+	})
+
+	Context("Built-in policies", func() {
+
+		It("must keep ExponentialBackoff delays within the capped exponential ceiling", func() {
+			eb := NewExponentialBackoff(10, 10*time.Millisecond, 50*time.Millisecond)
+			eb.SwitchReplica = true
+			for i := 1; i <= 10; i++ {
+				d := eb.Retry(&RetryAttempt{Attempt: i, ResultCode: KEY_BUSY})
+				gm.Expect(d.Retry).To(gm.BeTrue())
+				gm.Expect(d.SwitchReplica).To(gm.BeTrue())
+				gm.Expect(d.Delay).To(gm.BeNumerically(">=", 0))
+				gm.Expect(d.Delay).To(gm.BeNumerically("<=", 50*time.Millisecond))
+				if i == 1 {
+					gm.Expect(d.Delay).To(gm.BeNumerically("<", 10*time.Millisecond))
+				}
+			}
+			gm.Expect(eb.Retry(&RetryAttempt{Attempt: 11, ResultCode: TIMEOUT}).Retry).To(gm.BeFalse())
+			gm.Expect(eb.Retry(&RetryAttempt{Attempt: 1, ResultCode: TIMEOUT, Sent: true}).Retry).To(gm.BeTrue())
+			gm.Expect(eb.Retry(&RetryAttempt{Attempt: 1, ResultCode: TIMEOUT, Sent: true, InDoubt: true}).Retry).To(gm.BeFalse())
+			eb.RetryInDoubt = true
+			gm.Expect(eb.Retry(&RetryAttempt{Attempt: 1, ResultCode: TIMEOUT, Sent: true, InDoubt: true}).Retry).To(gm.BeTrue())
+			gm.Expect(eb.Retry(&RetryAttempt{Attempt: 1, ResultCode: GENERATION_ERROR}).Retry).To(gm.BeFalse())
+		})
+
+		It("must keep DecorrelatedJitterBackoff delays between the base and the cap", func() {
+			dj := NewDecorrelatedJitterBackoff(100, 10*time.Millisecond, 200*time.Millisecond)
+			var last time.Duration
+			for i := 1; i <= 100; i++ {
+				d := dj.Retry(&RetryAttempt{Attempt: i, ResultCode: TIMEOUT, LastDelay: last})
+				gm.Expect(d.Retry).To(gm.BeTrue())
+				gm.Expect(d.Delay).To(gm.BeNumerically(">=", 10*time.Millisecond))
+				gm.Expect(d.Delay).To(gm.BeNumerically("<=", 200*time.Millisecond))
+				if last > 0 {
+					gm.Expect(d.Delay).To(gm.BeNumerically("<=", 3*last))
+				}
+				last = d.Delay
+			}
+		})
+	})
+
+	It("must translate errors to result codes", func() {
+		gm.Expect(resultCodeOf(NewAerospikeError(KEY_BUSY))).To(gm.Equal(KEY_BUSY))
+		gm.Expect(resultCodeOf(io.EOF)).To(gm.Equal(SERVER_NOT_AVAILABLE))
+		gm.Expect(resultCodeOf(errors.New("unexpected"))).To(gm.Equal(PARSE_ERROR))
+	})
+})
@@ -53,8 +53,16 @@ func (cmd *scanCommand) getPolicy(ifc command) Policy {
 	return cmd.policy
 }
 
+func (cmd *scanCommand) commandType() CommandType {
+	return CMD_SCAN
+}
+
+func (cmd *scanCommand) target() (namespace, setName string) {
+	return cmd.namespace, cmd.setName
+}
+
 func (cmd *scanCommand) writeBuffer(ifc command) error {
-	return cmd.setScan(cmd.policy, &cmd.namespace, &cmd.setName, cmd.binNames, cmd.taskId)
+	return cmd.setScan(cmd.policy, &cmd.namespace, &cmd.setName, cmd.binNames, cmd.taskId, cmd.partitions, cmd.maxRecords)
 }
 
 func (cmd *scanCommand) parseResult(ifc command, conn *Connection) error {
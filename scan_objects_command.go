@@ -55,8 +55,16 @@ func (cmd *scanObjectsCommand) getPolicy(ifc command) Policy {
 	return cmd.policy
 }
 
+func (cmd *scanObjectsCommand) commandType() CommandType {
+	return CMD_SCAN
+}
+
+func (cmd *scanObjectsCommand) target() (namespace, setName string) {
+	return cmd.namespace, cmd.setName
+}
+
 func (cmd *scanObjectsCommand) writeBuffer(ifc command) error {
-	return cmd.setScan(cmd.policy, &cmd.namespace, &cmd.setName, cmd.binNames, cmd.taskId)
+	return cmd.setScan(cmd.policy, &cmd.namespace, &cmd.setName, cmd.binNames, cmd.taskId, cmd.partitions, cmd.maxRecords)
 }
 
 func (cmd *scanObjectsCommand) parseResult(ifc command, conn *Connection) error {
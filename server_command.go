@@ -32,6 +32,10 @@ func newServerCommand(node *Node, policy *QueryPolicy, statement *Statement) *se
 	}
 }
 
+func (cmd *serverCommand) commandType() CommandType {
+	return CMD_UDF
+}
+
 func (cmd *serverCommand) parseRecordResults(ifc command, receiveSize int) (bool, error) {
 	// Server commands (Query/Execute UDF) should only send back a return code.
 	// Keep parsing logic to empty socket buffer just in case server does
@@ -30,15 +30,23 @@ type singleCommand struct {
 
 func newSingleCommand(cluster *Cluster, key *Key) singleCommand {
 	return singleCommand{
-		baseCommand: baseCommand{},
+		baseCommand: baseCommand{pipelined: cluster != nil && cluster.clientPolicy.PipelineDepth > 0},
 		cluster:     cluster,
 		key:         key,
 		partition:   newPartitionByKey(key),
 	}
 }
 
+func (cmd *singleCommand) target() (namespace, setName string) {
+	return cmd.key.namespace, cmd.key.setName
+}
+
+func (cmd *singleCommand) digest() []byte {
+	return cmd.key.digest[:]
+}
+
 func (cmd *singleCommand) getConnection(timeout time.Duration) (*Connection, error) {
-	return cmd.node.getConnectionWithHint(timeout, cmd.key.digest[0])
+	return cmd.node.getConnectionWithHint(cmd.context(), timeout, cmd.key.digest[0])
 }
 
 func (cmd *singleCommand) putConnection(conn *Connection) {
@@ -76,6 +76,7 @@ func (stmt *Statement) Addfilter(filter *Filter) error {
 // Predicate expression filters are applied on the query results on the server.
 // Predicate expression filters may occur on any bin in the record.
 // To learn how to use this API, consult predexp_test.go file.
+// SetExpression builds the same filters from an expression tree, which is easier to get right.
 //
 // Postfix notation is described here: http://wiki.c2.com/?PostfixNotation
 //
@@ -110,6 +111,25 @@ func (stmt *Statement) SetPredExp(predexp ...PredExp) error {
 	return nil
 }
 
+// SetExpression sets the predicate expression filter for the statement from an
+// Expression tree built with the Expr builder. See Expr for an example.
+// An invalid expression returns an error and leaves the statement unchanged.
+// Passing nil removes the filter, like BasePolicy.SetExpression.
+// Supported only by Aerospike Server v3.12+.
+func (stmt *Statement) SetExpression(exp *Expression) error {
+	if exp == nil {
+		stmt.predExps = nil
+		return nil
+	}
+
+	predExp, err := exp.Compile()
+	if err != nil {
+		return err
+	}
+	stmt.predExps = predExp
+	return nil
+}
+
 // SetAggregateFunction sets aggregation function parameters.
 // This function will be called on both the server
 // and client for each selected item.
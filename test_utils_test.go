@@ -17,6 +17,9 @@ package aerospike_test
 import (
 	"math/rand"
 	"reflect"
+	"sync"
+
+	as "github.com/aerospike/aerospike-client-go"
 
 	. "github.com/onsi/gomega"
 )
@@ -122,3 +125,25 @@ func mapsEqual(ia, ib interface{}) {
 	Expect(len(a)).To(Equal(len(b)))
 	Expect(a).To(BeEquivalentTo(b))
 }
+
+// recordingListener records the events of finished commands
+type recordingListener struct {
+	mutex  sync.Mutex
+	events []*as.CommandEvent
+}
+
+func (rl *recordingListener) CommandStarted(event *as.CommandEvent) {
+	event.UserData = "started"
+}
+
+func (rl *recordingListener) CommandFinished(event *as.CommandEvent) {
+	rl.mutex.Lock()
+	rl.events = append(rl.events, event)
+	rl.mutex.Unlock()
+}
+
+func (rl *recordingListener) finished() []*as.CommandEvent {
+	rl.mutex.Lock()
+	defer rl.mutex.Unlock()
+	return append([]*as.CommandEvent(nil), rl.events...)
+}
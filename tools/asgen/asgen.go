@@ -0,0 +1,666 @@
+// Copyright 2013-2017 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Asgen generates implementations of the BinMarshaler and BinUnmarshaler
+// interfaces of the client for struct types, so that PutObject, GetObject,
+// BatchGetObjects and the object scans and queries do not use reflection.
+//
+// The bins are named by the same `as` struct tags used by reflection, and the
+// records written by generated code and reflection are interchangeable:
+//
+//  type User struct {
+//    Name     string            `as:"name"`
+//    Email    string            `as:"email,omitempty"`
+//    Created  time.Time         `as:"created"`
+//    Address  *Address          `as:"addr"`
+//    Tags     []string          `as:"tags"`
+//    Scores   map[string]int    `as:"scores"`
+//    Internal string            `as:"-"`
+//    Gen      uint32            `asm:"gen"`
+//    TTL      uint32            `asm:"ttl"`
+//  }
+//
+// Add a directive to a file of the package, and run go generate:
+//
+//  //go:generate go run github.com/aerospike/aerospike-client-go/tools/asgen -type=User,Address
+//
+// Struct types of the same package used in the fields of a type must be generated
+// as well, by the same or another directive of the package. A type must only be
+// generated by one directive. Fields of other packages are not supported, except
+// time.Time and time.Duration.
+//
+// Usage:
+//
+//  asgen -type=T1,T2 [-output=file.go] [-tag=as] [dir]
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+var (
+	typeNames = flag.String("type", "", "comma-separated list of struct type names; required")
+	output    = flag.String("output", "", "output file name; default <type>_asgen.go")
+	tagName   = flag.String("tag", "as", "struct tag of the bin names")
+)
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("asgen: ")
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: asgen -type=T1,T2 [-output=file.go] [-tag=as] [dir]\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if *typeNames == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+	names := strings.Split(*typeNames, ",")
+
+	dir := "."
+	if flag.NArg() > 0 {
+		dir = flag.Arg(0)
+	}
+
+	g, err := newGenerator(dir, names[0], *output, *tagName)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	src, err := g.generate(names)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if err := ioutil.WriteFile(g.output, src, 0644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// generator holds the types of the package and the generated code.
+type generator struct {
+	pkgName string
+	output  string
+	tag     string
+
+	// structs are the struct types of the package, and named the other types
+	structs map[string]*ast.StructType
+	named   map[string]ast.Expr
+
+	buf  bytes.Buffer
+	vars int
+
+	usesBinconv bool
+}
+
+// newGenerator parses the package in dir which declares the type.
+// The output file is not parsed, as it will be replaced.
+func newGenerator(dir, typeName, output, tag string) (*generator, error) {
+	if output == "" {
+		output = strings.ToLower(typeName) + "_asgen.go"
+	}
+	if !filepath.IsAbs(output) {
+		output = filepath.Join(dir, output)
+	}
+
+	// the output may be given as an absolute path, so the files are compared by absolute path
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+	absOutput, err := filepath.Abs(output)
+	if err != nil {
+		return nil, err
+	}
+
+	fset := token.NewFileSet()
+	pkgs, err := parser.ParseDir(fset, dir, func(fi os.FileInfo) bool {
+		return filepath.Join(absDir, fi.Name()) != absOutput
+	}, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, pkg := range pkgs {
+		g := &generator{
+			pkgName: pkg.Name,
+			output:  output,
+			tag:     tag,
+			structs: map[string]*ast.StructType{},
+			named:   map[string]ast.Expr{},
+		}
+
+		for _, file := range pkg.Files {
+			for _, decl := range file.Decls {
+				gd, ok := decl.(*ast.GenDecl)
+				if !ok || gd.Tok != token.TYPE {
+					continue
+				}
+				for _, spec := range gd.Specs {
+					ts := spec.(*ast.TypeSpec)
+					if st, ok := ts.Type.(*ast.StructType); ok {
+						g.structs[ts.Name.Name] = st
+					} else {
+						g.named[ts.Name.Name] = ts.Type
+					}
+				}
+			}
+		}
+
+		if _, exists := g.structs[typeName]; exists {
+			return g, nil
+		}
+	}
+	return nil, fmt.Errorf("struct type %s not found in %s", typeName, dir)
+}
+
+// field is a field of a struct which is stored in a bin, or set from the metadata of the record.
+type field struct {
+	name      string
+	bin       string
+	omitEmpty bool
+	meta      string
+	typ       ast.Expr
+}
+
+// fields returns the fields of the struct, following the rules of reflection.
+func (g *generator) fields(typeName string, st *ast.StructType) ([]field, error) {
+	var res []field
+	for _, f := range st.Fields.List {
+		names := make([]string, 0, len(f.Names))
+		for _, n := range f.Names {
+			names = append(names, n.Name)
+		}
+
+		// embedded fields are named by their type
+		if len(names) == 0 {
+			t := f.Type
+			if star, ok := t.(*ast.StarExpr); ok {
+				t = star.X
+			}
+			switch t := t.(type) {
+			case *ast.Ident:
+				names = append(names, t.Name)
+			case *ast.SelectorExpr:
+				names = append(names, t.Sel.Name)
+			}
+		}
+
+		var tag reflect.StructTag
+		if f.Tag != nil {
+			s, err := strconv.Unquote(f.Tag.Value)
+			if err != nil {
+				return nil, err
+			}
+			tag = reflect.StructTag(s)
+		}
+
+		bin, omitEmpty := parseTag(tag.Get(g.tag))
+		meta := strings.Trim(tag.Get("asm"), " ")
+		for _, name := range names {
+			if !ast.IsExported(name) {
+				continue
+			}
+
+			if bin != "" && meta != "" {
+				return nil, fmt.Errorf("cannot accept both data and metadata tags on the same attribute on struct: %s.%s", typeName, name)
+			}
+
+			switch {
+			case meta == "gen" || meta == "ttl":
+				if k, err := g.kind(f.Type); err != nil || (k != kindInt && k != kindUint) {
+					return nil, fmt.Errorf("metadata attribute %s.%s must be an integer", typeName, name)
+				}
+				res = append(res, field{name: name, meta: meta, typ: f.Type})
+			case meta != "":
+				return nil, fmt.Errorf("invalid metadata tag `%s` on struct attribute: %s.%s", meta, typeName, name)
+			case bin == "-":
+			case bin == "":
+				res = append(res, field{name: name, bin: name, omitEmpty: omitEmpty, typ: f.Type})
+			default:
+				res = append(res, field{name: name, bin: bin, omitEmpty: omitEmpty, typ: f.Type})
+			}
+		}
+	}
+	return res, nil
+}
+
+// parseTag splits a bin tag into the bin name and the omitempty option.
+func parseTag(tag string) (name string, omitEmpty bool) {
+	name = strings.Trim(tag, " ")
+	if i := strings.Index(name, ","); i >= 0 {
+		omitEmpty = strings.Trim(name[i+1:], " ") == "omitempty"
+		name = strings.Trim(name[:i], " ")
+	}
+	return name, omitEmpty
+}
+
+// kinds of the types of the fields
+const (
+	kindInt = iota
+	kindUint
+	kindFloat
+	kindBool
+	kindString
+	kindBytes
+	kindTime
+	kindStruct
+	kindPtr
+	kindSlice
+	kindArray
+	kindMap
+	kindInterface
+)
+
+// kind returns how the type is converted.
+func (g *generator) kind(t ast.Expr) (int, error) {
+	switch t := t.(type) {
+	case *ast.Ident:
+		switch t.Name {
+		case "int", "int8", "int16", "int32", "int64", "rune":
+			return kindInt, nil
+		case "uint", "uint8", "uint16", "uint32", "uint64", "uintptr", "byte":
+			return kindUint, nil
+		case "float32", "float64":
+			return kindFloat, nil
+		case "bool":
+			return kindBool, nil
+		case "string":
+			return kindString, nil
+		}
+
+		if _, exists := g.structs[t.Name]; exists {
+			return kindStruct, nil
+		}
+		if under, exists := g.named[t.Name]; exists {
+			return g.kind(under)
+		}
+	case *ast.SelectorExpr:
+		if pkg, ok := t.X.(*ast.Ident); ok && pkg.Name == "time" {
+			switch t.Sel.Name {
+			case "Time":
+				return kindTime, nil
+			case "Duration":
+				return kindInt, nil
+			}
+		}
+	case *ast.StarExpr:
+		return kindPtr, nil
+	case *ast.ArrayType:
+		if t.Len != nil {
+			return kindArray, nil
+		}
+		if elem, ok := t.Elt.(*ast.Ident); ok && (elem.Name == "byte" || elem.Name == "uint8") {
+			return kindBytes, nil
+		}
+		return kindSlice, nil
+	case *ast.MapType:
+		return kindMap, nil
+	case *ast.InterfaceType:
+		if len(t.Methods.List) == 0 {
+			return kindInterface, nil
+		}
+	}
+	return 0, fmt.Errorf("unsupported type %s", typeString(t))
+}
+
+// underlying returns the type expression of a named type of the package.
+func (g *generator) underlying(t ast.Expr) ast.Expr {
+	for {
+		id, ok := t.(*ast.Ident)
+		if !ok {
+			return t
+		}
+		under, exists := g.named[id.Name]
+		if !exists {
+			return t
+		}
+		t = under
+	}
+}
+
+func typeString(t ast.Expr) string {
+	var buf bytes.Buffer
+	format.Node(&buf, token.NewFileSet(), t)
+	return buf.String()
+}
+
+func (g *generator) printf(format string, args ...interface{}) {
+	fmt.Fprintf(&g.buf, format, args...)
+}
+
+// newVar returns a unique variable name.
+func (g *generator) newVar(prefix string) string {
+	g.vars++
+	return fmt.Sprintf("%s%d", prefix, g.vars)
+}
+
+// generate returns the formatted source of the implementations for the types.
+func (g *generator) generate(names []string) ([]byte, error) {
+	for _, name := range names {
+		st, exists := g.structs[name]
+		if !exists {
+			return nil, fmt.Errorf("struct type %s not found", name)
+		}
+
+		fields, err := g.fields(name, st)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := g.generateType(name, fields); err != nil {
+			return nil, err
+		}
+	}
+
+	body := g.buf.Bytes()
+
+	var src bytes.Buffer
+	fmt.Fprintf(&src, "// Code generated by asgen; DO NOT EDIT.\n\npackage %s\n\nimport (\n", g.pkgName)
+	if bytes.Contains(body, []byte("time.")) {
+		fmt.Fprintf(&src, "\t\"time\"\n\n")
+	}
+	fmt.Fprintf(&src, "\tas \"github.com/aerospike/aerospike-client-go\"\n")
+	if g.usesBinconv {
+		fmt.Fprintf(&src, "\t\"github.com/aerospike/aerospike-client-go/utils/binconv\"\n")
+	}
+	fmt.Fprintf(&src, ")\n")
+	src.Write(body)
+
+	res, err := format.Source(src.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("invalid generated code: %v\n%s", err, src.Bytes())
+	}
+	return res, nil
+}
+
+// generateType generates the methods and the conversion functions of the type.
+func (g *generator) generateType(name string, fields []field) error {
+	g.printf("\n// MarshalBins implements the as.BinMarshaler interface.\n")
+	g.printf("func (v *%s) MarshalBins() (as.BinMap, error) {\n", name)
+	g.printf("return as.BinMap(asgenEncode%s(v)), nil\n}\n", name)
+
+	g.printf("\n// UnmarshalBins implements the as.BinUnmarshaler interface.\n")
+	g.printf("func (v *%s) UnmarshalBins(bins as.BinMap, generation, expiration uint32) error {\n", name)
+	g.printf("if err := asgenDecode%s(v, map[string]interface{}(bins)); err != nil {\nreturn err\n}\n", name)
+	for _, f := range fields {
+		switch f.meta {
+		case "gen":
+			g.printf("v.%s = %s(generation)\n", f.name, typeString(f.typ))
+		case "ttl":
+			g.printf("v.%s = %s(expiration)\n", f.name, typeString(f.typ))
+		}
+	}
+	g.printf("return nil\n}\n")
+
+	g.printf("\nfunc asgenEncode%s(v *%s) map[string]interface{} {\n", name, name)
+	g.printf("m := make(map[string]interface{}, %d)\n", len(fields))
+	for _, f := range fields {
+		if f.meta != "" {
+			continue
+		}
+
+		src := "v." + f.name
+		dst := fmt.Sprintf("m[%q]", f.bin)
+		cond := ""
+		if f.omitEmpty {
+			var err error
+			if cond, err = g.nonEmpty(f.typ, src); err != nil {
+				return err
+			}
+		}
+
+		if cond != "" {
+			g.printf("if %s {\n", cond)
+		}
+		if err := g.encode(f.typ, src, dst, cond != ""); err != nil {
+			return fmt.Errorf("%s.%s: %v", name, f.name, err)
+		}
+		if cond != "" {
+			g.printf("}\n")
+		}
+	}
+	g.printf("return m\n}\n")
+
+	g.printf("\nfunc asgenDecode%s(v *%s, m map[string]interface{}) error {\n", name, name)
+	for _, f := range fields {
+		if f.meta != "" {
+			continue
+		}
+
+		g.printf("if value := m[%q]; value != nil {\n", f.bin)
+		if err := g.decode(f.typ, "value", "v."+f.name); err != nil {
+			return fmt.Errorf("%s.%s: %v", name, f.name, err)
+		}
+		g.printf("}\n")
+	}
+	g.printf("return nil\n}\n")
+	return nil
+}
+
+// nonEmpty returns the condition under which a field with the omitempty option is stored.
+// Structs and arrays are always stored.
+func (g *generator) nonEmpty(t ast.Expr, src string) (string, error) {
+	k, err := g.kind(t)
+	if err != nil {
+		return "", err
+	}
+
+	switch k {
+	case kindInt, kindUint, kindFloat:
+		return src + " != 0", nil
+	case kindBool:
+		return src, nil
+	case kindString:
+		return src + ` != ""`, nil
+	case kindBytes, kindSlice, kindMap:
+		return "len(" + src + ") != 0", nil
+	case kindPtr, kindInterface:
+		return src + " != nil", nil
+	}
+	return "", nil
+}
+
+// encode generates the statements which assign the bin value of src to dst.
+// src is addressable, and known not to be nil if nonNil is set.
+func (g *generator) encode(t ast.Expr, src, dst string, nonNil bool) error {
+	k, err := g.kind(t)
+	if err != nil {
+		return err
+	}
+
+	conv := func(typ string) string {
+		if typeString(t) == typ {
+			return src
+		}
+		return typ + "(" + src + ")"
+	}
+
+	// nil values remove the bin, or are stored as nil in lists and maps
+	nilCheck := func() (end string) {
+		if nonNil {
+			return ""
+		}
+		g.printf("if %s == nil {\n%s = nil\n} else {\n", src, dst)
+		return "}\n"
+	}
+
+	under := g.underlying(t)
+	switch k {
+	case kindInt, kindUint:
+		g.printf("%s = %s\n", dst, conv("int64"))
+	case kindFloat:
+		g.printf("%s = %s\n", dst, conv("float64"))
+	case kindBool:
+		g.usesBinconv = true
+		g.printf("%s = binconv.FromBool(%s)\n", dst, conv("bool"))
+	case kindString:
+		g.printf("%s = %s\n", dst, conv("string"))
+	case kindTime:
+		g.usesBinconv = true
+		g.printf("%s = binconv.FromTime(%s)\n", dst, src)
+	case kindInterface:
+		g.printf("%s = %s\n", dst, src)
+	case kindStruct:
+		if strings.HasPrefix(src, "(*") {
+			g.printf("%s = asgenEncode%s(%s)\n", dst, typeString(t), src[2:len(src)-1])
+		} else {
+			g.printf("%s = asgenEncode%s(&%s)\n", dst, typeString(t), src)
+		}
+	case kindBytes:
+		if nonNil {
+			g.printf("%s = %s\n", dst, conv("[]byte"))
+		} else {
+			g.printf("if %s == nil {\n%s = nil\n} else {\n%s = %s\n}\n", src, dst, dst, conv("[]byte"))
+		}
+	case kindPtr:
+		end := nilCheck()
+		if err := g.encode(under.(*ast.StarExpr).X, "(*"+src+")", dst, false); err != nil {
+			return err
+		}
+		g.buf.WriteString(end)
+	case kindSlice, kindArray:
+		end := "}\n"
+		if k == kindSlice {
+			end = nilCheck()
+		} else {
+			g.printf("{\n")
+		}
+		l, i := g.newVar("l"), g.newVar("i")
+		g.printf("%s := make([]interface{}, len(%s))\n", l, src)
+		g.printf("for %s := range %s {\n", i, src)
+		if err := g.encode(under.(*ast.ArrayType).Elt, src+"["+i+"]", l+"["+i+"]", false); err != nil {
+			return err
+		}
+		g.printf("}\n%s = %s\n%s", dst, l, end)
+	case kindMap:
+		mt := under.(*ast.MapType)
+		mm, key, elem, kk, ee := g.newVar("m"), g.newVar("k"), g.newVar("e"), g.newVar("k"), g.newVar("e")
+		end := nilCheck()
+		g.printf("%s := make(map[interface{}]interface{}, len(%s))\n", mm, src)
+		g.printf("for %s, %s := range %s {\n", key, elem, src)
+		g.printf("var %s, %s interface{}\n", kk, ee)
+		if err := g.encode(mt.Key, key, kk, false); err != nil {
+			return err
+		}
+		if err := g.encode(mt.Value, elem, ee, false); err != nil {
+			return err
+		}
+		g.printf("%s[%s] = %s\n}\n%s = %s\n%s", mm, kk, ee, dst, mm, end)
+	}
+	return nil
+}
+
+// decode generates the statements which set dst from the non-nil bin value src.
+// dst is addressable, and the statements are enclosed in a block by the caller.
+func (g *generator) decode(t ast.Expr, src, dst string) error {
+	k, err := g.kind(t)
+	if err != nil {
+		return err
+	}
+
+	conv := func(fn, typ string) {
+		g.usesBinconv = true
+		x := g.newVar("x")
+		g.printf("%s, err := binconv.%s(%s)\nif err != nil {\nreturn err\n}\n", x, fn, src)
+		if typeString(t) == typ {
+			g.printf("%s = %s\n", dst, x)
+		} else {
+			g.printf("%s = %s(%s)\n", dst, typeString(t), x)
+		}
+	}
+
+	under := g.underlying(t)
+	switch k {
+	case kindInt:
+		conv("Int64", "int64")
+	case kindUint:
+		conv("Uint64", "uint64")
+	case kindFloat:
+		conv("Float64", "float64")
+	case kindBool:
+		conv("Bool", "bool")
+	case kindString:
+		conv("String", "string")
+	case kindBytes:
+		conv("Bytes", "[]byte")
+	case kindTime:
+		conv("Time", "time.Time")
+	case kindInterface:
+		g.printf("%s = %s\n", dst, src)
+	case kindStruct:
+		g.usesBinconv = true
+		x := g.newVar("x")
+		g.printf("%s, err := binconv.StringMap(%s)\nif err != nil {\nreturn err\n}\n", x, src)
+		g.printf("if err := asgenDecode%s(&%s, %s); err != nil {\nreturn err\n}\n", typeString(t), dst, x)
+	case kindPtr:
+		elem := under.(*ast.StarExpr).X
+		p := g.newVar("p")
+		g.printf("var %s %s\n", p, typeString(elem))
+		if err := g.decode(elem, src, p); err != nil {
+			return err
+		}
+		g.printf("%s = &%s\n", dst, p)
+	case kindSlice, kindArray:
+		g.usesBinconv = true
+		l, s, i, e := g.newVar("l"), g.newVar("s"), g.newVar("i"), g.newVar("e")
+		g.printf("%s, err := binconv.List(%s)\nif err != nil {\nreturn err\n}\n", l, src)
+		if k == kindSlice {
+			g.printf("%s := make(%s, len(%s))\n", s, typeString(t), l)
+		} else {
+			g.printf("var %s %s\n", s, typeString(t))
+		}
+		g.printf("for %s, %s := range %s {\n", i, e, l)
+		if k == kindArray {
+			g.printf("if %s >= len(%s) {\nbreak\n}\n", i, s)
+		}
+		g.printf("if %s != nil {\n", e)
+		if err := g.decode(under.(*ast.ArrayType).Elt, e, s+"["+i+"]"); err != nil {
+			return err
+		}
+		g.printf("}\n}\n%s = %s\n", dst, s)
+	case kindMap:
+		g.usesBinconv = true
+		mt := under.(*ast.MapType)
+		mm, r, key, elem, kk, ee := g.newVar("m"), g.newVar("r"), g.newVar("k"), g.newVar("e"), g.newVar("k"), g.newVar("e")
+		g.printf("%s, err := binconv.Map(%s)\nif err != nil {\nreturn err\n}\n", mm, src)
+		g.printf("%s := make(%s, len(%s))\n", r, typeString(t), mm)
+		g.printf("for %s, %s := range %s {\n", key, elem, mm)
+		g.printf("var %s %s\nif %s != nil {\n", kk, typeString(mt.Key), key)
+		if err := g.decode(mt.Key, key, kk); err != nil {
+			return err
+		}
+		g.printf("}\nvar %s %s\nif %s != nil {\n", ee, typeString(mt.Value), elem)
+		if err := g.decode(mt.Value, elem, ee); err != nil {
+			return err
+		}
+		g.printf("}\n%s[%s] = %s\n}\n%s = %s\n", r, kk, ee, dst, r)
+	}
+	return nil
+}
@@ -49,6 +49,10 @@ func (cmd *touchCommand) getNode(ifc command) (*Node, error) {
 	return cmd.cluster.getMasterNode(&cmd.partition)
 }
 
+func (cmd *touchCommand) commandType() CommandType {
+	return CMD_TOUCH
+}
+
 func (cmd *touchCommand) parseResult(ifc command, conn *Connection) error {
 	// Read header.
 	if _, err := conn.Read(cmd.dataBuffer, int(_MSG_TOTAL_HEADER_SIZE)); err != nil {
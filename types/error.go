@@ -23,10 +23,51 @@ import (
 // All errors returning from the library are of this type.
 // Errors resulting from Go's stdlib are not translated to this type, unless
 // they are a net.Timeout error.
+//
+// Errors returned from commands also describe the command: the node and the key it
+// was sent to, the errors of all its attempts, and whether it may have been applied.
+//
+// Errors match the sentinel errors with the same result code in errors.Is:
+//
+//	if errors.Is(err, ErrKeyNotFound) {
+//		...
+//	}
 type AerospikeError struct {
 	error
 
 	resultCode ResultCode
+
+	// details is nil for errors without cause and command info
+	details *errorDetails
+}
+
+type errorDetails struct {
+	// cause is the underlying error, if any
+	cause error
+
+	// merged holds the errors of the commands of a batch, scan or query
+	merged []error
+
+	info CommandInfo
+}
+
+// CommandInfo describes the command an error was returned from.
+type CommandInfo struct {
+	// Node is the node of the last attempt, as returned by Node.String().
+	Node string
+
+	// Namespace is the namespace of the command, if any.
+	Namespace string
+
+	// Digest is the digest of the key, for single record commands.
+	Digest []byte
+
+	// InDoubt is true if the command is a write that may have been applied
+	// by the server, because an attempt failed after the command was sent.
+	InDoubt bool
+
+	// Attempts holds the errors of the attempts of the command, in order.
+	Attempts []error
 }
 
 // ResultCode returns the ResultCode from AerospikeError object.
@@ -34,6 +75,93 @@ func (ase AerospikeError) ResultCode() ResultCode {
 	return ase.resultCode
 }
 
+// Node returns the node the command was last sent to, or an empty string if the
+// error did not happen in a command.
+func (ase AerospikeError) Node() string {
+	if ase.details == nil {
+		return ""
+	}
+	return ase.details.info.Node
+}
+
+// Namespace returns the namespace of the command, if any.
+func (ase AerospikeError) Namespace() string {
+	if ase.details == nil {
+		return ""
+	}
+	return ase.details.info.Namespace
+}
+
+// Digest returns the digest of the key of a single record command.
+func (ase AerospikeError) Digest() []byte {
+	if ase.details == nil {
+		return nil
+	}
+	return ase.details.info.Digest
+}
+
+// InDoubt returns true if the command is a write that may have been applied by the
+// server. For merged errors, it returns true if any of the commands is in doubt.
+func (ase AerospikeError) InDoubt() bool {
+	if ase.details == nil {
+		return false
+	}
+	if ase.details.info.InDoubt {
+		return true
+	}
+	for _, err := range ase.details.merged {
+		if aerr, ok := err.(AerospikeError); ok && aerr.InDoubt() {
+			return true
+		}
+	}
+	return false
+}
+
+// Attempts returns the errors of the attempts of the command, in order.
+// Its length is the number of times the command was sent.
+func (ase AerospikeError) Attempts() []error {
+	if ase.details == nil {
+		return nil
+	}
+	return ase.details.info.Attempts
+}
+
+// Errors returns the errors of the commands of a batch, scan or query
+// that failed on multiple nodes.
+func (ase AerospikeError) Errors() []error {
+	if ase.details == nil {
+		return nil
+	}
+	return ase.details.merged
+}
+
+// Unwrap returns the underlying error, if any.
+func (ase AerospikeError) Unwrap() error {
+	if ase.details == nil {
+		return nil
+	}
+	return ase.details.cause
+}
+
+// Is returns true if the target is an AerospikeError with the same result code.
+// Merged errors also match the targets of any of their errors.
+func (ase AerospikeError) Is(target error) bool {
+	t, ok := target.(AerospikeError)
+	if ok && t.resultCode == ase.resultCode {
+		return true
+	}
+
+	if ase.details == nil {
+		return false
+	}
+	for _, err := range ase.details.merged {
+		if aerr, ok := err.(AerospikeError); ok && aerr.Is(target) {
+			return true
+		}
+	}
+	return false
+}
+
 // New AerospikeError generates a new AerospikeError instance.
 // If no message is provided, the result code will be translated into the default
 // error message automatically.
@@ -46,5 +174,102 @@ func NewAerospikeError(code ResultCode, messages ...string) error {
 	return AerospikeError{error: err, resultCode: code}
 }
 
-var ErrRecordsetClosed = NewAerospikeError(RECORDSET_CLOSED, "Recordset has already been closed.")
-var ErrConnectionPoolEmpty = NewAerospikeError(NO_AVAILABLE_CONNECTIONS_TO_NODE)
+// NewAerospikeErrorWithCause generates a new AerospikeError instance caused by
+// another error, which is returned by Unwrap.
+func NewAerospikeErrorWithCause(code ResultCode, cause error, messages ...string) error {
+	err := NewAerospikeError(code, messages...).(AerospikeError)
+	err.details = &errorDetails{cause: cause}
+	return err
+}
+
+// WithCommandInfo returns a copy of the error with the command info set.
+// Errors that are not AerospikeErrors are returned as they are.
+func WithCommandInfo(err error, info *CommandInfo) error {
+	aerr, ok := err.(AerospikeError)
+	if !ok {
+		return err
+	}
+
+	details := &errorDetails{info: *info}
+	if aerr.details != nil {
+		details.cause = aerr.details.cause
+		details.merged = aerr.details.merged
+	}
+	aerr.details = details
+	return aerr
+}
+
+// MergeErrors merges the errors of the commands of a batch, scan or query
+// that was sent to multiple nodes. A single error is returned as it is.
+// The merged error has the result code of the first AerospikeError, and its
+// message lists the messages of all errors.
+func MergeErrors(errs []error) error {
+	switch len(errs) {
+	case 0:
+		return nil
+	case 1:
+		return errs[0]
+	}
+
+	code := SERVER_NOT_AVAILABLE
+	for _, err := range errs {
+		if aerr, ok := err.(AerospikeError); ok {
+			code = aerr.resultCode
+			break
+		}
+	}
+
+	msgs := make([]string, len(errs))
+	for i, err := range errs {
+		msgs[i] = err.Error()
+	}
+
+	return AerospikeError{
+		error:      errors.New(strings.Join(msgs, "\n")),
+		resultCode: code,
+		details:    &errorDetails{merged: errs},
+	}
+}
+
+// Errors returned by the client, which can be matched with errors.Is.
+var (
+	ErrRecordsetClosed     = NewAerospikeError(RECORDSET_CLOSED, "Recordset has already been closed.")
+	ErrConnectionPoolEmpty = NewAerospikeError(NO_AVAILABLE_CONNECTIONS_TO_NODE)
+
+	ErrServerNotAvailable = NewAerospikeError(SERVER_NOT_AVAILABLE)
+	ErrInvalidNode        = NewAerospikeError(INVALID_NODE_ERROR)
+	ErrTimeout            = NewAerospikeError(TIMEOUT)
+	ErrKeyNotFound        = NewAerospikeError(KEY_NOT_FOUND_ERROR)
+	ErrKeyExists          = NewAerospikeError(KEY_EXISTS_ERROR)
+	ErrGeneration         = NewAerospikeError(GENERATION_ERROR)
+	ErrBinTypeMismatch    = NewAerospikeError(BIN_TYPE_ERROR)
+	ErrBinNotFound        = NewAerospikeError(BIN_NOT_FOUND)
+	ErrRecordTooBig       = NewAerospikeError(RECORD_TOO_BIG)
+	ErrKeyBusy            = NewAerospikeError(KEY_BUSY)
+	ErrDeviceOverload     = NewAerospikeError(DEVICE_OVERLOAD)
+	ErrInvalidNamespace   = NewAerospikeError(INVALID_NAMESPACE)
+	ErrNotAuthenticated   = NewAerospikeError(NOT_AUTHENTICATED)
+	ErrUDFBadResponse     = NewAerospikeError(UDF_BAD_RESPONSE)
+)
+
+// LuaError describes an error raised by a Lua UDF or stream function run by the client.
+// It is the cause of the UDF_BAD_RESPONSE AerospikeError returned for the failure:
+//
+//	if lerr, ok := err.(AerospikeError).Unwrap().(*LuaError); ok {
+//		log.Println(lerr.StackTrace)
+//	}
+type LuaError struct {
+	// Message is the error raised by the Lua code, including its position if known.
+	Message string
+
+	// StackTrace is the Lua stack trace of the error, if available.
+	StackTrace string
+}
+
+// Error returns the message and the stack trace of the error.
+func (e *LuaError) Error() string {
+	if e.StackTrace == "" {
+		return e.Message
+	}
+	return e.Message + "\n" + e.StackTrace
+}
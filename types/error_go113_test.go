@@ -0,0 +1,44 @@
+// +build go1.13
+
+// Copyright 2013-2017 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package types_test
+
+import (
+	"errors"
+	"io"
+
+	. "github.com/aerospike/aerospike-client-go/types"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Aerospike Error with the errors package", func() {
+
+	It("must support errors.Is and errors.As", func() {
+		err := WithCommandInfo(NewAerospikeErrorWithCause(TIMEOUT, io.EOF), &CommandInfo{Node: "BB9"})
+		Expect(errors.Is(err, ErrTimeout)).To(BeTrue())
+		Expect(errors.Is(err, io.EOF)).To(BeTrue())
+		Expect(errors.Is(err, ErrKeyNotFound)).To(BeFalse())
+
+		var aerr AerospikeError
+		Expect(errors.As(err, &aerr)).To(BeTrue())
+		Expect(aerr.Node()).To(Equal("BB9"))
+
+		merged := MergeErrors([]error{ErrKeyBusy, err})
+		Expect(errors.Is(merged, ErrTimeout)).To(BeTrue())
+	})
+})
@@ -0,0 +1,98 @@
+// Copyright 2013-2017 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package types_test
+
+import (
+	"errors"
+	"io"
+
+	. "github.com/aerospike/aerospike-client-go/types"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Aerospike Error", func() {
+
+	It("must match sentinel errors by result code", func() {
+		err := NewAerospikeError(KEY_NOT_FOUND_ERROR, "not here").(AerospikeError)
+		Expect(err.Is(ErrKeyNotFound)).To(BeTrue())
+		Expect(err.Is(ErrKeyExists)).To(BeFalse())
+		Expect(err.Is(io.EOF)).To(BeFalse())
+		Expect(err.Error()).To(Equal("not here"))
+	})
+
+	It("must unwrap the cause", func() {
+		err := NewAerospikeErrorWithCause(TIMEOUT, io.EOF, "timed out").(AerospikeError)
+		Expect(err.Unwrap()).To(Equal(io.EOF))
+		Expect(err.ResultCode()).To(Equal(TIMEOUT))
+		Expect(err.Error()).To(Equal("timed out"))
+
+		Expect(NewAerospikeError(TIMEOUT).(AerospikeError).Unwrap()).To(BeNil())
+	})
+
+	It("must describe the command", func() {
+		attempts := []error{io.EOF, ErrKeyExists}
+		cause := NewAerospikeErrorWithCause(KEY_EXISTS_ERROR, io.EOF)
+		err := WithCommandInfo(cause, &CommandInfo{
+			Node:      "BB9 127.0.0.1:3000",
+			Namespace: "test",
+			Digest:    []byte{1, 2, 3},
+			InDoubt:   true,
+			Attempts:  attempts,
+		}).(AerospikeError)
+
+		Expect(err.Node()).To(Equal("BB9 127.0.0.1:3000"))
+		Expect(err.Namespace()).To(Equal("test"))
+		Expect(err.Digest()).To(Equal([]byte{1, 2, 3}))
+		Expect(err.InDoubt()).To(BeTrue())
+		Expect(err.Attempts()).To(Equal(attempts))
+		Expect(err.Unwrap()).To(Equal(io.EOF))
+		Expect(err.Is(ErrKeyExists)).To(BeTrue())
+
+		// the original error is not changed
+		Expect(cause.(AerospikeError).Node()).To(BeEmpty())
+
+		// other errors are not annotated
+		Expect(WithCommandInfo(io.EOF, &CommandInfo{Node: "BB9"})).To(Equal(io.EOF))
+	})
+
+	It("must return zero values for errors without command info", func() {
+		err := ErrTimeout.(AerospikeError)
+		Expect(err.Node()).To(BeEmpty())
+		Expect(err.Namespace()).To(BeEmpty())
+		Expect(err.Digest()).To(BeNil())
+		Expect(err.InDoubt()).To(BeFalse())
+		Expect(err.Attempts()).To(BeNil())
+		Expect(err.Errors()).To(BeNil())
+	})
+
+	It("must merge errors", func() {
+		Expect(MergeErrors(nil)).To(BeNil())
+		Expect(MergeErrors([]error{ErrTimeout})).To(Equal(ErrTimeout))
+
+		inDoubt := WithCommandInfo(NewAerospikeError(TIMEOUT, "timed out"), &CommandInfo{InDoubt: true})
+		errs := []error{errors.New("boom"), NewAerospikeError(KEY_BUSY, "busy"), inDoubt}
+		err := MergeErrors(errs).(AerospikeError)
+
+		Expect(err.ResultCode()).To(Equal(KEY_BUSY))
+		Expect(err.Error()).To(Equal("boom\nbusy\ntimed out"))
+		Expect(err.Errors()).To(Equal(errs))
+		Expect(err.InDoubt()).To(BeTrue())
+		Expect(err.Is(ErrKeyBusy)).To(BeTrue())
+		Expect(err.Is(ErrTimeout)).To(BeTrue())
+		Expect(err.Is(ErrKeyNotFound)).To(BeFalse())
+	})
+})
@@ -34,7 +34,7 @@ const (
 	// RTA_DICT        = 15
 	// RTA_APPEND_DICT = 16
 	// RTA_APPEND_LIST = 17
-	// LUA_BLOB        = 18
+	HLL     = 18
 	MAP     = 19
 	LIST    = 20
 	LDT     = 21
@@ -133,6 +133,9 @@ const (
 	// Element Already Exists in CDT
 	FAIL_ELEMENT_EXISTS ResultCode = 24
 
+	// Transaction was not performed because the predicate expression filter returned false.
+	FILTERED_OUT ResultCode = 27
+
 	// There are no more records left for query.
 	QUERY_END ResultCode = 50
 
@@ -388,6 +391,9 @@ func ResultCodeToString(resultCode ResultCode) string {
 	case FAIL_ELEMENT_EXISTS:
 		return "Element exists"
 
+	case FILTERED_OUT:
+		return "Transaction filtered out by predicate expression"
+
 	case QUERY_END:
 		return "Query end"
 
@@ -206,6 +206,11 @@ func (upckr *unpacker) unpackBlob(count int, isMapKey bool) (interface{}, error)
 	case ParticleType.GEOJSON:
 		val = NewGeoJSONValue(string(upckr.buffer[upckr.offset : upckr.offset+count]))
 
+	case ParticleType.HLL:
+		b := make([]byte, count)
+		copy(b, upckr.buffer[upckr.offset:upckr.offset+count])
+		val = HLLValue(b)
+
 	default:
 		panic(NewAerospikeError(SERIALIZE_ERROR, fmt.Sprintf("Error while unpacking BLOB. Type-header with code `%d` not recognized.", theType)))
 	}
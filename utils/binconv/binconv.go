@@ -0,0 +1,184 @@
+// Copyright 2013-2017 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package binconv converts Go values to and from bin values, the way the
+// reflection based object API of the client does. It is used by the code
+// generated by tools/asgen.
+package binconv
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	. "github.com/aerospike/aerospike-client-go/types"
+)
+
+func typeError(value interface{}, typeName string) error {
+	return NewAerospikeError(PARSE_ERROR, fmt.Sprintf("cannot convert bin value of type %T to %s", value, typeName))
+}
+
+// FromBool converts a bool to the integer bin value 1 or 0.
+func FromBool(b bool) int64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// FromTime converts a time to its Unix time in nanoseconds.
+func FromTime(t time.Time) int64 {
+	return t.UTC().UnixNano()
+}
+
+// Int64 converts an integer bin value.
+func Int64(value interface{}) (int64, error) {
+	switch v := value.(type) {
+	case int:
+		return int64(v), nil
+	case int64:
+		return v, nil
+	case int32:
+		return int64(v), nil
+	case int16:
+		return int64(v), nil
+	case int8:
+		return int64(v), nil
+	case uint:
+		return int64(v), nil
+	case uint64:
+		return int64(v), nil
+	case uint32:
+		return int64(v), nil
+	case uint16:
+		return int64(v), nil
+	case uint8:
+		return int64(v), nil
+	}
+	return 0, typeError(value, "int64")
+}
+
+// Uint64 converts an integer bin value.
+func Uint64(value interface{}) (uint64, error) {
+	if v, ok := value.(uint64); ok {
+		return v, nil
+	}
+
+	v, err := Int64(value)
+	if err != nil {
+		return 0, typeError(value, "uint64")
+	}
+	return uint64(v), nil
+}
+
+// Float64 converts a float bin value. Integers are converted from their bits,
+// which is how floats are stored on servers without float support.
+func Float64(value interface{}) (float64, error) {
+	switch v := value.(type) {
+	case float64:
+		return v, nil
+	case float32:
+		return float64(v), nil
+	}
+
+	bits, err := Int64(value)
+	if err != nil {
+		return 0, typeError(value, "float64")
+	}
+	return math.Float64frombits(uint64(bits)), nil
+}
+
+// Bool converts a bool bin value, which is stored as an integer.
+func Bool(value interface{}) (bool, error) {
+	if v, ok := value.(bool); ok {
+		return v, nil
+	}
+
+	v, err := Int64(value)
+	if err != nil {
+		return false, typeError(value, "bool")
+	}
+	return v == 1, nil
+}
+
+// String converts a string bin value.
+func String(value interface{}) (string, error) {
+	if v, ok := value.(string); ok {
+		return v, nil
+	}
+	return "", typeError(value, "string")
+}
+
+// Bytes converts a blob bin value.
+func Bytes(value interface{}) ([]byte, error) {
+	if v, ok := value.([]byte); ok {
+		return v, nil
+	}
+	return nil, typeError(value, "[]byte")
+}
+
+// Time converts a time bin value, stored as its Unix time in nanoseconds.
+func Time(value interface{}) (time.Time, error) {
+	if v, ok := value.(time.Time); ok {
+		return v, nil
+	}
+
+	v, err := Int64(value)
+	if err != nil {
+		return time.Time{}, typeError(value, "time.Time")
+	}
+	return time.Unix(0, v), nil
+}
+
+// List converts a list bin value.
+func List(value interface{}) ([]interface{}, error) {
+	if v, ok := value.([]interface{}); ok {
+		return v, nil
+	}
+	return nil, typeError(value, "[]interface{}")
+}
+
+// Map converts a map bin value.
+func Map(value interface{}) (map[interface{}]interface{}, error) {
+	switch v := value.(type) {
+	case map[interface{}]interface{}:
+		return v, nil
+	case map[string]interface{}:
+		res := make(map[interface{}]interface{}, len(v))
+		for k, e := range v {
+			res[k] = e
+		}
+		return res, nil
+	}
+	return nil, typeError(value, "map[interface{}]interface{}")
+}
+
+// StringMap converts a map bin value with string keys, which holds a struct.
+func StringMap(value interface{}) (map[string]interface{}, error) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		return v, nil
+	case map[interface{}]interface{}:
+		res := make(map[string]interface{}, len(v))
+		for k, e := range v {
+			key, ok := k.(string)
+			if !ok {
+				return nil, typeError(value, "map[string]interface{}")
+			}
+			res[key] = e
+		}
+		return res, nil
+	}
+	return nil, typeError(value, "map[string]interface{}")
+}
@@ -428,6 +428,14 @@ func NewValue(v interface{}) Value {
 		return value
 	}
 
+	// registered codecs take precedence over reflection
+	if encoded, exists, err := encodeValue(v); exists {
+		if err != nil {
+			panic(err)
+		}
+		return NewValue(encoded)
+	}
+
 	if newValueReflect != nil {
 		if res := newValueReflect(v); res != nil {
 			return res
@@ -971,6 +979,43 @@ func (vl GeoJSONValue) String() string {
 	return string(vl)
 }
 
+///////////////////////////////////////////////////////////////////////////////
+
+// HLLValue encapsulates a HyperLogLog value.
+type HLLValue []byte
+
+// NewHLLValue generates a HLLValue instance.
+func NewHLLValue(bytes []byte) HLLValue {
+	return HLLValue(bytes)
+}
+
+func (vl HLLValue) estimateSize() (int, error) {
+	return len(vl), nil
+}
+
+func (vl HLLValue) write(cmd BufferEx) (int, error) {
+	return cmd.Write(vl)
+}
+
+func (vl HLLValue) pack(cmd BufferEx) (int, error) {
+	return __PackParticleBytes(cmd, ParticleType.HLL, vl)
+}
+
+// GetType returns wire protocol value type.
+func (vl HLLValue) GetType() int {
+	return ParticleType.HLL
+}
+
+// GetObject returns original value as an interface{}.
+func (vl HLLValue) GetObject() interface{} {
+	return []byte(vl)
+}
+
+// String implements Stringer interface.
+func (vl HLLValue) String() string {
+	return Buffer.BytesToHexString(vl)
+}
+
 //////////////////////////////////////////////////////////////////////////////
 
 func bytesToParticle(ptype int, buf []byte, offset int, length int) (interface{}, error) {
@@ -1005,6 +1050,11 @@ func bytesToParticle(ptype int, buf []byte, offset int, length int) (interface{}
 		copy(newObj, buf[offset:offset+length])
 		return newObj, nil
 
+	case ParticleType.HLL:
+		newObj := make([]byte, length)
+		copy(newObj, buf[offset:offset+length])
+		return HLLValue(newObj), nil
+
 	case ParticleType.LDT:
 		return newUnpacker(buf, offset, length).unpackObjects()
 
@@ -0,0 +1,178 @@
+// Copyright 2013-2017 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aerospike
+
+import (
+	"reflect"
+	"sync"
+	"sync/atomic"
+
+	. "github.com/aerospike/aerospike-client-go/types"
+)
+
+// ValueCodec converts the values of a Go type to and from values supported by the database.
+// Register codecs with RegisterValueCodec for types like time.Time, *big.Int or UUIDs,
+// which are otherwise rejected or stored by reflection in their internal representation.
+type ValueCodec interface {
+	// EncodeValue converts a value of the registered type to a string, an integer,
+	// a float, a []byte, a list or a map.
+	EncodeValue(value interface{}) (interface{}, error)
+
+	// DecodeValue converts a value read from the database back to the registered type.
+	DecodeValue(value interface{}) (interface{}, error)
+}
+
+// ValueCodecFuncs implements ValueCodec with a pair of functions.
+type ValueCodecFuncs struct {
+	Encode func(value interface{}) (interface{}, error)
+	Decode func(value interface{}) (interface{}, error)
+}
+
+// EncodeValue implements the ValueCodec interface.
+func (vc ValueCodecFuncs) EncodeValue(value interface{}) (interface{}, error) {
+	return vc.Encode(value)
+}
+
+// DecodeValue implements the ValueCodec interface.
+func (vc ValueCodecFuncs) DecodeValue(value interface{}) (interface{}, error) {
+	return vc.Decode(value)
+}
+
+var (
+	valueCodecsLock sync.Mutex
+	// valueCodecs holds a map[reflect.Type]ValueCodec which is replaced on each change,
+	// so that lookups do not need a lock.
+	valueCodecs atomic.Value
+)
+
+// RegisterValueCodec registers the codec for the type of sample, replacing a previous codec of the type.
+// The codec is used by NewValue, when packing lists and maps, and when mapping
+// struct fields to bins and back in PutObject, GetObject and the object scans and queries.
+//
+// Codecs take precedence over the built-in conversions, except for the types
+// converted directly by NewValue, like string, int or []byte.
+// Encoding errors are returned by the commands; only NewValue panics on them.
+//
+// Bins do not carry the type of their values, so the bins of records read with Get,
+// and the values of lists and maps, are returned in their encoded form.
+// Use DecodeValue to convert them.
+func RegisterValueCodec(sample interface{}, codec ValueCodec) {
+	setValueCodec(reflect.TypeOf(sample), codec)
+}
+
+// UnregisterValueCodec removes the codec of the type of sample.
+func UnregisterValueCodec(sample interface{}) {
+	setValueCodec(reflect.TypeOf(sample), nil)
+}
+
+func setValueCodec(t reflect.Type, codec ValueCodec) {
+	valueCodecsLock.Lock()
+	defer valueCodecsLock.Unlock()
+
+	old, _ := valueCodecs.Load().(map[reflect.Type]ValueCodec)
+	codecs := make(map[reflect.Type]ValueCodec, len(old)+1)
+	for k, v := range old {
+		codecs[k] = v
+	}
+
+	if codec != nil {
+		codecs[t] = codec
+	} else {
+		delete(codecs, t)
+	}
+	valueCodecs.Store(codecs)
+}
+
+// valueCodecFor returns the codec registered for the type, or nil.
+func valueCodecFor(t reflect.Type) ValueCodec {
+	codecs, _ := valueCodecs.Load().(map[reflect.Type]ValueCodec)
+	if len(codecs) == 0 {
+		return nil
+	}
+	return codecs[t]
+}
+
+// hasValueCodecs returns true if any codec is registered.
+func hasValueCodecs() bool {
+	codecs, _ := valueCodecs.Load().(map[reflect.Type]ValueCodec)
+	return len(codecs) > 0
+}
+
+// encodeValue converts the value with the codec registered for its type.
+// It returns false if there is no codec for the value.
+func encodeValue(value interface{}) (interface{}, bool, error) {
+	if value == nil || !hasValueCodecs() {
+		return nil, false, nil
+	}
+
+	t := reflect.TypeOf(value)
+	codec := valueCodecFor(t)
+	if codec == nil {
+		return nil, false, nil
+	}
+
+	res, err := codec.EncodeValue(value)
+	if err != nil {
+		return nil, true, err
+	}
+
+	// avoid infinite recursion
+	if res != nil && reflect.TypeOf(res) == t {
+		return nil, true, NewAerospikeError(PARAMETER_ERROR, "Value codec for type `"+t.String()+"` returned a value of the same type.")
+	}
+	return res, true, nil
+}
+
+// decodeValue sets f to the value decoded by the codec.
+func decodeValue(codec ValueCodec, f reflect.Value, value interface{}) error {
+	res, err := codec.DecodeValue(value)
+	if err != nil {
+		return err
+	}
+
+	if res == nil {
+		f.Set(reflect.Zero(f.Type()))
+		return nil
+	}
+
+	rv := reflect.ValueOf(res)
+	if !rv.Type().AssignableTo(f.Type()) {
+		if !rv.Type().ConvertibleTo(f.Type()) {
+			return NewAerospikeError(PARSE_ERROR, "Value codec for type `"+f.Type().String()+"` returned a value of type `"+rv.Type().String()+"`.")
+		}
+		rv = rv.Convert(f.Type())
+	}
+	f.Set(rv)
+	return nil
+}
+
+// DecodeValue converts a value read from the database with the codec registered
+// for the type obj points to, and stores it in obj.
+//
+//  var id uuid.UUID
+//  err := DecodeValue(rec.Bins["id"], &id)
+func DecodeValue(value interface{}, obj interface{}) error {
+	rv := reflect.ValueOf(obj)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return NewAerospikeError(PARAMETER_ERROR, "DecodeValue requires a non-nil pointer.")
+	}
+
+	f := rv.Elem()
+	codec := valueCodecFor(f.Type())
+	if codec == nil {
+		return NewAerospikeError(PARAMETER_ERROR, "No value codec registered for type `"+f.Type().String()+"`.")
+	}
+	return decodeValue(codec, f, value)
+}
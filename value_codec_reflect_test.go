@@ -0,0 +1,71 @@
+// +build !as_performance
+
+// Copyright 2013-2017 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aerospike
+
+import (
+	"reflect"
+
+	. "github.com/onsi/ginkgo"
+	gm "github.com/onsi/gomega"
+)
+
+type codecShape struct {
+	Center   codecPoint            `as:"center"`
+	Origin   *codecPoint           `as:"origin"`
+	Vertices []codecPoint          `as:"vertices"`
+	Named    map[string]codecPoint `as:"named"`
+}
+
+var _ = Describe("Value codec reflection test", func() {
+
+	BeforeEach(func() {
+		RegisterValueCodec(codecPoint{}, codecPointCodec)
+	})
+
+	AfterEach(func() {
+		UnregisterValueCodec(codecPoint{})
+	})
+
+	It("must map struct fields with the registered codec", func() {
+		shape := &codecShape{
+			Center:   codecPoint{1, 2},
+			Origin:   &codecPoint{0, 0},
+			Vertices: []codecPoint{{1, 1}, {2, 2}},
+			Named:    map[string]codecPoint{"a": {5, 6}},
+		}
+
+		bins, err := structToMap(reflect.ValueOf(shape).Elem(), true)
+		gm.Expect(err).ToNot(gm.HaveOccurred())
+		gm.Expect(bins["center"]).To(gm.Equal("1,2"))
+		gm.Expect(bins["origin"]).To(gm.Equal("0,0"))
+		gm.Expect(bins["vertices"]).To(gm.Equal([]interface{}{"1,1", "2,2"}))
+		gm.Expect(bins["named"]).To(gm.Equal(map[interface{}]interface{}{"a": "5,6"}))
+
+		res := &codecShape{}
+		obj := reflect.ValueOf(res).Elem()
+		gm.Expect(setObjectField(nil, obj, "Center", "1,2")).ToNot(gm.HaveOccurred())
+		gm.Expect(setObjectField(nil, obj, "Origin", "0,0")).ToNot(gm.HaveOccurred())
+		gm.Expect(setObjectField(nil, obj, "Vertices", []interface{}{"1,1", "2,2"})).ToNot(gm.HaveOccurred())
+		gm.Expect(setObjectField(nil, obj, "Named", map[interface{}]interface{}{"a": "5,6"})).ToNot(gm.HaveOccurred())
+		gm.Expect(res).To(gm.Equal(shape))
+
+		// codec errors are returned, not raised
+		shape.Vertices[1] = codecPoint{-1, 2}
+		_, err = marshal(shape, true)
+		gm.Expect(err).To(gm.HaveOccurred())
+	})
+})
@@ -0,0 +1,85 @@
+// Copyright 2013-2017 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aerospike
+
+import (
+	"errors"
+	"fmt"
+
+	. "github.com/onsi/ginkgo"
+	gm "github.com/onsi/gomega"
+)
+
+type codecPoint struct {
+	X, Y int
+}
+
+var codecPointCodec = ValueCodecFuncs{
+	Encode: func(value interface{}) (interface{}, error) {
+		p := value.(codecPoint)
+		if p.X < 0 {
+			return nil, errors.New("negative point")
+		}
+		return fmt.Sprintf("%d,%d", p.X, p.Y), nil
+	},
+	Decode: func(value interface{}) (interface{}, error) {
+		var p codecPoint
+		_, err := fmt.Sscanf(value.(string), "%d,%d", &p.X, &p.Y)
+		return p, err
+	},
+}
+
+var _ = Describe("Value codec test", func() {
+
+	BeforeEach(func() {
+		RegisterValueCodec(codecPoint{}, codecPointCodec)
+	})
+
+	AfterEach(func() {
+		UnregisterValueCodec(codecPoint{})
+	})
+
+	It("must encode values with the registered codec", func() {
+		gm.Expect(NewValue(codecPoint{1, 2})).To(gm.Equal(NewStringValue("1,2")))
+
+		UnregisterValueCodec(codecPoint{})
+		gm.Expect(func() { NewValue(codecPoint{1, 2}) }).To(gm.Panic())
+	})
+
+	It("must pack values in lists and maps with the registered codec", func() {
+		packer := newPacker()
+		_, err := NewListValue([]interface{}{codecPoint{1, 2}, map[interface{}]interface{}{"p": codecPoint{3, 4}}}).pack(packer)
+		gm.Expect(err).ToNot(gm.HaveOccurred())
+
+		list, err := newUnpacker(packer.Bytes(), 0, packer.Len()).UnpackList()
+		gm.Expect(err).ToNot(gm.HaveOccurred())
+		gm.Expect(list).To(gm.Equal([]interface{}{"1,2", map[interface{}]interface{}{"p": "3,4"}}))
+
+		_, err = NewListValue([]interface{}{codecPoint{-1, 2}}).pack(newPacker())
+		gm.Expect(err).To(gm.HaveOccurred())
+	})
+
+	It("must decode values read from the database", func() {
+		var p codecPoint
+		gm.Expect(DecodeValue("7,8", &p)).ToNot(gm.HaveOccurred())
+		gm.Expect(p).To(gm.Equal(codecPoint{7, 8}))
+
+		gm.Expect(DecodeValue("x", &p)).To(gm.HaveOccurred())
+		gm.Expect(DecodeValue("7,8", p)).To(gm.HaveOccurred())
+
+		var i int
+		gm.Expect(DecodeValue(1, &i)).To(gm.HaveOccurred())
+	})
+})
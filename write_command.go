@@ -61,6 +61,10 @@ func (cmd *writeCommand) getNode(ifc command) (*Node, error) {
 	return cmd.cluster.getMasterNode(&cmd.partition)
 }
 
+func (cmd *writeCommand) commandType() CommandType {
+	return CMD_WRITE
+}
+
 func (cmd *writeCommand) parseResult(ifc command, conn *Connection) error {
 	// Read header.
 	if _, err := conn.Read(cmd.dataBuffer, int(_MSG_TOTAL_HEADER_SIZE)); err != nil {